@@ -0,0 +1,137 @@
+//go:build bench
+
+package benchmark
+
+import (
+	"encoding/json"
+	"testing"
+
+	hb "github.com/aasanchez/ocpp16messages/heartbeat"
+	mv "github.com/aasanchez/ocpp16messages/metervalues"
+	sn "github.com/aasanchez/ocpp16messages/statusnotification"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const encodingBufferSize = 4096
+
+var sinkEncoded []byte
+
+func benchmarkHeartbeatConf(b *testing.B) hb.ConfMessage {
+	b.Helper()
+
+	message, err := hb.Conf(hb.ConfInput{CurrentTime: benchmarkTimestamp})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return message
+}
+
+func benchmarkStatusNotificationReq(b *testing.B) sn.ReqMessage {
+	b.Helper()
+
+	timestamp := benchmarkTimestamp
+	info := "Connector locked"
+
+	message, err := sn.Req(sn.ReqInput{
+		ConnectorId:     1,
+		ErrorCode:       "NoError",
+		Status:          "Charging",
+		Info:            &info,
+		Timestamp:       &timestamp,
+		VendorId:        nil,
+		VendorErrorCode: nil,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return message
+}
+
+func benchmarkMeterValuesReq(b *testing.B) mv.ReqMessage {
+	b.Helper()
+
+	transactionId := 42
+	unit := "Wh"
+	measurand := "Energy.Active.Import.Register"
+	sampled := types.SampledValueInput{
+		Value:     "1234.5",
+		Context:   nil,
+		Format:    nil,
+		Measurand: &measurand,
+		Phase:     nil,
+		Location:  nil,
+		Unit:      &unit,
+	}
+
+	message, err := mv.Req(mv.ReqInput{
+		ConnectorId:   1,
+		TransactionId: &transactionId,
+		MeterValue: []types.MeterValueInput{
+			{
+				Timestamp:    benchmarkTimestamp,
+				SampledValue: []types.SampledValueInput{sampled, sampled},
+			},
+		},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return message
+}
+
+func benchmarkEncodingJSON(b *testing.B, message any) {
+	b.Helper()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		encoded, err := json.Marshal(message)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		sinkEncoded = encoded
+	}
+}
+
+func benchmarkAppendJSON(
+	b *testing.B,
+	message interface{ AppendJSON(dst []byte) []byte },
+) {
+	b.Helper()
+	b.ReportAllocs()
+
+	buffer := make([]byte, 0, encodingBufferSize)
+
+	for i := 0; i < b.N; i++ {
+		buffer = message.AppendJSON(buffer[:0])
+	}
+
+	sinkEncoded = buffer
+}
+
+func BenchmarkHeartbeatConf_EncodingJSON(b *testing.B) {
+	benchmarkEncodingJSON(b, benchmarkHeartbeatConf(b))
+}
+
+func BenchmarkHeartbeatConf_AppendJSON(b *testing.B) {
+	benchmarkAppendJSON(b, benchmarkHeartbeatConf(b))
+}
+
+func BenchmarkStatusNotificationReq_EncodingJSON(b *testing.B) {
+	benchmarkEncodingJSON(b, benchmarkStatusNotificationReq(b))
+}
+
+func BenchmarkStatusNotificationReq_AppendJSON(b *testing.B) {
+	benchmarkAppendJSON(b, benchmarkStatusNotificationReq(b))
+}
+
+func BenchmarkMeterValuesReq_EncodingJSON(b *testing.B) {
+	benchmarkEncodingJSON(b, benchmarkMeterValuesReq(b))
+}
+
+func BenchmarkMeterValuesReq_AppendJSON(b *testing.B) {
+	benchmarkAppendJSON(b, benchmarkMeterValuesReq(b))
+}
@@ -0,0 +1,59 @@
+package authorize
+
+import "github.com/aasanchez/ocpp16messages/internal/wire"
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		IdTag: m.IdTag.String(),
+	}
+}
+
+// WithIdTag returns a copy of the message with IdTag set to value. The copy
+// is validated by Req.
+func (m ReqMessage) WithIdTag(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.IdTag = value
+
+	return Req(input)
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	return ConfInput{
+		Status:      m.IdTagInfo.Status().String(),
+		ExpiryDate:  wire.OptionalString(m.IdTagInfo.ExpiryDate()),
+		ParentIdTag: wire.OptionalString(m.IdTagInfo.ParentIdTag()),
+	}
+}
+
+// WithStatus returns a copy of the message with Status set to value. The
+// copy is validated by Conf.
+func (m ConfMessage) WithStatus(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.Status = value
+
+	return Conf(input)
+}
+
+// WithExpiryDate returns a copy of the message with ExpiryDate set to value.
+// The copy is validated by Conf.
+func (m ConfMessage) WithExpiryDate(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.ExpiryDate = &value
+
+	return Conf(input)
+}
+
+// WithParentIdTag returns a copy of the message with ParentIdTag set to
+// value. The copy is validated by Conf.
+func (m ConfMessage) WithParentIdTag(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.ParentIdTag = &value
+
+	return Conf(input)
+}
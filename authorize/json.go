@@ -0,0 +1,71 @@
+package authorize
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// reqWire is the OCPP-J payload of Authorize.req.
+type reqWire struct {
+	IdTag string `json:"idTag"`
+}
+
+// confWire is the OCPP-J payload of Authorize.conf.
+type confWire struct {
+	IdTagInfo wire.IdTagInfo `json:"idTagInfo"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J Authorize.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		IdTag: m.IdTag.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J Authorize.req payload and validates it with
+// Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J Authorize.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		IdTagInfo: wire.NewIdTagInfo(m.IdTagInfo),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J Authorize.conf payload and validates it with
+// Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, confFromWire)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// confPayload decodes the nested idTagInfo object of Authorize.conf, which
+// ConfInput flattens.
+type confPayload struct {
+	IdTagInfo types.IdTagInfoInput
+}
+
+// confFromWire flattens a decoded payload into ConfInput and validates it.
+func confFromWire(payload confPayload) (ConfMessage, error) {
+	return Conf(ConfInput{
+		Status:      payload.IdTagInfo.Status,
+		ExpiryDate:  payload.IdTagInfo.ExpiryDate,
+		ParentIdTag: payload.IdTagInfo.ParentIdTag,
+	})
+}
@@ -0,0 +1,53 @@
+package authorize
+
+import "github.com/aasanchez/ocpp16messages"
+
+// Action is the name of the Authorize action in CALL frames. It is initiated by
+// the Charge Point.
+const Action = "Authorize"
+
+// Action returns Authorize.
+func (ReqMessage) Action() string { return Action }
+
+// Kind returns ocpp16messages.Request.
+func (ReqMessage) Kind() ocpp16messages.Kind { return ocpp16messages.Request }
+
+// Direction returns ocpp16messages.ChargePointToCentralSystem.
+func (ReqMessage) Direction() ocpp16messages.Direction {
+	return ocpp16messages.ChargePointToCentralSystem
+}
+
+// FeatureProfile returns ocpp16messages.Core.
+func (ReqMessage) FeatureProfile() ocpp16messages.FeatureProfile {
+	return ocpp16messages.Core
+}
+
+// Action returns Authorize.
+func (ConfMessage) Action() string { return Action }
+
+// Kind returns ocpp16messages.Confirmation.
+func (ConfMessage) Kind() ocpp16messages.Kind {
+	return ocpp16messages.Confirmation
+}
+
+// Direction returns ocpp16messages.CentralSystemToChargePoint.
+func (ConfMessage) Direction() ocpp16messages.Direction {
+	return ocpp16messages.CentralSystemToChargePoint
+}
+
+// FeatureProfile returns ocpp16messages.Core.
+func (ConfMessage) FeatureProfile() ocpp16messages.FeatureProfile {
+	return ocpp16messages.Core
+}
+
+// Equal reports whether the message carries the same values as other, as
+// ocpp16messages.Diff compares them.
+func (m ReqMessage) Equal(other ReqMessage) bool {
+	return ocpp16messages.Equal(m, other)
+}
+
+// Equal reports whether the message carries the same values as other, as
+// ocpp16messages.Diff compares them.
+func (m ConfMessage) Equal(other ConfMessage) bool {
+	return ocpp16messages.Equal(m, other)
+}
@@ -0,0 +1,30 @@
+// Package availability implements ChangeAvailability on the Charge Point
+// side.
+//
+// A Manager answers each changeavailability.ReqMessage with Accepted,
+// Rejected for an unknown connector, or Scheduled when a transaction runs on
+// the target connector; the change then applies when the transaction stops.
+// ConnectorId 0 addresses the Charge Point and every connector at once.
+//
+// The requested availability is saved to a Store, so that an Inoperative
+// connector stays Inoperative after a reboot. FileStore keeps it in a JSON
+// file and MemoryStore in memory.
+//
+// Once a change applies, SendStatus reports it with a StatusNotification.req
+// of Available or Unavailable:
+//
+//	manager, err := availability.New(conn, availability.FileStore{
+//		Path: "/var/lib/charger/availability.json",
+//	}, 2)
+//	if err != nil {
+//		return err
+//	}
+//
+//	// In the ocppj.Handler:
+//	case changeavailability.ReqMessage:
+//		return manager.Handle(req)
+//
+//	// Once the ChangeAvailability.conf is sent, and after each
+//	// manager.TransactionStopped(connectorId):
+//	err = manager.SendStatus(ctx)
+package availability
@@ -0,0 +1,301 @@
+package availability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aasanchez/ocpp16messages/changeavailability"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// ErrUnknownConnector is returned for a connectorId above the number of
+// connectors of the Manager.
+var ErrUnknownConnector = errors.New("availability: unknown connector")
+
+// Caller sends a CALL and decodes its answer. *ocppj.Conn implements it.
+type Caller interface {
+	Call(ctx context.Context, action string, request, confirmation any) error
+}
+
+// Manager handles ChangeAvailability.req on the Charge Point side. It keeps
+// the availability of connector 0, the Charge Point as a whole, and of each
+// connector. A change to a connector with a running transaction is
+// scheduled and applied when the transaction stops; connector 0 counts as
+// running while any connector is. The requested availability is saved to
+// the Store, so that it survives a reboot, by which time every scheduled
+// change applies. A Manager is safe for concurrent use.
+type Manager struct {
+	caller Caller
+	store  Store
+
+	mu        sync.Mutex
+	current   []types.AvailabilityType
+	requested []types.AvailabilityType
+	running   []bool
+	changed   []bool
+}
+
+// New returns a Manager for a Charge Point with the given number of
+// connectors, restoring their availability from store. SendStatus sends
+// through caller. Returns an error if the store cannot be loaded or holds a
+// connectorId above connectors, wrapping ErrUnknownConnector.
+func New(caller Caller, store Store, connectors uint16) (*Manager, error) {
+	saved, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("availability: %w", err)
+	}
+
+	count := int(connectors) + 1
+	manager := &Manager{
+		caller:    caller,
+		store:     store,
+		mu:        sync.Mutex{},
+		current:   make([]types.AvailabilityType, count),
+		requested: make([]types.AvailabilityType, count),
+		running:   make([]bool, count),
+		changed:   make([]bool, count),
+	}
+
+	for connectorId := range manager.current {
+		manager.current[connectorId] = types.AvailabilityTypeOperative
+	}
+
+	for connectorId, value := range saved {
+		if int(connectorId) >= count {
+			return nil, fmt.Errorf(
+				"connectorId: %w: %d",
+				ErrUnknownConnector,
+				connectorId,
+			)
+		}
+
+		manager.current[connectorId] = value
+	}
+
+	copy(manager.requested, manager.current)
+
+	return manager, nil
+}
+
+// Availability returns the availability in effect for a connector, or for
+// the Charge Point as a whole with connectorId 0. A connector is only
+// usable when both it and connector 0 are Operative.
+func (m *Manager) Availability(connectorId uint16) types.AvailabilityType {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if int(connectorId) >= len(m.current) {
+		return ""
+	}
+
+	return m.current[connectorId]
+}
+
+// Handle answers a ChangeAvailability.req. ConnectorId 0 changes the
+// Charge Point and every connector. The status is:
+//   - Rejected for an unknown connector
+//   - Scheduled when a target connector runs a transaction and is not
+//     already of the requested type
+//   - Accepted otherwise, including when nothing changes
+//
+// Returns an error, and changes nothing, if the Store cannot save the
+// requested availability.
+func (m *Manager) Handle(
+	req changeavailability.ReqMessage,
+) (changeavailability.ConfMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	connectorId := int(req.ConnectorId.Value())
+	if connectorId >= len(m.current) {
+		return changeavailability.ConfMessage{
+			Status: types.AvailabilityStatusRejected,
+		}, nil
+	}
+
+	targets := []int{connectorId}
+	if connectorId == 0 {
+		targets = make([]int, len(m.current))
+		for target := range targets {
+			targets[target] = target
+		}
+	}
+
+	requested := make([]types.AvailabilityType, len(m.requested))
+	copy(requested, m.requested)
+
+	for _, target := range targets {
+		requested[target] = req.Type
+	}
+
+	err := m.save(requested)
+	if err != nil {
+		return changeavailability.ConfMessage{}, err
+	}
+
+	m.requested = requested
+	status := types.AvailabilityStatusAccepted
+
+	for _, target := range targets {
+		if !m.apply(target) {
+			status = types.AvailabilityStatusScheduled
+		}
+	}
+
+	return changeavailability.ConfMessage{Status: status}, nil
+}
+
+// TransactionStarted records that a transaction runs on a connector, so
+// that availability changes to it are scheduled.
+func (m *Manager) TransactionStarted(connectorId uint16) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if int(connectorId) < len(m.running) {
+		m.running[connectorId] = true
+	}
+}
+
+// TransactionStopped records that the transaction of a connector stopped
+// and applies the changes scheduled for it, and for connector 0 once no
+// transaction runs.
+func (m *Manager) TransactionStopped(connectorId uint16) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if int(connectorId) >= len(m.running) {
+		return
+	}
+
+	m.running[connectorId] = false
+	m.apply(int(connectorId))
+	m.apply(0)
+}
+
+// SendStatus sends a StatusNotification.req, Available or Unavailable, for
+// every connector whose availability changed since the last call. Call it
+// once the ChangeAvailability.conf is sent, and after TransactionStopped.
+// A Charge Point Inoperative as a whole reports Unavailable on every
+// connector. Connectors are reported in order; on an error, the connectors
+// not yet reported are kept for the next call.
+func (m *Manager) SendStatus(ctx context.Context) error {
+	for {
+		req, ok, err := m.nextStatus()
+		if err != nil || !ok {
+			return err
+		}
+
+		var conf statusnotification.ConfMessage
+
+		err = m.caller.Call(ctx, statusnotification.Action, req, &conf)
+		if err != nil {
+			m.mu.Lock()
+			m.changed[req.ConnectorId.Value()] = true
+			m.mu.Unlock()
+
+			return fmt.Errorf("availability: %w", err)
+		}
+	}
+}
+
+// nextStatus takes the StatusNotification.req of the first changed
+// connector.
+func (m *Manager) nextStatus() (statusnotification.ReqMessage, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for connectorId, changed := range m.changed {
+		if !changed {
+			continue
+		}
+
+		m.changed[connectorId] = false
+
+		status := "Available"
+		if m.current[connectorId] == types.AvailabilityTypeInoperative ||
+			m.current[0] == types.AvailabilityTypeInoperative {
+			status = "Unavailable"
+		}
+
+		req, err := statusnotification.Req(statusnotification.ReqInput{
+			ConnectorId:     connectorId,
+			ErrorCode:       types.ErrCodeNoError.String(),
+			Status:          status,
+			Info:            nil,
+			Timestamp:       nil,
+			VendorId:        nil,
+			VendorErrorCode: nil,
+		})
+		if err != nil {
+			return statusnotification.ReqMessage{}, false, fmt.Errorf(
+				"availability: %w",
+				err,
+			)
+		}
+
+		return req, true, nil
+	}
+
+	return statusnotification.ReqMessage{}, false, nil
+}
+
+// apply makes the requested availability of a connector current unless a
+// transaction runs on it, and reports whether nothing is left scheduled.
+// The caller holds m.mu.
+func (m *Manager) apply(connectorId int) bool {
+	if m.current[connectorId] == m.requested[connectorId] {
+		return true
+	}
+
+	if m.busy(connectorId) {
+		return false
+	}
+
+	m.current[connectorId] = m.requested[connectorId]
+	m.changed[connectorId] = true
+
+	if connectorId == 0 {
+		for other := 1; other < len(m.changed); other++ {
+			m.changed[other] = true
+		}
+	}
+
+	return true
+}
+
+// busy reports whether a transaction runs on a connector, or on any
+// connector for connector 0. The caller holds m.mu.
+func (m *Manager) busy(connectorId int) bool {
+	if connectorId != 0 {
+		return m.running[connectorId]
+	}
+
+	for _, running := range m.running {
+		if running {
+			return true
+		}
+	}
+
+	return false
+}
+
+// save writes the requested availability to the Store. The caller holds
+// m.mu.
+func (m *Manager) save(requested []types.AvailabilityType) error {
+	availability := make(map[uint16]types.AvailabilityType, len(requested))
+
+	for connectorId, value := range requested {
+		//nolint:gosec // At most 65535, as len(requested) <= 65536.
+		availability[uint16(connectorId)] = value
+	}
+
+	err := m.store.Save(availability)
+	if err != nil {
+		return fmt.Errorf("availability: %w", err)
+	}
+
+	return nil
+}
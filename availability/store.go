@@ -0,0 +1,158 @@
+package availability
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// Store keeps the availability of the connectors of a Charge Point across
+// reboots. Connector 0 is the Charge Point as a whole.
+type Store interface {
+	// Load returns the saved availability by connectorId. Connectors
+	// without an entry are Operative.
+	Load() (map[uint16]types.AvailabilityType, error)
+	// Save replaces the saved availability.
+	Save(availability map[uint16]types.AvailabilityType) error
+}
+
+// MemoryStore is a Store that keeps the availability in memory, for tests
+// and Charge Points that do not persist it. The zero value is ready to use.
+type MemoryStore struct {
+	mu           sync.Mutex
+	availability map[uint16]types.AvailabilityType
+}
+
+// Load returns a copy of the saved availability.
+func (s *MemoryStore) Load() (map[uint16]types.AvailabilityType, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return clone(s.availability), nil
+}
+
+// Save keeps a copy of availability.
+func (s *MemoryStore) Save(
+	availability map[uint16]types.AvailabilityType,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.availability = clone(availability)
+
+	return nil
+}
+
+// FileStore is a Store that keeps the availability in a JSON file, mapping
+// connectorIds to "Operative" or "Inoperative". A missing file means every
+// connector is Operative.
+type FileStore struct {
+	// Path is the file the availability is kept in.
+	Path string
+}
+
+// Load reads the file. Returns an error if:
+//   - The file cannot be read
+//   - The file is not a JSON object of connectorIds to availability types,
+//     wrapping types.ErrInvalidValue
+func (s FileStore) Load() (map[uint16]types.AvailabilityType, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[uint16]types.AvailabilityType{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("availability: %w", err)
+	}
+
+	var saved map[string]types.AvailabilityType
+
+	err = json.Unmarshal(data, &saved)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"availability: %s: %w: %w",
+			s.Path,
+			types.ErrInvalidValue,
+			err,
+		)
+	}
+
+	availability := make(map[uint16]types.AvailabilityType, len(saved))
+
+	for key, value := range saved {
+		connectorId, convErr := strconv.ParseUint(key, 10, 16)
+		if convErr != nil || !value.IsValid() {
+			return nil, fmt.Errorf(
+				"availability: %s: %w: connector %q is %q",
+				s.Path,
+				types.ErrInvalidValue,
+				key,
+				value,
+			)
+		}
+
+		//nolint:gosec // ParseUint bounds it to 16 bits.
+		availability[uint16(connectorId)] = value
+	}
+
+	return availability, nil
+}
+
+// Save writes the file through a temporary file in the same directory, so
+// that a crash leaves either the old or the new content.
+func (s FileStore) Save(
+	availability map[uint16]types.AvailabilityType,
+) error {
+	saved := make(map[string]types.AvailabilityType, len(availability))
+
+	for connectorId, value := range availability {
+		saved[strconv.FormatUint(uint64(connectorId), 10)] = value
+	}
+
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return fmt.Errorf("availability: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), ".availability-*")
+	if err != nil {
+		return fmt.Errorf("availability: %w", err)
+	}
+
+	_, err = tmp.Write(data)
+
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.Path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("availability: %w", err)
+	}
+
+	return nil
+}
+
+// clone copies an availability map.
+func clone(
+	availability map[uint16]types.AvailabilityType,
+) map[uint16]types.AvailabilityType {
+	copied := make(map[uint16]types.AvailabilityType, len(availability))
+	maps.Copy(copied, availability)
+
+	return copied
+}
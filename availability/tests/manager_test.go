@@ -0,0 +1,222 @@
+package availability_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/availability"
+	"github.com/aasanchez/ocpp16messages/changeavailability"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+var errStore = errors.New("store failed")
+
+// recordingCaller records the StatusNotification.req it sends as
+// "connectorId:status".
+type recordingCaller struct {
+	sent []string
+}
+
+func (r *recordingCaller) Call(
+	_ context.Context,
+	_ string,
+	request any,
+	_ any,
+) error {
+	if req, ok := request.(statusnotification.ReqMessage); ok {
+		r.sent = append(r.sent, fmt.Sprintf(
+			"%d:%s",
+			req.ConnectorId.Value(),
+			req.Status,
+		))
+	}
+
+	return nil
+}
+
+// failingStore fails to save.
+type failingStore struct{}
+
+func (failingStore) Load() (map[uint16]types.AvailabilityType, error) {
+	return map[uint16]types.AvailabilityType{}, nil
+}
+
+func (failingStore) Save(map[uint16]types.AvailabilityType) error {
+	return errStore
+}
+
+func change(
+	t *testing.T,
+	connectorId int,
+	availabilityType string,
+) changeavailability.ReqMessage {
+	t.Helper()
+
+	req, err := changeavailability.Req(changeavailability.ReqInput{
+		ConnectorId: connectorId,
+		Type:        availabilityType,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return req
+}
+
+func newManager(
+	t *testing.T,
+	store availability.Store,
+) (*availability.Manager, *recordingCaller) {
+	t.Helper()
+
+	caller := new(recordingCaller)
+
+	manager, err := availability.New(caller, store, 2)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return manager, caller
+}
+
+// handle sends a request to the manager and checks the status.
+func handle(
+	t *testing.T,
+	manager *availability.Manager,
+	req changeavailability.ReqMessage,
+	want types.AvailabilityStatus,
+) {
+	t.Helper()
+
+	conf, err := manager.Handle(req)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if conf.Status != want {
+		t.Errorf(types.ErrorMismatch, want, conf.Status)
+	}
+}
+
+// sent flushes the status notifications and returns them.
+func sent(
+	t *testing.T,
+	manager *availability.Manager,
+	caller *recordingCaller,
+) string {
+	t.Helper()
+
+	err := manager.SendStatus(context.Background())
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	got := strings.Join(caller.sent, " ")
+	caller.sent = nil
+
+	return got
+}
+
+func TestManager_Handle(t *testing.T) {
+	t.Parallel()
+
+	manager, caller := newManager(t, new(availability.MemoryStore))
+
+	handle(t, manager, change(t, 1, "Inoperative"),
+		types.AvailabilityStatusAccepted)
+
+	if got := sent(t, manager, caller); got != "1:Unavailable" {
+		t.Errorf(types.ErrorMismatch, "1:Unavailable", got)
+	}
+
+	handle(t, manager, change(t, 1, "Inoperative"),
+		types.AvailabilityStatusAccepted)
+
+	if got := sent(t, manager, caller); got != "" {
+		t.Errorf(types.ErrorMismatch, "", got)
+	}
+
+	handle(t, manager, change(t, 3, "Inoperative"),
+		types.AvailabilityStatusRejected)
+}
+
+func TestManager_Handle_scheduled(t *testing.T) {
+	t.Parallel()
+
+	manager, caller := newManager(t, new(availability.MemoryStore))
+	manager.TransactionStarted(2)
+
+	handle(t, manager, change(t, 0, "Inoperative"),
+		types.AvailabilityStatusScheduled)
+
+	if got := sent(t, manager, caller); got != "1:Unavailable" {
+		t.Errorf(types.ErrorMismatch, "1:Unavailable", got)
+	}
+
+	if manager.Availability(2) != types.AvailabilityTypeOperative {
+		t.Errorf(
+			types.ErrorMismatch,
+			types.AvailabilityTypeOperative,
+			manager.Availability(2),
+		)
+	}
+
+	manager.TransactionStopped(2)
+
+	want := "0:Unavailable 1:Unavailable 2:Unavailable"
+	if got := sent(t, manager, caller); got != want {
+		t.Errorf(types.ErrorMismatch, want, got)
+	}
+
+	if manager.Availability(0) != types.AvailabilityTypeInoperative {
+		t.Errorf(
+			types.ErrorMismatch,
+			types.AvailabilityTypeInoperative,
+			manager.Availability(0),
+		)
+	}
+}
+
+func TestManager_persists(t *testing.T) {
+	t.Parallel()
+
+	store := new(availability.MemoryStore)
+	manager, _ := newManager(t, store)
+	manager.TransactionStarted(1)
+
+	handle(t, manager, change(t, 1, "Inoperative"),
+		types.AvailabilityStatusScheduled)
+
+	rebooted, _ := newManager(t, store)
+
+	if rebooted.Availability(1) != types.AvailabilityTypeInoperative {
+		t.Errorf(
+			types.ErrorMismatch,
+			types.AvailabilityTypeInoperative,
+			rebooted.Availability(1),
+		)
+	}
+}
+
+func TestManager_Handle_storeFails(t *testing.T) {
+	t.Parallel()
+
+	manager, _ := newManager(t, failingStore{})
+
+	_, err := manager.Handle(change(t, 1, "Inoperative"))
+	if !errors.Is(err, errStore) {
+		t.Errorf(types.ErrorWrapping, err, errStore)
+	}
+
+	if manager.Availability(1) != types.AvailabilityTypeOperative {
+		t.Errorf(
+			types.ErrorMismatch,
+			types.AvailabilityTypeOperative,
+			manager.Availability(1),
+		)
+	}
+}
@@ -0,0 +1,66 @@
+package availability_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/availability"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	store := availability.FileStore{
+		Path: filepath.Join(t.TempDir(), "availability.json"),
+	}
+
+	loaded, err := store.Load()
+	if err != nil || len(loaded) != 0 {
+		t.Fatalf(types.ErrorMismatch, "empty", loaded)
+	}
+
+	err = store.Save(map[uint16]types.AvailabilityType{
+		0: types.AvailabilityTypeOperative,
+		2: types.AvailabilityTypeInoperative,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	loaded, err = store.Load()
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if loaded[2] != types.AvailabilityTypeInoperative || len(loaded) != 2 {
+		t.Errorf(types.ErrorMismatch, types.AvailabilityTypeInoperative, loaded)
+	}
+}
+
+func TestFileStore_Load_invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{
+		`not json`,
+		`{"1":"Broken"}`,
+		`{"x":"Operative"}`,
+		`{"70000":"Operative"}`,
+	}
+
+	for _, content := range tests {
+		path := filepath.Join(t.TempDir(), "availability.json")
+
+		err := os.WriteFile(path, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+
+		_, err = availability.FileStore{Path: path}.Load()
+		if !errors.Is(err, types.ErrInvalidValue) {
+			t.Errorf(types.ErrorWrapping, err, types.ErrInvalidValue)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package bootnotification
+
+import "github.com/aasanchez/ocpp16messages/internal/wire"
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		ChargePointVendor:       m.ChargePointVendor.String(),
+		ChargePointModel:        m.ChargePointModel.String(),
+		ChargePointSerialNumber: wire.OptionalString(m.ChargePointSerialNumber),
+		ChargeBoxSerialNumber:   wire.OptionalString(m.ChargeBoxSerialNumber),
+		FirmwareVersion:         wire.OptionalString(m.FirmwareVersion),
+		Iccid:                   wire.OptionalString(m.Iccid),
+		Imsi:                    wire.OptionalString(m.Imsi),
+		MeterType:               wire.OptionalString(m.MeterType),
+		MeterSerialNumber:       wire.OptionalString(m.MeterSerialNumber),
+	}
+}
+
+// WithChargePointVendor returns a copy of the message with ChargePointVendor
+// set to value. The copy is validated by Req.
+func (m ReqMessage) WithChargePointVendor(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ChargePointVendor = value
+
+	return Req(input)
+}
+
+// WithChargePointModel returns a copy of the message with ChargePointModel
+// set to value. The copy is validated by Req.
+func (m ReqMessage) WithChargePointModel(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ChargePointModel = value
+
+	return Req(input)
+}
+
+// WithChargePointSerialNumber returns a copy of the message with
+// ChargePointSerialNumber set to value. The copy is validated by Req.
+func (m ReqMessage) WithChargePointSerialNumber(
+	value string,
+) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ChargePointSerialNumber = &value
+
+	return Req(input)
+}
+
+// WithChargeBoxSerialNumber returns a copy of the message with
+// ChargeBoxSerialNumber set to value. The copy is validated by Req.
+func (m ReqMessage) WithChargeBoxSerialNumber(
+	value string,
+) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ChargeBoxSerialNumber = &value
+
+	return Req(input)
+}
+
+// WithFirmwareVersion returns a copy of the message with FirmwareVersion set
+// to value. The copy is validated by Req.
+func (m ReqMessage) WithFirmwareVersion(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.FirmwareVersion = &value
+
+	return Req(input)
+}
+
+// WithIccid returns a copy of the message with Iccid set to value. The copy
+// is validated by Req.
+func (m ReqMessage) WithIccid(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Iccid = &value
+
+	return Req(input)
+}
+
+// WithImsi returns a copy of the message with Imsi set to value. The copy is
+// validated by Req.
+func (m ReqMessage) WithImsi(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Imsi = &value
+
+	return Req(input)
+}
+
+// WithMeterType returns a copy of the message with MeterType set to value.
+// The copy is validated by Req.
+func (m ReqMessage) WithMeterType(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.MeterType = &value
+
+	return Req(input)
+}
+
+// WithMeterSerialNumber returns a copy of the message with MeterSerialNumber
+// set to value. The copy is validated by Req.
+func (m ReqMessage) WithMeterSerialNumber(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.MeterSerialNumber = &value
+
+	return Req(input)
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	return ConfInput{
+		Status:      m.Status.String(),
+		CurrentTime: m.CurrentTime.String(),
+		Interval:    int(m.Interval.Value()),
+	}
+}
+
+// WithStatus returns a copy of the message with Status set to value. The
+// copy is validated by Conf.
+func (m ConfMessage) WithStatus(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.Status = value
+
+	return Conf(input)
+}
+
+// WithCurrentTime returns a copy of the message with CurrentTime set to
+// value. The copy is validated by Conf.
+func (m ConfMessage) WithCurrentTime(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.CurrentTime = value
+
+	return Conf(input)
+}
+
+// WithInterval returns a copy of the message with Interval set to value. The
+// copy is validated by Conf.
+func (m ConfMessage) WithInterval(value int) (ConfMessage, error) {
+	input := m.ToInput()
+	input.Interval = value
+
+	return Conf(input)
+}
@@ -0,0 +1,75 @@
+package bootnotification
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of BootNotification.req.
+type reqWire struct {
+	ChargePointVendor       string  `json:"chargePointVendor"`
+	ChargePointModel        string  `json:"chargePointModel"`
+	ChargePointSerialNumber *string `json:"chargePointSerialNumber,omitempty"`
+	ChargeBoxSerialNumber   *string `json:"chargeBoxSerialNumber,omitempty"`
+	FirmwareVersion         *string `json:"firmwareVersion,omitempty"`
+	Iccid                   *string `json:"iccid,omitempty"`
+	Imsi                    *string `json:"imsi,omitempty"`
+	MeterType               *string `json:"meterType,omitempty"`
+	MeterSerialNumber       *string `json:"meterSerialNumber,omitempty"`
+}
+
+// confWire is the OCPP-J payload of BootNotification.conf.
+type confWire struct {
+	Status      string `json:"status"`
+	CurrentTime string `json:"currentTime"`
+	Interval    uint16 `json:"interval"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J BootNotification.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		ChargePointVendor:       m.ChargePointVendor.String(),
+		ChargePointModel:        m.ChargePointModel.String(),
+		ChargePointSerialNumber: wire.OptionalString(m.ChargePointSerialNumber),
+		ChargeBoxSerialNumber:   wire.OptionalString(m.ChargeBoxSerialNumber),
+		FirmwareVersion:         wire.OptionalString(m.FirmwareVersion),
+		Iccid:                   wire.OptionalString(m.Iccid),
+		Imsi:                    wire.OptionalString(m.Imsi),
+		MeterType:               wire.OptionalString(m.MeterType),
+		MeterSerialNumber:       wire.OptionalString(m.MeterSerialNumber),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J BootNotification.req payload and validates it
+// with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J BootNotification.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		Status:      m.Status.String(),
+		CurrentTime: m.CurrentTime.String(),
+		Interval:    m.Interval.Value(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J BootNotification.conf payload and validates
+// it with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package bootnotification_test
+
+import (
+	"reflect"
+	"testing"
+
+	bn "github.com/aasanchez/ocpp16messages/bootnotification"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+func fullReqInput() bn.ReqInput {
+	serial := "SN-0001"
+	firmware := "1.0.0"
+	meterType := "AC"
+
+	return bn.ReqInput{
+		ChargePointVendor:       "Vendor",
+		ChargePointModel:        "Model",
+		ChargePointSerialNumber: &serial,
+		ChargeBoxSerialNumber:   nil,
+		FirmwareVersion:         &firmware,
+		Iccid:                   nil,
+		Imsi:                    nil,
+		MeterType:               &meterType,
+		MeterSerialNumber:       nil,
+	}
+}
+
+func TestReqMessage_ToInput(t *testing.T) {
+	t.Parallel()
+
+	input := fullReqInput()
+
+	req, err := bn.Req(input)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	got := req.ToInput()
+	if !reflect.DeepEqual(got, input) {
+		t.Errorf(types.ErrorMismatch, input, got)
+	}
+
+	rebuilt, err := bn.Req(got)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if !reflect.DeepEqual(rebuilt, req) {
+		t.Errorf(types.ErrorMismatch, req, rebuilt)
+	}
+}
+
+func TestReqMessage_ToInput_SharesNoMemory(t *testing.T) {
+	t.Parallel()
+
+	req, err := bn.Req(fullReqInput())
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	input := req.ToInput()
+	*input.FirmwareVersion = "2.0.0"
+
+	if req.FirmwareVersion.String() != "1.0.0" {
+		t.Errorf(types.ErrorMismatchValue, "1.0.0", req.FirmwareVersion)
+	}
+}
+
+func TestReqMessage_WithFirmwareVersion(t *testing.T) {
+	t.Parallel()
+
+	req, err := bn.Req(fullReqInput())
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	updated, err := req.WithFirmwareVersion("2.0.0")
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if updated.FirmwareVersion.String() != "2.0.0" {
+		t.Errorf(types.ErrorMismatchValue, "2.0.0", updated.FirmwareVersion)
+	}
+
+	if req.FirmwareVersion.String() != "1.0.0" {
+		t.Errorf(types.ErrorMismatchValue, "1.0.0", req.FirmwareVersion)
+	}
+
+	if updated.ChargePointVendor != req.ChargePointVendor {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			req.ChargePointVendor,
+			updated.ChargePointVendor,
+		)
+	}
+}
+
+func TestReqMessage_WithChargePointVendor_Invalid(t *testing.T) {
+	t.Parallel()
+
+	req, err := bn.Req(fullReqInput())
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	_, err = req.WithChargePointVendor("")
+	if err == nil {
+		t.Errorf(types.ErrorWantNonNil, "empty chargePointVendor")
+	}
+
+	if req.ChargePointVendor.String() != "Vendor" {
+		t.Errorf(types.ErrorMismatchValue, "Vendor", req.ChargePointVendor)
+	}
+}
+
+func TestConfMessage_WithInterval(t *testing.T) {
+	t.Parallel()
+
+	conf, err := bn.Conf(bn.ConfInput{
+		Status:      "Accepted",
+		CurrentTime: "2025-01-02T15:00:00Z",
+		Interval:    300,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	updated, err := conf.WithInterval(60)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if updated.Interval.Value() != 60 {
+		t.Errorf(types.ErrorMismatchValue, 60, updated.Interval.Value())
+	}
+
+	if updated.Status != conf.Status ||
+		updated.CurrentTime != conf.CurrentTime {
+		t.Errorf(types.ErrorMismatch, conf, updated)
+	}
+
+	_, err = conf.WithInterval(-1)
+	if err == nil {
+		t.Errorf(types.ErrorWantNonNil, "negative interval")
+	}
+}
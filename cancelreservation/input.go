@@ -0,0 +1,37 @@
+package cancelreservation
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		ReservationId: int(m.ReservationId.Value()),
+	}
+}
+
+// WithReservationId returns a copy of the message with ReservationId set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithReservationId(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ReservationId = value
+
+	return Req(input)
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	return ConfInput{
+		Status: m.Status.String(),
+	}
+}
+
+// WithStatus returns a copy of the message with Status set to value. The
+// copy is validated by Conf.
+func (m ConfMessage) WithStatus(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.Status = value
+
+	return Conf(input)
+}
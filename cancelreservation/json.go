@@ -0,0 +1,55 @@
+package cancelreservation
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of CancelReservation.req.
+type reqWire struct {
+	ReservationId uint16 `json:"reservationId"`
+}
+
+// confWire is the OCPP-J payload of CancelReservation.conf.
+type confWire struct {
+	Status string `json:"status"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J CancelReservation.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		ReservationId: m.ReservationId.Value(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J CancelReservation.req payload and validates
+// it with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J CancelReservation.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		Status: m.Status.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J CancelReservation.conf payload and validates
+// it with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
@@ -0,0 +1,294 @@
+package canonical
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/schema"
+)
+
+// hexDigits are the digits of \u escapes.
+const hexDigits = "0123456789abcdef"
+
+// Exponent bounds of the ECMAScript number serialization: numbers outside
+// [minPlain, maxPlain) are written in exponent form.
+const (
+	minPlain = 1e-6
+	maxPlain = 1e21
+)
+
+// ErrNotMessage is returned for a value that is not the ReqMessage or
+// ConfMessage of an OCPP 1.6 action.
+var ErrNotMessage = errors.New("canonical: not an OCPP message")
+
+// definitions caches the parsed schemas by action and kind.
+var definitions sync.Map
+
+// definitionKey identifies a cached schema.
+type definitionKey struct {
+	action string
+	kind   schema.Kind
+}
+
+// Marshal returns the canonical JSON encoding of a message, e.g. an
+// authorize.ReqMessage. The encoding follows RFC 8785 (JCS): object keys are
+// sorted, insignificant whitespace is dropped, strings are escaped minimally
+// and numbers are written as ECMAScript does, so 16.0 becomes 16. DateTime
+// values are converted to UTC with the fraction trimmed of trailing zeros,
+// so 2025-01-02T15:00:00.000Z becomes 2025-01-02T15:00:00Z, and CiString
+// values, IdToken included, are upper-cased, as the specification compares
+// them case-insensitively. Lists keep their order, which carries meaning in
+// OCPP.
+func Marshal(msg any) ([]byte, error) {
+	action, kind, ok := schema.Identify(msg)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrNotMessage, msg)
+	}
+
+	def, err := load(action, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("canonical: %s: %w", action, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+
+	err = decoder.Decode(&value)
+	if err != nil {
+		return nil, fmt.Errorf("canonical: %s: %w", action, err)
+	}
+
+	return appendValue(nil, value, def)
+}
+
+// Hash returns the SHA-256 digest of the canonical encoding of a message.
+// Semantically equal messages hash identically, whatever the property
+// order, number formatting, DateTime precision or CiString case of the
+// payloads they were decoded from: messages ocpp16messages.Equal reports
+// equal have the same digest.
+func Hash(msg any) ([sha256.Size]byte, error) {
+	data, err := Marshal(msg)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+
+	return sha256.Sum256(data), nil
+}
+
+// load returns the official schema of an action's payload.
+func load(action string, kind schema.Kind) (*schema.Definition, error) {
+	key := definitionKey{action: action, kind: kind}
+
+	cached, ok := definitions.Load(key)
+	if ok {
+		//nolint:forcetypeassert // Only *schema.Definition is stored.
+		return cached.(*schema.Definition), nil
+	}
+
+	data, err := schema.Schema(action, kind)
+	if err != nil {
+		return nil, fmt.Errorf("canonical: %w", err)
+	}
+
+	var def schema.Definition
+
+	err = json.Unmarshal(data, &def)
+	if err != nil {
+		return nil, fmt.Errorf("canonical: %s %s: %w", action, kind, err)
+	}
+
+	definitions.Store(key, &def)
+
+	return &def, nil
+}
+
+// appendValue appends the canonical form of a JSON value decoded with
+// UseNumber. def, which may be nil, identifies the DateTime and CiString
+// strings.
+func appendValue(
+	dst []byte,
+	value any,
+	def *schema.Definition,
+) ([]byte, error) {
+	switch typed := value.(type) {
+	case map[string]any:
+		return appendObject(dst, typed, def)
+	case []any:
+		var (
+			items *schema.Definition
+			err   error
+		)
+
+		if def != nil {
+			items = def.Items
+		}
+
+		dst = append(dst, '[')
+
+		for i, item := range typed {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+
+			dst, err = appendValue(dst, item, items)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return append(dst, ']'), nil
+	case string:
+		switch {
+		case def == nil:
+		case def.Format == "date-time":
+			typed = normalizeDateTime(typed)
+		case isCiString(def):
+			typed = strings.ToUpper(typed)
+		}
+
+		return appendString(dst, typed), nil
+	case json.Number:
+		return appendNumber(dst, typed)
+	case bool:
+		return strconv.AppendBool(dst, typed), nil
+	default:
+		return append(dst, "null"...), nil
+	}
+}
+
+// isCiString reports whether a string property is a CiString, which the
+// official schemas give a maxLength and enumerations an enum.
+func isCiString(def *schema.Definition) bool {
+	return def.MaxLength != nil && def.Enum == nil
+}
+
+// appendObject appends an object with its keys sorted. The keys of OCPP
+// payloads are ASCII, so byte order is the UTF-16 order RFC 8785 requires.
+func appendObject(
+	dst []byte,
+	object map[string]any,
+	def *schema.Definition,
+) ([]byte, error) {
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	dst = append(dst, '{')
+
+	for i, name := range names {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+
+		var property *schema.Definition
+		if def != nil {
+			property = def.Properties[name]
+		}
+
+		var err error
+
+		dst = append(appendString(dst, name), ':')
+
+		dst, err = appendValue(dst, object[name], property)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return append(dst, '}'), nil
+}
+
+// appendString appends s as a JSON string escaped as RFC 8785 requires: only
+// quotation marks, backslashes and control characters, the latter with
+// their short escapes where JSON has one.
+func appendString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+
+	for i := range len(s) {
+		char := s[i]
+
+		switch {
+		case char == '"' || char == '\\':
+			dst = append(dst, '\\', char)
+		case char >= ' ':
+			dst = append(dst, char)
+		case char == '\b':
+			dst = append(dst, '\\', 'b')
+		case char == '\f':
+			dst = append(dst, '\\', 'f')
+		case char == '\n':
+			dst = append(dst, '\\', 'n')
+		case char == '\r':
+			dst = append(dst, '\\', 'r')
+		case char == '\t':
+			dst = append(dst, '\\', 't')
+		default:
+			dst = append(
+				dst,
+				'\\', 'u', '0', '0',
+				hexDigits[char>>4], hexDigits[char&0xF],
+			)
+		}
+	}
+
+	return append(dst, '"')
+}
+
+// appendNumber appends a number as ECMAScript's Number.prototype.toString
+// writes it: the shortest decimal that round-trips, without a fraction for
+// integral values and in exponent form outside [1e-6, 1e21).
+func appendNumber(dst []byte, number json.Number) ([]byte, error) {
+	value, err := number.Float64()
+	if err != nil || math.IsInf(value, 0) || math.IsNaN(value) {
+		return nil, fmt.Errorf("canonical: number %s", number)
+	}
+
+	if value == 0 {
+		return append(dst, '0'), nil
+	}
+
+	abs := math.Abs(value)
+	if abs >= minPlain && abs < maxPlain {
+		return strconv.AppendFloat(dst, value, 'f', -1, 64), nil
+	}
+
+	mantissa, exponent, _ := strings.Cut(
+		strconv.FormatFloat(value, 'e', -1, 64),
+		"e",
+	)
+
+	dst = append(dst, mantissa...)
+	dst = append(dst, 'e', exponent[0])
+
+	return append(dst, strings.TrimLeft(exponent[1:], "0")...), nil
+}
+
+// normalizeDateTime converts a date-time to UTC in RFC 3339 form with the
+// fraction trimmed of trailing zeros. Unparsable values are kept.
+func normalizeDateTime(value string) string {
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return value
+	}
+
+	return parsed.UTC().Format(time.RFC3339Nano)
+}
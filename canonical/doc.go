@@ -0,0 +1,21 @@
+// Package canonical provides a deterministic encoding of the messages of
+// this module, for audit trails and deduplication.
+//
+// Marshal writes the canonical JSON of a ReqMessage or ConfMessage following
+// RFC 8785, the JSON Canonicalization Scheme: sorted keys, no insignificant
+// whitespace, minimal string escaping and ECMAScript number formatting. In
+// addition DateTime values are normalized to UTC with trailing fraction
+// zeros trimmed, and CiString values, compared case-insensitively by the
+// specification, are upper-cased; both are identified by the official
+// OCPP 1.6 schemas.
+//
+// Hash returns the SHA-256 digest of that encoding. Two messages decoded
+// from payloads that differ only in property order, number formatting,
+// DateTime precision or CiString case hash identically, so Hash agrees with
+// ocpp16messages.Equal:
+//
+//	sum, err := canonical.Hash(req)
+//	if err != nil {
+//		// err wraps ErrNotMessage for values that are not messages
+//	}
+package canonical
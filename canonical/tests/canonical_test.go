@@ -0,0 +1,234 @@
+package canonical_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages"
+	"github.com/aasanchez/ocpp16messages/canonical"
+	"github.com/aasanchez/ocpp16messages/getcompositeschedule"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/metervalues"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// decode decodes a payload, failing the test on error.
+func decode(t *testing.T, action, payload string, confirmation bool) any {
+	t.Helper()
+
+	var (
+		msg any
+		err error
+	)
+
+	if confirmation {
+		msg, err = ocppj.DecodeConfirmation(action, []byte(payload))
+	} else {
+		msg, err = ocppj.DecodeRequest(action, []byte(payload))
+	}
+
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return msg
+}
+
+func TestMarshal_SortsKeysAndNormalizes(t *testing.T) {
+	t.Parallel()
+
+	msg := decode(
+		t,
+		ocppj.ActionGetCompositeSchedule,
+		`{"status":"Accepted","connectorId":1,`+
+			`"scheduleStart":"2025-01-02T15:00:00.000Z",`+
+			`"chargingSchedule":{"chargingSchedulePeriod":`+
+			`[{"startPeriod":0,"limit":16.0}],"chargingRateUnit":"A"}}`,
+		true,
+	)
+	if _, ok := msg.(getcompositeschedule.ConfMessage); !ok {
+		t.Fatalf(
+			types.ErrorMismatchValue,
+			"getcompositeschedule.ConfMessage",
+			msg,
+		)
+	}
+
+	got, err := canonical.Marshal(msg)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	want := `{"chargingSchedule":{"chargingRateUnit":"A",` +
+		`"chargingSchedulePeriod":[{"limit":16,"startPeriod":0}]},` +
+		`"connectorId":1,"scheduleStart":"2025-01-02T15:00:00Z",` +
+		`"status":"Accepted"}`
+
+	if string(got) != want {
+		t.Errorf(types.ErrorMismatch, want, string(got))
+	}
+}
+
+func TestHash_IgnoresPayloadOrder(t *testing.T) {
+	t.Parallel()
+
+	first := decode(
+		t,
+		ocppj.ActionMeterValues,
+		`{"connectorId":1,"transactionId":42,"meterValue":[`+
+			`{"timestamp":"2025-01-02T15:00:00Z","sampledValue":[`+
+			`{"value":"1234","unit":"Wh","measurand":`+
+			`"Energy.Active.Import.Register"}]}]}`,
+		false,
+	)
+	second := decode(
+		t,
+		ocppj.ActionMeterValues,
+		`{"meterValue":[{"sampledValue":[{"measurand":`+
+			`"Energy.Active.Import.Register","unit":"Wh","value":"1234"}],`+
+			`"timestamp":"2025-01-02T15:00:00.000Z"}],`+
+			`"transactionId":42,"connectorId":1}`,
+		false,
+	)
+
+	if _, ok := first.(metervalues.ReqMessage); !ok {
+		t.Fatalf(types.ErrorMismatchValue, "metervalues.ReqMessage", first)
+	}
+
+	firstSum, err := canonical.Hash(first)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	secondSum, err := canonical.Hash(second)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if firstSum != secondSum {
+		t.Errorf(types.ErrorMismatch, firstSum, secondSum)
+	}
+}
+
+func TestHash_DiffersOnValue(t *testing.T) {
+	t.Parallel()
+
+	first, err := heartbeat.Conf(heartbeat.ConfInput{
+		CurrentTime: "2025-01-02T15:00:00Z",
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	second, err := heartbeat.Conf(heartbeat.ConfInput{
+		CurrentTime: "2025-01-02T15:00:01Z",
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	firstSum, _ := canonical.Hash(first)
+	secondSum, _ := canonical.Hash(second)
+
+	if firstSum == secondSum {
+		t.Errorf(types.ErrorMismatchValue, "different digests", firstSum)
+	}
+}
+
+func TestHash_AgreesWithEqual(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		action  string
+		first   string
+		second  string
+		confirm bool
+	}{
+		{
+			"idTag case",
+			ocppj.ActionAuthorize,
+			`{"idTag":"RFID-abc123"}`,
+			`{"idTag":"rfid-ABC123"}`,
+			false,
+		},
+		{
+			"idTag value",
+			ocppj.ActionAuthorize,
+			`{"idTag":"RFID-ABC123"}`,
+			`{"idTag":"RFID-ABC124"}`,
+			false,
+		},
+		{
+			"parentIdTag case and expiryDate precision",
+			ocppj.ActionAuthorize,
+			`{"idTagInfo":{"status":"Accepted","parentIdTag":"group",` +
+				`"expiryDate":"2025-01-02T15:00:00Z"}}`,
+			`{"idTagInfo":{"expiryDate":"2025-01-02T15:00:00.000Z",` +
+				`"parentIdTag":"GROUP","status":"Accepted"}}`,
+			true,
+		},
+		{
+			"status",
+			ocppj.ActionAuthorize,
+			`{"idTagInfo":{"status":"Accepted"}}`,
+			`{"idTagInfo":{"status":"Blocked"}}`,
+			true,
+		},
+		{
+			"vendor case",
+			ocppj.ActionBootNotification,
+			`{"chargePointVendor":"Acme","chargePointModel":"X1"}`,
+			`{"chargePointVendor":"ACME","chargePointModel":"x1"}`,
+			false,
+		},
+		{
+			"configuration key case",
+			ocppj.ActionGetConfiguration,
+			`{"key":["HeartbeatInterval"]}`,
+			`{"key":["heartbeatinterval"]}`,
+			false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			first := decode(t, tc.action, tc.first, tc.confirm)
+			second := decode(t, tc.action, tc.second, tc.confirm)
+
+			firstMsg, _ := first.(ocpp16messages.Message)
+			secondMsg, _ := second.(ocpp16messages.Message)
+			equal := ocpp16messages.Equal(firstMsg, secondMsg)
+
+			firstSum, err := canonical.Hash(first)
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+
+			secondSum, err := canonical.Hash(second)
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+
+			if equal != (firstSum == secondSum) {
+				t.Errorf(
+					"Equal = %t, digests equal = %t",
+					equal,
+					firstSum == secondSum,
+				)
+			}
+		})
+	}
+}
+
+func TestMarshal_NotMessage(t *testing.T) {
+	t.Parallel()
+
+	_, err := canonical.Marshal(map[string]any{"idTag": "A"})
+	if !errors.Is(err, canonical.ErrNotMessage) {
+		t.Errorf(types.ErrorWrapping, err, canonical.ErrNotMessage)
+	}
+}
@@ -0,0 +1,229 @@
+package capabilities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aasanchez/ocpp16messages"
+	"github.com/aasanchez/ocpp16messages/getconfiguration"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// Configuration keys Capabilities is read from, as named by the OCPP 1.6
+// specification, section 9.
+const (
+	KeySupportedFeatureProfiles                = "SupportedFeatureProfiles"
+	KeyNumberOfConnectors                      = "NumberOfConnectors"
+	KeyChargingScheduleAllowedChargingRateUnit = "ChargingScheduleAllowedChargingRateUnit"
+	KeyMaxChargingProfilesInstalled            = "MaxChargingProfilesInstalled"
+	KeyChargeProfileMaxStackLevel              = "ChargeProfileMaxStackLevel"
+)
+
+// Values of ChargingScheduleAllowedChargingRateUnit.
+const (
+	rateUnitCurrent = "Current"
+	rateUnitPower   = "Power"
+)
+
+// Caller sends a CALL and decodes its answer. *ocppj.Conn and *soap.Client
+// implement it.
+type Caller interface {
+	Call(ctx context.Context, action string, request, confirmation any) error
+}
+
+// Capabilities describes what a Charge Point supports, as reported by its
+// configuration keys. A nil list or pointer means the key is unknown, and
+// the checks that depend on it are skipped.
+type Capabilities struct {
+	// SupportedFeatureProfiles lists the feature profiles the Charge Point
+	// implements.
+	SupportedFeatureProfiles []ocpp16messages.FeatureProfile
+	// NumberOfConnectors is the number of physical connectors.
+	NumberOfConnectors *int
+	// ChargingScheduleAllowedChargingRateUnit lists the units a charging
+	// schedule may use.
+	ChargingScheduleAllowedChargingRateUnit []types.ChargingRateUnit
+	// MaxChargingProfilesInstalled is the number of charging profiles the
+	// Charge Point can hold.
+	MaxChargingProfilesInstalled *int
+	// ChargeProfileMaxStackLevel is the highest stackLevel the Charge Point
+	// accepts.
+	ChargeProfileMaxStackLevel *int
+}
+
+// Keys returns the configuration keys Capabilities is read from, for a
+// GetConfiguration.req.
+func Keys() []string {
+	return []string{
+		KeySupportedFeatureProfiles,
+		KeyNumberOfConnectors,
+		KeyChargingScheduleAllowedChargingRateUnit,
+		KeyMaxChargingProfilesInstalled,
+		KeyChargeProfileMaxStackLevel,
+	}
+}
+
+// Discover asks a Charge Point for the keys of Capabilities with a
+// GetConfiguration.req and returns what it reports.
+func Discover(ctx context.Context, caller Caller) (Capabilities, error) {
+	req, err := getconfiguration.Req(getconfiguration.ReqInput{Key: Keys()})
+	if err != nil {
+		return Capabilities{}, fmt.Errorf("capabilities: %w", err)
+	}
+
+	var conf getconfiguration.ConfMessage
+
+	err = caller.Call(ctx, getconfiguration.Action, req, &conf)
+	if err != nil {
+		return Capabilities{}, fmt.Errorf("capabilities: %w", err)
+	}
+
+	return FromConfiguration(conf)
+}
+
+// FromConfiguration reads Capabilities from a GetConfiguration.conf. Keys
+// are matched case-insensitively; keys that are missing or have no value
+// are left unknown. Comma-separated lists may contain spaces, and names
+// outside the specification, such as vendor feature profiles, are ignored;
+// a list naming none is left unknown.
+// All malformed values are accumulated and returned together, each wrapping
+// types.ErrInvalidValue. Returns an error if:
+//   - NumberOfConnectors, MaxChargingProfilesInstalled or
+//     ChargeProfileMaxStackLevel is not a non-negative integer
+func FromConfiguration(
+	conf getconfiguration.ConfMessage,
+) (Capabilities, error) {
+	var (
+		caps Capabilities
+		errs []error
+	)
+
+	for _, keyValue := range conf.ConfigurationKey {
+		value := keyValue.Value()
+		if value == nil {
+			continue
+		}
+
+		key := keyValue.Key().String()
+
+		switch {
+		case strings.EqualFold(key, KeySupportedFeatureProfiles):
+			caps.SupportedFeatureProfiles = featureProfiles(value.String())
+		case strings.EqualFold(key, KeyNumberOfConnectors):
+			caps.NumberOfConnectors, errs = count(key, value.String(), errs)
+		case strings.EqualFold(key, KeyChargingScheduleAllowedChargingRateUnit):
+			caps.ChargingScheduleAllowedChargingRateUnit = rateUnits(
+				value.String(),
+			)
+		case strings.EqualFold(key, KeyMaxChargingProfilesInstalled):
+			caps.MaxChargingProfilesInstalled, errs = count(
+				key,
+				value.String(),
+				errs,
+			)
+		case strings.EqualFold(key, KeyChargeProfileMaxStackLevel):
+			caps.ChargeProfileMaxStackLevel, errs = count(
+				key,
+				value.String(),
+				errs,
+			)
+		}
+	}
+
+	if errs != nil {
+		return Capabilities{}, errors.Join(errs...)
+	}
+
+	return caps, nil
+}
+
+// Supports reports whether the Charge Point implements a feature profile.
+// It returns true when SupportedFeatureProfiles is unknown, and always for
+// Core, which every Charge Point implements.
+func (c Capabilities) Supports(profile ocpp16messages.FeatureProfile) bool {
+	if c.SupportedFeatureProfiles == nil || profile == ocpp16messages.Core {
+		return true
+	}
+
+	return slices.Contains(c.SupportedFeatureProfiles, profile)
+}
+
+// featureProfiles parses the value of SupportedFeatureProfiles. It returns
+// nil, unknown, when the value names no profile of the specification, as an
+// empty or vendor-only value says nothing about what the Charge Point
+// supports.
+func featureProfiles(value string) []ocpp16messages.FeatureProfile {
+	known := []ocpp16messages.FeatureProfile{
+		ocpp16messages.Core,
+		ocpp16messages.FirmwareManagement,
+		ocpp16messages.LocalAuthListManagement,
+		ocpp16messages.Reservation,
+		ocpp16messages.SmartCharging,
+		ocpp16messages.RemoteTrigger,
+	}
+
+	var profiles []ocpp16messages.FeatureProfile
+
+	for _, name := range list(value) {
+		for _, profile := range known {
+			if strings.EqualFold(name, string(profile)) {
+				profiles = append(profiles, profile)
+			}
+		}
+	}
+
+	return profiles
+}
+
+// rateUnits parses the value of ChargingScheduleAllowedChargingRateUnit,
+// Current and Power, or the A and W of the charging schedules. It returns
+// nil, unknown, when the value names no unit.
+func rateUnits(value string) []types.ChargingRateUnit {
+	var units []types.ChargingRateUnit
+
+	for _, name := range list(value) {
+		switch {
+		case strings.EqualFold(name, rateUnitCurrent),
+			strings.EqualFold(name, types.ChargingRateUnitAmperes.String()):
+			units = append(units, types.ChargingRateUnitAmperes)
+		case strings.EqualFold(name, rateUnitPower),
+			strings.EqualFold(name, types.ChargingRateUnitWatts.String()):
+			units = append(units, types.ChargingRateUnitWatts)
+		}
+	}
+
+	return units
+}
+
+// list splits a comma-separated value and trims its items.
+func list(value string) []string {
+	var items []string
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// count parses a non-negative integer value.
+func count(key, value string, errs []error) (*int, []error) {
+	number, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || number < 0 {
+		return nil, append(errs, fmt.Errorf(
+			"%s: %w: %q is not a non-negative integer",
+			key,
+			types.ErrInvalidValue,
+			value,
+		))
+	}
+
+	return &number, errs
+}
@@ -0,0 +1,121 @@
+package capabilities
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aasanchez/ocpp16messages"
+	"github.com/aasanchez/ocpp16messages/changeavailability"
+	"github.com/aasanchez/ocpp16messages/clearchargingprofile"
+	"github.com/aasanchez/ocpp16messages/getcompositeschedule"
+	"github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/reservenow"
+	"github.com/aasanchez/ocpp16messages/setchargingprofile"
+	"github.com/aasanchez/ocpp16messages/triggermessage"
+	"github.com/aasanchez/ocpp16messages/unlockconnector"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+var (
+	// ErrProfileNotSupported is returned for a request whose feature profile
+	// is missing from SupportedFeatureProfiles.
+	ErrProfileNotSupported = errors.New(
+		"capabilities: feature profile not supported",
+	)
+	// ErrUnknownConnector is returned for a connectorId above
+	// NumberOfConnectors.
+	ErrUnknownConnector = errors.New("capabilities: unknown connector")
+	// ErrProfilesFull is returned for a SetChargingProfile.req that would
+	// exceed MaxChargingProfilesInstalled.
+	ErrProfilesFull = errors.New(
+		"capabilities: MaxChargingProfilesInstalled reached",
+	)
+)
+
+// Check reports whether a Charge Point with these capabilities can handle a
+// request the Central System is about to send. Checks whose configuration
+// key is unknown are skipped. All violations are accumulated and returned
+// together. Returns an error if:
+//   - The feature profile of the request is not supported, wrapping
+//     ErrProfileNotSupported
+//   - The connectorId of the request exceeds NumberOfConnectors, wrapping
+//     ErrUnknownConnector
+//   - A SetChargingProfile.req fails setchargingprofile.Validate against
+//     ChargeProfileMaxStackLevel and
+//     ChargingScheduleAllowedChargingRateUnit, wrapping
+//     types.ErrInvalidValue
+func (c Capabilities) Check(request ocpp16messages.Message) error {
+	var errs []error
+
+	if profile := request.FeatureProfile(); !c.Supports(profile) {
+		errs = append(errs, fmt.Errorf(
+			"%w: %s requires %s",
+			ErrProfileNotSupported,
+			request.Action(),
+			profile,
+		))
+	}
+
+	connectorId, ok := connectorOf(request)
+	if ok && c.NumberOfConnectors != nil &&
+		int(connectorId) > *c.NumberOfConnectors {
+		errs = append(errs, fmt.Errorf(
+			"connectorId: %w: %d exceeds NumberOfConnectors %d",
+			ErrUnknownConnector,
+			connectorId,
+			*c.NumberOfConnectors,
+		))
+	}
+
+	if req, isProfile := request.(setchargingprofile.ReqMessage); isProfile {
+		err := setchargingprofile.Validate(req, c.validationConfig())
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validationConfig returns the setchargingprofile.ValidationConfig of the
+// capabilities. An unknown ChargeProfileMaxStackLevel allows any stackLevel.
+func (c Capabilities) validationConfig() setchargingprofile.ValidationConfig {
+	return setchargingprofile.ValidationConfig{
+		ChargeProfileMaxStackLevel: c.ChargeProfileMaxStackLevel,
+		ChargingScheduleAllowedChargingRateUnit: c.
+			ChargingScheduleAllowedChargingRateUnit,
+	}
+}
+
+// connectorOf returns the connectorId a request addresses, if any.
+func connectorOf(request ocpp16messages.Message) (uint16, bool) {
+	switch req := request.(type) {
+	case changeavailability.ReqMessage:
+		return req.ConnectorId.Value(), true
+	case getcompositeschedule.ReqMessage:
+		return req.ConnectorId.Value(), true
+	case reservenow.ReqMessage:
+		return req.ConnectorId.Value(), true
+	case setchargingprofile.ReqMessage:
+		return req.ConnectorId.Value(), true
+	case unlockconnector.ReqMessage:
+		return req.ConnectorId.Value(), true
+	case clearchargingprofile.ReqMessage:
+		return optional(req.ConnectorId)
+	case remotestarttransaction.ReqMessage:
+		return optional(req.ConnectorId)
+	case triggermessage.ReqMessage:
+		return optional(req.ConnectorId)
+	default:
+		return 0, false
+	}
+}
+
+// optional returns the value of an optional connectorId.
+func optional(connectorId *types.Integer) (uint16, bool) {
+	if connectorId == nil {
+		return 0, false
+	}
+
+	return connectorId.Value(), true
+}
@@ -0,0 +1,29 @@
+// Package capabilities lets a Central System check a request against what
+// the target Charge Point supports before sending it, instead of learning
+// from a NotSupported CALLERROR in production.
+//
+// Capabilities models the configuration keys that bound what a Charge Point
+// accepts: SupportedFeatureProfiles, NumberOfConnectors,
+// ChargingScheduleAllowedChargingRateUnit, MaxChargingProfilesInstalled and
+// ChargeProfileMaxStackLevel. Discover reads them with a
+// GetConfiguration.req, and FromConfiguration parses a GetConfiguration.conf
+// received otherwise.
+//
+// Capabilities.Check rejects, for example, a reservenow.ReqMessage for a
+// Charge Point without the Reservation profile, or a
+// setchargingprofile.ReqMessage whose connectorId exceeds NumberOfConnectors:
+//
+//	caps, err := capabilities.Discover(ctx, conn)
+//	if err != nil {
+//		return err
+//	}
+//
+//	err = caps.Check(req)
+//	if errors.Is(err, capabilities.ErrProfileNotSupported) {
+//		// do not send
+//	}
+//
+// Gate wraps an *ocppj.Conn or a *soap.Client and runs the checks on every
+// Call, either rejecting the request or reporting the problem and sending it
+// anyway.
+package capabilities
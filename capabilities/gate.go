@@ -0,0 +1,200 @@
+package capabilities
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aasanchez/ocpp16messages"
+	"github.com/aasanchez/ocpp16messages/clearchargingprofile"
+	"github.com/aasanchez/ocpp16messages/setchargingprofile"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// installedProfile is a charging profile a Gate saw the Charge Point accept.
+type installedProfile struct {
+	connectorId uint16
+	purpose     types.ChargingProfilePurposeType
+	stackLevel  uint16
+}
+
+// Gate is a Caller that checks requests against the Capabilities of one
+// Charge Point before sending them through another Caller. It also counts
+// the ChargePointMaxProfile and TxDefaultProfile charging profiles the Charge
+// Point accepts and clears, to enforce MaxChargingProfilesInstalled; a
+// TxProfile ends with its transaction and is not counted. A Gate is safe for
+// concurrent use.
+type Gate struct {
+	next Caller
+	warn func(action string, err error)
+
+	mu        sync.Mutex
+	caps      Capabilities
+	installed map[uint16]installedProfile
+}
+
+// NewGate returns a Gate sending through next. When warn is nil, a request
+// that fails the checks is rejected: Call returns the error without sending
+// it. Otherwise warn is called with the error and the request is sent
+// anyway, which suits rolling the checks out on an existing fleet.
+func NewGate(
+	next Caller,
+	capabilities Capabilities,
+	warn func(action string, err error),
+) *Gate {
+	return &Gate{
+		next:      next,
+		warn:      warn,
+		mu:        sync.Mutex{},
+		caps:      capabilities,
+		installed: map[uint16]installedProfile{},
+	}
+}
+
+// Capabilities returns the capabilities requests are checked against.
+func (g *Gate) Capabilities() Capabilities {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.caps
+}
+
+// SetCapabilities replaces the capabilities requests are checked against,
+// e.g. with the result of Discover once the Charge Point has booted.
+func (g *Gate) SetCapabilities(capabilities Capabilities) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.caps = capabilities
+}
+
+// Call checks request with Capabilities.Check and against
+// MaxChargingProfilesInstalled, then sends it through the next Caller.
+// Requests that are not messages of this module are sent unchecked.
+func (g *Gate) Call(
+	ctx context.Context,
+	action string,
+	request any,
+	confirmation any,
+) error {
+	if msg, ok := request.(ocpp16messages.Message); ok {
+		err := g.check(msg)
+		if err != nil {
+			if g.warn == nil {
+				return fmt.Errorf("%s: %w", action, err)
+			}
+
+			g.warn(action, err)
+		}
+	}
+
+	err := g.next.Call(ctx, action, request, confirmation)
+	if err != nil {
+		return err //nolint:wrapcheck // Returned as the next Caller made it.
+	}
+
+	g.record(request, confirmation)
+
+	return nil
+}
+
+// check runs the checks of a request.
+func (g *Gate) check(request ocpp16messages.Message) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	err := g.caps.Check(request)
+
+	req, ok := request.(setchargingprofile.ReqMessage)
+	if !ok || err != nil || g.caps.MaxChargingProfilesInstalled == nil {
+		return err
+	}
+
+	profile := req.CsChargingProfiles
+	if profile.ChargingProfilePurpose() == types.TxProfile {
+		return nil
+	}
+
+	id := profile.ChargingProfileId().Value()
+	entry := installedFrom(req)
+	installed := 0
+
+	for otherId, other := range g.installed {
+		if otherId != id && other != entry {
+			installed++
+		}
+	}
+
+	if installed >= *g.caps.MaxChargingProfilesInstalled {
+		return fmt.Errorf(
+			"%w: %d profiles installed",
+			ErrProfilesFull,
+			installed,
+		)
+	}
+
+	return nil
+}
+
+// record updates the installed profiles after an accepted
+// SetChargingProfile.req or ClearChargingProfile.req.
+func (g *Gate) record(request, confirmation any) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch req := request.(type) {
+	case setchargingprofile.ReqMessage:
+		conf, ok := confirmation.(*setchargingprofile.ConfMessage)
+		if !ok || conf.Status != types.ChargingProfileStatusAccepted ||
+			req.CsChargingProfiles.ChargingProfilePurpose() == types.TxProfile {
+			return
+		}
+
+		entry := installedFrom(req)
+		for id, other := range g.installed {
+			if other == entry {
+				delete(g.installed, id)
+			}
+		}
+
+		g.installed[req.CsChargingProfiles.ChargingProfileId().Value()] = entry
+	case clearchargingprofile.ReqMessage:
+		conf, ok := confirmation.(*clearchargingprofile.ConfMessage)
+		if !ok || conf.Status != types.ClearChargingProfileStatusAccepted {
+			return
+		}
+
+		for id, other := range g.installed {
+			if clears(req, id, other) {
+				delete(g.installed, id)
+			}
+		}
+	}
+}
+
+// installedFrom returns the installed profile a request would add.
+func installedFrom(req setchargingprofile.ReqMessage) installedProfile {
+	return installedProfile{
+		connectorId: req.ConnectorId.Value(),
+		purpose:     req.CsChargingProfiles.ChargingProfilePurpose(),
+		stackLevel:  req.CsChargingProfiles.StackLevel().Value(),
+	}
+}
+
+// clears reports whether a ClearChargingProfile.req removes an installed
+// profile: by id when one is given, otherwise by every criterion given.
+func clears(
+	req clearchargingprofile.ReqMessage,
+	id uint16,
+	profile installedProfile,
+) bool {
+	if req.Id != nil {
+		return req.Id.Value() == id
+	}
+
+	return (req.ConnectorId == nil ||
+		req.ConnectorId.Value() == profile.connectorId) &&
+		(req.ChargingProfilePurpose == nil ||
+			*req.ChargingProfilePurpose == profile.purpose) &&
+		(req.StackLevel == nil || req.StackLevel.Value() == profile.stackLevel)
+}
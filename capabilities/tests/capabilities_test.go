@@ -0,0 +1,263 @@
+package capabilities_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages"
+	"github.com/aasanchez/ocpp16messages/capabilities"
+	"github.com/aasanchez/ocpp16messages/getconfiguration"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/reservenow"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// configuration builds a GetConfiguration.conf from key/value pairs.
+func configuration(t *testing.T, pairs ...string) getconfiguration.ConfMessage {
+	t.Helper()
+
+	keys := make([]types.KeyValueInput, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		keys = append(keys, types.KeyValueInput{
+			Key:      pairs[i],
+			Readonly: true,
+			Value:    &pairs[i+1],
+		})
+	}
+
+	conf, err := getconfiguration.Conf(getconfiguration.ConfInput{
+		ConfigurationKey: keys,
+		UnknownKey:       nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return conf
+}
+
+func reserveNow(t *testing.T, connectorId int) reservenow.ReqMessage {
+	t.Helper()
+
+	req, err := reservenow.Req(reservenow.ReqInput{
+		ReservationId: 1,
+		ConnectorId:   connectorId,
+		IdTag:         "RFID-ABC123",
+		ExpiryDate:    "2025-01-02T15:00:00Z",
+		ParentIdTag:   nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return req
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestFromConfiguration(t *testing.T) {
+	t.Parallel()
+
+	caps, err := capabilities.FromConfiguration(configuration(t,
+		"SupportedFeatureProfiles", "Core, smartcharging,VendorProfile",
+		"numberofconnectors", "2",
+		"ChargingScheduleAllowedChargingRateUnit", "Current",
+		"MaxChargingProfilesInstalled", "8",
+	))
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	profiles := []ocpp16messages.FeatureProfile{
+		ocpp16messages.Core,
+		ocpp16messages.SmartCharging,
+	}
+	if !slices.Equal(caps.SupportedFeatureProfiles, profiles) {
+		t.Errorf(types.ErrorMismatch, profiles, caps.SupportedFeatureProfiles)
+	}
+
+	if caps.NumberOfConnectors == nil || *caps.NumberOfConnectors != 2 {
+		t.Errorf(types.ErrorMismatchValue, 2, caps.NumberOfConnectors)
+	}
+
+	units := []types.ChargingRateUnit{types.ChargingRateUnitAmperes}
+	if !slices.Equal(caps.ChargingScheduleAllowedChargingRateUnit, units) {
+		t.Errorf(
+			types.ErrorMismatch,
+			units,
+			caps.ChargingScheduleAllowedChargingRateUnit,
+		)
+	}
+
+	if caps.MaxChargingProfilesInstalled == nil ||
+		*caps.MaxChargingProfilesInstalled != 8 {
+		t.Errorf(types.ErrorMismatchValue, 8, caps.MaxChargingProfilesInstalled)
+	}
+
+	if caps.ChargeProfileMaxStackLevel != nil {
+		t.Errorf(types.ErrorMismatchValue, nil, caps.ChargeProfileMaxStackLevel)
+	}
+}
+
+func TestFromConfiguration_NothingRecognized(t *testing.T) {
+	t.Parallel()
+
+	// A value cannot be empty in a GetConfiguration.conf, so the empty list
+	// is written with separators only.
+	for _, value := range []string{" ", " , ", "VendorX", "VendorX,Other"} {
+		caps, err := capabilities.FromConfiguration(configuration(t,
+			"SupportedFeatureProfiles", value,
+			"ChargingScheduleAllowedChargingRateUnit", value,
+		))
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+
+		if caps.SupportedFeatureProfiles != nil {
+			t.Errorf(
+				types.ErrorMismatchValue,
+				nil,
+				caps.SupportedFeatureProfiles,
+			)
+		}
+
+		if caps.ChargingScheduleAllowedChargingRateUnit != nil {
+			t.Errorf(
+				types.ErrorMismatchValue,
+				nil,
+				caps.ChargingScheduleAllowedChargingRateUnit,
+			)
+		}
+
+		for _, profile := range []ocpp16messages.FeatureProfile{
+			ocpp16messages.Core,
+			ocpp16messages.Reservation,
+		} {
+			if !caps.Supports(profile) {
+				t.Errorf(types.ErrorMismatchValue, true, false)
+			}
+		}
+
+		err = caps.Check(reserveNow(t, 1))
+		if err != nil {
+			t.Errorf(types.ErrorUnexpectedError, err)
+		}
+	}
+}
+
+func TestFromConfiguration_InvalidNumber(t *testing.T) {
+	t.Parallel()
+
+	_, err := capabilities.FromConfiguration(configuration(t,
+		"NumberOfConnectors", "two",
+		"ChargeProfileMaxStackLevel", "-1",
+	))
+	if !errors.Is(err, types.ErrInvalidValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrInvalidValue)
+	}
+}
+
+func TestCheck_UnknownCapabilities(t *testing.T) {
+	t.Parallel()
+
+	var caps capabilities.Capabilities
+
+	err := caps.Check(reserveNow(t, 9))
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+}
+
+func TestCheck_ProfileNotSupported(t *testing.T) {
+	t.Parallel()
+
+	caps := capabilities.Capabilities{
+		SupportedFeatureProfiles: []ocpp16messages.FeatureProfile{
+			ocpp16messages.Core,
+		},
+		NumberOfConnectors:                      nil,
+		ChargingScheduleAllowedChargingRateUnit: nil,
+		MaxChargingProfilesInstalled:            nil,
+		ChargeProfileMaxStackLevel:              nil,
+	}
+
+	err := caps.Check(reserveNow(t, 1))
+	if !errors.Is(err, capabilities.ErrProfileNotSupported) {
+		t.Errorf(types.ErrorWrapping, err, capabilities.ErrProfileNotSupported)
+	}
+
+	err = caps.Check(heartbeat.ConfMessage{CurrentTime: types.DateTime{}})
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+}
+
+func TestSupports_CoreAlways(t *testing.T) {
+	t.Parallel()
+
+	caps := capabilities.Capabilities{
+		SupportedFeatureProfiles: []ocpp16messages.FeatureProfile{
+			ocpp16messages.SmartCharging,
+		},
+		NumberOfConnectors:                      nil,
+		ChargingScheduleAllowedChargingRateUnit: nil,
+		MaxChargingProfilesInstalled:            nil,
+		ChargeProfileMaxStackLevel:              nil,
+	}
+
+	if !caps.Supports(ocpp16messages.Core) {
+		t.Errorf(types.ErrorMismatchValue, true, false)
+	}
+
+	if caps.Supports(ocpp16messages.Reservation) {
+		t.Errorf(types.ErrorMismatchValue, false, true)
+	}
+}
+
+func TestCheck_UnknownConnector(t *testing.T) {
+	t.Parallel()
+
+	caps := capabilities.Capabilities{
+		SupportedFeatureProfiles:                nil,
+		NumberOfConnectors:                      intPtr(2),
+		ChargingScheduleAllowedChargingRateUnit: nil,
+		MaxChargingProfilesInstalled:            nil,
+		ChargeProfileMaxStackLevel:              nil,
+	}
+
+	err := caps.Check(reserveNow(t, 2))
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+
+	err = caps.Check(reserveNow(t, 3))
+	if !errors.Is(err, capabilities.ErrUnknownConnector) {
+		t.Errorf(types.ErrorWrapping, err, capabilities.ErrUnknownConnector)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	caller := &fakeCaller{
+		configuration: configuration(t, "NumberOfConnectors", "4"),
+		calls:         nil,
+	}
+
+	caps, err := capabilities.Discover(context.Background(), caller)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if caps.NumberOfConnectors == nil || *caps.NumberOfConnectors != 4 {
+		t.Errorf(types.ErrorMismatchValue, 4, caps.NumberOfConnectors)
+	}
+
+	if !slices.Equal(caller.calls, []string{getconfiguration.Action}) {
+		t.Errorf(types.ErrorMismatch, getconfiguration.Action, caller.calls)
+	}
+}
@@ -0,0 +1,228 @@
+package capabilities_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/capabilities"
+	"github.com/aasanchez/ocpp16messages/clearchargingprofile"
+	"github.com/aasanchez/ocpp16messages/getconfiguration"
+	"github.com/aasanchez/ocpp16messages/setchargingprofile"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// fakeCaller records the actions it is called with and accepts every
+// charging profile request.
+type fakeCaller struct {
+	configuration getconfiguration.ConfMessage
+	calls         []string
+}
+
+func (f *fakeCaller) Call(
+	_ context.Context,
+	action string,
+	_ any,
+	confirmation any,
+) error {
+	f.calls = append(f.calls, action)
+
+	switch conf := confirmation.(type) {
+	case *getconfiguration.ConfMessage:
+		*conf = f.configuration
+	case *setchargingprofile.ConfMessage:
+		conf.Status = types.ChargingProfileStatusAccepted
+	case *clearchargingprofile.ConfMessage:
+		conf.Status = types.ClearChargingProfileStatusAccepted
+	}
+
+	return nil
+}
+
+func chargingProfile(
+	t *testing.T,
+	id int,
+	stackLevel int,
+	rateUnit string,
+) setchargingprofile.ReqMessage {
+	t.Helper()
+
+	req, err := setchargingprofile.Req(setchargingprofile.ReqInput{
+		ConnectorId: 1,
+		CsChargingProfiles: types.ChargingProfileInput{
+			ChargingProfileId:      id,
+			TransactionId:          nil,
+			StackLevel:             stackLevel,
+			ChargingProfilePurpose: "TxDefaultProfile",
+			ChargingProfileKind:    "Absolute",
+			RecurrencyKind:         nil,
+			ValidFrom:              nil,
+			ValidTo:                nil,
+			ChargingSchedule: types.ChargingScheduleInput{
+				Duration:         nil,
+				StartSchedule:    nil,
+				ChargingRateUnit: rateUnit,
+				ChargingSchedulePeriod: []types.ChargingSchedulePeriodInput{
+					{StartPeriod: 0, Limit: 16, NumberPhases: nil},
+				},
+				MinChargingRate: nil,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return req
+}
+
+// smartCharging returns the capabilities of a single-connector Charge Point
+// holding at most one profile, charged in Amperes.
+func smartCharging() capabilities.Capabilities {
+	return capabilities.Capabilities{
+		SupportedFeatureProfiles: nil,
+		NumberOfConnectors:       intPtr(1),
+		ChargingScheduleAllowedChargingRateUnit: []types.ChargingRateUnit{
+			types.ChargingRateUnitAmperes,
+		},
+		MaxChargingProfilesInstalled: intPtr(1),
+		ChargeProfileMaxStackLevel:   intPtr(3),
+	}
+}
+
+func callProfile(
+	gate *capabilities.Gate,
+	req setchargingprofile.ReqMessage,
+) error {
+	var conf setchargingprofile.ConfMessage
+
+	return gate.Call(
+		context.Background(),
+		setchargingprofile.Action,
+		req,
+		&conf,
+	)
+}
+
+func TestGate_Rejects(t *testing.T) {
+	t.Parallel()
+
+	next := new(fakeCaller)
+	gate := capabilities.NewGate(next, smartCharging(), nil)
+
+	err := callProfile(gate, chargingProfile(t, 1, 0, "W"))
+	if !errors.Is(err, types.ErrInvalidValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrInvalidValue)
+	}
+
+	err = callProfile(gate, chargingProfile(t, 1, 4, "A"))
+	if !errors.Is(err, types.ErrInvalidValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrInvalidValue)
+	}
+
+	if len(next.calls) != 0 {
+		t.Errorf(types.ErrorMismatchValue, 0, len(next.calls))
+	}
+}
+
+func TestGate_Warns(t *testing.T) {
+	t.Parallel()
+
+	var warnings []error
+
+	next := new(fakeCaller)
+	gate := capabilities.NewGate(next, smartCharging(),
+		func(_ string, err error) { warnings = append(warnings, err) })
+
+	err := callProfile(gate, chargingProfile(t, 1, 0, "W"))
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+
+	if len(warnings) != 1 || len(next.calls) != 1 {
+		t.Errorf(types.ErrorMismatch, "one warning and one call", warnings)
+	}
+}
+
+func TestGate_MaxChargingProfilesInstalled(t *testing.T) {
+	t.Parallel()
+
+	next := new(fakeCaller)
+	gate := capabilities.NewGate(next, smartCharging(), nil)
+
+	err := callProfile(gate, chargingProfile(t, 1, 0, "A"))
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	err = callProfile(gate, chargingProfile(t, 2, 1, "A"))
+	if !errors.Is(err, capabilities.ErrProfilesFull) {
+		t.Errorf(types.ErrorWrapping, err, capabilities.ErrProfilesFull)
+	}
+
+	// The same id replaces the installed profile.
+	err = callProfile(gate, chargingProfile(t, 1, 1, "A"))
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+
+	clearReq, err := clearchargingprofile.Req(clearchargingprofile.ReqInput{
+		Id:                     intPtr(1),
+		ConnectorId:            nil,
+		ChargingProfilePurpose: nil,
+		StackLevel:             nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	var cleared clearchargingprofile.ConfMessage
+
+	err = gate.Call(
+		context.Background(),
+		clearchargingprofile.Action,
+		clearReq,
+		&cleared,
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	err = callProfile(gate, chargingProfile(t, 2, 1, "A"))
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+}
+
+func TestCheck_ZeroMaxStackLevel(t *testing.T) {
+	t.Parallel()
+
+	caps, err := capabilities.FromConfiguration(configuration(t,
+		"ChargeProfileMaxStackLevel", "0",
+	))
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	err = caps.Check(chargingProfile(t, 1, 0, "A"))
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+
+	err = caps.Check(chargingProfile(t, 1, 1, "A"))
+	if !errors.Is(err, types.ErrInvalidValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrInvalidValue)
+	}
+
+	next := new(fakeCaller)
+	gate := capabilities.NewGate(next, caps, nil)
+
+	err = callProfile(gate, chargingProfile(t, 1, 1, "A"))
+	if !errors.Is(err, types.ErrInvalidValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrInvalidValue)
+	}
+
+	if len(next.calls) != 0 {
+		t.Errorf(types.ErrorMismatchValue, 0, len(next.calls))
+	}
+}
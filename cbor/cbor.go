@@ -0,0 +1,180 @@
+package cbor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/schema"
+)
+
+// Version is the version of the encoding written by Marshal. Unmarshal
+// rejects data of other versions.
+const Version = 1
+
+// fields is the number of items of an encoded message.
+const fields = 4
+
+var (
+	// ErrMalformed is returned for data that is not a well-formed message
+	// of this encoding.
+	ErrMalformed = errors.New("cbor: malformed data")
+	// ErrVersion is returned for data of an unsupported Version.
+	ErrVersion = errors.New("cbor: unsupported version")
+	// ErrUnencodable is returned by Marshal for a payload the schema of its
+	// action cannot describe.
+	ErrUnencodable = errors.New("cbor: unencodable payload")
+)
+
+// Message is an OCPP message with its action.
+type Message struct {
+	// Action is the OCPP action, e.g. Authorize.
+	Action string
+	// Kind tells a request from a confirmation.
+	Kind schema.Kind
+	// Payload is the ReqMessage or ConfMessage of the action's package,
+	// e.g. authorize.ReqMessage.
+	Payload any
+}
+
+// Marshal encodes a message as a CBOR array of the Version, the action, the
+// kind (0 for a request, 1 for a confirmation) and the payload. The payload
+// is the OCPP-J payload with each property name replaced by its position,
+// from 1, among the properties of the official schema sorted by name.
+func Marshal(msg Message) ([]byte, error) {
+	shape, err := load(msg.Action, msg.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(msg.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("cbor: %s: %w", msg.Action, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value map[string]any
+
+	err = decoder.Decode(&value)
+	if err != nil || value == nil {
+		return nil, fmt.Errorf("%w: %s: not an object", ErrUnencodable,
+			msg.Action)
+	}
+
+	dst := make([]byte, 0, len(data))
+	dst = appendHead(dst, majorArray, fields)
+	dst = appendHead(dst, majorUnsigned, Version)
+	dst = appendText(dst, msg.Action)
+	dst = appendHead(dst, majorUnsigned, uint64(msg.Kind))
+
+	dst, err = appendObject(dst, value, shape)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", msg.Action, err)
+	}
+
+	return dst, nil
+}
+
+// Unmarshal decodes a message written by Marshal. The payload is turned back
+// into its OCPP-J form and decoded with ocppj.DecodeRequest or
+// ocppj.DecodeConfirmation, which run the Req/Conf constructors, so only
+// valid messages are returned; their errors can be classified with
+// ocppj.ErrorFor.
+func Unmarshal(data []byte) (Message, error) {
+	msg := Message{Action: "", Kind: schema.Request, Payload: nil}
+	r := &reader{data: data, pos: 0, depth: 0}
+
+	major, _, count, err := r.head()
+	if err != nil {
+		return msg, err
+	}
+
+	if major != majorArray || count != fields {
+		return msg, fmt.Errorf("%w: want an array of %d items", ErrMalformed,
+			fields)
+	}
+
+	version, err := r.uint64Of()
+	if err != nil {
+		return msg, err
+	}
+
+	if version != Version {
+		return msg, fmt.Errorf("%w: %d", ErrVersion, version)
+	}
+
+	payload, err := r.message(&msg)
+	if err != nil {
+		return msg, err
+	}
+
+	if msg.Kind == schema.Confirmation {
+		msg.Payload, err = ocppj.DecodeConfirmation(msg.Action, payload)
+	} else {
+		msg.Payload, err = ocppj.DecodeRequest(msg.Action, payload)
+	}
+
+	if err != nil {
+		return msg, fmt.Errorf("%s: %w", msg.Action, err)
+	}
+
+	return msg, nil
+}
+
+// message reads the action, kind and payload of a message and returns the
+// OCPP-J form of the payload.
+func (r *reader) message(msg *Message) (json.RawMessage, error) {
+	action, err := r.textOf()
+	if err != nil {
+		return nil, err
+	}
+
+	kind, err := r.uint64Of()
+	if err != nil {
+		return nil, err
+	}
+
+	if kind > uint64(schema.Confirmation) {
+		return nil, fmt.Errorf("%w: kind %d", ErrMalformed, kind)
+	}
+
+	msg.Action = action
+	msg.Kind = schema.Kind(kind)
+
+	shape, err := load(msg.Action, msg.Kind)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", err, ocppj.NewError(
+			ocppj.NotImplemented,
+			fmt.Sprintf("unknown action %q", action),
+		))
+	}
+
+	major, _, count, err := r.head()
+	if err != nil {
+		return nil, err
+	}
+
+	if major != majorMap {
+		return nil, fmt.Errorf("%w: payload is not a map", ErrMalformed)
+	}
+
+	object, err := r.object(count, shape)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.remaining() != 0 {
+		return nil, fmt.Errorf("%w: data after the message", ErrMalformed)
+	}
+
+	payload, err := json.Marshal(object)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	return payload, nil
+}
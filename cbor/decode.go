@@ -0,0 +1,248 @@
+package cbor
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+)
+
+// maxDepth bounds the nesting of decoded data items. The deepest OCPP 1.6
+// payload, SetChargingProfile.req, nests five levels.
+const maxDepth = 16
+
+// reader decodes the data items of a byte slice.
+type reader struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+// remaining returns the number of unread bytes.
+func (r *reader) remaining() uint64 {
+	return uint64(len(r.data) - r.pos)
+}
+
+// take returns the next n bytes.
+func (r *reader) take(n uint64) ([]byte, error) {
+	if n > r.remaining() {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrMalformed)
+	}
+
+	taken := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+
+	return taken, nil
+}
+
+// head reads the initial byte and argument of a data item. Floats and simple
+// values keep their raw argument.
+func (r *reader) head() (byte, byte, uint64, error) {
+	initial, err := r.take(1)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	major, info := initial[0]>>5, initial[0]&0x1f
+
+	var size uint64
+
+	switch info {
+	case infoUint8:
+		size = 1
+	case infoUint16:
+		size = 2
+	case infoUint32:
+		size = 4
+	case infoUint64:
+		size = 8
+	default:
+		if info > infoUint64 {
+			return 0, 0, 0, fmt.Errorf(
+				"%w: unsupported additional information %d",
+				ErrMalformed,
+				info,
+			)
+		}
+
+		return major, info, uint64(info), nil
+	}
+
+	raw, err := r.take(size)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	var argument uint64
+	for _, b := range raw {
+		argument = argument<<8 | uint64(b)
+	}
+
+	return major, info, argument, nil
+}
+
+// value reads a data item into its JSON value, naming map keys by the
+// layout.
+func (r *reader) value(shape *layout) (any, error) {
+	r.depth++
+	defer func() { r.depth-- }()
+
+	if r.depth > maxDepth {
+		return nil, fmt.Errorf("%w: nesting exceeds %d", ErrMalformed, maxDepth)
+	}
+
+	major, info, argument, err := r.head()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case majorUnsigned:
+		return json.Number(strconv.FormatUint(argument, 10)), nil
+	case majorNegative:
+		if argument > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: integer overflow", ErrMalformed)
+		}
+
+		return json.Number(strconv.FormatInt(-1-int64(argument), 10)), nil
+	case majorText:
+		return r.text(argument)
+	case majorArray:
+		return r.array(argument, shape.itemsOf())
+	case majorMap:
+		return r.object(argument, shape)
+	case majorSimple:
+		return simple(info, argument)
+	default:
+		return nil, fmt.Errorf(
+			"%w: unsupported major type %d",
+			ErrMalformed,
+			major,
+		)
+	}
+}
+
+// text reads the content of a text string.
+func (r *reader) text(length uint64) (string, error) {
+	raw, err := r.take(length)
+	if err != nil {
+		return "", err
+	}
+
+	if !utf8.Valid(raw) {
+		return "", fmt.Errorf("%w: invalid UTF-8 text", ErrMalformed)
+	}
+
+	return string(raw), nil
+}
+
+// array reads the items of an array.
+func (r *reader) array(count uint64, items *layout) ([]any, error) {
+	if count > r.remaining() {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrMalformed)
+	}
+
+	list := make([]any, 0, count)
+
+	for range count {
+		item, err := r.value(items)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, item)
+	}
+
+	return list, nil
+}
+
+// object reads the entries of a map keyed by the layout.
+func (r *reader) object(count uint64, shape *layout) (map[string]any, error) {
+	if count > r.remaining()/2 {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrMalformed)
+	}
+
+	object := make(map[string]any, count)
+
+	for range count {
+		major, _, key, err := r.head()
+		if err != nil {
+			return nil, err
+		}
+
+		name, known := shape.name(key)
+		if major != majorUnsigned || !known {
+			return nil, fmt.Errorf(
+				"%w: key %d",
+				ocppj.ErrUnknownProperty,
+				key,
+			)
+		}
+
+		if _, seen := object[name]; seen {
+			return nil, fmt.Errorf("%w: /%s", ocppj.ErrDuplicateKey, name)
+		}
+
+		object[name], err = r.value(shape.child(name))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return object, nil
+}
+
+// simple returns the value of a simple value or float.
+func simple(info byte, argument uint64) (any, error) {
+	var float float64
+
+	switch info {
+	case infoFalse:
+		return false, nil
+	case infoTrue:
+		return true, nil
+	case infoFloat32:
+		float = float64(math.Float32frombits(uint32(argument)))
+	case infoFloat64:
+		float = math.Float64frombits(argument)
+	default:
+		return nil, fmt.Errorf("%w: simple value %d", ErrMalformed, info)
+	}
+
+	if math.IsNaN(float) || math.IsInf(float, 0) {
+		return nil, fmt.Errorf("%w: non-finite number", ErrMalformed)
+	}
+
+	return json.Number(strconv.FormatFloat(float, 'g', -1, 64)), nil
+}
+
+// uint64Of reads an unsigned integer item.
+func (r *reader) uint64Of() (uint64, error) {
+	major, _, argument, err := r.head()
+	if err != nil {
+		return 0, err
+	}
+
+	if major != majorUnsigned {
+		return 0, fmt.Errorf("%w: want an unsigned integer", ErrMalformed)
+	}
+
+	return argument, nil
+}
+
+// textOf reads a text string item.
+func (r *reader) textOf() (string, error) {
+	major, _, argument, err := r.head()
+	if err != nil {
+		return "", err
+	}
+
+	if major != majorText {
+		return "", fmt.Errorf("%w: want a text string", ErrMalformed)
+	}
+
+	return r.text(argument)
+}
@@ -0,0 +1,22 @@
+// Package cbor implements a compact binary encoding of the messages of this
+// module for internal message buses, based on CBOR (RFC 8949).
+//
+// A message is encoded as a CBOR array holding the encoding Version, the
+// action name, the kind (0 for a request, 1 for a confirmation) and the
+// payload:
+//
+//	[1, "Heartbeat", 1, {1: "2025-01-02T15:00:00Z"}]
+//
+// The payload is the OCPP-J payload with every property name replaced by
+// its position, from 1, among the properties of the official OCPP 1.6
+// schema sorted by name, so a MeterValues.req is about half the size of its
+// JSON form. Integers are written as CBOR integers and decimals as float64;
+// text stays text, including DateTime values.
+//
+// Unmarshal rebuilds the OCPP-J payload and decodes it with
+// ocppj.DecodeRequest or ocppj.DecodeConfirmation, so every decoded message
+// went through its Req/Conf constructor, exactly as if it had been received
+// from a charger. The numbering is fixed by Version: a change to it would
+// ship under a new Version, and Unmarshal rejects versions it does not know
+// with ErrVersion.
+package cbor
@@ -0,0 +1,174 @@
+package cbor
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CBOR major types (RFC 8949, section 3.1).
+const (
+	majorUnsigned byte = 0
+	majorNegative byte = 1
+	majorText     byte = 3
+	majorArray    byte = 4
+	majorMap      byte = 5
+	majorSimple   byte = 7
+)
+
+// CBOR additional information values. Arguments below infoUint8 are
+// stored in the initial byte.
+const (
+	infoUint8   = 24
+	infoUint16  = 25
+	infoUint32  = 26
+	infoUint64  = 27
+	infoFalse   = 20
+	infoTrue    = 21
+	infoFloat32 = 26
+	infoFloat64 = 27
+)
+
+// appendHead appends the initial byte and argument of a data item.
+func appendHead(dst []byte, major byte, argument uint64) []byte {
+	major <<= 5
+
+	switch {
+	case argument < infoUint8:
+		return append(dst, major|byte(argument))
+	case argument <= math.MaxUint8:
+		return append(dst, major|infoUint8, byte(argument))
+	case argument <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(
+			append(dst, major|infoUint16),
+			uint16(argument),
+		)
+	case argument <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(
+			append(dst, major|infoUint32),
+			uint32(argument),
+		)
+	default:
+		return binary.BigEndian.AppendUint64(
+			append(dst, major|infoUint64),
+			argument,
+		)
+	}
+}
+
+// appendText appends a text string.
+func appendText(dst []byte, text string) []byte {
+	return append(appendHead(dst, majorText, uint64(len(text))), text...)
+}
+
+// appendValue appends a JSON value decoded with UseNumber. Objects are
+// written as maps keyed by the layout, in key order.
+func appendValue(dst []byte, value any, shape *layout) ([]byte, error) {
+	switch typed := value.(type) {
+	case map[string]any:
+		return appendObject(dst, typed, shape)
+	case []any:
+		var err error
+
+		dst = appendHead(dst, majorArray, uint64(len(typed)))
+		for _, item := range typed {
+			dst, err = appendValue(dst, item, shape.itemsOf())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return dst, nil
+	case string:
+		return appendText(dst, typed), nil
+	case json.Number:
+		return appendNumber(dst, typed)
+	case bool:
+		if typed {
+			return append(dst, majorSimple<<5|infoTrue), nil
+		}
+
+		return append(dst, majorSimple<<5|infoFalse), nil
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnencodable, value)
+	}
+}
+
+// appendObject appends an object as a map from property keys to values.
+func appendObject(
+	dst []byte,
+	object map[string]any,
+	shape *layout,
+) ([]byte, error) {
+	keys := make([]uint64, 0, len(object))
+
+	for name, value := range object {
+		key := shape.key(name)
+		if key == 0 {
+			return nil, fmt.Errorf("%w: property %q", ErrUnencodable, name)
+		}
+
+		if value != nil {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	dst = appendHead(dst, majorMap, uint64(len(keys)))
+
+	for _, key := range keys {
+		name, _ := shape.name(key)
+
+		var err error
+
+		dst = appendHead(dst, majorUnsigned, key)
+
+		dst, err = appendValue(dst, object[name], shape.child(name))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return dst, nil
+}
+
+// appendNumber appends an integer literal as an integer and any other
+// number as a float64.
+func appendNumber(dst []byte, number json.Number) ([]byte, error) {
+	literal := number.String()
+
+	if !strings.ContainsAny(literal, ".eE") {
+		unsigned, err := strconv.ParseUint(literal, 10, 64)
+		if err == nil {
+			return appendHead(dst, majorUnsigned, unsigned), nil
+		}
+
+		signed, err := strconv.ParseInt(literal, 10, 64)
+		if err == nil && signed < 0 {
+			return appendHead(dst, majorNegative, uint64(-(signed + 1))), nil
+		}
+	}
+
+	float, err := number.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("%w: number %s", ErrUnencodable, literal)
+	}
+
+	dst = append(dst, majorSimple<<5|infoFloat64)
+
+	return binary.BigEndian.AppendUint64(dst, math.Float64bits(float)), nil
+}
+
+// itemsOf returns the layout of the items of a list.
+func (l *layout) itemsOf() *layout {
+	if l == nil {
+		return nil
+	}
+
+	return l.items
+}
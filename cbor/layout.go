@@ -0,0 +1,112 @@
+package cbor
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/aasanchez/ocpp16messages/schema"
+)
+
+// layout assigns the keys of an object: the properties of its schema in
+// name order, numbered from 1. The official schemas are frozen, so the
+// numbering is part of Version.
+type layout struct {
+	names    []string
+	children map[string]*layout
+	items    *layout
+}
+
+// layouts caches the layouts by action and kind.
+var layouts sync.Map
+
+// layoutKey identifies a cached layout.
+type layoutKey struct {
+	action string
+	kind   schema.Kind
+}
+
+// load returns the layout of an action's payload.
+func load(action string, kind schema.Kind) (*layout, error) {
+	key := layoutKey{action: action, kind: kind}
+
+	cached, ok := layouts.Load(key)
+	if ok {
+		//nolint:forcetypeassert // Only *layout is stored.
+		return cached.(*layout), nil
+	}
+
+	data, err := schema.Schema(action, kind)
+	if err != nil {
+		return nil, fmt.Errorf("cbor: %w", err)
+	}
+
+	var root schema.Definition
+
+	err = json.Unmarshal(data, &root)
+	if err != nil {
+		return nil, fmt.Errorf("cbor: %s %s: %w", action, kind, err)
+	}
+
+	built := newLayout(&root)
+	layouts.Store(key, built)
+
+	return built, nil
+}
+
+// newLayout returns the layout of a schema definition.
+func newLayout(def *schema.Definition) *layout {
+	if def == nil {
+		return nil
+	}
+
+	built := &layout{
+		names:    make([]string, 0, len(def.Properties)),
+		children: make(map[string]*layout, len(def.Properties)),
+		items:    newLayout(def.Items),
+	}
+
+	for name, property := range def.Properties {
+		built.names = append(built.names, name)
+		built.children[name] = newLayout(property)
+	}
+
+	slices.Sort(built.names)
+
+	return built
+}
+
+// key returns the key of a property, or 0 when the schema does not define
+// it.
+func (l *layout) key(name string) uint64 {
+	if l == nil {
+		return 0
+	}
+
+	index, found := slices.BinarySearch(l.names, name)
+	if !found {
+		return 0
+	}
+
+	return uint64(index) + 1
+}
+
+// name returns the property of a key, or false when the schema does not
+// define it.
+func (l *layout) name(key uint64) (string, bool) {
+	if l == nil || key == 0 || key > uint64(len(l.names)) {
+		return "", false
+	}
+
+	return l.names[key-1], true
+}
+
+// child returns the layout of a property or of list items.
+func (l *layout) child(name string) *layout {
+	if l == nil {
+		return nil
+	}
+
+	return l.children[name]
+}
@@ -0,0 +1,180 @@
+package cbor_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/cbor"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/schema"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const testCurrentTime = "2025-01-02T15:00:00Z"
+
+// encode builds a message from raw CBOR items.
+func encode(items ...[]byte) []byte {
+	return bytes.Join(items, nil)
+}
+
+// text returns a short CBOR text string.
+func text(s string) []byte {
+	return append([]byte{0x60 | byte(len(s))}, s...)
+}
+
+func TestMarshal_Heartbeat(t *testing.T) {
+	t.Parallel()
+
+	conf, err := heartbeat.Conf(heartbeat.ConfInput{
+		CurrentTime: testCurrentTime,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	data, err := cbor.Marshal(cbor.Message{
+		Action:  ocppj.ActionHeartbeat,
+		Kind:    schema.Confirmation,
+		Payload: conf,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	want := encode(
+		[]byte{0x84, 0x01},
+		text("Heartbeat"),
+		[]byte{0x01, 0xa1, 0x01},
+		text(testCurrentTime),
+	)
+
+	if !bytes.Equal(data, want) {
+		t.Errorf(types.ErrorMismatch, want, data)
+	}
+}
+
+func TestMarshal_RoundTripAllActions(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range conforming {
+		assertRoundTrip(t, tc.action, schema.Request, tc.request)
+		assertRoundTrip(t, tc.action, schema.Confirmation, tc.confirmation)
+	}
+}
+
+// assertRoundTrip checks that a payload survives the binary encoding and is
+// smaller than its JSON form.
+func assertRoundTrip(
+	t *testing.T,
+	action string,
+	kind schema.Kind,
+	payload string,
+) {
+	t.Helper()
+
+	var (
+		msg any
+		err error
+	)
+
+	if kind == schema.Confirmation {
+		msg, err = ocppj.DecodeConfirmation(action, []byte(payload))
+	} else {
+		msg, err = ocppj.DecodeRequest(action, []byte(payload))
+	}
+
+	if err != nil {
+		t.Fatalf("%s %s: %v", action, kind, err)
+	}
+
+	data, err := cbor.Marshal(cbor.Message{
+		Action:  action,
+		Kind:    kind,
+		Payload: msg,
+	})
+	if err != nil {
+		t.Fatalf("%s %s: %v", action, kind, err)
+	}
+
+	decoded, err := cbor.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("%s %s: %v", action, kind, err)
+	}
+
+	want, _ := json.Marshal(msg)
+	got, _ := json.Marshal(decoded.Payload)
+
+	if string(got) != string(want) {
+		t.Errorf("%s %s: want %s, got %s", action, kind, want, got)
+	}
+
+	if len(want) > 2 && len(data)-len(action) >= len(want) {
+		t.Errorf("%s %s: %d bytes, JSON has %d", action, kind, len(data),
+			len(want))
+	}
+}
+
+func TestUnmarshal_Errors(t *testing.T) {
+	t.Parallel()
+
+	header := encode([]byte{0x84, 0x01}, text("Reset"), []byte{0x00})
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"empty", nil, cbor.ErrMalformed},
+		{"not an array", text("Reset"), cbor.ErrMalformed},
+		{
+			"version",
+			encode([]byte{0x84, 0x02}, text("Reset"), []byte{0x00, 0xa0}),
+			cbor.ErrVersion,
+		},
+		{
+			"unknown action",
+			encode([]byte{0x84, 0x01}, text("Reboot"), []byte{0x00, 0xa0}),
+			ocppj.NewError(ocppj.NotImplemented, ""),
+		},
+		{
+			"trailing data",
+			encode(header, []byte{0xa1, 0x01}, text("Soft"), []byte{0x00}),
+			cbor.ErrMalformed,
+		},
+		{
+			"unknown key",
+			encode(header, []byte{0xa1, 0x02}, text("Soft")),
+			ocppj.ErrUnknownProperty,
+		},
+		{
+			"duplicate key",
+			encode(header, []byte{0xa2, 0x01}, text("Soft"),
+				[]byte{0x01}, text("Hard")),
+			ocppj.ErrDuplicateKey,
+		},
+		{
+			"truncated",
+			encode(header, []byte{0xa1, 0x01, 0x64}, []byte("So")),
+			cbor.ErrMalformed,
+		},
+		{
+			"invalid value",
+			encode(header, []byte{0xa1, 0x01}, text("Hard reboot")),
+			types.ErrInvalidValue,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := cbor.Unmarshal(tc.data)
+			if !errors.Is(err, tc.want) {
+				t.Errorf(types.ErrorWrapping, err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package changeavailability
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of ChangeAvailability.req.
+type reqWire struct {
+	ConnectorId uint16 `json:"connectorId"`
+	Type        string `json:"type"`
+}
+
+// confWire is the OCPP-J payload of ChangeAvailability.conf.
+type confWire struct {
+	Status string `json:"status"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J ChangeAvailability.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		ConnectorId: m.ConnectorId.Value(),
+		Type:        m.Type.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J ChangeAvailability.req payload and validates
+// it with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J ChangeAvailability.conf
+// payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		Status: m.Status.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J ChangeAvailability.conf payload and validates
+// it with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
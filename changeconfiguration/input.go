@@ -0,0 +1,47 @@
+package changeconfiguration
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		Key:   m.Key.String(),
+		Value: m.Value.String(),
+	}
+}
+
+// WithKey returns a copy of the message with Key set to value. The copy is
+// validated by Req.
+func (m ReqMessage) WithKey(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Key = value
+
+	return Req(input)
+}
+
+// WithValue returns a copy of the message with Value set to value. The copy
+// is validated by Req.
+func (m ReqMessage) WithValue(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Value = value
+
+	return Req(input)
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	return ConfInput{
+		Status: m.Status.String(),
+	}
+}
+
+// WithStatus returns a copy of the message with Status set to value. The
+// copy is validated by Conf.
+func (m ConfMessage) WithStatus(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.Status = value
+
+	return Conf(input)
+}
@@ -0,0 +1,59 @@
+package changeconfiguration
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of ChangeConfiguration.req.
+type reqWire struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// confWire is the OCPP-J payload of ChangeConfiguration.conf.
+type confWire struct {
+	Status string `json:"status"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J ChangeConfiguration.req
+// payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		Key:   m.Key.String(),
+		Value: m.Value.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J ChangeConfiguration.req payload and validates
+// it with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J ChangeConfiguration.conf
+// payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		Status: m.Status.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J ChangeConfiguration.conf payload and
+// validates it with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
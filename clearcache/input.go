@@ -0,0 +1,24 @@
+package clearcache
+
+// ToInput returns the empty ReqInput, as ClearCache.req has no fields.
+func (ReqMessage) ToInput() ReqInput {
+	return ReqInput{}
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	return ConfInput{
+		Status: m.Status.String(),
+	}
+}
+
+// WithStatus returns a copy of the message with Status set to value. The
+// copy is validated by Conf.
+func (m ConfMessage) WithStatus(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.Status = value
+
+	return Conf(input)
+}
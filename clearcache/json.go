@@ -0,0 +1,51 @@
+package clearcache
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of ClearCache.req.
+type reqWire struct{}
+
+// confWire is the OCPP-J payload of ClearCache.conf.
+type confWire struct {
+	Status string `json:"status"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J ClearCache.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{})
+}
+
+// UnmarshalJSON decodes an OCPP-J ClearCache.req payload and validates it with
+// Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J ClearCache.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		Status: m.Status.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J ClearCache.conf payload and validates it with
+// Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
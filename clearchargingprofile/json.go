@@ -0,0 +1,63 @@
+package clearchargingprofile
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of ClearChargingProfile.req.
+type reqWire struct {
+	Id                     *uint16 `json:"id,omitempty"`
+	ConnectorId            *uint16 `json:"connectorId,omitempty"`
+	ChargingProfilePurpose *string `json:"chargingProfilePurpose,omitempty"`
+	StackLevel             *uint16 `json:"stackLevel,omitempty"`
+}
+
+// confWire is the OCPP-J payload of ClearChargingProfile.conf.
+type confWire struct {
+	Status string `json:"status"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J ClearChargingProfile.req
+// payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		Id:                     wire.OptionalInteger(m.Id),
+		ConnectorId:            wire.OptionalInteger(m.ConnectorId),
+		ChargingProfilePurpose: wire.OptionalString(m.ChargingProfilePurpose),
+		StackLevel:             wire.OptionalInteger(m.StackLevel),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J ClearChargingProfile.req payload and
+// validates it with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J ClearChargingProfile.conf
+// payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		Status: m.Status.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J ClearChargingProfile.conf payload and
+// validates it with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
@@ -0,0 +1,154 @@
+// Command ocpp16-schema writes the JSON Schema documents and the OpenAPI 3
+// components generated from the message types of this module, so REST
+// gateways and frontends validate exactly what the library validates.
+//
+// Usage:
+//
+//	ocpp16-schema -out schemas/          # one <Title>.json per payload
+//	ocpp16-schema -format openapi        # OpenAPI 3 document on stdout
+//	ocpp16-schema -format openapi -out api/ocpp16.json
+//
+// JSON Schema documents are named after their title, e.g.
+// AuthorizeRequest.json and AuthorizeResponse.json.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/aasanchez/ocpp16messages/schema"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+
+	formatJSONSchema = "jsonschema"
+	formatOpenAPI    = "openapi"
+
+	dirMode  = 0o755
+	fileMode = 0o644
+)
+
+var (
+	errUnknownFormat = errors.New("unknown -format")
+	errMissingOut    = errors.New("-out is required for -format jsonschema")
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run generates the documents and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("ocpp16-schema", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	format := flags.String(
+		"format",
+		formatJSONSchema,
+		"output format: jsonschema or openapi",
+	)
+	out := flags.String(
+		"out",
+		"",
+		"output directory for jsonschema, output file for openapi "+
+			"(default stdout)",
+	)
+
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+
+	if err != nil {
+		return exitUsage
+	}
+
+	switch *format {
+	case formatJSONSchema:
+		if *out == "" {
+			_, _ = fmt.Fprintln(stderr, errMissingOut)
+
+			return exitUsage
+		}
+
+		err = writeJSONSchemas(*out)
+	case formatOpenAPI:
+		err = writeOpenAPI(*out, stdout)
+	default:
+		_, _ = fmt.Fprintf(stderr, "%v: %q\n", errUnknownFormat, *format)
+
+		return exitUsage
+	}
+
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+
+		return exitError
+	}
+
+	return exitOK
+}
+
+// writeJSONSchemas writes one JSON Schema document per payload into dir.
+func writeJSONSchemas(dir string) error {
+	definitions, err := schema.GenerateAll()
+	if err != nil {
+		return fmt.Errorf("generate: %w", err)
+	}
+
+	err = os.MkdirAll(dir, dirMode)
+	if err != nil {
+		return fmt.Errorf("output directory: %w", err)
+	}
+
+	for _, definition := range definitions {
+		data, err := json.MarshalIndent(definition, "", "  ")
+		if err != nil {
+			return fmt.Errorf("%s: %w", definition.Title, err)
+		}
+
+		name := filepath.Join(dir, definition.Title+".json")
+
+		err = os.WriteFile(name, append(data, '\n'), fileMode)
+		if err != nil {
+			return fmt.Errorf("write: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeOpenAPI writes the OpenAPI document to file, or to stdout when file
+// is empty.
+func writeOpenAPI(file string, stdout io.Writer) error {
+	data, err := schema.OpenAPI()
+	if err != nil {
+		return fmt.Errorf("generate: %w", err)
+	}
+
+	data = append(data, '\n')
+
+	if file == "" {
+		_, err = stdout.Write(data)
+		if err != nil {
+			return fmt.Errorf("write: %w", err)
+		}
+
+		return nil
+	}
+
+	err = os.WriteFile(file, data, fileMode)
+	if err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
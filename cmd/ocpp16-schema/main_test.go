@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const payloadSchemas = 56
+
+func TestRun_JSONSchema(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"-out", dir}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf(types.ErrorMismatchValue, exitOK, code)
+	}
+
+	names, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if len(names) != payloadSchemas {
+		t.Errorf(types.ErrorMismatchValue, payloadSchemas, len(names))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "AuthorizeRequest.json"))
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	var document map[string]any
+
+	err = json.Unmarshal(data, &document)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if document["title"] != "AuthorizeRequest" {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			"AuthorizeRequest",
+			document["title"],
+		)
+	}
+}
+
+func TestRun_OpenAPI(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"-format", "openapi"}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf(types.ErrorMismatchValue, exitOK, code)
+	}
+
+	if !strings.Contains(stdout.String(), `"AuthorizeResponse"`) {
+		t.Errorf(types.ErrorWantContains, stdout.String(), "AuthorizeResponse")
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{
+		{"-format", "yaml"},
+		{"-format", "jsonschema"},
+		{"-unknown"},
+	} {
+		var stdout, stderr bytes.Buffer
+
+		code := run(args, &stdout, &stderr)
+		if code != exitUsage {
+			t.Errorf(types.ErrorMismatchValue, exitUsage, code)
+		}
+	}
+}
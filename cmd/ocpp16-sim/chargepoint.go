@@ -0,0 +1,576 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/diagnosticsstatusnotification"
+	"github.com/aasanchez/ocpp16messages/firmwarestatusnotification"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+)
+
+const (
+	statusAvailable   = "Available"
+	statusUnavailable = "Unavailable"
+	statusPreparing   = "Preparing"
+	statusCharging    = "Charging"
+	statusFinishing   = "Finishing"
+	errorCodeNone     = "NoError"
+
+	keyNumberOfConnectors        = "NumberOfConnectors"
+	keyHeartbeatInterval         = "HeartbeatInterval"
+	keyMeterValueSampleInterval  = "MeterValueSampleInterval"
+	keyAuthorizeRemoteTxRequests = "AuthorizeRemoteTxRequests"
+
+	timestampLayout = "2006-01-02T15:04:05Z"
+)
+
+var (
+	errOffline  = errors.New("charge point is not connected")
+	errRejected = errors.New("registration rejected")
+)
+
+// stats counts the traffic of one simulated charge point.
+type stats struct {
+	sent     map[string]int
+	received map[string]int
+	// errors counts the failed CALLs.
+	errors int
+	// connectErrors counts the connection attempts that ended before the
+	// Central System accepted the BootNotification.
+	connectErrors int
+	sessions      int
+}
+
+// chargePoint is one simulated Charge Point.
+type chargePoint struct {
+	id     string
+	cfg    config
+	logger *log.Logger
+
+	mu            sync.Mutex
+	conn          *ocppj.Conn
+	booted        bool
+	statuses      map[int]string
+	sessions      map[int]*session
+	values        map[string]string
+	listVersion   int
+	meterRegister map[int]int
+	stats         stats
+
+	sessionsWG sync.WaitGroup
+	tasks      sync.WaitGroup
+}
+
+// newChargePoint returns a simulated Charge Point in its power-on state.
+func newChargePoint(id string, cfg config, logger *log.Logger) *chargePoint {
+	cp := &chargePoint{
+		id:            id,
+		cfg:           cfg,
+		logger:        logger,
+		mu:            sync.Mutex{},
+		conn:          nil,
+		booted:        false,
+		statuses:      map[int]string{},
+		sessions:      map[int]*session{},
+		values:        nil,
+		listVersion:   0,
+		meterRegister: map[int]int{},
+		stats: stats{
+			sent:          map[string]int{},
+			received:      map[string]int{},
+			errors:        0,
+			connectErrors: 0,
+			sessions:      0,
+		},
+		sessionsWG: sync.WaitGroup{},
+		tasks:      sync.WaitGroup{},
+	}
+
+	cp.values = cp.configurationDefaults()
+
+	return cp
+}
+
+// run connects, boots and runs the script, reconnecting after resets and
+// connection losses until ctx is done or, without -keep-alive, until the
+// script has finished. It gives up after -retries consecutive connection
+// attempts that fail before the boot is accepted.
+func (cp *chargePoint) run(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	defer cp.tasks.Wait()
+
+	scriptStarted := false
+	failures := 0
+
+	for ctx.Err() == nil {
+		booted := false
+
+		err := cp.connect(ctx, func(connCtx context.Context) {
+			booted = true
+
+			if scriptStarted {
+				return
+			}
+
+			scriptStarted = true
+
+			cp.tasks.Add(1)
+
+			go func() {
+				defer cp.tasks.Done()
+
+				cp.runScript(connCtx)
+
+				if !cp.cfg.keepAlive {
+					cancel()
+				}
+			}()
+		})
+		if err != nil && ctx.Err() == nil {
+			cp.logf("connection: %v", err)
+		}
+
+		switch {
+		case booted:
+			failures = 0
+		case ctx.Err() == nil:
+			failures++
+			cp.connectFailed()
+
+			if cp.cfg.retries > 0 && failures > cp.cfg.retries {
+				cp.logf("giving up after %d connection attempts", failures)
+
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(cp.cfg.reconnectDelay):
+		}
+	}
+}
+
+// connect runs one connection: dial, boot, report statuses and send
+// heartbeats until the connection closes. onBoot runs after a successful
+// boot.
+func (cp *chargePoint) connect(
+	ctx context.Context,
+	onBoot func(context.Context),
+) error {
+	transport, err := ocppj.Dial(ctx, cp.cfg.url, cp.id, nil)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+
+	conn := ocppj.NewConn(transport, ocppj.RoleChargePoint, cp.handle)
+
+	connCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() { done <- conn.Run(connCtx) }()
+
+	cp.setConn(conn)
+	defer cp.setConn(nil)
+
+	interval, err := cp.boot(connCtx)
+	if err != nil {
+		_ = conn.Close()
+		<-done
+
+		return err
+	}
+
+	for connectorId := 0; connectorId <= cp.cfg.connectors; connectorId++ {
+		cp.sendStatus(connCtx, connectorId, cp.status(connectorId))
+	}
+
+	onBoot(ctx)
+
+	cp.heartbeatLoop(connCtx, interval, conn.Done())
+
+	return <-done
+}
+
+// boot sends BootNotification until the Central System accepts it and
+// returns the heartbeat interval to use.
+func (cp *chargePoint) boot(ctx context.Context) (time.Duration, error) {
+	for {
+		status, interval, err := cp.bootNotification(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		if status == statusAccepted {
+			cp.mu.Lock()
+			cp.booted = true
+			cp.mu.Unlock()
+
+			if cp.cfg.heartbeatInterval > 0 {
+				interval = cp.cfg.heartbeatInterval
+			}
+
+			cp.setConfiguration(
+				keyHeartbeatInterval,
+				fmt.Sprint(int(interval.Seconds())),
+			)
+
+			return interval, nil
+		}
+
+		if interval <= 0 {
+			interval = cp.cfg.bootRetry
+		}
+
+		cp.logf("registration %s, retrying in %s", status, interval)
+
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("%w: %w", errRejected, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// bootNotification sends one BootNotification and returns the registration
+// status and interval.
+func (cp *chargePoint) bootNotification(
+	ctx context.Context,
+) (string, time.Duration, error) {
+	req, err := bootnotification.Req(bootnotification.ReqInput{
+		ChargePointVendor:       cp.cfg.vendor,
+		ChargePointModel:        cp.cfg.model,
+		ChargePointSerialNumber: &cp.id,
+		ChargeBoxSerialNumber:   nil,
+		FirmwareVersion:         nil,
+		Iccid:                   nil,
+		Imsi:                    nil,
+		MeterType:               nil,
+		MeterSerialNumber:       nil,
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("bootNotification: %w", err)
+	}
+
+	var conf bootnotification.ConfMessage
+
+	err = cp.call(ctx, ocppj.ActionBootNotification, req, &conf)
+	if err != nil {
+		return "", 0, err
+	}
+
+	interval := time.Duration(conf.Interval.Value()) * time.Second
+
+	return conf.Status.String(), interval, nil
+}
+
+// heartbeatLoop sends Heartbeat at the given interval until done closes.
+func (cp *chargePoint) heartbeatLoop(
+	ctx context.Context,
+	interval time.Duration,
+	done <-chan struct{},
+) {
+	if interval <= 0 {
+		<-done
+
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			cp.heartbeat(ctx)
+		}
+	}
+}
+
+// heartbeat sends one Heartbeat.
+func (cp *chargePoint) heartbeat(ctx context.Context) {
+	req, err := heartbeat.Req(heartbeat.ReqInput{})
+	if err != nil {
+		cp.logf("heartbeat: %v", err)
+
+		return
+	}
+
+	var conf heartbeat.ConfMessage
+
+	_ = cp.call(ctx, ocppj.ActionHeartbeat, req, &conf)
+}
+
+// sendStatus reports a connector status and remembers it.
+func (cp *chargePoint) sendStatus(
+	ctx context.Context,
+	connectorId int,
+	status string,
+) {
+	cp.mu.Lock()
+	cp.statuses[connectorId] = status
+	cp.mu.Unlock()
+
+	timestamp := now()
+
+	req, err := statusnotification.Req(statusnotification.ReqInput{
+		ConnectorId:     connectorId,
+		ErrorCode:       errorCodeNone,
+		Status:          status,
+		Info:            nil,
+		Timestamp:       &timestamp,
+		VendorId:        nil,
+		VendorErrorCode: nil,
+	})
+	if err != nil {
+		cp.logf("statusNotification: %v", err)
+
+		return
+	}
+
+	var conf statusnotification.ConfMessage
+
+	_ = cp.call(ctx, ocppj.ActionStatusNotification, req, &conf)
+}
+
+// sendDiagnosticsStatus sends a DiagnosticsStatusNotification.
+func (cp *chargePoint) sendDiagnosticsStatus(
+	ctx context.Context,
+	status string,
+) {
+	req, err := diagnosticsstatusnotification.Req(
+		diagnosticsstatusnotification.ReqInput{Status: status},
+	)
+	if err != nil {
+		cp.logf("diagnosticsStatusNotification: %v", err)
+
+		return
+	}
+
+	var conf diagnosticsstatusnotification.ConfMessage
+
+	_ = cp.call(ctx, ocppj.ActionDiagnosticsStatusNotification, req, &conf)
+}
+
+// sendFirmwareStatus sends a FirmwareStatusNotification.
+func (cp *chargePoint) sendFirmwareStatus(ctx context.Context, status string) {
+	req, err := firmwarestatusnotification.Req(
+		firmwarestatusnotification.ReqInput{Status: status},
+	)
+	if err != nil {
+		cp.logf("firmwareStatusNotification: %v", err)
+
+		return
+	}
+
+	var conf firmwarestatusnotification.ConfMessage
+
+	_ = cp.call(ctx, ocppj.ActionFirmwareStatusNotification, req, &conf)
+}
+
+// call sends a CALL over the current connection and records the outcome.
+func (cp *chargePoint) call(
+	ctx context.Context,
+	action string,
+	request any,
+	confirmation any,
+) error {
+	cp.mu.Lock()
+	conn := cp.conn
+	cp.stats.sent[action]++
+	cp.mu.Unlock()
+
+	if conn == nil {
+		return cp.failed(action, errOffline)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, cp.cfg.callTimeout)
+	defer cancel()
+
+	err := conn.Call(callCtx, action, request, confirmation)
+	if err != nil && ctx.Err() != nil {
+		// The simulation is shutting down; the CALL did not fail.
+		return err
+	}
+
+	if err != nil {
+		return cp.failed(action, err)
+	}
+
+	cp.logf("-> %s", action)
+
+	return nil
+}
+
+// failed records and logs a failed CALL.
+func (cp *chargePoint) failed(action string, err error) error {
+	cp.mu.Lock()
+	cp.stats.errors++
+	cp.mu.Unlock()
+
+	cp.logf("-> %s failed: %v", action, err)
+
+	return err
+}
+
+// connectFailed records a failed connection attempt.
+func (cp *chargePoint) connectFailed() {
+	cp.mu.Lock()
+	cp.stats.connectErrors++
+	cp.mu.Unlock()
+}
+
+// received records an incoming CALL.
+func (cp *chargePoint) received(action string) {
+	cp.mu.Lock()
+	cp.stats.received[action]++
+	cp.mu.Unlock()
+
+	cp.logf("<- %s", action)
+}
+
+// reboot stops all sessions and drops the connection; run reconnects and
+// boots again.
+func (cp *chargePoint) reboot(resetType string) {
+	cp.logf("reset (%s)", resetType)
+
+	reason := reasonSoftReset
+	if resetType == resetHard {
+		reason = reasonHardReset
+	}
+
+	cp.stopAll(reason)
+	cp.sessionsWG.Wait()
+
+	cp.mu.Lock()
+	conn := cp.conn
+	cp.booted = false
+	cp.mu.Unlock()
+
+	if conn != nil {
+		_ = conn.Close()
+	}
+}
+
+// setConn replaces the current connection.
+func (cp *chargePoint) setConn(conn *ocppj.Conn) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	cp.conn = conn
+}
+
+// status returns the last reported status of a connector.
+func (cp *chargePoint) status(connectorId int) string {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	status, ok := cp.statuses[connectorId]
+	if !ok {
+		return statusAvailable
+	}
+
+	return status
+}
+
+// configuration returns a copy of the configuration keys.
+func (cp *chargePoint) configuration() map[string]string {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	values := make(map[string]string, len(cp.values))
+	for key, value := range cp.values {
+		values[key] = value
+	}
+
+	return values
+}
+
+// setConfiguration stores a configuration key.
+func (cp *chargePoint) setConfiguration(key, value string) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	cp.values[key] = value
+}
+
+// localListVersion returns the version of the local authorization list.
+func (cp *chargePoint) localListVersion() int {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	return cp.listVersion
+}
+
+// setLocalListVersion stores the version of the local authorization list.
+func (cp *chargePoint) setLocalListVersion(version int) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	cp.listVersion = version
+}
+
+// snapshot returns a copy of the statistics.
+func (cp *chargePoint) snapshot() stats {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	copied := stats{
+		sent:          make(map[string]int, len(cp.stats.sent)),
+		received:      make(map[string]int, len(cp.stats.received)),
+		errors:        cp.stats.errors,
+		connectErrors: cp.stats.connectErrors,
+		sessions:      cp.stats.sessions,
+	}
+
+	for action, count := range cp.stats.sent {
+		copied.sent[action] = count
+	}
+
+	for action, count := range cp.stats.received {
+		copied.received[action] = count
+	}
+
+	return copied
+}
+
+// logf logs a line prefixed with the charge point id unless -quiet is set.
+func (cp *chargePoint) logf(format string, args ...any) {
+	if cp.cfg.quiet {
+		return
+	}
+
+	cp.logger.Printf(cp.id+" "+format, args...)
+}
+
+// now returns the current time as an OCPP DateTime string.
+func now() string {
+	return time.Now().UTC().Format(timestampLayout)
+}
+
+// sortedKeys returns the keys of a map in order.
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
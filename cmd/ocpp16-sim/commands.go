@@ -0,0 +1,405 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/cancelreservation"
+	"github.com/aasanchez/ocpp16messages/changeavailability"
+	"github.com/aasanchez/ocpp16messages/changeconfiguration"
+	"github.com/aasanchez/ocpp16messages/clearcache"
+	"github.com/aasanchez/ocpp16messages/clearchargingprofile"
+	"github.com/aasanchez/ocpp16messages/datatransfer"
+	"github.com/aasanchez/ocpp16messages/getcompositeschedule"
+	"github.com/aasanchez/ocpp16messages/getconfiguration"
+	"github.com/aasanchez/ocpp16messages/getdiagnostics"
+	"github.com/aasanchez/ocpp16messages/getlocallistversion"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/remotestoptransaction"
+	"github.com/aasanchez/ocpp16messages/reservenow"
+	"github.com/aasanchez/ocpp16messages/reset"
+	"github.com/aasanchez/ocpp16messages/sendlocallist"
+	"github.com/aasanchez/ocpp16messages/setchargingprofile"
+	"github.com/aasanchez/ocpp16messages/triggermessage"
+	"github.com/aasanchez/ocpp16messages/unlockconnector"
+	"github.com/aasanchez/ocpp16messages/updatefirmware"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	statusAccepted    = "Accepted"
+	statusRejected    = "Rejected"
+	statusUnlocked    = "Unlocked"
+	statusUnknownVend = "UnknownVendor"
+	statusOperative   = "Operative"
+
+	// followUpDelay lets the CALLRESULT of a remote command reach the
+	// Central System before the messages it triggers.
+	followUpDelay = 50 * time.Millisecond
+
+	diagnosticsFileName = "diagnostics.log"
+)
+
+// defaultStatuses are the answers used when no -respond option is given.
+var defaultStatuses = map[string]string{
+	ocppj.ActionCancelReservation:      statusAccepted,
+	ocppj.ActionChangeAvailability:     statusAccepted,
+	ocppj.ActionChangeConfiguration:    statusAccepted,
+	ocppj.ActionClearCache:             statusAccepted,
+	ocppj.ActionClearChargingProfile:   statusAccepted,
+	ocppj.ActionDataTransfer:           statusUnknownVend,
+	ocppj.ActionGetCompositeSchedule:   statusRejected,
+	ocppj.ActionRemoteStartTransaction: statusAccepted,
+	ocppj.ActionRemoteStopTransaction:  statusAccepted,
+	ocppj.ActionReserveNow:             statusAccepted,
+	ocppj.ActionReset:                  statusAccepted,
+	ocppj.ActionSendLocalList:          statusAccepted,
+	ocppj.ActionSetChargingProfile:     statusAccepted,
+	ocppj.ActionTriggerMessage:         statusAccepted,
+	ocppj.ActionUnlockConnector:        statusUnlocked,
+}
+
+// statusConfirmation builds the ConfMessage of an action whose
+// confirmation only carries a status, validating status with the package's
+// Conf constructor.
+//
+//nolint:cyclop,funlen // One case per remote command.
+func statusConfirmation(action, status string) (any, error) {
+	switch action {
+	case ocppj.ActionCancelReservation:
+		return cancelreservation.Conf(
+			cancelreservation.ConfInput{Status: status},
+		)
+	case ocppj.ActionChangeAvailability:
+		return changeavailability.Conf(
+			changeavailability.ConfInput{Status: status},
+		)
+	case ocppj.ActionChangeConfiguration:
+		return changeconfiguration.Conf(
+			changeconfiguration.ConfInput{Status: status},
+		)
+	case ocppj.ActionClearCache:
+		return clearcache.Conf(clearcache.ConfInput{Status: status})
+	case ocppj.ActionClearChargingProfile:
+		return clearchargingprofile.Conf(
+			clearchargingprofile.ConfInput{Status: status},
+		)
+	case ocppj.ActionDataTransfer:
+		return datatransfer.Conf(
+			datatransfer.ConfInput{Status: status, Data: nil},
+		)
+	case ocppj.ActionGetCompositeSchedule:
+		return getcompositeschedule.Conf(getcompositeschedule.ConfInput{
+			Status:           status,
+			ConnectorId:      nil,
+			ScheduleStart:    nil,
+			ChargingSchedule: nil,
+		})
+	case ocppj.ActionRemoteStartTransaction:
+		return remotestarttransaction.Conf(
+			remotestarttransaction.ConfInput{Status: status},
+		)
+	case ocppj.ActionRemoteStopTransaction:
+		return remotestoptransaction.Conf(
+			remotestoptransaction.ConfInput{Status: status},
+		)
+	case ocppj.ActionReserveNow:
+		return reservenow.Conf(reservenow.ConfInput{Status: status})
+	case ocppj.ActionReset:
+		return reset.Conf(reset.ConfInput{Status: status})
+	case ocppj.ActionSendLocalList:
+		return sendlocallist.Conf(sendlocallist.ConfInput{Status: status})
+	case ocppj.ActionSetChargingProfile:
+		return setchargingprofile.Conf(
+			setchargingprofile.ConfInput{Status: status},
+		)
+	case ocppj.ActionTriggerMessage:
+		return triggermessage.Conf(triggermessage.ConfInput{Status: status})
+	case ocppj.ActionUnlockConnector:
+		return unlockconnector.Conf(unlockconnector.ConfInput{Status: status})
+	default:
+		return nil, fmt.Errorf(
+			"%w: %s.conf has no status; only error:<code> is supported",
+			errInvalidValue,
+			action,
+		)
+	}
+}
+
+// configuredResponse returns the confirmation configured for an action, or
+// the CALLERROR requested with "error:<code>".
+func (cp *chargePoint) configuredResponse(action string) (any, string, error) {
+	status, ok := cp.cfg.responses[action]
+	if !ok {
+		status = defaultStatuses[action]
+	}
+
+	code, isError := strings.CutPrefix(status, errorResponsePrefix)
+	if isError {
+		return nil, "", ocppj.NewError(
+			ocppj.ErrorCode(code),
+			"configured by -respond",
+		)
+	}
+
+	if status == "" {
+		return nil, "", nil
+	}
+
+	conf, err := statusConfirmation(action, status)
+	if err != nil {
+		return nil, "", ocppj.NewError(ocppj.InternalError, err.Error())
+	}
+
+	return conf, status, nil
+}
+
+// handle answers the remote commands of the Central System.
+//
+//nolint:cyclop,funlen // One case per remote command.
+func (cp *chargePoint) handle(
+	ctx context.Context,
+	action string,
+	request any,
+) (any, error) {
+	cp.received(action)
+
+	conf, status, err := cp.configuredResponse(action)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := status == statusAccepted
+
+	switch req := request.(type) {
+	case remotestarttransaction.ReqMessage:
+		return cp.remoteStart(ctx, req, conf, accepted)
+	case remotestoptransaction.ReqMessage:
+		return cp.remoteStop(req, conf, accepted)
+	case reset.ReqMessage:
+		if accepted {
+			cp.after(func() { cp.reboot(req.Type.String()) })
+		}
+	case changeavailability.ReqMessage:
+		if accepted {
+			cp.after(func() { cp.changeAvailability(ctx, req) })
+		}
+	case changeconfiguration.ReqMessage:
+		if accepted {
+			cp.setConfiguration(req.Key.String(), req.Value.String())
+		}
+	case triggermessage.ReqMessage:
+		if accepted {
+			cp.after(func() { cp.trigger(ctx, req) })
+		}
+	case getconfiguration.ReqMessage:
+		return cp.getConfiguration(req)
+	case getdiagnostics.ReqMessage:
+		cp.after(func() { cp.uploadDiagnostics(ctx) })
+
+		name := diagnosticsFileName
+
+		return getdiagnostics.Conf(getdiagnostics.ConfInput{FileName: &name})
+	case getlocallistversion.ReqMessage:
+		return getlocallistversion.Conf(
+			getlocallistversion.ConfInput{ListVersion: cp.localListVersion()},
+		)
+	case sendlocallist.ReqMessage:
+		if accepted {
+			cp.setLocalListVersion(int(req.ListVersion.Value()))
+		}
+	case updatefirmware.ReqMessage:
+		cp.after(func() { cp.installFirmware(ctx) })
+
+		return updatefirmware.Conf(updatefirmware.ConfInput{})
+	}
+
+	return conf, nil
+}
+
+// remoteStart answers RemoteStartTransaction and starts a session on an
+// idle connector without a prior Authorize.
+func (cp *chargePoint) remoteStart(
+	ctx context.Context,
+	req remotestarttransaction.ReqMessage,
+	conf any,
+	accepted bool,
+) (any, error) {
+	if !accepted {
+		return conf, nil
+	}
+
+	connectorId := 0
+	if req.ConnectorId != nil {
+		connectorId = int(req.ConnectorId.Value())
+	}
+
+	connectorId, ok := cp.reserveConnector(connectorId)
+	if !ok {
+		return remotestarttransaction.Conf(
+			remotestarttransaction.ConfInput{Status: statusRejected},
+		)
+	}
+
+	idTag := req.IdTag.String()
+
+	cp.after(func() {
+		cp.runSession(ctx, connectorId, idTag, false)
+	})
+
+	return conf, nil
+}
+
+// remoteStop answers RemoteStopTransaction and stops the matching session.
+func (cp *chargePoint) remoteStop(
+	req remotestoptransaction.ReqMessage,
+	conf any,
+	accepted bool,
+) (any, error) {
+	transactionId := int(req.TransactionId.Value())
+
+	if accepted && !cp.stopTransaction(transactionId, reasonRemote) {
+		return remotestoptransaction.Conf(
+			remotestoptransaction.ConfInput{Status: statusRejected},
+		)
+	}
+
+	return conf, nil
+}
+
+// changeAvailability applies an accepted ChangeAvailability and reports the
+// resulting connector status.
+func (cp *chargePoint) changeAvailability(
+	ctx context.Context,
+	req changeavailability.ReqMessage,
+) {
+	status := statusUnavailable
+	if req.Type.String() == statusOperative {
+		status = statusAvailable
+	}
+
+	connectorId := int(req.ConnectorId.Value())
+	if connectorId == 0 {
+		for id := 0; id <= cp.cfg.connectors; id++ {
+			cp.sendStatus(ctx, id, status)
+		}
+
+		return
+	}
+
+	cp.sendStatus(ctx, connectorId, status)
+}
+
+// trigger sends the message requested by TriggerMessage.
+func (cp *chargePoint) trigger(
+	ctx context.Context,
+	req triggermessage.ReqMessage,
+) {
+	connectorId := 0
+	if req.ConnectorId != nil {
+		connectorId = int(req.ConnectorId.Value())
+	}
+
+	switch req.RequestedMessage.String() {
+	case ocppj.ActionBootNotification:
+		_, _, _ = cp.bootNotification(ctx)
+	case ocppj.ActionHeartbeat:
+		cp.heartbeat(ctx)
+	case ocppj.ActionStatusNotification:
+		cp.sendStatus(ctx, connectorId, cp.status(connectorId))
+	case ocppj.ActionMeterValues:
+		cp.sendMeterValues(ctx, max(connectorId, 1), nil)
+	case ocppj.ActionDiagnosticsStatusNotification:
+		cp.sendDiagnosticsStatus(ctx, "Idle")
+	case ocppj.ActionFirmwareStatusNotification:
+		cp.sendFirmwareStatus(ctx, "Idle")
+	}
+}
+
+// getConfiguration answers GetConfiguration from the simulated
+// configuration keys.
+func (cp *chargePoint) getConfiguration(
+	req getconfiguration.ReqMessage,
+) (any, error) {
+	configuration := cp.configuration()
+
+	var (
+		known   []types.KeyValueInput
+		unknown []string
+	)
+
+	keys := make([]string, 0, len(req.Key))
+	for _, key := range req.Key {
+		keys = append(keys, key.String())
+	}
+
+	if len(keys) == 0 {
+		keys = sortedKeys(configuration)
+	}
+
+	for _, key := range keys {
+		value, ok := configuration[key]
+		if !ok {
+			unknown = append(unknown, key)
+
+			continue
+		}
+
+		known = append(known, types.KeyValueInput{
+			Key:      key,
+			Readonly: key == keyNumberOfConnectors,
+			Value:    &value,
+		})
+	}
+
+	return getconfiguration.Conf(getconfiguration.ConfInput{
+		ConfigurationKey: known,
+		UnknownKey:       unknown,
+	})
+}
+
+// uploadDiagnostics simulates a successful diagnostics upload.
+func (cp *chargePoint) uploadDiagnostics(ctx context.Context) {
+	cp.sendDiagnosticsStatus(ctx, "Uploading")
+	cp.sendDiagnosticsStatus(ctx, "Uploaded")
+}
+
+// installFirmware simulates a successful firmware update.
+func (cp *chargePoint) installFirmware(ctx context.Context) {
+	for _, status := range []string{
+		"Downloading",
+		"Downloaded",
+		"Installing",
+		"Installed",
+	} {
+		cp.sendFirmwareStatus(ctx, status)
+	}
+}
+
+// after runs fn once the current CALLRESULT has been sent.
+func (cp *chargePoint) after(fn func()) {
+	cp.tasks.Add(1)
+
+	time.AfterFunc(followUpDelay, func() {
+		defer cp.tasks.Done()
+
+		fn()
+	})
+}
+
+// configurationDefaults returns the configuration keys reported by
+// GetConfiguration before any ChangeConfiguration.
+func (cp *chargePoint) configurationDefaults() map[string]string {
+	return map[string]string{
+		keyNumberOfConnectors: strconv.Itoa(cp.cfg.connectors),
+		keyHeartbeatInterval:  "0",
+		keyMeterValueSampleInterval: strconv.Itoa(
+			int(cp.cfg.meterInterval.Seconds()),
+		),
+		keyAuthorizeRemoteTxRequests: "false",
+	}
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+)
+
+const (
+	defaultIdPrefix        = "SIM"
+	defaultCount           = 1
+	defaultConnectors      = 2
+	defaultVendor          = "ocpp16messages"
+	defaultModel           = "ocpp16-sim"
+	defaultIdTags          = "SIM-TAG-001"
+	defaultSessions        = 1
+	defaultSessionDuration = time.Minute
+	defaultMeterInterval   = 10 * time.Second
+	defaultPower           = 7400
+	defaultReconnect       = 5 * time.Second
+	defaultBootRetry       = 30 * time.Second
+	defaultCallTimeout     = 30 * time.Second
+	defaultRetries         = 5
+	defaultMockAddr        = "127.0.0.1:0"
+	defaultMockInterval    = 300
+
+	// errorResponsePrefix marks a -respond value that answers with a
+	// CALLERROR instead of a confirmation, e.g. "Reset=error:NotSupported".
+	errorResponsePrefix = "error:"
+	responseSeparator   = "="
+	listSeparator       = ","
+	minCount            = 1
+)
+
+var (
+	errMissingURL   = errors.New("either -url or -mock is required")
+	errInvalidValue = errors.New("invalid value")
+)
+
+// config holds the command line options.
+type config struct {
+	url               string
+	idPrefix          string
+	count             int
+	connectors        int
+	vendor            string
+	model             string
+	idTags            []string
+	sessions          int
+	sessionDuration   time.Duration
+	meterInterval     time.Duration
+	heartbeatInterval time.Duration
+	power             int
+	reconnectDelay    time.Duration
+	retries           int
+	bootRetry         time.Duration
+	callTimeout       time.Duration
+	keepAlive         bool
+	mock              bool
+	mockAddr          string
+	mockInterval      int
+	responses         responses
+	quiet             bool
+}
+
+// responses maps an action to the status (or "error:<code>") the simulator
+// answers it with. It implements flag.Value for the repeatable -respond flag.
+type responses map[string]string
+
+// String implements flag.Value.
+func (r responses) String() string {
+	pairs := make([]string, 0, len(r))
+	for action, status := range r {
+		pairs = append(pairs, action+responseSeparator+status)
+	}
+
+	return strings.Join(pairs, listSeparator)
+}
+
+// Set implements flag.Value.
+func (r responses) Set(value string) error {
+	action, status, found := strings.Cut(value, responseSeparator)
+	if !found || action == "" || status == "" {
+		return fmt.Errorf(
+			"-respond %q: %w: want Action=Status",
+			value,
+			errInvalidValue,
+		)
+	}
+
+	if !ocppj.Initiates(ocppj.RoleCentralSystem, action) {
+		return fmt.Errorf(
+			"-respond %q: %w: %s is not sent by a Central System",
+			value,
+			errInvalidValue,
+			action,
+		)
+	}
+
+	r[action] = status
+
+	return nil
+}
+
+// parseConfig parses the command line arguments.
+func parseConfig(args []string, output io.Writer) (config, error) {
+	cfg := config{
+		url:               "",
+		idPrefix:          "",
+		count:             0,
+		connectors:        0,
+		vendor:            "",
+		model:             "",
+		idTags:            nil,
+		sessions:          0,
+		sessionDuration:   0,
+		meterInterval:     0,
+		heartbeatInterval: 0,
+		power:             0,
+		reconnectDelay:    0,
+		retries:           0,
+		bootRetry:         0,
+		callTimeout:       0,
+		keepAlive:         false,
+		mock:              false,
+		mockAddr:          "",
+		mockInterval:      0,
+		responses:         responses{},
+		quiet:             false,
+	}
+
+	var idTags string
+
+	flags := flag.NewFlagSet("ocpp16-sim", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	flags.StringVar(
+		&cfg.url,
+		"url",
+		"",
+		"Central System URL, e.g. ws://localhost:9000/ocpp",
+	)
+	flags.StringVar(
+		&cfg.idPrefix,
+		"id",
+		defaultIdPrefix,
+		"charge point id prefix; ids are <prefix>001, <prefix>002, ...",
+	)
+	flags.IntVar(
+		&cfg.count,
+		"count",
+		defaultCount,
+		"number of simulated charge points",
+	)
+	flags.IntVar(
+		&cfg.connectors,
+		"connectors",
+		defaultConnectors,
+		"connectors per charge point",
+	)
+	flags.StringVar(
+		&cfg.vendor,
+		"vendor",
+		defaultVendor,
+		"BootNotification chargePointVendor",
+	)
+	flags.StringVar(
+		&cfg.model,
+		"model",
+		defaultModel,
+		"BootNotification chargePointModel",
+	)
+	flags.StringVar(
+		&idTags,
+		"idtags",
+		defaultIdTags,
+		"comma separated idTags used by scripted sessions",
+	)
+	flags.IntVar(
+		&cfg.sessions,
+		"sessions",
+		defaultSessions,
+		"scripted sessions per charge point",
+	)
+	flags.DurationVar(
+		&cfg.sessionDuration,
+		"session-duration",
+		defaultSessionDuration,
+		"length of a scripted session",
+	)
+	flags.DurationVar(
+		&cfg.meterInterval,
+		"meter-interval",
+		defaultMeterInterval,
+		"MeterValues interval during a session",
+	)
+	flags.DurationVar(
+		&cfg.heartbeatInterval,
+		"heartbeat",
+		0,
+		"heartbeat interval; 0 uses BootNotification.conf interval",
+	)
+	flags.IntVar(
+		&cfg.power,
+		"power",
+		defaultPower,
+		"simulated charging power in W",
+	)
+	flags.DurationVar(
+		&cfg.reconnectDelay,
+		"reconnect",
+		defaultReconnect,
+		"delay before reconnecting after a disconnect or reset",
+	)
+	flags.IntVar(
+		&cfg.retries,
+		"retries",
+		defaultRetries,
+		"failed connection attempts in a row before a charge point gives "+
+			"up; 0 retries forever",
+	)
+	flags.DurationVar(
+		&cfg.bootRetry,
+		"boot-retry",
+		defaultBootRetry,
+		"BootNotification retry when Pending/Rejected carry no interval",
+	)
+	flags.DurationVar(
+		&cfg.callTimeout,
+		"timeout",
+		defaultCallTimeout,
+		"timeout for each CALL",
+	)
+	flags.BoolVar(
+		&cfg.keepAlive,
+		"keep-alive",
+		false,
+		"stay connected after the scripted sessions to answer remote commands",
+	)
+	flags.BoolVar(
+		&cfg.mock,
+		"mock",
+		false,
+		"start a local mock Central System and connect to it",
+	)
+	flags.StringVar(
+		&cfg.mockAddr,
+		"mock-addr",
+		defaultMockAddr,
+		"listen address of the mock Central System",
+	)
+	flags.IntVar(
+		&cfg.mockInterval,
+		"mock-interval",
+		defaultMockInterval,
+		"heartbeat interval in seconds sent by the mock Central System",
+	)
+	flags.Var(
+		cfg.responses,
+		"respond",
+		"answer a remote command with a status, e.g. Reset=Rejected or "+
+			"UnlockConnector=error:NotSupported (repeatable)",
+	)
+	flags.BoolVar(&cfg.quiet, "quiet", false, "only print the summary")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return config{}, fmt.Errorf("flags: %w", err)
+	}
+
+	cfg.idTags = splitList(idTags)
+
+	err = cfg.validate()
+	if err != nil {
+		return config{}, err
+	}
+
+	return cfg, nil
+}
+
+// validate checks option combinations and that every -respond status is
+// accepted by the matching confirmation constructor.
+func (c config) validate() error {
+	var errs []error
+
+	if c.url == "" && !c.mock {
+		errs = append(errs, errMissingURL)
+	}
+
+	if c.count < minCount {
+		errs = append(errs, fmt.Errorf("-count: %w", errInvalidValue))
+	}
+
+	if c.retries < 0 {
+		errs = append(errs, fmt.Errorf("-retries: %w", errInvalidValue))
+	}
+
+	if c.connectors < minCount {
+		errs = append(errs, fmt.Errorf("-connectors: %w", errInvalidValue))
+	}
+
+	if len(c.idTags) == 0 {
+		errs = append(errs, fmt.Errorf("-idtags: %w", errInvalidValue))
+	}
+
+	if c.meterInterval <= 0 || c.sessionDuration <= 0 {
+		errs = append(
+			errs,
+			fmt.Errorf(
+				"-meter-interval/-session-duration: %w",
+				errInvalidValue,
+			),
+		)
+	}
+
+	for action, status := range c.responses {
+		if strings.HasPrefix(status, errorResponsePrefix) {
+			continue
+		}
+
+		_, err := statusConfirmation(action, status)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("-respond %s: %w", action, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// splitList splits a comma separated flag value, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+
+	for _, item := range strings.Split(value, listSeparator) {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,185 @@
+// Command ocpp16-sim simulates one or more OCPP 1.6 JSON Charge Points
+// against a Central System, for load and integration testing of a CSMS
+// without hardware.
+//
+// Every simulated charge point connects over WebSocket, sends
+// BootNotification until it is accepted, reports the status of its
+// connectors, sends heartbeats and runs scripted charging sessions:
+// Authorize, StartTransaction, periodic MeterValues and StopTransaction,
+// with the StatusNotifications a real charge point would send. Messages are
+// built with the constructors of this module, so every request sent and
+// every confirmation received is validated.
+//
+// Remote commands of the Central System are answered: RemoteStart and
+// RemoteStopTransaction start and stop sessions, Reset reconnects and boots
+// again, ChangeAvailability, TriggerMessage, GetConfiguration,
+// ChangeConfiguration, GetDiagnostics and UpdateFirmware behave as on a
+// charge point. The answer to any command can be overridden with -respond,
+// e.g. -respond Reset=Rejected or -respond UnlockConnector=error:NotSupported.
+//
+// Usage:
+//
+//	ocpp16-sim -url ws://localhost:9000/ocpp -count 10 -sessions 3
+//	ocpp16-sim -mock -session-duration 5s -meter-interval 1s
+//
+// With -mock a local Central System is started and the simulated charge
+// points connect to it, which exercises the whole stack offline. A summary
+// of the messages exchanged is printed when the simulation ends, after the
+// scripted sessions or on interrupt.
+//
+// A charge point that cannot connect, or whose BootNotification fails, tries
+// again after -reconnect and gives up after -retries attempts in a row. The
+// command exits with status 1 when any CALL or connection attempt failed.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+
+	idDigits = 3
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(
+		context.Background(),
+		os.Interrupt,
+		syscall.SIGTERM,
+	)
+
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+
+	stop()
+	os.Exit(code)
+}
+
+// run runs the simulator and returns the exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	cfg, err := parseConfig(args, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+
+		return exitUsage
+	}
+
+	logger := log.New(stderr, "", log.LstdFlags|log.Lmicroseconds)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if cfg.mock {
+		mock := newMockCSMS(cfg.mockInterval, logger, cfg.quiet)
+
+		cfg.url, err = mock.start(ctx, cfg.mockAddr)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+
+			return exitError
+		}
+
+		logger.Printf("mock Central System listening on %s", cfg.url)
+	}
+
+	chargePoints := simulate(ctx, cfg, logger)
+
+	failures := printSummary(stdout, chargePoints)
+	if failures > 0 {
+		_, _ = fmt.Fprintf(stderr, "%d failures\n", failures)
+
+		return exitError
+	}
+
+	return exitOK
+}
+
+// simulate runs cfg.count charge points concurrently until they finish.
+func simulate(
+	ctx context.Context,
+	cfg config,
+	logger *log.Logger,
+) []*chargePoint {
+	chargePoints := make([]*chargePoint, 0, cfg.count)
+
+	var wg sync.WaitGroup
+
+	for index := range cfg.count {
+		id := fmt.Sprintf("%s%0*d", cfg.idPrefix, idDigits, index+1)
+		cp := newChargePoint(id, cfg, logger)
+		chargePoints = append(chargePoints, cp)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			cp.run(ctx)
+		}()
+	}
+
+	wg.Wait()
+
+	return chargePoints
+}
+
+// printSummary prints the messages exchanged by each charge point and
+// returns the number of failed CALLs and connection attempts.
+func printSummary(output io.Writer, chargePoints []*chargePoint) int {
+	failures := 0
+
+	for _, cp := range chargePoints {
+		snapshot := cp.snapshot()
+		failures += snapshot.errors + snapshot.connectErrors
+
+		_, _ = fmt.Fprintf(
+			output,
+			"%s: %d sessions, %d errors, %d connection errors\n",
+			cp.id,
+			snapshot.sessions,
+			snapshot.errors,
+			snapshot.connectErrors,
+		)
+
+		printCounts(output, "sent", snapshot.sent)
+		printCounts(output, "received", snapshot.received)
+	}
+
+	return failures
+}
+
+// printCounts prints per-action message counts in action order.
+func printCounts(output io.Writer, label string, counts map[string]int) {
+	actions := make([]string, 0, len(counts))
+	for action := range counts {
+		actions = append(actions, action)
+	}
+
+	slices.Sort(actions)
+
+	for _, action := range actions {
+		_, _ = fmt.Fprintf(
+			output,
+			"  %-8s %-32s %d\n",
+			label,
+			action,
+			counts[action],
+		)
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/datatransfer"
+	"github.com/aasanchez/ocpp16messages/diagnosticsstatusnotification"
+	"github.com/aasanchez/ocpp16messages/firmwarestatusnotification"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/metervalues"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/starttransaction"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	"github.com/aasanchez/ocpp16messages/stoptransaction"
+)
+
+const (
+	mockPath              = "/ocpp/"
+	mockReadHeaderTimeout = 10 * time.Second
+	firstTransactionId    = 1
+)
+
+var errNotConnected = errors.New("charge point not connected")
+
+// mockCSMS is a minimal Central System that accepts every Charge Point and
+// idTag, hands out transaction ids and counts the CALLs it receives.
+type mockCSMS struct {
+	interval int
+	logger   *log.Logger
+	quiet    bool
+
+	mu            sync.Mutex
+	conns         map[string]*ocppj.Conn
+	counts        map[string]int
+	transactionId int
+	connected     chan string
+}
+
+// newMockCSMS returns a mock Central System that sends interval as the
+// heartbeat interval.
+func newMockCSMS(interval int, logger *log.Logger, quiet bool) *mockCSMS {
+	return &mockCSMS{
+		interval:      interval,
+		logger:        logger,
+		quiet:         quiet,
+		mu:            sync.Mutex{},
+		conns:         map[string]*ocppj.Conn{},
+		counts:        map[string]int{},
+		transactionId: firstTransactionId,
+		connected:     make(chan string, 1),
+	}
+}
+
+// start listens on addr and serves Charge Points until ctx is done. It
+// returns the Central System URL to dial.
+func (m *mockCSMS) start(ctx context.Context, addr string) (string, error) {
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("mock: %w", err)
+	}
+
+	server := &http.Server{
+		Handler:           m,
+		ReadHeaderTimeout: mockReadHeaderTimeout,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	go func() { _ = server.Serve(listener) }()
+
+	context.AfterFunc(ctx, func() {
+		_ = server.Close()
+
+		m.closeAll()
+	})
+
+	return "ws://" + listener.Addr().String() + mockPath, nil
+}
+
+// ServeHTTP accepts one Charge Point connection and serves it until it
+// closes.
+func (m *mockCSMS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	transport, err := ocppj.Accept(w, r)
+	if err != nil {
+		return
+	}
+
+	id := ocppj.ChargePointId(r)
+	conn := ocppj.NewConn(transport, ocppj.RoleCentralSystem, m.handle)
+
+	m.mu.Lock()
+	m.conns[id] = conn
+	m.mu.Unlock()
+
+	select {
+	case m.connected <- id:
+	default:
+	}
+
+	_ = conn.Run(r.Context())
+
+	m.mu.Lock()
+	if m.conns[id] == conn {
+		delete(m.conns, id)
+	}
+	m.mu.Unlock()
+}
+
+// handle answers the CALLs of a Charge Point.
+func (m *mockCSMS) handle(
+	_ context.Context,
+	action string,
+	request any,
+) (any, error) {
+	m.mu.Lock()
+	m.counts[action]++
+	m.mu.Unlock()
+
+	if !m.quiet {
+		m.logger.Printf("mock <- %s", action)
+	}
+
+	switch request.(type) {
+	case bootnotification.ReqMessage:
+		return bootnotification.Conf(bootnotification.ConfInput{
+			Status:      statusAccepted,
+			CurrentTime: now(),
+			Interval:    m.interval,
+		})
+	case heartbeat.ReqMessage:
+		return heartbeat.Conf(heartbeat.ConfInput{CurrentTime: now()})
+	case authorize.ReqMessage:
+		return authorize.Conf(authorize.ConfInput{
+			Status:      statusAccepted,
+			ExpiryDate:  nil,
+			ParentIdTag: nil,
+		})
+	case starttransaction.ReqMessage:
+		return starttransaction.Conf(starttransaction.ConfInput{
+			TransactionId: m.nextTransactionId(),
+			Status:        statusAccepted,
+			ExpiryDate:    nil,
+			ParentIdTag:   nil,
+		})
+	case stoptransaction.ReqMessage:
+		status := statusAccepted
+
+		return stoptransaction.Conf(stoptransaction.ConfInput{
+			Status:      &status,
+			ExpiryDate:  nil,
+			ParentIdTag: nil,
+		})
+	case metervalues.ReqMessage:
+		return metervalues.Conf(metervalues.ConfInput{})
+	case statusnotification.ReqMessage:
+		return statusnotification.Conf(statusnotification.ConfInput{})
+	case diagnosticsstatusnotification.ReqMessage:
+		return diagnosticsstatusnotification.Conf(
+			diagnosticsstatusnotification.ConfInput{},
+		)
+	case firmwarestatusnotification.ReqMessage:
+		return firmwarestatusnotification.Conf(
+			firmwarestatusnotification.ConfInput{},
+		)
+	case datatransfer.ReqMessage:
+		return datatransfer.Conf(
+			datatransfer.ConfInput{Status: statusUnknownVend, Data: nil},
+		)
+	default:
+		return nil, ocppj.NewError(
+			ocppj.NotSupported,
+			fmt.Sprintf("mock does not support %s", action),
+		)
+	}
+}
+
+// call sends a CALL to a connected Charge Point.
+func (m *mockCSMS) call(
+	ctx context.Context,
+	chargePointId string,
+	action string,
+	request any,
+	confirmation any,
+) error {
+	m.mu.Lock()
+	conn, ok := m.conns[chargePointId]
+	m.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%s: %w", chargePointId, errNotConnected)
+	}
+
+	err := conn.Call(ctx, action, request, confirmation)
+	if err != nil {
+		return fmt.Errorf("%s: %w", chargePointId, err)
+	}
+
+	return nil
+}
+
+// count returns how many CALLs of action were received.
+func (m *mockCSMS) count(action string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.counts[action]
+}
+
+// nextTransactionId returns a new transaction id.
+func (m *mockCSMS) nextTransactionId() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := m.transactionId
+	m.transactionId++
+
+	return id
+}
+
+// closeAll closes every Charge Point connection.
+func (m *mockCSMS) closeAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, conn := range m.conns {
+		_ = conn.Close()
+	}
+}
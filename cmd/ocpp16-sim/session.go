@@ -0,0 +1,403 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/metervalues"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/starttransaction"
+	"github.com/aasanchez/ocpp16messages/stoptransaction"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	reasonLocal        = "Local"
+	reasonRemote       = "Remote"
+	reasonHardReset    = "HardReset"
+	reasonSoftReset    = "SoftReset"
+	reasonDeAuthorized = "DeAuthorized"
+	resetHard          = "Hard"
+
+	measurandEnergy = "Energy.Active.Import.Register"
+	measurandPower  = "Power.Active.Import"
+	unitWh          = "Wh"
+	unitW           = "W"
+	contextPeriodic = "Sample.Periodic"
+	contextTrigger  = "Trigger"
+
+	// maxMeterWh is the largest meter reading StartTransaction and
+	// StopTransaction can carry; the simulated register stops there.
+	maxMeterWh = 65535
+
+	// pendingTransaction marks a reserved connector whose
+	// StartTransaction has not been confirmed yet.
+	pendingTransaction = -1
+)
+
+// session is a charging session on one connector.
+type session struct {
+	transactionId int
+	stop          chan string
+}
+
+// signal asks the session to stop with reason. Only the first reason is
+// kept.
+func (s *session) signal(reason string) {
+	select {
+	case s.stop <- reason:
+	default:
+	}
+}
+
+// runScript runs the scripted sessions one after another, each on the
+// first idle connector and with the next idTag of -idtags.
+func (cp *chargePoint) runScript(ctx context.Context) {
+	for index := range cp.cfg.sessions {
+		if ctx.Err() != nil {
+			return
+		}
+
+		connectorId, ok := cp.reserveConnector(0)
+		if !ok {
+			cp.logf("session %d: no idle connector", index+1)
+
+			continue
+		}
+
+		idTag := cp.cfg.idTags[index%len(cp.cfg.idTags)]
+
+		cp.runSession(ctx, connectorId, idTag, true)
+	}
+}
+
+// reserveConnector claims connectorId, or the first idle connector when
+// connectorId is 0, for a new session. It reports false when the connector
+// is unknown, unavailable or busy.
+func (cp *chargePoint) reserveConnector(connectorId int) (int, bool) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	idle := func(id int) bool {
+		_, busy := cp.sessions[id]
+		status, reported := cp.statuses[id]
+
+		return !busy && (!reported || status == statusAvailable)
+	}
+
+	if connectorId == 0 {
+		for id := 1; id <= cp.cfg.connectors; id++ {
+			if idle(id) {
+				connectorId = id
+
+				break
+			}
+		}
+	}
+
+	if connectorId < 1 || connectorId > cp.cfg.connectors ||
+		!idle(connectorId) {
+		return 0, false
+	}
+
+	cp.sessions[connectorId] = &session{
+		transactionId: pendingTransaction,
+		stop:          make(chan string, 1),
+	}
+	cp.sessionsWG.Add(1)
+
+	return connectorId, true
+}
+
+// runSession charges on a reserved connector: Authorize (when
+// authorizeFirst is set), StartTransaction, periodic MeterValues and
+// StopTransaction, with the matching StatusNotifications. The session ends
+// after -session-duration, on RemoteStopTransaction or on Reset.
+func (cp *chargePoint) runSession(
+	ctx context.Context,
+	connectorId int,
+	idTag string,
+	authorizeFirst bool,
+) {
+	defer cp.release(connectorId)
+
+	if authorizeFirst && !cp.authorize(ctx, idTag) {
+		return
+	}
+
+	cp.sendStatus(ctx, connectorId, statusPreparing)
+
+	transactionId, accepted, err := cp.startTransaction(
+		ctx,
+		connectorId,
+		idTag,
+	)
+	if err != nil {
+		cp.sendStatus(ctx, connectorId, statusAvailable)
+
+		return
+	}
+
+	current := cp.startSession(connectorId, transactionId)
+
+	reason := reasonDeAuthorized
+	if accepted {
+		cp.sendStatus(ctx, connectorId, statusCharging)
+
+		reason = cp.charge(ctx, connectorId, current)
+	}
+
+	stopCtx := context.WithoutCancel(ctx)
+
+	cp.stopSession(stopCtx, connectorId, transactionId, idTag, reason)
+	cp.sendStatus(stopCtx, connectorId, statusFinishing)
+	cp.sendStatus(stopCtx, connectorId, statusAvailable)
+}
+
+// charge sends MeterValues until the session ends and returns the stop
+// reason.
+func (cp *chargePoint) charge(
+	ctx context.Context,
+	connectorId int,
+	current *session,
+) string {
+	ticker := time.NewTicker(cp.cfg.meterInterval)
+	defer ticker.Stop()
+
+	timer := time.NewTimer(cp.cfg.sessionDuration)
+	defer timer.Stop()
+
+	last := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return reasonLocal
+		case <-timer.C:
+			cp.addEnergy(connectorId, time.Since(last))
+
+			return reasonLocal
+		case reason := <-current.stop:
+			cp.addEnergy(connectorId, time.Since(last))
+
+			return reason
+		case tick := <-ticker.C:
+			cp.addEnergy(connectorId, tick.Sub(last))
+			last = tick
+
+			cp.sendMeterValues(ctx, connectorId, &current.transactionId)
+		}
+	}
+}
+
+// authorize sends Authorize and reports whether idTag was accepted.
+func (cp *chargePoint) authorize(ctx context.Context, idTag string) bool {
+	req, err := authorize.Req(authorize.ReqInput{IdTag: idTag})
+	if err != nil {
+		cp.logf("authorize: %v", err)
+
+		return false
+	}
+
+	var conf authorize.ConfMessage
+
+	err = cp.call(ctx, ocppj.ActionAuthorize, req, &conf)
+	if err != nil {
+		return false
+	}
+
+	return conf.IdTagInfo.Status().String() == statusAccepted
+}
+
+// startTransaction sends StartTransaction and returns the transaction id
+// and whether idTag was accepted.
+func (cp *chargePoint) startTransaction(
+	ctx context.Context,
+	connectorId int,
+	idTag string,
+) (int, bool, error) {
+	req, err := starttransaction.Req(starttransaction.ReqInput{
+		ConnectorId:   connectorId,
+		IdTag:         idTag,
+		MeterStart:    cp.meter(connectorId),
+		Timestamp:     now(),
+		ReservationId: nil,
+	})
+	if err != nil {
+		cp.logf("startTransaction: %v", err)
+
+		return 0, false, err
+	}
+
+	var conf starttransaction.ConfMessage
+
+	err = cp.call(ctx, ocppj.ActionStartTransaction, req, &conf)
+	if err != nil {
+		return 0, false, err
+	}
+
+	accepted := conf.IdTagInfo.Status().String() == statusAccepted
+
+	return int(conf.TransactionId.Value()), accepted, nil
+}
+
+// stopSession sends StopTransaction.
+func (cp *chargePoint) stopSession(
+	ctx context.Context,
+	connectorId int,
+	transactionId int,
+	idTag string,
+	reason string,
+) {
+	req, err := stoptransaction.Req(stoptransaction.ReqInput{
+		TransactionId:   transactionId,
+		IdTag:           &idTag,
+		MeterStop:       cp.meter(connectorId),
+		Timestamp:       now(),
+		Reason:          &reason,
+		TransactionData: nil,
+	})
+	if err != nil {
+		cp.logf("stopTransaction: %v", err)
+
+		return
+	}
+
+	var conf stoptransaction.ConfMessage
+
+	_ = cp.call(ctx, ocppj.ActionStopTransaction, req, &conf)
+}
+
+// sendMeterValues reports the energy register of a connector, and the
+// charging power while a transaction is running.
+func (cp *chargePoint) sendMeterValues(
+	ctx context.Context,
+	connectorId int,
+	transactionId *int,
+) {
+	readingContext := contextTrigger
+	power := 0
+
+	if transactionId != nil {
+		readingContext = contextPeriodic
+		power = cp.cfg.power
+	}
+
+	energy := strconv.Itoa(cp.meter(connectorId))
+	watts := strconv.Itoa(power)
+	energyMeasurand := measurandEnergy
+	powerMeasurand := measurandPower
+	energyUnit := unitWh
+	powerUnit := unitW
+
+	req, err := metervalues.Req(metervalues.ReqInput{
+		ConnectorId:   connectorId,
+		TransactionId: transactionId,
+		MeterValue: []types.MeterValueInput{{
+			Timestamp: now(),
+			SampledValue: []types.SampledValueInput{
+				{
+					Value:     energy,
+					Context:   &readingContext,
+					Format:    nil,
+					Measurand: &energyMeasurand,
+					Phase:     nil,
+					Location:  nil,
+					Unit:      &energyUnit,
+				},
+				{
+					Value:     watts,
+					Context:   &readingContext,
+					Format:    nil,
+					Measurand: &powerMeasurand,
+					Phase:     nil,
+					Location:  nil,
+					Unit:      &powerUnit,
+				},
+			},
+		}},
+	})
+	if err != nil {
+		cp.logf("meterValues: %v", err)
+
+		return
+	}
+
+	var conf metervalues.ConfMessage
+
+	_ = cp.call(ctx, ocppj.ActionMeterValues, req, &conf)
+}
+
+// startSession records the transaction id of a reserved connector.
+func (cp *chargePoint) startSession(
+	connectorId int,
+	transactionId int,
+) *session {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	current := cp.sessions[connectorId]
+	current.transactionId = transactionId
+	cp.stats.sessions++
+
+	return current
+}
+
+// release frees a connector after its session.
+func (cp *chargePoint) release(connectorId int) {
+	cp.mu.Lock()
+	delete(cp.sessions, connectorId)
+	cp.mu.Unlock()
+
+	cp.sessionsWG.Done()
+}
+
+// stopTransaction asks the session running transactionId to stop and
+// reports whether there was one.
+func (cp *chargePoint) stopTransaction(transactionId int, reason string) bool {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	for _, current := range cp.sessions {
+		if current.transactionId == transactionId {
+			current.signal(reason)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// stopAll asks every running session to stop.
+func (cp *chargePoint) stopAll(reason string) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	for _, current := range cp.sessions {
+		current.signal(reason)
+	}
+}
+
+// meter returns the energy register of a connector in Wh.
+func (cp *chargePoint) meter(connectorId int) int {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	return cp.meterRegister[connectorId]
+}
+
+// addEnergy advances the energy register of a connector by the energy
+// charged at -power during elapsed.
+func (cp *chargePoint) addEnergy(connectorId int, elapsed time.Duration) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	energy := int(float64(cp.cfg.power) * elapsed.Hours())
+	cp.meterRegister[connectorId] = min(
+		cp.meterRegister[connectorId]+energy,
+		maxMeterWh,
+	)
+}
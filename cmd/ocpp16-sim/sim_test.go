@@ -0,0 +1,354 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/remotestoptransaction"
+	"github.com/aasanchez/ocpp16messages/reset"
+	"github.com/aasanchez/ocpp16messages/unlockconnector"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	testTimeout  = 10 * time.Second
+	pollInterval = 5 * time.Millisecond
+	testIdTag    = "TAG-1"
+)
+
+// startMock starts a mock Central System for the duration of the test.
+func startMock(t *testing.T, ctx context.Context) (*mockCSMS, string) {
+	t.Helper()
+
+	mock := newMockCSMS(1, log.New(io.Discard, "", 0), true)
+
+	url, err := mock.start(ctx, defaultMockAddr)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return mock, url
+}
+
+// testConfig parses args with the fast timings used by the tests.
+func testConfig(t *testing.T, url string, args ...string) config {
+	t.Helper()
+
+	cfg, err := parseConfig(append([]string{
+		"-url", url,
+		"-quiet",
+		"-session-duration", "100ms",
+		"-meter-interval", "20ms",
+		"-heartbeat", "30ms",
+		"-reconnect", "20ms",
+		"-timeout", "2s",
+	}, args...), io.Discard)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return cfg
+}
+
+// waitFor polls condition until it holds or the test times out.
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(testTimeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
+
+func TestParseConfig_MissingURL(t *testing.T) {
+	t.Parallel()
+
+	_, err := parseConfig(nil, io.Discard)
+	if !errors.Is(err, errMissingURL) {
+		t.Errorf(types.ErrorWrapping, err, errMissingURL)
+	}
+}
+
+func TestParseConfig_RespondInvalidStatus(t *testing.T) {
+	t.Parallel()
+
+	_, err := parseConfig(
+		[]string{"-mock", "-respond", "Reset=Maybe"},
+		io.Discard,
+	)
+	if err == nil {
+		t.Fatalf(types.ErrorWantNonNil, "error")
+	}
+
+	if !strings.Contains(err.Error(), "Reset") {
+		t.Errorf(types.ErrorWantContains, err, "Reset")
+	}
+}
+
+func TestParseConfig_RespondChargePointAction(t *testing.T) {
+	t.Parallel()
+
+	_, err := parseConfig(
+		[]string{"-mock", "-respond", "Heartbeat=Accepted"},
+		io.Discard,
+	)
+	if err == nil {
+		t.Fatalf(types.ErrorWantNonNil, "error")
+	}
+
+	if !strings.Contains(err.Error(), "not sent by a Central System") {
+		t.Errorf(
+			types.ErrorWantContains,
+			err,
+			"not sent by a Central System",
+		)
+	}
+}
+
+func TestRun_Mock(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+
+	code := run(ctx, []string{
+		"-mock",
+		"-quiet",
+		"-session-duration", "100ms",
+		"-meter-interval", "20ms",
+		"-heartbeat", "30ms",
+	}, &stdout, io.Discard)
+	if code != exitOK {
+		t.Fatalf(types.ErrorMismatchValue, exitOK, code)
+	}
+
+	want := "SIM001: 1 sessions, 0 errors, 0 connection errors"
+	if !strings.Contains(stdout.String(), want) {
+		t.Errorf(types.ErrorWantContains, stdout.String(), want)
+	}
+}
+
+func TestRun_Unreachable(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", defaultMockAddr)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	url := "ws://" + listener.Addr().String() + "/ocpp"
+	_ = listener.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+
+	code := run(ctx, []string{
+		"-url", url,
+		"-quiet",
+		"-reconnect", "10ms",
+		"-retries", "2",
+	}, &stdout, io.Discard)
+	if ctx.Err() != nil {
+		t.Fatalf("simulation did not give up: %v", ctx.Err())
+	}
+
+	if code != exitError {
+		t.Errorf(types.ErrorMismatchValue, exitError, code)
+	}
+
+	want := "SIM001: 0 sessions, 0 errors, 3 connection errors"
+	if !strings.Contains(stdout.String(), want) {
+		t.Errorf(types.ErrorWantContains, stdout.String(), want)
+	}
+}
+
+func TestChargePoint_ScriptedSession(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mock, url := startMock(t, ctx)
+	cp := newChargePoint("CP001", testConfig(t, url), log.Default())
+
+	cp.run(ctx)
+
+	if ctx.Err() != nil {
+		t.Fatalf("simulation did not finish: %v", ctx.Err())
+	}
+
+	for _, action := range []string{
+		ocppj.ActionBootNotification,
+		ocppj.ActionAuthorize,
+		ocppj.ActionStartTransaction,
+		ocppj.ActionStopTransaction,
+	} {
+		if mock.count(action) != 1 {
+			t.Errorf(types.ErrorMismatchValue, 1, mock.count(action))
+		}
+	}
+
+	if mock.count(ocppj.ActionMeterValues) == 0 {
+		t.Error("no MeterValues received")
+	}
+
+	snapshot := cp.snapshot()
+	if snapshot.sessions != 1 || snapshot.errors != 0 {
+		t.Errorf(
+			"sessions, errors = %d, %d; want 1, 0",
+			snapshot.sessions,
+			snapshot.errors,
+		)
+	}
+}
+
+func TestChargePoint_RemoteCommands(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mock, url := startMock(t, ctx)
+	cfg := testConfig(
+		t,
+		url,
+		"-sessions", "0",
+		"-session-duration", "1m",
+		"-keep-alive",
+		"-respond", "Reset=Rejected",
+		"-respond", "UnlockConnector=error:NotSupported",
+	)
+	cp := newChargePoint("CP002", cfg, log.Default())
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		cp.run(ctx)
+	}()
+
+	waitFor(t, "connector statuses", func() bool {
+		return mock.count(ocppj.ActionStatusNotification) > cfg.connectors
+	})
+
+	connectorId := 1
+
+	startReq, err := remotestarttransaction.Req(
+		remotestarttransaction.ReqInput{
+			IdTag:           testIdTag,
+			ConnectorId:     &connectorId,
+			ChargingProfile: nil,
+		},
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	var startConf remotestarttransaction.ConfMessage
+
+	err = mock.call(
+		ctx,
+		"CP002",
+		ocppj.ActionRemoteStartTransaction,
+		startReq,
+		&startConf,
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if startConf.Status.String() != statusAccepted {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			statusAccepted,
+			startConf.Status.String(),
+		)
+	}
+
+	waitFor(t, "StartTransaction", func() bool {
+		return mock.count(ocppj.ActionStartTransaction) == 1
+	})
+
+	if mock.count(ocppj.ActionAuthorize) != 0 {
+		t.Error("remote start sent Authorize")
+	}
+
+	stopReq, err := remotestoptransaction.Req(
+		remotestoptransaction.ReqInput{TransactionId: firstTransactionId},
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	var stopConf remotestoptransaction.ConfMessage
+
+	err = mock.call(
+		ctx,
+		"CP002",
+		ocppj.ActionRemoteStopTransaction,
+		stopReq,
+		&stopConf,
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	waitFor(t, "StopTransaction", func() bool {
+		return mock.count(ocppj.ActionStopTransaction) == 1
+	})
+
+	resetReq, err := reset.Req(reset.ReqInput{Type: resetHard})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	var resetConf reset.ConfMessage
+
+	err = mock.call(ctx, "CP002", ocppj.ActionReset, resetReq, &resetConf)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if resetConf.Status.String() != statusRejected {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			statusRejected,
+			resetConf.Status.String(),
+		)
+	}
+
+	unlockReq, err := unlockconnector.Req(
+		unlockconnector.ReqInput{ConnectorId: connectorId},
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	err = mock.call(ctx, "CP002", ocppj.ActionUnlockConnector, unlockReq, nil)
+
+	var callErr *ocppj.Error
+	if !errors.As(err, &callErr) || callErr.Code != ocppj.NotSupported {
+		t.Errorf(types.ErrorWrapping, err, ocppj.NotSupported)
+	}
+
+	cancel()
+	<-done
+}
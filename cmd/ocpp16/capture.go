@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+)
+
+// maxLineSize bounds a line of a capture; a SendLocalList.req with a full
+// list is far below it.
+const maxLineSize = 16 << 20
+
+// stdinName names standard input in locations.
+const stdinName = "-"
+
+var errNoFrame = errors.New("no OCPP-J frame on the line")
+
+// frameFields are the properties of a JSON object line that may carry the
+// frame, in the order they are looked up.
+var frameFields = []string{"frame", "message", "msg", "data", "payload"}
+
+// chargePointFields are the properties of a JSON object line that may name
+// the Charge Point of the frame, in the order they are looked up.
+var chargePointFields = []string{
+	"chargePointId",
+	"chargeBoxId",
+	"chargePoint",
+	"identity",
+	"source",
+}
+
+// entry is one frame read from a capture.
+type entry struct {
+	// location is file:line of the frame.
+	location string
+	// raw is the JSON text of the frame, nil when none was found.
+	raw json.RawMessage
+	// frame is the parsed frame, valid when err is nil.
+	frame ocppj.Frame
+	// chargePoint names the Charge Point of the frame, empty when the line
+	// does not say.
+	chargePoint string
+	// action is the action of a CALL, or of the CALL a CALLRESULT or
+	// CALLERROR answers; empty when unknown.
+	action string
+	// unmatched is set for a CALLRESULT or CALLERROR whose CALL is not in
+	// the capture, such as one starting mid-session.
+	unmatched bool
+	// err is set when the line holds no well-formed frame.
+	err error
+}
+
+// callKey identifies a CALL awaiting its answer. UniqueIds are only unique
+// per Charge Point, and captures merged from several collide.
+type callKey struct {
+	chargePoint string
+	uniqueId    string
+}
+
+// readCaptures reads the frames of each file, or of stdin when there are no
+// files, and passes them to visit in order.
+func readCaptures(files []string, stdin io.Reader, visit func(entry)) error {
+	if len(files) == 0 {
+		return readCapture(stdinName, stdin, visit)
+	}
+
+	for _, name := range files {
+		err := readFile(name, stdin, visit)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readFile reads the frames of one file; "-" is stdin.
+func readFile(name string, stdin io.Reader, visit func(entry)) error {
+	if name == stdinName {
+		return readCapture(name, stdin, visit)
+	}
+
+	file, err := os.Open(name) //nolint:gosec // Files named by the user.
+	if err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+
+	defer file.Close()
+
+	return readCapture(name, file, visit)
+}
+
+// readCapture reads the frames of one capture. A CALLRESULT or CALLERROR is
+// matched with the CALL of the same Charge Point and uniqueId read before
+// it, or of the same uniqueId on a line naming no Charge Point.
+func readCapture(name string, reader io.Reader, visit func(entry)) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, maxLineSize)
+
+	calls := map[callKey]string{}
+	number := 0
+
+	for scanner.Scan() {
+		number++
+
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		visit(parseEntry(fmt.Sprintf("%s:%d", name, number), line, calls))
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	return nil
+}
+
+// parseEntry parses the frame of a line and resolves its action.
+func parseEntry(
+	location string,
+	line []byte,
+	calls map[callKey]string,
+) entry {
+	found := entry{
+		location:    location,
+		raw:         nil,
+		frame:       ocppj.Frame{},
+		chargePoint: "",
+		action:      "",
+		unmatched:   false,
+		err:         nil,
+	}
+
+	found.raw, found.chargePoint, found.err = extractFrame(line)
+	if found.err != nil {
+		return found
+	}
+
+	found.frame, found.err = ocppj.ParseFrame(found.raw)
+	if found.err != nil {
+		return found
+	}
+
+	key := callKey{
+		chargePoint: found.chargePoint,
+		uniqueId:    found.frame.UniqueId,
+	}
+
+	if found.frame.Type == ocppj.MessageTypeCall {
+		found.action = found.frame.Action
+		calls[key] = found.action
+
+		return found
+	}
+
+	action, ok := calls[key]
+	if !ok && key.chargePoint != "" {
+		key.chargePoint = ""
+		action, ok = calls[key]
+	}
+
+	if !ok {
+		found.unmatched = true
+
+		return found
+	}
+
+	delete(calls, key)
+	found.action = action
+
+	return found
+}
+
+// extractFrame returns the JSON array of a line and the Charge Point the
+// line names: the line itself, the array held by a frame field of a JSON
+// object line, or the first array following a log prefix, whose last
+// bracketed word, such as [CP001], names the Charge Point.
+func extractFrame(line []byte) (json.RawMessage, string, error) {
+	if line[0] == '{' {
+		return objectFrame(line)
+	}
+
+	for offset := 0; ; {
+		start := bytes.IndexByte(line[offset:], '[')
+		if start < 0 {
+			return nil, "", errNoFrame
+		}
+
+		offset += start
+
+		var raw json.RawMessage
+
+		err := json.NewDecoder(bytes.NewReader(line[offset:])).Decode(&raw)
+		if err == nil {
+			return raw, prefixChargePoint(line[:offset]), nil
+		}
+
+		offset++
+	}
+}
+
+// prefixChargePoint returns the last bracketed word of a log prefix, empty
+// when there is none.
+func prefixChargePoint(prefix []byte) string {
+	end := bytes.LastIndexByte(prefix, ']')
+	if end < 0 {
+		return ""
+	}
+
+	start := bytes.LastIndexByte(prefix[:end], '[')
+	if start < 0 {
+		return ""
+	}
+
+	word := bytes.TrimSpace(prefix[start+1 : end])
+	if bytes.ContainsAny(word, " \t") {
+		return ""
+	}
+
+	return string(word)
+}
+
+// objectFrame returns the frame carried by a JSON object line, as an array
+// or as a string holding one, and the Charge Point named by the line.
+func objectFrame(line []byte) (json.RawMessage, string, error) {
+	var fields map[string]json.RawMessage
+
+	err := json.Unmarshal(line, &fields)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", errNoFrame, err)
+	}
+
+	frame, err := fieldFrame(fields)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return frame, fieldChargePoint(fields), nil
+}
+
+// fieldChargePoint returns the first string of the Charge Point fields of a
+// JSON object line, empty when there is none.
+func fieldChargePoint(fields map[string]json.RawMessage) string {
+	for _, name := range chargePointFields {
+		var text string
+
+		if json.Unmarshal(fields[name], &text) == nil && text != "" {
+			return text
+		}
+	}
+
+	return ""
+}
+
+// fieldFrame returns the frame held by the frame fields of a JSON object
+// line.
+func fieldFrame(fields map[string]json.RawMessage) (json.RawMessage, error) {
+	for _, name := range frameFields {
+		value := bytes.TrimSpace(fields[name])
+
+		var text string
+
+		switch {
+		case len(value) == 0:
+			continue
+		case value[0] == '[':
+			return value, nil
+		case json.Unmarshal(value, &text) == nil:
+			text = strings.TrimSpace(text)
+			if len(text) > 0 && text[0] == '[' {
+				return json.RawMessage(text), nil
+			}
+		}
+	}
+
+	return nil, errNoFrame
+}
+
+// describe names the frame of an entry, e.g. CALL Authorize "42".
+func describe(found entry) string {
+	if found.raw == nil || found.frame.UniqueId == "" {
+		return "frame"
+	}
+
+	var kind string
+
+	switch found.frame.Type {
+	case ocppj.MessageTypeCall:
+		kind = "CALL"
+	case ocppj.MessageTypeCallResult:
+		kind = "CALLRESULT"
+	case ocppj.MessageTypeCallError:
+		kind = "CALLERROR"
+	default:
+		kind = "frame"
+	}
+
+	if found.action == "" {
+		return fmt.Sprintf("%s %q", kind, found.frame.UniqueId)
+	}
+
+	return fmt.Sprintf("%s %s %q", kind, found.action, found.frame.UniqueId)
+}
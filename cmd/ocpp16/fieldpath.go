@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// errorSeparator separates the wrapped parts of an error line.
+const errorSeparator = ": "
+
+// withFieldPath rewrites the leading field names of an error line, as
+// wrapped by the message packages, into the full path of the field in the
+// payload: status: <why> of an Authorize.conf becomes
+// idTagInfo.status: <why>. A name that is not a property of the value
+// reached so far is looked up among its nested objects, where it must be
+// unique. Lines whose first part names no property are returned unchanged.
+func withFieldPath(payload json.RawMessage, line string) string {
+	var node any
+
+	err := json.Unmarshal(payload, &node)
+	if err != nil {
+		return line
+	}
+
+	parts := strings.Split(line, errorSeparator)
+
+	var path []string
+
+	consumed := 0
+
+	for _, part := range parts[:len(parts)-1] {
+		child, steps, ok := locate(node, part)
+		if !ok {
+			break
+		}
+
+		node = child
+		path = append(path, steps...)
+		consumed++
+	}
+
+	if consumed == 0 {
+		return line
+	}
+
+	return strings.Join(path, ".") + errorSeparator +
+		strings.Join(parts[consumed:], errorSeparator)
+}
+
+// locate finds the property named by part, such as idTag or
+// sampledValue[0], in node or, when node lacks it, in exactly one of the
+// objects nested in node. It returns the value and the path leading to it.
+func locate(node any, part string) (any, []string, bool) {
+	name, index, indexed := splitIndex(part)
+
+	object, ok := node.(map[string]any)
+	if !ok {
+		return nil, nil, false
+	}
+
+	if value, found := object[name]; found {
+		if !indexed {
+			return value, []string{part}, true
+		}
+
+		list, isList := value.([]any)
+		if !isList || index < 0 || index >= len(list) {
+			return nil, nil, false
+		}
+
+		return list[index], []string{part}, true
+	}
+
+	var (
+		match   any
+		steps   []string
+		matches int
+	)
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		child, childSteps, found := locate(object[key], part)
+		if found {
+			match = child
+			steps = append([]string{key}, childSteps...)
+			matches++
+		}
+	}
+
+	if matches != 1 {
+		return nil, nil, false
+	}
+
+	return match, steps, true
+}
+
+// splitIndex splits a part such as sampledValue[0] into its name and index.
+func splitIndex(part string) (string, int, bool) {
+	open := strings.IndexByte(part, '[')
+	if open < 0 || !strings.HasSuffix(part, "]") {
+		return part, 0, false
+	}
+
+	index, err := strconv.Atoi(part[open+1 : len(part)-1])
+	if err != nil {
+		return part, 0, false
+	}
+
+	return part[:open], index, true
+}
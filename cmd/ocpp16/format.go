@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// runFormat pretty-prints every frame, each after a comment line naming its
+// location. Lines without a frame are reported on stderr and make it exit
+// with exitError.
+func runFormat(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("fmt", stderr)
+	indent := flags.String("indent", "  ", "indentation of nested values")
+
+	code, ok := parseFlags(flags, args)
+	if !ok {
+		return code
+	}
+
+	code = exitOK
+
+	err := readCaptures(flags.Args(), stdin, func(found entry) {
+		if found.raw == nil {
+			_, _ = fmt.Fprintf(stderr, "%s: %v\n", found.location, found.err)
+			code = exitError
+
+			return
+		}
+
+		var pretty bytes.Buffer
+
+		// The raw frame is a decoded JSON value, so it always indents.
+		_ = json.Indent(&pretty, found.raw, "", *indent)
+
+		_, _ = fmt.Fprintf(
+			stdout,
+			"# %s %s\n%s\n",
+			found.location,
+			describe(found),
+			pretty.Bytes(),
+		)
+	})
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+
+		return exitError
+	}
+
+	return code
+}
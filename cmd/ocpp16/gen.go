@@ -0,0 +1,455 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	ocpp16 "github.com/aasanchez/ocpp16messages"
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/cancelreservation"
+	"github.com/aasanchez/ocpp16messages/changeavailability"
+	"github.com/aasanchez/ocpp16messages/changeconfiguration"
+	"github.com/aasanchez/ocpp16messages/clearcache"
+	"github.com/aasanchez/ocpp16messages/clearchargingprofile"
+	"github.com/aasanchez/ocpp16messages/datatransfer"
+	"github.com/aasanchez/ocpp16messages/diagnosticsstatusnotification"
+	"github.com/aasanchez/ocpp16messages/firmwarestatusnotification"
+	"github.com/aasanchez/ocpp16messages/getcompositeschedule"
+	"github.com/aasanchez/ocpp16messages/getconfiguration"
+	"github.com/aasanchez/ocpp16messages/getdiagnostics"
+	"github.com/aasanchez/ocpp16messages/getlocallistversion"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/metervalues"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/remotestoptransaction"
+	"github.com/aasanchez/ocpp16messages/reservenow"
+	"github.com/aasanchez/ocpp16messages/reset"
+	"github.com/aasanchez/ocpp16messages/sendlocallist"
+	"github.com/aasanchez/ocpp16messages/setchargingprofile"
+	"github.com/aasanchez/ocpp16messages/starttransaction"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	"github.com/aasanchez/ocpp16messages/stoptransaction"
+	"github.com/aasanchez/ocpp16messages/triggermessage"
+	"github.com/aasanchez/ocpp16messages/unlockconnector"
+	"github.com/aasanchez/ocpp16messages/updatefirmware"
+)
+
+var (
+	errMissingAction = errors.New("missing action")
+	errUnknownAction = errors.New("unknown action")
+	errKindConflict  = errors.New("-req and -conf are exclusive")
+	errSetSyntax     = errors.New("want -set path=value")
+	errEmptyInput    = errors.New("empty input")
+	errUnexpectedArg = errors.New("unexpected argument")
+	// errFlagSyntax marks flag errors already reported by the flag package.
+	errFlagSyntax = errors.New("invalid flags")
+)
+
+// builder builds a validated message from a decoded input document.
+type builder func(document any) (any, error)
+
+// builders are the request and confirmation builders of an action.
+type builders struct {
+	request      builder
+	confirmation builder
+}
+
+// generators maps every OCPP 1.6 action to the Req and Conf constructors of
+// its package.
+var generators = map[string]builders{
+	ocppj.ActionAuthorize: {
+		build(authorize.Req),
+		build(authorize.Conf),
+	},
+	ocppj.ActionBootNotification: {
+		build(bootnotification.Req),
+		build(bootnotification.Conf),
+	},
+	ocppj.ActionCancelReservation: {
+		build(cancelreservation.Req),
+		build(cancelreservation.Conf),
+	},
+	ocppj.ActionChangeAvailability: {
+		build(changeavailability.Req),
+		build(changeavailability.Conf),
+	},
+	ocppj.ActionChangeConfiguration: {
+		build(changeconfiguration.Req),
+		build(changeconfiguration.Conf),
+	},
+	ocppj.ActionClearCache: {
+		build(clearcache.Req),
+		build(clearcache.Conf),
+	},
+	ocppj.ActionClearChargingProfile: {
+		build(clearchargingprofile.Req),
+		build(clearchargingprofile.Conf),
+	},
+	ocppj.ActionDataTransfer: {
+		build(datatransfer.Req),
+		build(datatransfer.Conf),
+	},
+	ocppj.ActionDiagnosticsStatusNotification: {
+		build(diagnosticsstatusnotification.Req),
+		build(diagnosticsstatusnotification.Conf),
+	},
+	ocppj.ActionFirmwareStatusNotification: {
+		build(firmwarestatusnotification.Req),
+		build(firmwarestatusnotification.Conf),
+	},
+	ocppj.ActionGetCompositeSchedule: {
+		build(getcompositeschedule.Req),
+		build(getcompositeschedule.Conf),
+	},
+	ocppj.ActionGetConfiguration: {
+		build(getconfiguration.Req),
+		build(getconfiguration.Conf),
+	},
+	ocppj.ActionGetDiagnostics: {
+		build(getdiagnostics.Req),
+		build(getdiagnostics.Conf),
+	},
+	ocppj.ActionGetLocalListVersion: {
+		build(getlocallistversion.Req),
+		build(getlocallistversion.Conf),
+	},
+	ocppj.ActionHeartbeat: {
+		build(heartbeat.Req),
+		build(heartbeat.Conf),
+	},
+	ocppj.ActionMeterValues: {
+		build(metervalues.Req),
+		build(metervalues.Conf),
+	},
+	ocppj.ActionRemoteStartTransaction: {
+		build(remotestarttransaction.Req),
+		build(remotestarttransaction.Conf),
+	},
+	ocppj.ActionRemoteStopTransaction: {
+		build(remotestoptransaction.Req),
+		build(remotestoptransaction.Conf),
+	},
+	ocppj.ActionReserveNow: {
+		build(reservenow.Req),
+		build(reservenow.Conf),
+	},
+	ocppj.ActionReset: {
+		build(reset.Req),
+		build(reset.Conf),
+	},
+	ocppj.ActionSendLocalList: {
+		build(sendlocallist.Req),
+		build(sendlocallist.Conf),
+	},
+	ocppj.ActionSetChargingProfile: {
+		build(setchargingprofile.Req),
+		build(setchargingprofile.Conf),
+	},
+	ocppj.ActionStartTransaction: {
+		build(starttransaction.Req),
+		build(starttransaction.Conf),
+	},
+	ocppj.ActionStatusNotification: {
+		build(statusnotification.Req),
+		build(statusnotification.Conf),
+	},
+	ocppj.ActionStopTransaction: {
+		build(stoptransaction.Req),
+		build(stoptransaction.Conf),
+	},
+	ocppj.ActionTriggerMessage: {
+		build(triggermessage.Req),
+		build(triggermessage.Conf),
+	},
+	ocppj.ActionUnlockConnector: {
+		build(unlockconnector.Req),
+		build(unlockconnector.Conf),
+	},
+	ocppj.ActionUpdateFirmware: {
+		build(updatefirmware.Req),
+		build(updatefirmware.Conf),
+	},
+}
+
+// build returns the builder of a constructor: the document fills its input
+// type, e.g. authorize.ReqInput, which the constructor validates.
+func build[I, M any](constructor func(I) (M, error)) builder {
+	return func(document any) (any, error) {
+		var input I
+
+		err := assign(reflect.ValueOf(&input).Elem(), document, "")
+		if err != nil {
+			return nil, err
+		}
+
+		return constructor(input)
+	}
+}
+
+// genOptions are the flags of gen.
+type genOptions struct {
+	action string
+	kind   ocpp16.Kind
+	file   string
+	sets   []string
+	id     string
+	indent bool
+}
+
+// runGen builds the payload of an action from an input file and -set flags
+// and prints it, or prints the validation errors of the input and exits
+// with exitError.
+func runGen(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	options, err := parseGenFlags(args, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+
+	if err != nil {
+		if !errors.Is(err, errFlagSyntax) {
+			_, _ = fmt.Fprintln(stderr, err)
+		}
+
+		return exitUsage
+	}
+
+	payload, err := generate(options, stdin)
+	if err != nil {
+		prefix := options.action + "." + options.kind.String()
+		for _, line := range errorLines(err) {
+			_, _ = fmt.Fprintf(stderr, "%s: %s\n", prefix, line)
+		}
+
+		return exitError
+	}
+
+	_, _ = fmt.Fprintf(stdout, "%s\n", payload)
+
+	return exitOK
+}
+
+// parseGenFlags parses "<Action> [flags]"; the action may also follow the
+// flags.
+func parseGenFlags(args []string, stderr io.Writer) (genOptions, error) {
+	options := genOptions{
+		action: "",
+		kind:   ocpp16.Request,
+		file:   "",
+		sets:   nil,
+		id:     "",
+		indent: false,
+	}
+
+	flags := newFlagSet("gen", stderr)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(
+			flags.Output(),
+			"usage: ocpp16 gen <Action> [-req|-conf] [-f file] "+
+				"[-set path=value ...]",
+		)
+		flags.PrintDefaults()
+	}
+
+	request := flags.Bool("req", false, "build the request (default)")
+	confirmation := flags.Bool("conf", false, "build the confirmation")
+	flags.StringVar(
+		&options.file,
+		"f",
+		"",
+		"YAML or JSON input file, - for stdin",
+	)
+	flags.Func("set", "set the input field at a dotted path, e.g. "+
+		"chargingSchedule.chargingSchedulePeriod.0.limit=16; repeatable",
+		func(value string) error {
+			options.sets = append(options.sets, value)
+
+			return nil
+		})
+	flags.StringVar(
+		&options.id,
+		"id",
+		"",
+		"wrap the payload in a CALL or CALLRESULT frame with this uniqueId",
+	)
+	flags.BoolVar(&options.indent, "indent", false, "indent the output")
+
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		options.action, args = args[0], args[1:]
+	}
+
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return options, err //nolint:wrapcheck // Matched by the caller.
+	}
+
+	if err != nil {
+		return options, errFlagSyntax
+	}
+
+	if options.action == "" && flags.NArg() > 0 {
+		options.action = flags.Arg(0)
+	} else if flags.NArg() > 0 {
+		return options, fmt.Errorf("%w: %q", errUnexpectedArg, flags.Arg(0))
+	}
+
+	return options, options.resolve(*request, *confirmation)
+}
+
+// resolve checks the action and selects the kind.
+func (o *genOptions) resolve(request, confirmation bool) error {
+	if request && confirmation {
+		return errKindConflict
+	}
+
+	if confirmation {
+		o.kind = ocpp16.Confirmation
+	}
+
+	if o.action == "" {
+		return errMissingAction
+	}
+
+	for _, name := range ocppj.Actions() {
+		if strings.EqualFold(name, o.action) {
+			o.action = name
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %q", errUnknownAction, o.action)
+}
+
+// generate builds and encodes the message.
+func generate(options genOptions, stdin io.Reader) ([]byte, error) {
+	document, err := readInput(options.file, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, set := range options.sets {
+		path, value, ok := strings.Cut(set, "=")
+		if !ok || path == "" {
+			return nil, fmt.Errorf("%w: %q", errSetSyntax, set)
+		}
+
+		parsed, err := parseFlagValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("-set %s: %w", path, err)
+		}
+
+		document, err = setPath(document, strings.Split(path, "."), parsed)
+		if err != nil {
+			return nil, fmt.Errorf("-set %s: %w", path, err)
+		}
+	}
+
+	generator := generators[options.action].request
+	if options.kind == ocpp16.Confirmation {
+		generator = generators[options.action].confirmation
+	}
+
+	msg, err := generator(document)
+	if err != nil {
+		return nil, err
+	}
+
+	return encode(options, msg)
+}
+
+// readInput decodes the input file, nil when there is none. A document
+// starting with { is JSON, anything else YAML.
+func readInput(file string, stdin io.Reader) (any, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch file {
+	case "":
+		return nil, nil //nolint:nilnil // No input is an empty document.
+	case stdinName:
+		data, err = io.ReadAll(stdin)
+	default:
+		data, err = os.ReadFile(file) //nolint:gosec // Named by the user.
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read input: %w", err)
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return parseYAML(data)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document any
+
+	err = decoder.Decode(&document)
+	if err != nil {
+		return nil, fmt.Errorf("input: %w", err)
+	}
+
+	return document, nil
+}
+
+// parseFlagValue parses the value of a -set flag like a YAML scalar, so
+// that quotes keep a string and JSON lists and objects are accepted.
+func parseFlagValue(value string) (any, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	parser := yamlParser{lines: nil, next: 0}
+
+	return parser.scalar(yamlLine{number: 1, indent: 0, text: value}, value)
+}
+
+// encode encodes the payload, or the frame carrying it when -id is set.
+func encode(options genOptions, msg any) ([]byte, error) {
+	var (
+		value any = msg
+		err   error
+	)
+
+	if options.id != "" {
+		if options.kind == ocpp16.Confirmation {
+			value, err = ocppj.NewCallResult(options.id, msg)
+		} else {
+			value, err = ocppj.NewCall(options.id, options.action, msg)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("frame: %w", err)
+		}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("encode: %w", err)
+	}
+
+	if !options.indent {
+		return data, nil
+	}
+
+	var pretty bytes.Buffer
+
+	// The data was just encoded, so it always indents.
+	_ = json.Indent(&pretty, data, "", "  ")
+
+	return pretty.Bytes(), nil
+}
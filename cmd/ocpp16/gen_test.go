@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// profile is a SetChargingProfile.req input in the YAML subset gen reads.
+const profile = `# Limit connector 1 to 16 A, then 8.5 A
+connectorId: 1
+csChargingProfiles:
+  chargingProfileId: 7
+  stackLevel: 0
+  chargingProfilePurpose: TxDefaultProfile
+  chargingProfileKind: 'Absolute'
+  chargingSchedule:
+    chargingRateUnit: A
+    startSchedule: "2025-01-02T15:00:00Z"
+    chargingSchedulePeriod:
+    - startPeriod: 0
+      limit: 16
+    - startPeriod: 3600
+      limit: 8.5
+      numberPhases: 3
+`
+
+const profilePayload = `{"connectorId":1,"csChargingProfiles":{` +
+	`"chargingProfileId":7,"stackLevel":0,` +
+	`"chargingProfilePurpose":"TxDefaultProfile",` +
+	`"chargingProfileKind":"Absolute","chargingSchedule":{` +
+	`"startSchedule":"2025-01-02T15:00:00Z","chargingRateUnit":"A",` +
+	`"chargingSchedulePeriod":[{"startPeriod":0,"limit":16},` +
+	`{"startPeriod":3600,"limit":8.5,"numberPhases":3}]}}}`
+
+func TestRun_GenFile(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "profile.yaml")
+
+	err := os.WriteFile(name, []byte(profile), 0o600)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	code, output := runWith(t, "", "gen", "SetChargingProfile", "-f", name)
+	if code != exitOK {
+		t.Fatalf(types.ErrorMismatchValue, exitOK, code)
+	}
+
+	if output != profilePayload+"\n" {
+		t.Errorf(types.ErrorMismatch, profilePayload, output)
+	}
+}
+
+func TestRun_GenFlags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{
+			[]string{"gen", "Authorize", "-set", "idTag=12345"},
+			`{"idTag":"12345"}`,
+		},
+		{
+			[]string{"gen", "-conf", "-set", "status=Accepted", "authorize"},
+			`{"idTagInfo":{"status":"Accepted"}}`,
+		},
+		{
+			[]string{"gen", "Reset", "-set", "type=Hard", "-id", "42"},
+			`[2,"42","Reset",{"type":"Hard"}]`,
+		},
+		{
+			[]string{"gen", "Heartbeat", "-conf", "-id", "7", "-set",
+				"currentTime=2025-01-02T15:00:00Z"},
+			`[3,"7",{"currentTime":"2025-01-02T15:00:00Z"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		code, output := runWith(t, "", tt.args...)
+		if code != exitOK || output != tt.want+"\n" {
+			t.Errorf(types.ErrorMismatch, tt.want, output)
+		}
+	}
+}
+
+func TestRun_GenStdinAndOverride(t *testing.T) {
+	t.Parallel()
+
+	code, output := runWith(
+		t,
+		`{"connectorId": 1, "type": "Inoperative"}`,
+		"gen", "ChangeAvailability", "-f", "-", "-set", "type=Operative",
+	)
+	if code != exitOK {
+		t.Fatalf(types.ErrorMismatchValue, exitOK, code)
+	}
+
+	want := `{"connectorId":1,"type":"Operative"}` + "\n"
+	if output != want {
+		t.Errorf(types.ErrorMismatch, want, output)
+	}
+}
+
+func TestRun_GenOverrideIgnoresCase(t *testing.T) {
+	t.Parallel()
+
+	code, output := runWith(
+		t,
+		"idTag: FROMFILE\n",
+		"gen", "Authorize", "-f", "-", "-set", "IdTag=FROMFLAG",
+	)
+	if code != exitOK {
+		t.Fatalf(types.ErrorMismatchValue, exitOK, code)
+	}
+
+	want := `{"idTag":"FROMFLAG"}` + "\n"
+	if output != want {
+		t.Errorf(types.ErrorMismatch, want, output)
+	}
+}
+
+func TestRun_GenInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		stdin string
+		args  []string
+		want  string
+	}{
+		{
+			"",
+			[]string{"gen", "Authorize"},
+			"Authorize.req: idTag: ",
+		},
+		{
+			"",
+			[]string{"gen", "Authorize", "-set", "idTag=A", "-set", "tag=B"},
+			"Authorize.req: tag: " + errUnknownField.Error(),
+		},
+		{
+			"connectorId: one\n",
+			[]string{"gen", "UnlockConnector", "-f", "-"},
+			"UnlockConnector.req: connectorId: ",
+		},
+		{
+			"IdTag: A\nidTag: B\n",
+			[]string{"gen", "Authorize", "-f", "-"},
+			"Authorize.req: idTag: " + errDuplicateKey.Error() + " IdTag",
+		},
+		{
+			"idTag:\n  - A\n",
+			[]string{"gen", "Authorize", "-f", "-"},
+			"Authorize.req: idTag: " + errWantScalar.Error(),
+		},
+		{
+			"",
+			[]string{"gen", "Authorize", "-set", "idTag.0=A"},
+			"Authorize.req: idTag: " + errWantScalar.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		code, output := runWith(t, tt.stdin, tt.args...)
+		if code != exitError {
+			t.Errorf(types.ErrorMismatchValue, exitError, code)
+		}
+
+		if !strings.HasPrefix(output, tt.want) {
+			t.Errorf(types.ErrorWantContains, output, tt.want)
+		}
+	}
+}
+
+func TestRun_GenUsage(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{
+		{"gen"},
+		{"gen", "Bogus"},
+		{"gen", "Reset", "-req", "-conf"},
+		{"gen", "Reset", "extra"},
+		{"gen", "Reset", "-unknown"},
+	} {
+		code, _ := runWith(t, "", args...)
+		if code != exitUsage {
+			t.Errorf(types.ErrorMismatchValue, exitUsage, code)
+		}
+	}
+}
+
+func TestParseYAML(t *testing.T) {
+	t.Parallel()
+
+	document := `---
+name: 'It''s' # comment
+"quoted key": "a # b"
+empty:
+list:
+- one
+-
+  nested: true
+- [1, 2]
+map:
+  inner: ~
+  items:
+    - a: 1
+      b: 2
+`
+
+	got, err := parseYAML([]byte(document))
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	want := map[string]any{
+		"name":       "It's",
+		"quoted key": "a # b",
+		"empty":      nil,
+		"list": []any{
+			"one",
+			map[string]any{"nested": "true"},
+			[]any{json.Number("1"), json.Number("2")},
+		},
+		"map": map[string]any{
+			"inner": nil,
+			"items": []any{map[string]any{"a": "1", "b": "2"}},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf(types.ErrorMismatch, want, got)
+	}
+}
+
+func TestParseYAML_errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		document string
+		want     error
+	}{
+		{"", errEmptyInput},
+		{"a: 1\n  b: 2\n", errYAMLSyntax},
+		{"a: 1\na: 2\n", errYAMLSyntax},
+		{"a: 1\n- b\n", errYAMLSyntax},
+		{"just text\n", errYAMLSyntax},
+		{"\ta: 1\n", errYAMLSyntax},
+		{"a: \"open\n", errYAMLSyntax},
+		{"a: |\n  text\n", errYAMLUnsupported},
+		{"a: &anchor 1\n", errYAMLUnsupported},
+		{"a: [one, two]\n", errYAMLUnsupported},
+		{"a: 1\n---\nb: 2\n", errYAMLUnsupported},
+	}
+
+	for _, tt := range tests {
+		_, err := parseYAML([]byte(tt.document))
+		if !errors.Is(err, tt.want) {
+			t.Errorf(types.ErrorWrapping, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	errUnknownField = errors.New("unknown field")
+	errWantObject   = errors.New("want an object")
+	errWantList     = errors.New("want a list")
+	errWantScalar   = errors.New("want a single value")
+	errBadIndex     = errors.New("list index out of range")
+	errDuplicateKey = errors.New("same field as key")
+)
+
+// assign fills target, a ReqInput or ConfInput value or one of its fields,
+// from a decoded YAML or JSON document. Object keys match field names
+// regardless of case, so both idTag and IdTag fill IdTag, but not both in
+// one object. Scalars are
+// converted to the type of the field; null leaves it unset. Errors carry
+// the path of the value, e.g. chargingSchedulePeriod[0].limit.
+func assign(target reflect.Value, value any, path string) error {
+	if value == nil {
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(target.Type().Elem())
+
+		err := assign(elem.Elem(), value, path)
+		if err != nil {
+			return err
+		}
+
+		target.Set(elem)
+
+		return nil
+	case reflect.Struct:
+		return assignStruct(target, value, path)
+	case reflect.Slice:
+		return assignSlice(target, value, path)
+	default:
+		return assignScalar(target, value, path)
+	}
+}
+
+// assignStruct fills the fields of a struct from an object.
+func assignStruct(target reflect.Value, value any, path string) error {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s: %w", describePath(path), errWantObject)
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	var errs []error
+
+	assigned := map[string]string{}
+
+	for _, key := range keys {
+		structField, found := target.Type().FieldByNameFunc(
+			func(name string) bool {
+				return strings.EqualFold(name, key)
+			},
+		)
+
+		fieldPath := joinPath(path, key)
+
+		if !found || !structField.IsExported() {
+			errs = append(
+				errs,
+				fmt.Errorf("%s: %w", fieldPath, errUnknownField),
+			)
+
+			continue
+		}
+
+		if previous, ok := assigned[structField.Name]; ok {
+			errs = append(
+				errs,
+				fmt.Errorf("%s: %w %s", fieldPath, errDuplicateKey, previous),
+			)
+
+			continue
+		}
+
+		assigned[structField.Name] = key
+
+		err := assign(
+			target.FieldByIndex(structField.Index),
+			object[key],
+			fieldPath,
+		)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// assignSlice fills a slice from a list.
+func assignSlice(target reflect.Value, value any, path string) error {
+	list, ok := value.([]any)
+	if !ok {
+		return fmt.Errorf("%s: %w", describePath(path), errWantList)
+	}
+
+	slice := reflect.MakeSlice(target.Type(), len(list), len(list))
+
+	var errs []error
+
+	for index, item := range list {
+		itemPath := fmt.Sprintf("%s[%d]", path, index)
+
+		err := assign(slice.Index(index), item, itemPath)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	target.Set(slice)
+
+	return errors.Join(errs...)
+}
+
+// assignScalar converts a scalar to a string, integer, float or boolean
+// field.
+func assignScalar(target reflect.Value, value any, path string) error {
+	text, ok := scalarText(value)
+	if !ok {
+		return fmt.Errorf("%s: %w", describePath(path), errWantScalar)
+	}
+
+	var err error
+
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		var number int64
+
+		number, err = strconv.ParseInt(text, 10, target.Type().Bits())
+		target.SetInt(number)
+	case reflect.Float32, reflect.Float64:
+		var number float64
+
+		number, err = strconv.ParseFloat(text, target.Type().Bits())
+		target.SetFloat(number)
+	case reflect.Bool:
+		var flag bool
+
+		flag, err = strconv.ParseBool(text)
+		target.SetBool(flag)
+	default:
+		err = fmt.Errorf("%w: %s", errUnknownField, target.Type())
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", describePath(path), err)
+	}
+
+	return nil
+}
+
+// scalarText returns the text of a scalar document value.
+func scalarText(value any) (string, bool) {
+	switch scalar := value.(type) {
+	case string:
+		return scalar, true
+	case json.Number:
+		return scalar.String(), true
+	case bool:
+		return strconv.FormatBool(scalar), true
+	default:
+		return "", false
+	}
+}
+
+// setPath sets the value at a dotted path of a document, e.g.
+// csChargingProfiles.chargingSchedule.chargingSchedulePeriod.0.limit,
+// creating the objects and lists on the way. A numeric segment indexes a
+// list; the index one past its end appends to it. A name replaces the key
+// of an object that differs from it only in case, as both fill one field.
+func setPath(node any, segments []string, value any) (any, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	segment, rest := segments[0], segments[1:]
+
+	index, err := strconv.Atoi(segment)
+	if err != nil {
+		object, ok := node.(map[string]any)
+		if node == nil {
+			object, ok = map[string]any{}, true
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", segment, errWantObject)
+		}
+
+		for key := range object {
+			if strings.EqualFold(key, segment) {
+				segment = key
+
+				break
+			}
+		}
+
+		child, err := setPath(object[segment], rest, value)
+		if err != nil {
+			return nil, err
+		}
+
+		object[segment] = child
+
+		return object, nil
+	}
+
+	list, ok := node.([]any)
+	if node == nil {
+		ok = true
+	}
+
+	if !ok || index < 0 || index > len(list) {
+		return nil, fmt.Errorf("%d: %w", index, errBadIndex)
+	}
+
+	if index == len(list) {
+		list = append(list, nil)
+	}
+
+	child, err := setPath(list[index], rest, value)
+	if err != nil {
+		return nil, err
+	}
+
+	list[index] = child
+
+	return list, nil
+}
+
+// joinPath appends a field name to a path.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// describePath names the root of a document for errors.
+func describePath(path string) string {
+	if path == "" {
+		return "input"
+	}
+
+	return path
+}
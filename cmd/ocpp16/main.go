@@ -0,0 +1,157 @@
+// Command ocpp16 inspects OCPP-J captures, such as charger logs, and
+// builds valid payloads, so that both can be done without writing Go.
+//
+// Usage:
+//
+//	ocpp16 validate capture.log          # report invalid frames
+//	ocpp16 fmt < capture.jsonl           # pretty-print frames
+//	ocpp16 stats day1.log day2.log       # action counts and error rates
+//	ocpp16 gen SetChargingProfile -f profile.yaml
+//	ocpp16 gen Authorize -conf -set status=Accepted -id 42
+//
+// Frames are read from the files named, or from stdin when there are none
+// or the file is "-". Each line holds one frame: a bare OCPP-J array, a log
+// line with the array after a prefix, or a JSON object (JSONL) carrying the
+// array, or a string holding it, in its frame, message, msg, data or
+// payload property. Blank lines and lines starting with # are skipped.
+//
+// validate decodes the payload of every CALL, and of every CALLRESULT
+// answering a CALL of the same capture, with the message package of the
+// action, and prints each error with the path of the field at fault:
+//
+//	capture.log:3: CALL Authorize "42": idTag: <why the value is invalid>
+//
+// It exits with status 1 when any frame is invalid. An answer is matched
+// with its CALL by uniqueId and Charge Point, named by the last bracketed
+// word of a log prefix, such as [CP001], or by the chargePointId,
+// chargeBoxId, chargePoint, identity or source property of a JSON object,
+// so captures merged from several Charge Points can be read. Answers whose
+// CALL is not in the capture, as when it starts mid-session, are reported
+// as unmatched rather than invalid, and stats counts them apart.
+//
+// gen fills the ReqInput, or the ConfInput with -conf, of an action from a
+// YAML or JSON file and from -set flags, which override the file, and prints
+// the payload built by the Req or Conf constructor; -id wraps it in a CALL
+// or CALLRESULT frame. Keys are the field names of the input type in any
+// case, so idTag fills IdTag, and -set takes a dotted path such as
+// csChargingProfiles.chargingSchedule.chargingSchedulePeriod.0.limit=16.
+// When the input is rejected, gen prints the validation errors of the
+// library and exits with status 1. YAML is limited to block mappings and
+// sequences, quoted and plain scalars, JSON flow collections and comments.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+var errUnknownCommand = errors.New("unknown command")
+
+// command is a subcommand.
+type command struct {
+	// run runs the subcommand and returns the exit code.
+	run func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+	// summary is the one line description printed by usage.
+	summary string
+}
+
+// commands maps subcommand names to their implementation.
+var commands = map[string]command{
+	"fmt": {
+		run:     runFormat,
+		summary: "pretty-print frames",
+	},
+	"gen": {
+		run:     runGen,
+		summary: "build a valid payload from flags or a YAML/JSON file",
+	},
+	"stats": {
+		run:     runStats,
+		summary: "count frames and errors per action",
+	},
+	"validate": {
+		run:     runValidate,
+		summary: "report malformed frames and invalid payloads",
+	},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run dispatches to the subcommand named by the first argument and returns
+// the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "-h", "-help", "--help", "help":
+		usage(stdout)
+
+		return exitOK
+	}
+
+	subcommand, ok := commands[args[0]]
+	if !ok {
+		_, _ = fmt.Fprintf(stderr, "%v: %q\n", errUnknownCommand, args[0])
+		usage(stderr)
+
+		return exitUsage
+	}
+
+	return subcommand.run(args[1:], stdin, stdout, stderr)
+}
+
+// usage lists the subcommands.
+func usage(output io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	_, _ = fmt.Fprintln(output, "usage: ocpp16 <command> [flags] [file ...]")
+	_, _ = fmt.Fprintln(output, "commands:")
+
+	for _, name := range names {
+		_, _ = fmt.Fprintf(output, "  %-10s %s\n", name, commands[name].summary)
+	}
+}
+
+// newFlagSet returns the flag set of a subcommand.
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet("ocpp16 "+name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	return flags
+}
+
+// parseFlags parses the flags of a subcommand. When it returns false the
+// subcommand exits with the code returned.
+func parseFlags(flags *flag.FlagSet, args []string) (int, bool) {
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK, false
+	}
+
+	if err != nil {
+		return exitUsage, false
+	}
+
+	return exitOK, true
+}
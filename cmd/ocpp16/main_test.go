@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// capture mixes the line formats read by every command.
+const capture = `# charger CP001
+2025-01-02T15:00:00Z [CP001] >> [2,"1","Authorize",{"idTag":"TAG-1"}]
+2025-01-02T15:00:00Z [CP001] << [3,"1",{"idTagInfo":{"status":"Accepted"}}]
+{"time":"2025-01-02T15:00:01Z","frame":[2,"2","Heartbeat",{}]}
+{"message":"[3,\"2\",{\"currentTime\":\"2025-01-02T15:00:01Z\"}]"}
+
+[2,"3","Reset",{"type":"Soft"}]
+[4,"3","NotSupported","",{}]
+`
+
+// invalidCapture holds one invalid frame of each kind.
+const invalidCapture = `[2,"1","Authorize",{"idTag":"TAG-1","extra":1}]
+[3,"1",{"idTagInfo":{"status":"Maybe"}}]
+[3,"9",{}]
+[2,"2","Bogus",{}]
+not a frame
+`
+
+func runWith(t *testing.T, stdin string, args ...string) (int, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return code, stdout.String() + stderr.String()
+}
+
+func TestRun_Validate(t *testing.T) {
+	t.Parallel()
+
+	code, output := runWith(t, capture, "validate")
+	if code != exitOK {
+		t.Fatalf(types.ErrorMismatchValue, exitOK, code)
+	}
+
+	want := "6 frames, 0 invalid, 0 unmatched\n"
+	if output != want {
+		t.Errorf(types.ErrorMismatch, want, output)
+	}
+}
+
+func TestRun_ValidateMergedCaptures(t *testing.T) {
+	t.Parallel()
+
+	merged := `[CP001] >> [2,"1","Authorize",{"idTag":"TAG-1"}]
+[CP002] >> [2,"1","Heartbeat",{}]
+[CP001] << [3,"1",{"idTagInfo":{"status":"Accepted"}}]
+{"chargePointId":"CP003","frame":[2,"1","Heartbeat",{}]}
+[CP002] << [3,"1",{"currentTime":"2025-01-02T15:00:01Z"}]
+{"chargePointId":"CP003","frame":[3,"1",{"currentTime":"2025-01-02T15:00:01Z"}]}
+`
+
+	code, output := runWith(t, merged, "validate")
+	if code != exitOK {
+		t.Fatalf(types.ErrorMismatchValue, exitOK, code)
+	}
+
+	want := "6 frames, 0 invalid, 0 unmatched\n"
+	if output != want {
+		t.Errorf(types.ErrorMismatch, want, output)
+	}
+}
+
+func TestRun_ValidateUnmatched(t *testing.T) {
+	t.Parallel()
+
+	midSession := `[3,"7",{"currentTime":"2025-01-02T15:00:01Z"}]
+[4,"8","InternalError","",{}]
+`
+
+	code, output := runWith(t, midSession, "validate")
+	if code != exitOK {
+		t.Fatalf(types.ErrorMismatchValue, exitOK, code)
+	}
+
+	for _, want := range []string{
+		`-:1: CALLRESULT "7": ` + unmatchedNote,
+		`-:2: CALLERROR "8": ` + unmatchedNote,
+		"2 frames, 0 invalid, 2 unmatched",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf(types.ErrorWantContains, output, want)
+		}
+	}
+
+	_, output = runWith(t, midSession, "validate", "-q")
+	if output != "" {
+		t.Errorf(types.ErrorMismatch, "no output with -q", output)
+	}
+}
+
+func TestRun_ValidateInvalid(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "capture.log")
+
+	err := os.WriteFile(name, []byte(invalidCapture), 0o600)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	code, output := runWith(t, "", "validate", "-q", name)
+	if code != exitError {
+		t.Fatalf(types.ErrorMismatchValue, exitError, code)
+	}
+
+	for _, want := range []string{
+		name + `:1: CALL Authorize "1": `,
+		"/extra",
+		name + `:2: CALLRESULT Authorize "1": idTagInfo.status: `,
+		name + `:4: CALL Bogus "2": `,
+		name + ":5: frame: " + errNoFrame.Error(),
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf(types.ErrorWantContains, output, want)
+		}
+	}
+
+	if strings.Contains(output, "frames,") ||
+		strings.Contains(output, `CALLRESULT "9"`) {
+		t.Errorf(types.ErrorMismatch, "no summary with -q", output)
+	}
+}
+
+func TestRun_Format(t *testing.T) {
+	t.Parallel()
+
+	code, output := runWith(t, capture, "fmt", "-indent", "\t")
+	if code != exitOK {
+		t.Fatalf(types.ErrorMismatchValue, exitOK, code)
+	}
+
+	want := "# -:4 CALL Heartbeat \"2\"\n" +
+		"[\n\t2,\n\t\"2\",\n\t\"Heartbeat\",\n\t{}\n]\n"
+	if !strings.Contains(output, want) {
+		t.Errorf(types.ErrorWantContains, output, want)
+	}
+
+	code, _ = runWith(t, "not a frame\n", "fmt")
+	if code != exitError {
+		t.Errorf(types.ErrorMismatchValue, exitError, code)
+	}
+}
+
+func TestRun_Stats(t *testing.T) {
+	t.Parallel()
+
+	code, output := runWith(t, capture+invalidCapture, "stats")
+	if code != exitOK {
+		t.Fatalf(types.ErrorMismatchValue, exitOK, code)
+	}
+
+	lines := map[string][]string{}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 {
+			lines[fields[0]] = fields[1:]
+		}
+	}
+
+	tests := map[string]string{
+		"Authorize":   "4 2 2 0 2 0.0% 50.0%",
+		"Reset":       "2 1 0 1 0 100.0% 0.0%",
+		"(unknown)":   "1 0 0 0 1 0.0% 100.0%",
+		"(unmatched)": "1 0 1 0 0 0.0% 0.0%",
+		"TOTAL":       "11 5 4 1 4 20.0% 36.4%",
+	}
+
+	for action, want := range tests {
+		if got := strings.Join(lines[action], " "); got != want {
+			t.Errorf(types.ErrorMismatch, action+" "+want, got)
+		}
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{
+		nil,
+		{"lint"},
+		{"validate", "-unknown"},
+	} {
+		code, _ := runWith(t, "", args...)
+		if code != exitUsage {
+			t.Errorf(types.ErrorMismatchValue, exitUsage, code)
+		}
+	}
+
+	code, output := runWith(t, "", "help")
+	if code != exitOK || !strings.Contains(output, "validate") {
+		t.Errorf(types.ErrorWantContains, output, "validate")
+	}
+
+	code, _ = runWith(t, "", "stats", "missing.log")
+	if code != exitError {
+		t.Errorf(types.ErrorMismatchValue, exitError, code)
+	}
+}
+
+func TestRun_ValidateMissingProperty(t *testing.T) {
+	t.Parallel()
+
+	frame := `[2,"5","StopTransaction",{"timestamp":"2025-01-02T15:00:00Z"}]`
+
+	code, output := runWith(t, frame+"\n", "validate", "-q")
+	if code != exitError {
+		t.Fatalf(types.ErrorMismatchValue, exitError, code)
+	}
+
+	if !strings.Contains(output, "/transactionId") {
+		t.Errorf(types.ErrorWantContains, output, "/transactionId")
+	}
+}
+
+func TestRun_ValidateFieldPaths(t *testing.T) {
+	t.Parallel()
+
+	nested := `[2,"1","MeterValues",{"connectorId":1,"meterValue":[` +
+		`{"timestamp":"2025-01-02T15:00:00Z","sampledValue":[` +
+		`{"value":"1"},{"value":"2","unit":"bogus"}]}]}]` + "\n"
+
+	code, output := runWith(t, nested, "validate", "-q")
+	if code != exitError {
+		t.Fatalf(types.ErrorMismatchValue, exitError, code)
+	}
+
+	want := `-:1: CALL MeterValues "1": meterValue[0].sampledValue[1].unit: `
+	if !strings.HasPrefix(output, want) {
+		t.Errorf(types.ErrorWantContains, output, want)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+)
+
+// Rows of the frames whose action is unknown: unknownAction groups lines
+// without a well-formed frame, and unmatchedAction answers to a CALL missing
+// from the capture, which are not invalid.
+const (
+	unknownAction   = "(unknown)"
+	unmatchedAction = "(unmatched)"
+)
+
+// percent converts a ratio to a percentage.
+const percent = 100
+
+// actionStats counts the frames of one action.
+type actionStats struct {
+	frames     int
+	calls      int
+	results    int
+	callErrors int
+	invalid    int
+}
+
+// runStats prints, per action, the number of frames, of CALL, CALLRESULT and
+// CALLERROR frames and of invalid frames, with the share of CALLs answered
+// by a CALLERROR and the share of invalid frames.
+func runStats(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("stats", stderr)
+
+	code, ok := parseFlags(flags, args)
+	if !ok {
+		return code
+	}
+
+	stats := map[string]*actionStats{}
+
+	err := readCaptures(flags.Args(), stdin, func(found entry) {
+		action := found.action
+
+		switch {
+		case found.unmatched:
+			action = unmatchedAction
+		case action == "":
+			action = unknownAction
+		}
+
+		counts, ok := stats[action]
+		if !ok {
+			counts = new(actionStats)
+			stats[action] = counts
+		}
+
+		counts.add(found)
+	})
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+
+		return exitError
+	}
+
+	printStats(stdout, stats)
+
+	return exitOK
+}
+
+// add counts a frame.
+func (s *actionStats) add(found entry) {
+	s.frames++
+
+	if len(problems(found)) > 0 {
+		s.invalid++
+	}
+
+	if found.raw == nil || found.frame.UniqueId == "" {
+		return
+	}
+
+	switch found.frame.Type {
+	case ocppj.MessageTypeCall:
+		s.calls++
+	case ocppj.MessageTypeCallResult:
+		s.results++
+	case ocppj.MessageTypeCallError:
+		s.callErrors++
+	}
+}
+
+// print prints one row of the table.
+func (s *actionStats) print(output io.Writer, action string) {
+	_, _ = fmt.Fprintf(
+		output,
+		"%-32s %6d %6d %7d %9d %7d %7.1f%% %7.1f%%\n",
+		action,
+		s.frames,
+		s.calls,
+		s.results,
+		s.callErrors,
+		s.invalid,
+		ratio(s.callErrors, s.calls),
+		ratio(s.invalid, s.frames),
+	)
+}
+
+// printStats prints the table in action order, then the totals.
+func printStats(output io.Writer, stats map[string]*actionStats) {
+	actions := make([]string, 0, len(stats))
+	for action := range stats {
+		actions = append(actions, action)
+	}
+
+	slices.Sort(actions)
+
+	_, _ = fmt.Fprintf(
+		output,
+		"%-32s %6s %6s %7s %9s %7s %8s %8s\n",
+		"ACTION",
+		"FRAMES",
+		"CALL",
+		"RESULT",
+		"CALLERROR",
+		"INVALID",
+		"ERROR%",
+		"INVALID%",
+	)
+
+	var total actionStats
+
+	for _, action := range actions {
+		counts := stats[action]
+		counts.print(output, action)
+
+		total.frames += counts.frames
+		total.calls += counts.calls
+		total.results += counts.results
+		total.callErrors += counts.callErrors
+		total.invalid += counts.invalid
+	}
+
+	total.print(output, "TOTAL")
+}
+
+// ratio returns part of whole as a percentage, 0 for an empty whole.
+func ratio(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+
+	return float64(part) * percent / float64(whole)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+)
+
+// unmatchedNote follows an answer to a CALL missing from the capture.
+const unmatchedNote = "unmatched: no CALL with this uniqueId earlier, " +
+	"payload not checked"
+
+// runValidate reports every frame that is malformed or whose payload the
+// message package of its action rejects. Answers to a CALL missing from the
+// capture are listed as unmatched, as their payload cannot be checked, but
+// are not invalid. It exits with exitError when any frame is invalid.
+func runValidate(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("validate", stderr)
+	quiet := flags.Bool("q", false, "print only the invalid frames")
+
+	code, ok := parseFlags(flags, args)
+	if !ok {
+		return code
+	}
+
+	frames, invalid, unmatched := 0, 0, 0
+
+	err := readCaptures(flags.Args(), stdin, func(found entry) {
+		frames++
+
+		if found.unmatched {
+			unmatched++
+
+			if !*quiet {
+				_, _ = fmt.Fprintf(
+					stdout,
+					"%s: %s: %s\n",
+					found.location,
+					describe(found),
+					unmatchedNote,
+				)
+			}
+
+			return
+		}
+
+		lines := problems(found)
+		if len(lines) == 0 {
+			return
+		}
+
+		invalid++
+
+		for _, line := range lines {
+			_, _ = fmt.Fprintf(
+				stdout,
+				"%s: %s: %s\n",
+				found.location,
+				describe(found),
+				line,
+			)
+		}
+	})
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+
+		return exitError
+	}
+
+	if !*quiet {
+		_, _ = fmt.Fprintf(
+			stdout,
+			"%d frames, %d invalid, %d unmatched\n",
+			frames,
+			invalid,
+			unmatched,
+		)
+	}
+
+	if invalid > 0 {
+		return exitError
+	}
+
+	return exitOK
+}
+
+// problems returns the errors of a frame, one per field: the frame error,
+// or the errors of decoding its payload with the package of its action.
+// Unmatched answers have none, as their action is unknown.
+// Field errors carry the full path of the field, e.g. idTagInfo.status.
+func problems(found entry) []string {
+	if found.unmatched {
+		return nil
+	}
+
+	if found.err != nil {
+		return errorLines(found.err)
+	}
+
+	err := decodePayload(found)
+	if err == nil {
+		return nil
+	}
+
+	lines := errorLines(err)
+	for i, line := range lines {
+		lines[i] = withFieldPath(found.frame.Payload, line)
+	}
+
+	return lines
+}
+
+// errorLines splits an error, such as the errors.Join of the field errors
+// of a constructor, into its lines.
+func errorLines(err error) []string {
+	var lines []string
+
+	for _, line := range strings.Split(err.Error(), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// decodePayload decodes the payload of a CALL or CALLRESULT into its
+// message type, which validates it.
+func decodePayload(found entry) error {
+	var err error
+
+	switch found.frame.Type {
+	case ocppj.MessageTypeCall:
+		_, err = ocppj.DecodeRequest(found.action, found.frame.Payload)
+	case ocppj.MessageTypeCallResult:
+		_, err = ocppj.DecodeConfirmation(found.action, found.frame.Payload)
+	case ocppj.MessageTypeCallError:
+	}
+
+	return err
+}
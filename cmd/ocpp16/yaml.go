@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	errYAMLSyntax      = errors.New("invalid YAML")
+	errYAMLUnsupported = errors.New("unsupported YAML")
+)
+
+// yamlLine is a significant line of a YAML document.
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+// yamlParser parses the YAML subset gen reads: block mappings and
+// sequences, plain, single and double quoted scalars, JSON flow collections
+// and comments. Anchors, tags, block scalars and multiple documents are
+// rejected. Plain scalars stay strings; gen converts them to the type of
+// the field they fill. Null, ~ and empty values are nil.
+type yamlParser struct {
+	lines []yamlLine
+	next  int
+}
+
+// parseYAML parses a YAML document into maps, slices, strings and nils.
+func parseYAML(data []byte) (any, error) {
+	lines, err := yamlLines(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lines) == 0 {
+		return nil, errEmptyInput
+	}
+
+	parser := &yamlParser{lines: lines, next: 0}
+
+	value, err := parser.block(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+
+	if parser.next < len(lines) {
+		return nil, parser.fail("unexpected line or indentation")
+	}
+
+	return value, nil
+}
+
+// yamlLines splits a document into lines without comments or blank lines.
+func yamlLines(data []byte) ([]yamlLine, error) {
+	var lines []yamlLine
+
+	for index, raw := range strings.Split(string(data), "\n") {
+		raw = strings.TrimRight(stripComment(raw), " \t\r")
+		text := strings.TrimLeft(raw, " ")
+		number := index + 1
+
+		switch {
+		case text == "":
+			continue
+		case text[0] == '\t':
+			return nil, fmt.Errorf(
+				"%w: line %d: tab indentation",
+				errYAMLSyntax,
+				number,
+			)
+		case text == "---" && len(lines) == 0:
+			continue
+		case text == "---" || text == "...":
+			return nil, fmt.Errorf(
+				"%w: line %d: multiple documents",
+				errYAMLUnsupported,
+				number,
+			)
+		}
+
+		lines = append(lines, yamlLine{
+			number: number,
+			indent: len(raw) - len(text),
+			text:   text,
+		})
+	}
+
+	return lines, nil
+}
+
+// stripComment removes a comment: a # at the start of the line or after a
+// space, outside quotes.
+func stripComment(line string) string {
+	var quote byte
+
+	for index := range len(line) {
+		char := line[index]
+
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '"' || char == '\'':
+			quote = char
+		case char == '#' && (index == 0 || line[index-1] == ' '):
+			return line[:index]
+		}
+	}
+
+	return line
+}
+
+// block parses the mapping or sequence starting at the current line.
+func (p *yamlParser) block(indent int) (any, error) {
+	if isSequenceItem(p.lines[p.next].text) {
+		return p.sequence(indent)
+	}
+
+	return p.mapping(indent)
+}
+
+// sequence parses the items of a block sequence.
+func (p *yamlParser) sequence(indent int) (any, error) {
+	items := []any{}
+
+	// A sequence below a key may share its indentation, and then ends at
+	// the next key.
+	for p.next < len(p.lines) && p.lines[p.next].indent == indent &&
+		isSequenceItem(p.lines[p.next].text) {
+		line := p.lines[p.next]
+		rest := strings.TrimLeft(line.text[1:], " ")
+
+		var (
+			item any
+			err  error
+		)
+
+		switch {
+		case rest == "":
+			item, err = p.nested(indent)
+		case isMappingEntry(rest):
+			// The item is a mapping starting on the line of its dash.
+			p.lines[p.next].indent += len(line.text) - len(rest)
+			p.lines[p.next].text = rest
+			item, err = p.mapping(p.lines[p.next].indent)
+		default:
+			p.next++
+			item, err = p.scalar(line, rest)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// mapping parses the entries of a block mapping.
+func (p *yamlParser) mapping(indent int) (any, error) {
+	entries := map[string]any{}
+
+	for p.next < len(p.lines) && p.lines[p.next].indent == indent {
+		line := p.lines[p.next]
+
+		key, rest, ok := splitMappingEntry(line.text)
+		if !ok {
+			return nil, p.fail("want key: value")
+		}
+
+		if _, duplicate := entries[key]; duplicate {
+			return nil, p.fail(fmt.Sprintf("duplicate key %q", key))
+		}
+
+		var (
+			value any
+			err   error
+		)
+
+		if rest == "" {
+			value, err = p.nested(indent)
+		} else {
+			p.next++
+			value, err = p.scalar(line, rest)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		entries[key] = value
+	}
+
+	return entries, nil
+}
+
+// nested parses the block below a key or dash without a value on its line,
+// nil when there is none. A sequence may sit at the indentation of its key.
+func (p *yamlParser) nested(indent int) (any, error) {
+	p.next++
+
+	if p.next == len(p.lines) {
+		return nil, nil //nolint:nilnil // An empty value is null.
+	}
+
+	line := p.lines[p.next]
+
+	switch {
+	case line.indent > indent:
+		return p.block(line.indent)
+	case line.indent == indent && isSequenceItem(line.text) &&
+		!isSequenceItem(p.lines[p.next-1].text):
+		return p.sequence(indent)
+	default:
+		return nil, nil //nolint:nilnil // An empty value is null.
+	}
+}
+
+// scalar parses the value written on a line.
+func (p *yamlParser) scalar(line yamlLine, text string) (any, error) {
+	fail := func(reason string, kind error) error {
+		return fmt.Errorf("%w: line %d: %s", kind, line.number, reason)
+	}
+
+	switch text[0] {
+	case '"':
+		var value string
+
+		err := json.Unmarshal([]byte(text), &value)
+		if err != nil {
+			return nil, fail("invalid double quoted string", errYAMLSyntax)
+		}
+
+		return value, nil
+	case '\'':
+		if len(text) < 2 || text[len(text)-1] != '\'' {
+			return nil, fail("unterminated single quoted string", errYAMLSyntax)
+		}
+
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	case '[', '{':
+		decoder := json.NewDecoder(bytes.NewReader([]byte(text)))
+		decoder.UseNumber()
+
+		var value any
+
+		err := decoder.Decode(&value)
+		if err != nil || decoder.More() {
+			return nil, fail("non-JSON flow collection", errYAMLUnsupported)
+		}
+
+		return value, nil
+	case '|', '>':
+		return nil, fail("block scalars", errYAMLUnsupported)
+	case '&', '*', '!':
+		return nil, fail("anchors, aliases and tags", errYAMLUnsupported)
+	}
+
+	switch text {
+	case "~", "null", "Null", "NULL":
+		return nil, nil //nolint:nilnil // A null value is nil.
+	}
+
+	return text, nil
+}
+
+// fail returns a syntax error at the current line.
+func (p *yamlParser) fail(reason string) error {
+	return fmt.Errorf(
+		"%w: line %d: %s",
+		errYAMLSyntax,
+		p.lines[p.next].number,
+		reason,
+	)
+}
+
+// isSequenceItem reports whether a line starts a sequence item.
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// isMappingEntry reports whether a text starts with a key.
+func isMappingEntry(text string) bool {
+	_, _, ok := splitMappingEntry(text)
+
+	return ok
+}
+
+// splitMappingEntry splits "key: value" into its key and value text. The
+// key may be quoted.
+func splitMappingEntry(text string) (string, string, bool) {
+	key, rest := text, ""
+
+	if text[0] == '"' || text[0] == '\'' {
+		end := strings.IndexByte(text[1:], text[0])
+		if end < 0 {
+			return "", "", false
+		}
+
+		key, rest = text[1:end+1], text[end+2:]
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", false
+		}
+
+		rest = rest[1:]
+	} else {
+		colon := strings.Index(text, ": ")
+		if colon < 0 && strings.HasSuffix(text, ":") {
+			colon = len(text) - 1
+		}
+
+		if colon <= 0 || text[0] == '[' || text[0] == '{' {
+			return "", "", false
+		}
+
+		key, rest = strings.TrimSpace(text[:colon]), text[colon+1:]
+	}
+
+	if rest != "" && rest[0] != ' ' {
+		return "", "", false
+	}
+
+	return key, strings.TrimSpace(rest), true
+}
@@ -0,0 +1,202 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+)
+
+var (
+	// ErrUnexpectedCall is returned when the system under test sends a CALL
+	// that is neither expected nor tolerated.
+	ErrUnexpectedCall = errors.New("conformance: unexpected call")
+	// ErrUnexpectedAnswer is returned when a CALL is answered differently
+	// from what the step requires.
+	ErrUnexpectedAnswer = errors.New("conformance: unexpected answer")
+	// ErrTimeout is returned when a step does not complete within the step
+	// timeout.
+	ErrTimeout = errors.New("conformance: timeout")
+)
+
+// Case is a declarative test case.
+type Case struct {
+	// Id identifies the case in reports, e.g. "CP_BOOT_PENDING".
+	Id string
+	// Name describes the case, e.g. "Cold boot with Pending registration".
+	Name string
+	// Target is the role of the system under test. The tester plays the
+	// other role.
+	Target ocppj.Role
+	// Tolerate lists the actions answered with a default and skipped while
+	// an Expect step waits. nil selects DefaultTolerated(Target).
+	Tolerate []string
+	// Steps are played in order; the case fails at the first failing step.
+	Steps []Step
+}
+
+// Step is one action of the tester. Create steps with Send, SendFunc,
+// SendRejected, Expect and Sleep.
+type Step struct {
+	// Description is shown in reports, e.g. "expect StartTransaction".
+	Description string
+
+	run func(ctx context.Context, s *session) error
+}
+
+// DefaultTolerated returns the actions tolerated while waiting when no
+// Case.Tolerate is set: the periodic and status messages of a Charge Point,
+// and nothing for a Central System.
+func DefaultTolerated(target ocppj.Role) []string {
+	if target != ocppj.RoleChargePoint {
+		return nil
+	}
+
+	return []string{
+		ocppj.ActionBootNotification,
+		ocppj.ActionHeartbeat,
+		ocppj.ActionStatusNotification,
+		ocppj.ActionMeterValues,
+	}
+}
+
+// Send returns a step that sends a CALL and decodes the confirmation into a
+// Conf, e.g. reset.ConfMessage, which validates it. check, when not nil,
+// inspects the confirmation; returning an error fails the step.
+func Send[Conf any](
+	action string,
+	request any,
+	check func(Conf) error,
+) Step {
+	return SendFunc(
+		action,
+		func() (any, error) { return request, nil },
+		check,
+	)
+}
+
+// SendFunc is Send with a request built when the step runs, so it can use
+// values captured by earlier steps, such as a transaction id.
+func SendFunc[Conf any](
+	action string,
+	build func() (any, error),
+	check func(Conf) error,
+) Step {
+	return Step{
+		Description: "send " + action,
+		run: func(ctx context.Context, s *session) error {
+			request, err := build()
+			if err != nil {
+				return fmt.Errorf("%s.req: %w", action, err)
+			}
+
+			var conf Conf
+
+			err = s.conn.Call(ctx, action, request, &conf)
+			if err != nil {
+				return fmt.Errorf("%s: %w", action, err)
+			}
+
+			if check == nil {
+				return nil
+			}
+
+			err = check(conf)
+			if err != nil {
+				return fmt.Errorf("%s.conf: %w", action, err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// SendRejected returns a step that sends a CALL which must be answered with
+// a CALLERROR carrying code.
+func SendRejected(action string, request any, code ocppj.ErrorCode) Step {
+	return Step{
+		Description: fmt.Sprintf("send %s, expect %s", action, code),
+		run: func(ctx context.Context, s *session) error {
+			err := s.conn.Call(ctx, action, request, nil)
+			if errors.Is(err, ocppj.NewError(code, "")) {
+				return nil
+			}
+
+			if err == nil {
+				return fmt.Errorf(
+					"%s: %w: CALLRESULT, want %s",
+					action,
+					ErrUnexpectedAnswer,
+					code,
+				)
+			}
+
+			return fmt.Errorf(
+				"%s: %w: %w, want %s",
+				action,
+				ErrUnexpectedAnswer,
+				err,
+				code,
+			)
+		},
+	}
+}
+
+// Expect returns a step that waits for a CALL of action whose request, a
+// Req such as starttransaction.ReqMessage, passes match. match may be nil.
+// respond builds the answer; nil answers with the default of the action and
+// an *ocppj.Error answers with a CALLERROR. A CALL of action that does not
+// pass match is skipped when the action is tolerated and fails the step
+// otherwise.
+func Expect[Req any](
+	action string,
+	match func(Req) error,
+	respond func(Req) (any, error),
+) Step {
+	return Step{
+		Description: "expect " + action,
+		run: func(ctx context.Context, s *session) error {
+			return s.expect(
+				ctx,
+				action,
+				func(request any) error {
+					req, ok := request.(Req)
+					if !ok {
+						return fmt.Errorf(
+							"%w: request type %T",
+							ErrUnexpectedCall,
+							request,
+						)
+					}
+
+					if match == nil {
+						return nil
+					}
+
+					return match(req)
+				},
+				func(request any) (any, error) {
+					req, _ := request.(Req)
+					if respond == nil {
+						return defaultResponse(action)
+					}
+
+					return respond(req)
+				},
+			)
+		},
+	}
+}
+
+// Sleep returns a step that pauses the case, e.g. to give a Charge Point
+// time to apply a configuration. Tolerated CALLs are answered meanwhile.
+func Sleep(duration time.Duration) Step {
+	return Step{
+		Description: fmt.Sprintf("sleep %s", duration),
+		run: func(ctx context.Context, s *session) error {
+			return s.idle(ctx, duration)
+		},
+	}
+}
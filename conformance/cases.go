@@ -0,0 +1,515 @@
+package conformance
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/clearchargingprofile"
+	"github.com/aasanchez/ocpp16messages/getcompositeschedule"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/remotestoptransaction"
+	"github.com/aasanchez/ocpp16messages/reservenow"
+	"github.com/aasanchez/ocpp16messages/setchargingprofile"
+	"github.com/aasanchez/ocpp16messages/starttransaction"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	"github.com/aasanchez/ocpp16messages/stoptransaction"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	statusPending   = "Pending"
+	statusReserved  = "Reserved"
+	statusCharging  = "Charging"
+	statusAvailable = "Available"
+	errorCodeNone   = "NoError"
+
+	purposeTxDefault = "TxDefaultProfile"
+	kindRelative     = "Relative"
+	unitAmpere       = "A"
+
+	// pendingInterval is the retry interval in seconds sent with a Pending
+	// registration.
+	pendingInterval     = 1
+	reservationLifetime = time.Hour
+	scheduleDuration    = 3600
+	defaultLimit        = 16.0
+)
+
+// errStatus is returned by the checks of the built-in cases.
+var errStatus = errors.New("unexpected status")
+
+// Options parameterizes the built-in cases.
+type Options struct {
+	// ConnectorId is the connector used by transaction and profile cases.
+	ConnectorId int
+	// IdTag is the idTag used to authorize, reserve and start.
+	IdTag string
+	// ReservationId is the id of the ReserveNow request.
+	ReservationId int
+	// TransactionId is handed out by the tester when it plays the Central
+	// System.
+	TransactionId int
+	// ChargingProfileId is the id of the SetChargingProfile request.
+	ChargingProfileId int
+}
+
+// DefaultOptions returns the options used by the examples and tests.
+func DefaultOptions() Options {
+	return Options{
+		ConnectorId:       1,
+		IdTag:             "CONFORMANCE-TAG",
+		ReservationId:     1,
+		TransactionId:     1,
+		ChargingProfileId: 1,
+	}
+}
+
+// ChargePointCases returns the built-in cases for a Charge Point under test.
+func ChargePointCases(opts Options) ([]Case, error) {
+	reservation, errReservation := RemoteStartWithReservation(opts)
+	profile, errProfile := SetTxDefaultProfile(opts)
+
+	err := errors.Join(errReservation, errProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Case{ColdBootPending(), reservation, profile}, nil
+}
+
+// CentralSystemCases returns the built-in cases for a Central System under
+// test.
+func CentralSystemCases(opts Options) ([]Case, error) {
+	boot, errBoot := CentralSystemBoot()
+	transaction, errTransaction := CentralSystemTransaction(opts)
+
+	err := errors.Join(errBoot, errTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Case{boot, transaction}, nil
+}
+
+// ColdBootPending checks that a Charge Point retries BootNotification after
+// a Pending registration and reports its connectors once accepted. The
+// Charge Point must boot when it connects.
+func ColdBootPending() Case {
+	return Case{
+		Id:     "CP_BOOT_PENDING",
+		Name:   "Cold boot with Pending registration",
+		Target: ocppj.RoleChargePoint,
+		Tolerate: []string{
+			ocppj.ActionHeartbeat,
+			ocppj.ActionStatusNotification,
+			ocppj.ActionMeterValues,
+		},
+		Steps: []Step{
+			Expect(
+				ocppj.ActionBootNotification,
+				nil,
+				func(bootnotification.ReqMessage) (any, error) {
+					return bootAnswer(statusPending, pendingInterval)
+				},
+			),
+			Expect(
+				ocppj.ActionBootNotification,
+				nil,
+				func(bootnotification.ReqMessage) (any, error) {
+					return bootAnswer(statusAccepted, DefaultHeartbeatInterval)
+				},
+			),
+			Expect[statusnotification.ReqMessage](
+				ocppj.ActionStatusNotification,
+				nil,
+				nil,
+			),
+		},
+	}
+}
+
+// RemoteStartWithReservation reserves a connector, starts a transaction
+// remotely on it and checks that StartTransaction carries the reservation,
+// then stops the transaction remotely.
+func RemoteStartWithReservation(opts Options) (Case, error) {
+	reserve, errReserve := reservenow.Req(reservenow.ReqInput{
+		ReservationId: opts.ReservationId,
+		ConnectorId:   opts.ConnectorId,
+		IdTag:         opts.IdTag,
+		ExpiryDate: time.Now().Add(reservationLifetime).
+			UTC().
+			Format(timestampLayout),
+		ParentIdTag: nil,
+	})
+	start, errStart := remotestarttransaction.Req(
+		remotestarttransaction.ReqInput{
+			IdTag:           opts.IdTag,
+			ConnectorId:     &opts.ConnectorId,
+			ChargingProfile: nil,
+		},
+	)
+	stop, errStop := remotestoptransaction.Req(
+		remotestoptransaction.ReqInput{TransactionId: opts.TransactionId},
+	)
+
+	err := errors.Join(errReserve, errStart, errStop)
+	if err != nil {
+		return Case{}, fmt.Errorf("RemoteStartWithReservation: %w", err)
+	}
+
+	return Case{
+		Id:       "CP_REMOTE_START_RESERVATION",
+		Name:     "Remote start with reservation",
+		Target:   ocppj.RoleChargePoint,
+		Tolerate: nil,
+		Steps: []Step{
+			Send(
+				ocppj.ActionReserveNow,
+				reserve,
+				func(conf reservenow.ConfMessage) error {
+					return statusIs(conf.Status, statusAccepted)
+				},
+			),
+			expectStatus(opts.ConnectorId, statusReserved),
+			Send(
+				ocppj.ActionRemoteStartTransaction,
+				start,
+				func(conf remotestarttransaction.ConfMessage) error {
+					return statusIs(conf.Status, statusAccepted)
+				},
+			),
+			Expect(
+				ocppj.ActionStartTransaction,
+				func(req starttransaction.ReqMessage) error {
+					return checkStart(req, opts)
+				},
+				func(starttransaction.ReqMessage) (any, error) {
+					return starttransaction.Conf(starttransaction.ConfInput{
+						TransactionId: opts.TransactionId,
+						Status:        statusAccepted,
+						ExpiryDate:    nil,
+						ParentIdTag:   nil,
+					})
+				},
+			),
+			expectStatus(opts.ConnectorId, statusCharging),
+			Send(
+				ocppj.ActionRemoteStopTransaction,
+				stop,
+				func(conf remotestoptransaction.ConfMessage) error {
+					return statusIs(conf.Status, statusAccepted)
+				},
+			),
+			Expect(
+				ocppj.ActionStopTransaction,
+				func(req stoptransaction.ReqMessage) error {
+					return valueIs(
+						"transactionId",
+						int(req.TransactionId.Value()),
+						opts.TransactionId,
+					)
+				},
+				nil,
+			),
+		},
+	}, nil
+}
+
+// SetTxDefaultProfile installs a TxDefaultProfile on the Charge Point,
+// reads the composite schedule of the connector and clears the profile.
+func SetTxDefaultProfile(opts Options) (Case, error) {
+	set, errSet := setchargingprofile.Req(setchargingprofile.ReqInput{
+		ConnectorId: 0,
+		CsChargingProfiles: types.ChargingProfileInput{
+			ChargingProfileId:      opts.ChargingProfileId,
+			TransactionId:          nil,
+			StackLevel:             0,
+			ChargingProfilePurpose: purposeTxDefault,
+			ChargingProfileKind:    kindRelative,
+			RecurrencyKind:         nil,
+			ValidFrom:              nil,
+			ValidTo:                nil,
+			ChargingSchedule: types.ChargingScheduleInput{
+				Duration:         nil,
+				StartSchedule:    nil,
+				ChargingRateUnit: unitAmpere,
+				ChargingSchedulePeriod: []types.ChargingSchedulePeriodInput{
+					{StartPeriod: 0, Limit: defaultLimit, NumberPhases: nil},
+				},
+				MinChargingRate: nil,
+			},
+		},
+	})
+	unit := unitAmpere
+	composite, errComposite := getcompositeschedule.Req(
+		getcompositeschedule.ReqInput{
+			ConnectorId:      opts.ConnectorId,
+			Duration:         scheduleDuration,
+			ChargingRateUnit: &unit,
+		},
+	)
+	clear, errClear := clearchargingprofile.Req(clearchargingprofile.ReqInput{
+		Id:                     &opts.ChargingProfileId,
+		ConnectorId:            nil,
+		ChargingProfilePurpose: nil,
+		StackLevel:             nil,
+	})
+
+	err := errors.Join(errSet, errComposite, errClear)
+	if err != nil {
+		return Case{}, fmt.Errorf("SetTxDefaultProfile: %w", err)
+	}
+
+	return Case{
+		Id:       "CP_SET_TX_DEFAULT_PROFILE",
+		Name:     "SetChargingProfile TxDefaultProfile",
+		Target:   ocppj.RoleChargePoint,
+		Tolerate: nil,
+		Steps: []Step{
+			Send(
+				ocppj.ActionSetChargingProfile,
+				set,
+				func(conf setchargingprofile.ConfMessage) error {
+					return statusIs(conf.Status, statusAccepted)
+				},
+			),
+			Send(
+				ocppj.ActionGetCompositeSchedule,
+				composite,
+				func(conf getcompositeschedule.ConfMessage) error {
+					return statusIs(conf.Status, statusAccepted)
+				},
+			),
+			Send(
+				ocppj.ActionClearChargingProfile,
+				clear,
+				func(conf clearchargingprofile.ConfMessage) error {
+					return statusIs(conf.Status, statusAccepted)
+				},
+			),
+		},
+	}, nil
+}
+
+// CentralSystemBoot checks that a Central System accepts a BootNotification.
+func CentralSystemBoot() (Case, error) {
+	boot, err := bootRequest()
+	if err != nil {
+		return Case{}, fmt.Errorf("CentralSystemBoot: %w", err)
+	}
+
+	return Case{
+		Id:       "CS_BOOT",
+		Name:     "Cold boot",
+		Target:   ocppj.RoleCentralSystem,
+		Tolerate: nil,
+		Steps: []Step{
+			Send(
+				ocppj.ActionBootNotification,
+				boot,
+				func(conf bootnotification.ConfMessage) error {
+					return statusIs(conf.Status, statusAccepted)
+				},
+			),
+		},
+	}, nil
+}
+
+// CentralSystemTransaction plays a locally started transaction against a
+// Central System: boot, Authorize, StartTransaction, StatusNotification
+// Charging and StopTransaction with the transaction id it handed out.
+func CentralSystemTransaction(opts Options) (Case, error) {
+	boot, errBoot := bootRequest()
+	auth, errAuth := authorize.Req(authorize.ReqInput{IdTag: opts.IdTag})
+	charging, errCharging := statusRequest(opts.ConnectorId, statusCharging)
+	available, errAvailable := statusRequest(
+		opts.ConnectorId,
+		statusAvailable,
+	)
+
+	err := errors.Join(errBoot, errAuth, errCharging, errAvailable)
+	if err != nil {
+		return Case{}, fmt.Errorf("CentralSystemTransaction: %w", err)
+	}
+
+	var transactionId int
+
+	return Case{
+		Id:       "CS_LOCAL_TRANSACTION",
+		Name:     "Local start and stop transaction",
+		Target:   ocppj.RoleCentralSystem,
+		Tolerate: nil,
+		Steps: []Step{
+			Send(
+				ocppj.ActionBootNotification,
+				boot,
+				func(conf bootnotification.ConfMessage) error {
+					return statusIs(conf.Status, statusAccepted)
+				},
+			),
+			Send(
+				ocppj.ActionAuthorize,
+				auth,
+				func(conf authorize.ConfMessage) error {
+					return statusIs(conf.IdTagInfo.Status(), statusAccepted)
+				},
+			),
+			SendFunc(
+				ocppj.ActionStartTransaction,
+				func() (any, error) {
+					return starttransaction.Req(starttransaction.ReqInput{
+						ConnectorId:   opts.ConnectorId,
+						IdTag:         opts.IdTag,
+						MeterStart:    0,
+						Timestamp:     Now(),
+						ReservationId: nil,
+					})
+				},
+				func(conf starttransaction.ConfMessage) error {
+					transactionId = int(conf.TransactionId.Value())
+
+					return statusIs(conf.IdTagInfo.Status(), statusAccepted)
+				},
+			),
+			Send[statusnotification.ConfMessage](
+				ocppj.ActionStatusNotification,
+				charging,
+				nil,
+			),
+			SendFunc[stoptransaction.ConfMessage](
+				ocppj.ActionStopTransaction,
+				func() (any, error) {
+					return stoptransaction.Req(stoptransaction.ReqInput{
+						TransactionId:   transactionId,
+						IdTag:           &opts.IdTag,
+						MeterStop:       0,
+						Timestamp:       Now(),
+						Reason:          nil,
+						TransactionData: nil,
+					})
+				},
+				nil,
+			),
+			Send[statusnotification.ConfMessage](
+				ocppj.ActionStatusNotification,
+				available,
+				nil,
+			),
+		},
+	}, nil
+}
+
+// expectStatus waits for a StatusNotification of status on connectorId.
+func expectStatus(connectorId int, status string) Step {
+	step := Expect(
+		ocppj.ActionStatusNotification,
+		func(req statusnotification.ReqMessage) error {
+			return errors.Join(
+				valueIs(
+					"connectorId",
+					int(req.ConnectorId.Value()),
+					connectorId,
+				),
+				statusIs(req.Status, status),
+			)
+		},
+		nil,
+	)
+	step.Description = fmt.Sprintf(
+		"expect StatusNotification %s on connector %d",
+		status,
+		connectorId,
+	)
+
+	return step
+}
+
+// checkStart checks that StartTransaction uses the reserved connector,
+// idTag and reservation.
+func checkStart(req starttransaction.ReqMessage, opts Options) error {
+	reservationId := 0
+	if req.ReservationId != nil {
+		reservationId = int(req.ReservationId.Value())
+	}
+
+	idTagErr := error(nil)
+	if req.IdTag.String() != opts.IdTag {
+		idTagErr = fmt.Errorf(
+			"idTag: %w: %s, want %s",
+			errStatus,
+			req.IdTag.String(),
+			opts.IdTag,
+		)
+	}
+
+	return errors.Join(
+		valueIs("connectorId", int(req.ConnectorId.Value()), opts.ConnectorId),
+		valueIs("reservationId", reservationId, opts.ReservationId),
+		idTagErr,
+	)
+}
+
+// bootAnswer builds a BootNotification confirmation.
+func bootAnswer(status string, interval int) (any, error) {
+	return bootnotification.Conf(bootnotification.ConfInput{
+		Status:      status,
+		CurrentTime: Now(),
+		Interval:    interval,
+	})
+}
+
+// bootRequest builds the BootNotification sent when playing a Charge Point.
+func bootRequest() (bootnotification.ReqMessage, error) {
+	return bootnotification.Req(bootnotification.ReqInput{
+		ChargePointVendor:       "ocpp16messages",
+		ChargePointModel:        "conformance",
+		ChargePointSerialNumber: nil,
+		ChargeBoxSerialNumber:   nil,
+		FirmwareVersion:         nil,
+		Iccid:                   nil,
+		Imsi:                    nil,
+		MeterType:               nil,
+		MeterSerialNumber:       nil,
+	})
+}
+
+// statusRequest builds a StatusNotification sent when playing a Charge
+// Point.
+func statusRequest(
+	connectorId int,
+	status string,
+) (statusnotification.ReqMessage, error) {
+	timestamp := Now()
+
+	return statusnotification.Req(statusnotification.ReqInput{
+		ConnectorId:     connectorId,
+		ErrorCode:       errorCodeNone,
+		Status:          status,
+		Info:            nil,
+		Timestamp:       &timestamp,
+		VendorId:        nil,
+		VendorErrorCode: nil,
+	})
+}
+
+// statusIs compares an enumeration value with the wanted status.
+func statusIs(got fmt.Stringer, want string) error {
+	if got.String() != want {
+		return fmt.Errorf("status: %w: %s, want %s", errStatus, got, want)
+	}
+
+	return nil
+}
+
+// valueIs compares an integer field with the wanted value.
+func valueIs(field string, got, want int) error {
+	if got != want {
+		return fmt.Errorf("%s: %w: %d, want %d", field, errStatus, got, want)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package conformance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/diagnosticsstatusnotification"
+	"github.com/aasanchez/ocpp16messages/firmwarestatusnotification"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/metervalues"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	"github.com/aasanchez/ocpp16messages/stoptransaction"
+)
+
+const (
+	// DefaultHeartbeatInterval is the interval in seconds of the default
+	// BootNotification answer.
+	DefaultHeartbeatInterval = 300
+
+	statusAccepted  = "Accepted"
+	timestampLayout = "2006-01-02T15:04:05Z"
+)
+
+// defaultResponse returns the answer to a CALL no step scripted. Actions
+// without a sensible default, such as StartTransaction whose transaction
+// id must come from the case, are answered with NotSupported.
+func defaultResponse(action string) (any, error) {
+	switch action {
+	case ocppj.ActionBootNotification:
+		return bootnotification.Conf(bootnotification.ConfInput{
+			Status:      statusAccepted,
+			CurrentTime: Now(),
+			Interval:    DefaultHeartbeatInterval,
+		})
+	case ocppj.ActionHeartbeat:
+		return heartbeat.Conf(heartbeat.ConfInput{CurrentTime: Now()})
+	case ocppj.ActionAuthorize:
+		return authorize.Conf(authorize.ConfInput{
+			Status:      statusAccepted,
+			ExpiryDate:  nil,
+			ParentIdTag: nil,
+		})
+	case ocppj.ActionStopTransaction:
+		return stoptransaction.Conf(stoptransaction.ConfInput{
+			Status:      nil,
+			ExpiryDate:  nil,
+			ParentIdTag: nil,
+		})
+	case ocppj.ActionMeterValues:
+		return metervalues.Conf(metervalues.ConfInput{})
+	case ocppj.ActionStatusNotification:
+		return statusnotification.Conf(statusnotification.ConfInput{})
+	case ocppj.ActionDiagnosticsStatusNotification:
+		return diagnosticsstatusnotification.Conf(
+			diagnosticsstatusnotification.ConfInput{},
+		)
+	case ocppj.ActionFirmwareStatusNotification:
+		return firmwarestatusnotification.Conf(
+			firmwarestatusnotification.ConfInput{},
+		)
+	default:
+		return nil, ocppj.NewError(
+			ocppj.NotSupported,
+			fmt.Sprintf("no default answer for %s", action),
+		)
+	}
+}
+
+// Now returns the current time as an OCPP DateTime string, for building
+// requests and answers in cases.
+func Now() string {
+	return time.Now().UTC().Format(timestampLayout)
+}
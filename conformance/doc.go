@@ -0,0 +1,47 @@
+// Package conformance runs declarative OCPP 1.6 test cases, modeled on the
+// OCA OCPP Compliance Testing Tool (OCTT), against a Charge Point or a
+// Central System implementation over OCPP-J.
+//
+// A Case is a sequence of Steps played by the tester, which takes the role
+// opposite to the system under test:
+//   - Send issues a CALL built with a message package and checks the
+//     validated confirmation; SendFunc builds the request when the step
+//     runs, from values captured by earlier steps
+//   - SendRejected issues a CALL that must be answered with a CALLERROR
+//   - Expect waits for a CALL from the system under test, checks the
+//     validated request and answers it
+//   - Sleep pauses the case
+//
+// While an Expect step waits, CALLs of the actions listed in Case.Tolerate
+// (by default BootNotification, Heartbeat, StatusNotification and
+// MeterValues when the system under test is a Charge Point) are answered
+// with a valid default and skipped. Any other CALL fails the step.
+//
+// # Running
+//
+// A Runner obtains one transport per case from its Connector. To test a
+// Charge Point, start a Listener, point the Charge Point at Listener.URL and
+// use Listener.Accept as the Connector; the Charge Point must reconnect
+// after each case. To test a Central System, use Dialer with its URL.
+//
+//	listener, _ := conformance.NewListener("127.0.0.1:0")
+//	defer listener.Close()
+//
+//	runner := conformance.Runner{
+//		Connect:     listener.Accept,
+//		StepTimeout: 30 * time.Second,
+//	}
+//
+//	cases, err := conformance.ChargePointCases(conformance.DefaultOptions())
+//	if err != nil {
+//		return err
+//	}
+//
+//	report := runner.Run(ctx, cases)
+//	fmt.Print(report)
+//
+// ChargePointCases and CentralSystemCases return the built-in cases, such
+// as a cold boot with a Pending registration, a remote start on a reserved
+// connector and a TxDefaultProfile. The Report lists each case as PASS or
+// FAIL with the failing step.
+package conformance
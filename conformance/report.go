@@ -0,0 +1,118 @@
+package conformance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	verdictPass = "PASS"
+	verdictFail = "FAIL"
+)
+
+// StepResult is the outcome of one step.
+type StepResult struct {
+	Description string
+	Err         error
+	Duration    time.Duration
+}
+
+// Result is the outcome of one case.
+type Result struct {
+	Id     string
+	Name   string
+	Passed bool
+	// Steps lists the steps played, up to and including the failing one.
+	Steps []StepResult
+	// Err is the reason the case failed, nil when it passed.
+	Err      error
+	Duration time.Duration
+}
+
+// Report is the outcome of a run.
+type Report struct {
+	Results []Result
+}
+
+// Passed reports whether every case passed.
+func (r Report) Passed() bool {
+	for _, result := range r.Results {
+		if !result.Passed {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Failed returns the results of the failed cases.
+func (r Report) Failed() []Result {
+	var failed []Result
+
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed = append(failed, result)
+		}
+	}
+
+	return failed
+}
+
+// String formats the report as one PASS/FAIL line per case, the failing
+// step of each failed case and a summary line:
+//
+//	PASS CP_BOOT_PENDING Cold boot with Pending registration (1.204s)
+//	FAIL CP_REMOTE_START_RESERVATION Remote start with reservation (30.002s)
+//	     step 4 expect StartTransaction: conformance: timeout ...
+//	1 passed, 1 failed
+func (r Report) String() string {
+	var builder strings.Builder
+
+	passed := 0
+
+	for _, result := range r.Results {
+		verdict := verdictFail
+		if result.Passed {
+			verdict = verdictPass
+			passed++
+		}
+
+		fmt.Fprintf(
+			&builder,
+			"%s %s %s (%s)\n",
+			verdict,
+			result.Id,
+			result.Name,
+			result.Duration.Round(time.Millisecond),
+		)
+
+		if result.Passed {
+			continue
+		}
+
+		if len(result.Steps) == 0 {
+			fmt.Fprintf(&builder, "     %v\n", result.Err)
+
+			continue
+		}
+
+		failed := result.Steps[len(result.Steps)-1]
+		fmt.Fprintf(
+			&builder,
+			"     step %d %s: %v\n",
+			len(result.Steps),
+			failed.Description,
+			failed.Err,
+		)
+	}
+
+	fmt.Fprintf(
+		&builder,
+		"%d passed, %d failed\n",
+		passed,
+		len(r.Results)-passed,
+	)
+
+	return builder.String()
+}
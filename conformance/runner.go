@@ -0,0 +1,265 @@
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+)
+
+// DefaultStepTimeout bounds each step, and the wait for a connection, when
+// Runner.StepTimeout is zero.
+const DefaultStepTimeout = 30 * time.Second
+
+// Connector returns the transport to the system under test for one case.
+// Listener.Accept and Dialer provide the two directions.
+type Connector func(ctx context.Context) (ocppj.Transport, error)
+
+// Runner plays Cases against a system under test.
+type Runner struct {
+	// Connect returns the transport of each case.
+	Connect Connector
+	// StepTimeout bounds each step; zero means DefaultStepTimeout.
+	StepTimeout time.Duration
+}
+
+// Run plays cases in order, each over its own connection, and returns the
+// report. A failing case does not stop the run.
+func (r Runner) Run(ctx context.Context, cases []Case) Report {
+	results := make([]Result, 0, len(cases))
+
+	for _, testCase := range cases {
+		results = append(results, r.RunCase(ctx, testCase))
+	}
+
+	return Report{Results: results}
+}
+
+// RunCase plays a single case.
+func (r Runner) RunCase(ctx context.Context, testCase Case) Result {
+	started := time.Now()
+	result := Result{
+		Id:       testCase.Id,
+		Name:     testCase.Name,
+		Passed:   false,
+		Steps:    nil,
+		Err:      nil,
+		Duration: 0,
+	}
+
+	defer func() { result.Duration = time.Since(started) }()
+
+	timeout := r.StepTimeout
+	if timeout <= 0 {
+		timeout = DefaultStepTimeout
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, timeout)
+	transport, err := r.Connect(connectCtx)
+
+	cancel()
+
+	if err != nil {
+		result.Err = fmt.Errorf("connect: %w", err)
+
+		return result
+	}
+
+	s := newSession(testCase)
+	s.conn = ocppj.NewConn(transport, testerRole(testCase.Target), s.handle)
+
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_ = s.conn.Run(runCtx)
+	}()
+
+	defer func() {
+		stop()
+		<-done
+	}()
+
+	for _, step := range testCase.Steps {
+		stepStarted := time.Now()
+		stepCtx, cancelStep := context.WithTimeout(runCtx, timeout)
+
+		err = step.run(stepCtx, s)
+
+		cancelStep()
+
+		result.Steps = append(result.Steps, StepResult{
+			Description: step.Description,
+			Err:         err,
+			Duration:    time.Since(stepStarted),
+		})
+
+		if err != nil {
+			result.Err = err
+
+			return result
+		}
+	}
+
+	result.Passed = true
+
+	return result
+}
+
+// testerRole returns the role played by the tester.
+func testerRole(target ocppj.Role) ocppj.Role {
+	if target == ocppj.RoleChargePoint {
+		return ocppj.RoleCentralSystem
+	}
+
+	return ocppj.RoleChargePoint
+}
+
+// incoming is a CALL from the system under test waiting for its answer.
+type incoming struct {
+	action  string
+	request any
+	reply   chan answer
+}
+
+// answer is the CALLRESULT payload or CALLERROR of an incoming CALL.
+type answer struct {
+	confirmation any
+	err          error
+}
+
+// session is the state of one running case.
+type session struct {
+	conn     *ocppj.Conn
+	calls    chan incoming
+	tolerate []string
+}
+
+// newSession returns the session of a case.
+func newSession(testCase Case) *session {
+	tolerate := testCase.Tolerate
+	if tolerate == nil {
+		tolerate = DefaultTolerated(testCase.Target)
+	}
+
+	return &session{
+		conn:     nil,
+		calls:    make(chan incoming),
+		tolerate: tolerate,
+	}
+}
+
+// handle queues an incoming CALL for the steps and waits for its answer.
+func (s *session) handle(
+	ctx context.Context,
+	action string,
+	request any,
+) (any, error) {
+	call := incoming{
+		action:  action,
+		request: request,
+		reply:   make(chan answer, 1),
+	}
+
+	select {
+	case s.calls <- call:
+	case <-ctx.Done():
+		return nil, ocppj.NewError(ocppj.GenericError, "test case ended")
+	}
+
+	select {
+	case result := <-call.reply:
+		return result.confirmation, result.err
+	case <-ctx.Done():
+		return nil, ocppj.NewError(ocppj.GenericError, "test case ended")
+	}
+}
+
+// expect waits for a CALL of action that passes match and answers it with
+// respond.
+func (s *session) expect(
+	ctx context.Context,
+	action string,
+	match func(request any) error,
+	respond func(request any) (any, error),
+) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w waiting for %s", ErrTimeout, action)
+		case call := <-s.calls:
+			if call.action != action {
+				err := s.skip(call)
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			err := match(call.request)
+			if err == nil {
+				confirmation, respondErr := respond(call.request)
+				call.reply <- answer{
+					confirmation: confirmation,
+					err:          respondErr,
+				}
+
+				return nil
+			}
+
+			replyDefault(call)
+
+			if !s.tolerated(action) {
+				return fmt.Errorf("%s: %w", action, err)
+			}
+		}
+	}
+}
+
+// idle answers tolerated CALLs until duration elapses.
+func (s *session) idle(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrTimeout, ctx.Err())
+		case call := <-s.calls:
+			err := s.skip(call)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// skip answers a CALL that no step waits for. Tolerated actions get their
+// default answer; anything else fails the step.
+func (s *session) skip(call incoming) error {
+	replyDefault(call)
+
+	if s.tolerated(call.action) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrUnexpectedCall, call.action)
+}
+
+// replyDefault answers a CALL with the default of its action.
+func replyDefault(call incoming) {
+	confirmation, err := defaultResponse(call.action)
+	call.reply <- answer{confirmation: confirmation, err: err}
+}
+
+// tolerated reports whether CALLs of action may be skipped.
+func (s *session) tolerated(action string) bool {
+	return slices.Contains(s.tolerate, action)
+}
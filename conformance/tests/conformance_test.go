@@ -0,0 +1,441 @@
+package conformance_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/clearchargingprofile"
+	"github.com/aasanchez/ocpp16messages/conformance"
+	"github.com/aasanchez/ocpp16messages/datatransfer"
+	"github.com/aasanchez/ocpp16messages/getcompositeschedule"
+	"github.com/aasanchez/ocpp16messages/ocpp16test"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/remotestoptransaction"
+	"github.com/aasanchez/ocpp16messages/reservenow"
+	"github.com/aasanchez/ocpp16messages/reset"
+	"github.com/aasanchez/ocpp16messages/setchargingprofile"
+	"github.com/aasanchez/ocpp16messages/starttransaction"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	"github.com/aasanchez/ocpp16messages/stoptransaction"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	testChargePoint = "CP001"
+	stepTimeout     = 5 * time.Second
+	shortTimeout    = 100 * time.Millisecond
+	statusAccepted  = "Accepted"
+	statusPending   = "Pending"
+)
+
+func TestRunner_CentralSystemCases(t *testing.T) {
+	t.Parallel()
+
+	harness := ocpp16test.New(t)
+
+	cases, err := conformance.CentralSystemCases(conformance.DefaultOptions())
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	runner := conformance.Runner{
+		Connect:     conformance.Dialer(harness.URL(), testChargePoint, nil),
+		StepTimeout: stepTimeout,
+	}
+
+	report := runner.Run(context.Background(), cases)
+
+	if !report.Passed() {
+		t.Fatalf(types.ErrorUnexpectedError, report.String())
+	}
+
+	if len(report.Results) != len(cases) {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			len(cases),
+			len(report.Results),
+		)
+	}
+}
+
+func TestRunner_ChargePointCases(t *testing.T) {
+	t.Parallel()
+
+	listener, err := conformance.NewListener("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	cases, err := conformance.ChargePointCases(conformance.DefaultOptions())
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		runChargePoint(ctx, listener.URL(), len(cases))
+	}()
+
+	runner := conformance.Runner{
+		Connect:     listener.Accept,
+		StepTimeout: stepTimeout,
+	}
+
+	report := runner.Run(ctx, cases)
+
+	cancel()
+	<-done
+
+	if !report.Passed() {
+		t.Fatalf(types.ErrorUnexpectedError, report.String())
+	}
+}
+
+func TestRunner_FailingCase(t *testing.T) {
+	t.Parallel()
+
+	harness := ocpp16test.New(t)
+	runner := conformance.Runner{
+		Connect:     conformance.Dialer(harness.URL(), testChargePoint, nil),
+		StepTimeout: shortTimeout,
+	}
+
+	testCase := conformance.Case{
+		Id:       "CS_RESET",
+		Name:     "Central System resets the Charge Point",
+		Target:   ocppj.RoleCentralSystem,
+		Tolerate: nil,
+		Steps: []conformance.Step{
+			conformance.Expect[reset.ReqMessage](
+				ocppj.ActionReset,
+				nil,
+				nil,
+			),
+		},
+	}
+
+	report := runner.Run(
+		context.Background(),
+		[]conformance.Case{testCase},
+	)
+
+	if report.Passed() {
+		t.Fatal("Report.Passed() = true, want false")
+	}
+
+	failed := report.Failed()
+	if len(failed) != 1 {
+		t.Fatalf(types.ErrorMismatchValue, 1, len(failed))
+	}
+
+	if !errors.Is(failed[0].Err, conformance.ErrTimeout) {
+		t.Errorf(types.ErrorWrapping, failed[0].Err, conformance.ErrTimeout)
+	}
+
+	text := report.String()
+	for _, want := range []string{
+		"FAIL CS_RESET",
+		"step 1 expect Reset",
+		"0 passed, 1 failed",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf(types.ErrorWantContains, text, want)
+		}
+	}
+}
+
+func TestRunner_SendRejected(t *testing.T) {
+	t.Parallel()
+
+	harness := ocpp16test.New(t)
+	harness.RespondError(
+		ocppj.ActionDataTransfer,
+		ocppj.NewError(ocppj.NotImplemented, "no vendor extensions"),
+	)
+
+	req, err := datatransfer.Req(datatransfer.ReqInput{
+		VendorId:  "com.example",
+		MessageId: nil,
+		Data:      nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	runner := conformance.Runner{
+		Connect:     conformance.Dialer(harness.URL(), testChargePoint, nil),
+		StepTimeout: stepTimeout,
+	}
+
+	report := runner.Run(context.Background(), []conformance.Case{{
+		Id:       "CS_DATA_TRANSFER_REJECTED",
+		Name:     "Unsupported DataTransfer",
+		Target:   ocppj.RoleCentralSystem,
+		Tolerate: nil,
+		Steps: []conformance.Step{
+			conformance.SendRejected(
+				ocppj.ActionDataTransfer,
+				req,
+				ocppj.NotImplemented,
+			),
+		},
+	}})
+
+	if !report.Passed() {
+		t.Fatalf(types.ErrorUnexpectedError, report.String())
+	}
+}
+
+func TestRunner_ConnectError(t *testing.T) {
+	t.Parallel()
+
+	listener, err := conformance.NewListener("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	runner := conformance.Runner{
+		Connect:     listener.Accept,
+		StepTimeout: shortTimeout,
+	}
+
+	result := runner.RunCase(
+		context.Background(),
+		conformance.ColdBootPending(),
+	)
+
+	_ = listener.Close()
+
+	if result.Passed {
+		t.Fatal("Result.Passed = true, want false")
+	}
+
+	if len(result.Steps) != 0 {
+		t.Errorf(types.ErrorMismatchValue, 0, len(result.Steps))
+	}
+
+	if !strings.Contains(result.Err.Error(), "connect") {
+		t.Errorf(types.ErrorWantContains, result.Err, "connect")
+	}
+}
+
+// fakeChargePoint is a minimal Charge Point under test: it boots on every
+// connection and follows the remote commands of the built-in cases.
+type fakeChargePoint struct {
+	conn          *ocppj.Conn
+	mu            sync.Mutex
+	reservationId *int
+	transactionId int
+}
+
+// runChargePoint connects to url once per case and boots each time.
+func runChargePoint(ctx context.Context, url string, connections int) {
+	for range connections {
+		transport, err := ocppj.Dial(ctx, url, testChargePoint, nil)
+		if err != nil {
+			return
+		}
+
+		cp := &fakeChargePoint{
+			conn:          nil,
+			mu:            sync.Mutex{},
+			reservationId: nil,
+			transactionId: 0,
+		}
+		cp.conn = ocppj.NewConn(transport, ocppj.RoleChargePoint, cp.handle)
+
+		go func() { _ = cp.conn.Run(ctx) }()
+
+		if cp.boot(ctx) {
+			cp.status(ctx, 1, "Available")
+		}
+
+		select {
+		case <-cp.conn.Done():
+		case <-ctx.Done():
+			_ = cp.conn.Close()
+
+			return
+		}
+	}
+}
+
+// boot sends BootNotification until it is accepted.
+func (cp *fakeChargePoint) boot(ctx context.Context) bool {
+	req, err := bootnotification.Req(bootnotification.ReqInput{
+		ChargePointVendor:       "Vendor",
+		ChargePointModel:        "Model",
+		ChargePointSerialNumber: nil,
+		ChargeBoxSerialNumber:   nil,
+		FirmwareVersion:         nil,
+		Iccid:                   nil,
+		Imsi:                    nil,
+		MeterType:               nil,
+		MeterSerialNumber:       nil,
+	})
+	if err != nil {
+		return false
+	}
+
+	for {
+		var conf bootnotification.ConfMessage
+
+		err = cp.conn.Call(ctx, ocppj.ActionBootNotification, req, &conf)
+		if err != nil {
+			return false
+		}
+
+		if conf.Status.String() != statusPending {
+			return conf.Status.String() == statusAccepted
+		}
+
+		select {
+		case <-time.After(time.Duration(conf.Interval.Value()) * time.Second):
+		case <-cp.conn.Done():
+			return false
+		}
+	}
+}
+
+// handle answers the CALLs of the tester.
+func (cp *fakeChargePoint) handle(
+	ctx context.Context,
+	_ string,
+	request any,
+) (any, error) {
+	switch req := request.(type) {
+	case reservenow.ReqMessage:
+		reservationId := int(req.ReservationId.Value())
+
+		cp.mu.Lock()
+		cp.reservationId = &reservationId
+		cp.mu.Unlock()
+
+		go cp.status(ctx, int(req.ConnectorId.Value()), "Reserved")
+
+		return reservenow.Conf(reservenow.ConfInput{Status: statusAccepted})
+	case remotestarttransaction.ReqMessage:
+		go cp.start(ctx, 1, req.IdTag.String())
+
+		return remotestarttransaction.Conf(
+			remotestarttransaction.ConfInput{Status: statusAccepted},
+		)
+	case remotestoptransaction.ReqMessage:
+		go cp.stop(ctx, int(req.TransactionId.Value()))
+
+		return remotestoptransaction.Conf(
+			remotestoptransaction.ConfInput{Status: statusAccepted},
+		)
+	case setchargingprofile.ReqMessage:
+		return setchargingprofile.Conf(
+			setchargingprofile.ConfInput{Status: statusAccepted},
+		)
+	case getcompositeschedule.ReqMessage:
+		return getcompositeschedule.Conf(getcompositeschedule.ConfInput{
+			Status:           statusAccepted,
+			ConnectorId:      nil,
+			ScheduleStart:    nil,
+			ChargingSchedule: nil,
+		})
+	case clearchargingprofile.ReqMessage:
+		return clearchargingprofile.Conf(
+			clearchargingprofile.ConfInput{Status: statusAccepted},
+		)
+	default:
+		return nil, ocppj.NewError(ocppj.NotSupported, "")
+	}
+}
+
+// start starts a transaction on the reserved connector.
+func (cp *fakeChargePoint) start(
+	ctx context.Context,
+	connectorId int,
+	idTag string,
+) {
+	cp.mu.Lock()
+	reservationId := cp.reservationId
+	cp.mu.Unlock()
+
+	req, err := starttransaction.Req(starttransaction.ReqInput{
+		ConnectorId:   connectorId,
+		IdTag:         idTag,
+		MeterStart:    0,
+		Timestamp:     conformance.Now(),
+		ReservationId: reservationId,
+	})
+	if err != nil {
+		return
+	}
+
+	var conf starttransaction.ConfMessage
+
+	err = cp.conn.Call(ctx, ocppj.ActionStartTransaction, req, &conf)
+	if err != nil {
+		return
+	}
+
+	cp.mu.Lock()
+	cp.transactionId = int(conf.TransactionId.Value())
+	cp.mu.Unlock()
+
+	cp.status(ctx, connectorId, "Charging")
+}
+
+// stop stops the running transaction.
+func (cp *fakeChargePoint) stop(ctx context.Context, transactionId int) {
+	req, err := stoptransaction.Req(stoptransaction.ReqInput{
+		TransactionId:   transactionId,
+		IdTag:           nil,
+		MeterStop:       0,
+		Timestamp:       conformance.Now(),
+		Reason:          nil,
+		TransactionData: nil,
+	})
+	if err != nil {
+		return
+	}
+
+	var conf stoptransaction.ConfMessage
+
+	_ = cp.conn.Call(ctx, ocppj.ActionStopTransaction, req, &conf)
+}
+
+// status sends a StatusNotification.
+func (cp *fakeChargePoint) status(
+	ctx context.Context,
+	connectorId int,
+	status string,
+) {
+	timestamp := conformance.Now()
+
+	req, err := statusnotification.Req(statusnotification.ReqInput{
+		ConnectorId:     connectorId,
+		ErrorCode:       "NoError",
+		Status:          status,
+		Info:            nil,
+		Timestamp:       &timestamp,
+		VendorId:        nil,
+		VendorErrorCode: nil,
+	})
+	if err != nil {
+		return
+	}
+
+	var conf statusnotification.ConfMessage
+
+	_ = cp.conn.Call(ctx, ocppj.ActionStatusNotification, req, &conf)
+}
@@ -0,0 +1,112 @@
+package conformance
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+)
+
+const (
+	listenerPath          = "/ocpp"
+	listenerHeaderTimeout = 10 * time.Second
+)
+
+// Dialer returns a Connector that connects to a Central System under test
+// as chargePointId, for cases targeting ocppj.RoleCentralSystem.
+func Dialer(
+	centralSystemURL string,
+	chargePointId string,
+	tlsConfig *tls.Config,
+) Connector {
+	return func(ctx context.Context) (ocppj.Transport, error) {
+		return ocppj.Dial(ctx, centralSystemURL, chargePointId, tlsConfig)
+	}
+}
+
+// Listener accepts connections from a Charge Point under test, for cases
+// targeting ocppj.RoleChargePoint.
+type Listener struct {
+	listener    net.Listener
+	server      *http.Server
+	connections chan ocppj.Transport
+	closed      chan struct{}
+}
+
+// NewListener listens on addr, e.g. "127.0.0.1:0" or ":9000".
+func NewListener(addr string) (*Listener, error) {
+	netListener, err := (&net.ListenConfig{}).Listen(
+		context.Background(),
+		"tcp",
+		addr,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("conformance: %w", err)
+	}
+
+	listener := &Listener{
+		listener:    netListener,
+		server:      nil,
+		connections: make(chan ocppj.Transport),
+		closed:      make(chan struct{}),
+	}
+
+	listener.server = &http.Server{
+		Handler:           http.HandlerFunc(listener.serve),
+		ReadHeaderTimeout: listenerHeaderTimeout,
+	}
+
+	go func() { _ = listener.server.Serve(netListener) }()
+
+	return listener, nil
+}
+
+// URL returns the Central System URL to configure on the Charge Point.
+func (l *Listener) URL() string {
+	return "ws://" + l.listener.Addr().String() + listenerPath
+}
+
+// Accept waits for the next Charge Point connection. It is a Connector.
+func (l *Listener) Accept(ctx context.Context) (ocppj.Transport, error) {
+	select {
+	case transport := <-l.connections:
+		return transport, nil
+	case <-l.closed:
+		return nil, ocppj.ErrClosed
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for charge point: %w", ctx.Err())
+	}
+}
+
+// Close stops listening. Connections handed out by Accept stay open.
+func (l *Listener) Close() error {
+	close(l.closed)
+
+	err := l.server.Close()
+	if err != nil {
+		return fmt.Errorf("conformance: %w", err)
+	}
+
+	return nil
+}
+
+// serve upgrades an incoming connection and hands it to Accept. A
+// connection nobody accepts is closed.
+func (l *Listener) serve(w http.ResponseWriter, r *http.Request) {
+	transport, err := ocppj.Accept(w, r)
+	if err != nil {
+		return
+	}
+
+	select {
+	case l.connections <- transport:
+	case <-l.closed:
+		_ = transport.Close()
+	case <-r.Context().Done():
+		_ = transport.Close()
+	}
+}
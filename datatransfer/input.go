@@ -0,0 +1,69 @@
+package datatransfer
+
+import "github.com/aasanchez/ocpp16messages/internal/wire"
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		VendorId:  m.VendorId.String(),
+		MessageId: wire.OptionalString(m.MessageId),
+		Data:      wire.Copy(m.Data),
+	}
+}
+
+// WithVendorId returns a copy of the message with VendorId set to value. The
+// copy is validated by Req.
+func (m ReqMessage) WithVendorId(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.VendorId = value
+
+	return Req(input)
+}
+
+// WithMessageId returns a copy of the message with MessageId set to value.
+// The copy is validated by Req.
+func (m ReqMessage) WithMessageId(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.MessageId = &value
+
+	return Req(input)
+}
+
+// WithData returns a copy of the message with Data set to value. The copy is
+// validated by Req.
+func (m ReqMessage) WithData(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Data = &value
+
+	return Req(input)
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	return ConfInput{
+		Status: m.Status.String(),
+		Data:   wire.Copy(m.Data),
+	}
+}
+
+// WithStatus returns a copy of the message with Status set to value. The
+// copy is validated by Conf.
+func (m ConfMessage) WithStatus(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.Status = value
+
+	return Conf(input)
+}
+
+// WithData returns a copy of the message with Data set to value. The copy is
+// validated by Conf.
+func (m ConfMessage) WithData(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.Data = &value
+
+	return Conf(input)
+}
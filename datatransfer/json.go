@@ -0,0 +1,61 @@
+package datatransfer
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of DataTransfer.req.
+type reqWire struct {
+	VendorId  string  `json:"vendorId"`
+	MessageId *string `json:"messageId,omitempty"`
+	Data      *string `json:"data,omitempty"`
+}
+
+// confWire is the OCPP-J payload of DataTransfer.conf.
+type confWire struct {
+	Status string  `json:"status"`
+	Data   *string `json:"data,omitempty"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J DataTransfer.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		VendorId:  m.VendorId.String(),
+		MessageId: wire.OptionalString(m.MessageId),
+		Data:      m.Data,
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J DataTransfer.req payload and validates it
+// with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J DataTransfer.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		Status: m.Status.String(),
+		Data:   m.Data,
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J DataTransfer.conf payload and validates it
+// with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
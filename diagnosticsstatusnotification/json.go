@@ -0,0 +1,53 @@
+package diagnosticsstatusnotification
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of DiagnosticsStatusNotification.req.
+type reqWire struct {
+	Status string `json:"status"`
+}
+
+// confWire is the OCPP-J payload of DiagnosticsStatusNotification.conf.
+type confWire struct{}
+
+// MarshalJSON encodes the message as its OCPP-J
+// DiagnosticsStatusNotification.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		Status: m.Status.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J DiagnosticsStatusNotification.req payload and
+// validates it with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J
+// DiagnosticsStatusNotification.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{})
+}
+
+// UnmarshalJSON decodes an OCPP-J DiagnosticsStatusNotification.conf payload
+// and validates it with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package ocpp16messages
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// Difference is a field whose value differs between two messages.
+type Difference struct {
+	// Path locates the field by its Go names, e.g.
+	// ChargingSchedule.ChargingSchedulePeriod[1].Limit. It is empty when the
+	// messages are of different types.
+	Path string
+	// A and B are the values of the field in the two messages, nil where the
+	// field is absent.
+	A, B any
+}
+
+// String describes the difference, e.g. IdTag: "ABC" != "XYZ".
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: %s != %s", d.Path, describe(d.A), describe(d.B))
+}
+
+// component is a named part of a composite value.
+type component struct {
+	name  string
+	value any
+}
+
+// Equal reports whether two messages carry the same values, that is whether
+// Diff finds no difference between them.
+func Equal(a, b Message) bool {
+	return len(Diff(a, b)) == 0
+}
+
+// Diff returns the fields whose values differ between two messages, in field
+// order. Values are compared by meaning rather than representation:
+//   - DateTime values are equal when they denote the same instant.
+//   - CiString values, IdToken included, are compared case-insensitively, as
+//     the specification defines them; enumerations and other strings are
+//     compared exactly.
+//   - A nil list equals an empty one.
+//
+// Lists are compared item by item, so an item missing from one message is
+// reported with a nil value on that side. canonical.Hash normalizes the same
+// differences away, so equal messages hash identically.
+func Diff(a, b Message) []Difference {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return []Difference{{Path: "", A: a, B: b}}
+	}
+
+	return diffValue(nil, "", a, b)
+}
+
+// diffValue appends the differences between two values found at path.
+func diffValue(diffs []Difference, path string, a, b any) []Difference {
+	a, b = deref(a), deref(b)
+
+	if a == nil || b == nil {
+		if a == nil && b == nil {
+			return diffs
+		}
+
+		return append(diffs, Difference{Path: path, A: a, B: b})
+	}
+
+	if parts, ok := components(a); ok {
+		others, _ := components(b)
+
+		for i, part := range parts {
+			diffs = diffValue(diffs, join(path, part.name), part.value,
+				others[i].value)
+		}
+
+		return diffs
+	}
+
+	left, right := reflect.ValueOf(a), reflect.ValueOf(b)
+
+	switch {
+	case left.Kind() == reflect.Slice && right.Kind() == reflect.Slice:
+		return diffList(diffs, path, left, right)
+	case left.Kind() == reflect.Struct && isMessage(left.Type()):
+		for i := range left.NumField() {
+			diffs = diffValue(diffs, join(path, left.Type().Field(i).Name),
+				left.Field(i).Interface(), right.Field(i).Interface())
+		}
+
+		return diffs
+	case sameValue(a, b):
+		return diffs
+	default:
+		return append(diffs, Difference{Path: path, A: a, B: b})
+	}
+}
+
+// diffList appends the differences between the items of two lists.
+func diffList(
+	diffs []Difference,
+	path string,
+	left, right reflect.Value,
+) []Difference {
+	for i := range max(left.Len(), right.Len()) {
+		var a, b any
+
+		if i < left.Len() {
+			a = left.Index(i).Interface()
+		}
+
+		if i < right.Len() {
+			b = right.Index(i).Interface()
+		}
+
+		diffs = diffValue(diffs, path+"["+strconv.Itoa(i)+"]", a, b)
+	}
+
+	return diffs
+}
+
+// sameValue compares two scalar values of the same type.
+func sameValue(a, b any) bool {
+	switch typed := a.(type) {
+	case types.DateTime:
+		other, ok := b.(types.DateTime)
+
+		return ok && typed.Value().Equal(other.Value())
+	case types.CiString20Type, types.CiString25Type, types.CiString50Type,
+		types.CiString255Type, types.CiString500Type, types.IdToken:
+		left, _ := a.(fmt.Stringer)
+		right, ok := b.(fmt.Stringer)
+
+		return ok && strings.EqualFold(left.String(), right.String())
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+// components returns the parts of the composite ocpp16types values, read
+// through their getters.
+func components(value any) ([]component, bool) {
+	switch typed := value.(type) {
+	case types.IdTagInfo:
+		return []component{
+			{"Status", typed.Status()},
+			{"ExpiryDate", typed.ExpiryDate()},
+			{"ParentIdTag", typed.ParentIdTag()},
+		}, true
+	case types.AuthorizationData:
+		return []component{
+			{"IdTag", typed.IdTag()},
+			{"IdTagInfo", typed.IdTagInfo()},
+		}, true
+	case types.KeyValue:
+		return []component{
+			{"Key", typed.Key()},
+			{"Readonly", typed.Readonly()},
+			{"Value", typed.Value()},
+		}, true
+	case types.MeterValue:
+		return []component{
+			{"Timestamp", typed.Timestamp()},
+			{"SampledValue", typed.SampledValue()},
+		}, true
+	case types.SampledValue:
+		return sampledValueComponents(typed), true
+	case types.ChargingSchedulePeriod:
+		return []component{
+			{"StartPeriod", typed.StartPeriod()},
+			{"Limit", typed.Limit()},
+			{"NumberPhases", typed.NumberPhases()},
+		}, true
+	case types.ChargingSchedule:
+		return chargingScheduleComponents(typed), true
+	case types.ChargingProfile:
+		return chargingProfileComponents(typed), true
+	default:
+		return nil, false
+	}
+}
+
+// sampledValueComponents returns the parts of a types.SampledValue.
+func sampledValueComponents(sampled types.SampledValue) []component {
+	return []component{
+		{"Value", sampled.Value()},
+		{"Context", sampled.Context()},
+		{"Format", sampled.Format()},
+		{"Measurand", sampled.Measurand()},
+		{"Phase", sampled.Phase()},
+		{"Location", sampled.Location()},
+		{"Unit", sampled.Unit()},
+	}
+}
+
+// chargingScheduleComponents returns the parts of a types.ChargingSchedule.
+func chargingScheduleComponents(schedule types.ChargingSchedule) []component {
+	return []component{
+		{"Duration", schedule.Duration()},
+		{"StartSchedule", schedule.StartSchedule()},
+		{"ChargingRateUnit", schedule.ChargingRateUnit()},
+		{"ChargingSchedulePeriod", schedule.ChargingSchedulePeriod()},
+		{"MinChargingRate", schedule.MinChargingRate()},
+	}
+}
+
+// chargingProfileComponents returns the parts of a types.ChargingProfile.
+func chargingProfileComponents(profile types.ChargingProfile) []component {
+	return []component{
+		{"ChargingProfileId", profile.ChargingProfileId()},
+		{"TransactionId", profile.TransactionId()},
+		{"StackLevel", profile.StackLevel()},
+		{"ChargingProfilePurpose", profile.ChargingProfilePurpose()},
+		{"ChargingProfileKind", profile.ChargingProfileKind()},
+		{"RecurrencyKind", profile.RecurrencyKind()},
+		{"ValidFrom", profile.ValidFrom()},
+		{"ValidTo", profile.ValidTo()},
+		{"ChargingSchedule", profile.ChargingSchedule()},
+	}
+}
+
+// isMessage tells the ReqMessage and ConfMessage structs, whose fields are
+// all exported, from the ocpp16types values, whose fields are not.
+func isMessage(goType reflect.Type) bool {
+	for i := range goType.NumField() {
+		if !goType.Field(i).IsExported() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// deref returns the value a pointer points to, or nil for a nil pointer.
+func deref(value any) any {
+	reflected := reflect.ValueOf(value)
+	if reflected.Kind() != reflect.Pointer {
+		return value
+	}
+
+	if reflected.IsNil() {
+		return nil
+	}
+
+	return reflected.Elem().Interface()
+}
+
+// join appends a field name to a path.
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// describe formats a value of a Difference.
+func describe(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "<absent>"
+	case fmt.Stringer:
+		return strconv.Quote(typed.String())
+	case string:
+		return strconv.Quote(typed)
+	default:
+		return fmt.Sprintf("%v", typed)
+	}
+}
@@ -14,6 +14,13 @@
 // panicking. Types are designed to be thread-safe with immutable fields and
 // value receivers.
 //
+// Every ReqMessage and ConfMessage implements Message, which reports the
+// action, kind, direction and feature profile of the message. Decode, Encode
+// and Validate work on any message type, so generic code needs neither any
+// nor reflection. Equal and Diff compare two messages by meaning: DateTime
+// values by instant, CiString values case-insensitively and nil lists as
+// empty ones.
+//
 // Example:
 //
 //	package main
@@ -0,0 +1,25 @@
+package firmwarestatusnotification
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		Status: m.Status.String(),
+	}
+}
+
+// WithStatus returns a copy of the message with Status set to value. The
+// copy is validated by Req.
+func (m ReqMessage) WithStatus(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Status = value
+
+	return Req(input)
+}
+
+// ToInput returns the empty ConfInput, as FirmwareStatusNotification.conf
+// has no fields.
+func (ConfMessage) ToInput() ConfInput {
+	return ConfInput{}
+}
@@ -0,0 +1,53 @@
+package firmwarestatusnotification
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of FirmwareStatusNotification.req.
+type reqWire struct {
+	Status string `json:"status"`
+}
+
+// confWire is the OCPP-J payload of FirmwareStatusNotification.conf.
+type confWire struct{}
+
+// MarshalJSON encodes the message as its OCPP-J FirmwareStatusNotification.req
+// payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		Status: m.Status.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J FirmwareStatusNotification.req payload and
+// validates it with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J FirmwareStatusNotification.conf
+// payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{})
+}
+
+// UnmarshalJSON decodes an OCPP-J FirmwareStatusNotification.conf payload and
+// validates it with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package getcompositeschedule
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		ConnectorId:      int(m.ConnectorId.Value()),
+		Duration:         int(m.Duration.Value()),
+		ChargingRateUnit: wire.OptionalString(m.ChargingRateUnit),
+	}
+}
+
+// WithConnectorId returns a copy of the message with ConnectorId set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithConnectorId(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ConnectorId = value
+
+	return Req(input)
+}
+
+// WithDuration returns a copy of the message with Duration set to value. The
+// copy is validated by Req.
+func (m ReqMessage) WithDuration(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Duration = value
+
+	return Req(input)
+}
+
+// WithChargingRateUnit returns a copy of the message with ChargingRateUnit
+// set to value. The copy is validated by Req.
+func (m ReqMessage) WithChargingRateUnit(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ChargingRateUnit = &value
+
+	return Req(input)
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	input := ConfInput{
+		Status:           m.Status.String(),
+		ConnectorId:      wire.OptionalInt(m.ConnectorId),
+		ScheduleStart:    wire.OptionalString(m.ScheduleStart),
+		ChargingSchedule: nil,
+	}
+
+	if m.ChargingSchedule != nil {
+		schedule := wire.ChargingScheduleInput(*m.ChargingSchedule)
+		input.ChargingSchedule = &schedule
+	}
+
+	return input
+}
+
+// WithStatus returns a copy of the message with Status set to value. The
+// copy is validated by Conf.
+func (m ConfMessage) WithStatus(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.Status = value
+
+	return Conf(input)
+}
+
+// WithConnectorId returns a copy of the message with ConnectorId set to
+// value. The copy is validated by Conf.
+func (m ConfMessage) WithConnectorId(value int) (ConfMessage, error) {
+	input := m.ToInput()
+	input.ConnectorId = &value
+
+	return Conf(input)
+}
+
+// WithScheduleStart returns a copy of the message with ScheduleStart set to
+// value. The copy is validated by Conf.
+func (m ConfMessage) WithScheduleStart(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.ScheduleStart = &value
+
+	return Conf(input)
+}
+
+// WithChargingSchedule returns a copy of the message with ChargingSchedule
+// set to value. The copy is validated by Conf.
+func (m ConfMessage) WithChargingSchedule(
+	value types.ChargingScheduleInput,
+) (ConfMessage, error) {
+	input := m.ToInput()
+	input.ChargingSchedule = &value
+
+	return Conf(input)
+}
@@ -0,0 +1,79 @@
+package getcompositeschedule
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// reqWire is the OCPP-J payload of GetCompositeSchedule.req.
+type reqWire struct {
+	ConnectorId      uint16  `json:"connectorId"`
+	Duration         uint16  `json:"duration"`
+	ChargingRateUnit *string `json:"chargingRateUnit,omitempty"`
+}
+
+// confWire is the OCPP-J payload of GetCompositeSchedule.conf.
+type confWire struct {
+	Status           string                 `json:"status"`
+	ConnectorId      *uint16                `json:"connectorId,omitempty"`
+	ScheduleStart    *string                `json:"scheduleStart,omitempty"`
+	ChargingSchedule *wire.ChargingSchedule `json:"chargingSchedule,omitempty"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J GetCompositeSchedule.req
+// payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		ConnectorId:      m.ConnectorId.Value(),
+		Duration:         m.Duration.Value(),
+		ChargingRateUnit: wire.OptionalString(m.ChargingRateUnit),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J GetCompositeSchedule.req payload and
+// validates it with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J GetCompositeSchedule.conf
+// payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		Status:           m.Status.String(),
+		ConnectorId:      wire.OptionalInteger(m.ConnectorId),
+		ScheduleStart:    wire.OptionalString(m.ScheduleStart),
+		ChargingSchedule: chargingSchedule(m.ChargingSchedule),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J GetCompositeSchedule.conf payload and
+// validates it with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// chargingSchedule converts the optional ChargingSchedule to its wire form.
+func chargingSchedule(schedule *types.ChargingSchedule) *wire.ChargingSchedule {
+	if schedule == nil {
+		return nil
+	}
+
+	converted := wire.NewChargingSchedule(*schedule)
+
+	return &converted
+}
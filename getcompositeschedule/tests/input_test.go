@@ -0,0 +1,83 @@
+package getcompositeschedule_test
+
+import (
+	"reflect"
+	"testing"
+
+	gcs "github.com/aasanchez/ocpp16messages/getcompositeschedule"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+func TestConfMessage_ToInput(t *testing.T) {
+	t.Parallel()
+
+	input := gcs.ConfInput{
+		Status:        "Accepted",
+		ConnectorId:   intPtr(1),
+		ScheduleStart: strPtr(validTimestamp),
+		ChargingSchedule: &types.ChargingScheduleInput{
+			Duration:         intPtr(durationConfValue),
+			StartSchedule:    strPtr(validTimestamp),
+			ChargingRateUnit: "A",
+			ChargingSchedulePeriod: []types.ChargingSchedulePeriodInput{
+				{
+					StartPeriod:  0,
+					Limit:        limitConfValue,
+					NumberPhases: intPtr(3),
+				},
+			},
+			MinChargingRate: nil,
+		},
+	}
+
+	conf, err := gcs.Conf(input)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	got := conf.ToInput()
+	if !reflect.DeepEqual(got, input) {
+		t.Errorf(types.ErrorMismatch, input, got)
+	}
+
+	rebuilt, err := gcs.Conf(got)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if !reflect.DeepEqual(rebuilt, conf) {
+		t.Errorf(types.ErrorMismatch, conf, rebuilt)
+	}
+}
+
+func TestConfMessage_WithStatus(t *testing.T) {
+	t.Parallel()
+
+	conf, err := gcs.Conf(gcs.ConfInput{
+		Status:           "Accepted",
+		ConnectorId:      intPtr(1),
+		ScheduleStart:    nil,
+		ChargingSchedule: nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	updated, err := conf.WithStatus("Rejected")
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if updated.Status.String() != "Rejected" {
+		t.Errorf(types.ErrorMismatchValue, "Rejected", updated.Status)
+	}
+
+	if updated.ConnectorId == nil || updated.ConnectorId.Value() != 1 {
+		t.Errorf(types.ErrorMismatchValue, 1, updated.ConnectorId)
+	}
+
+	_, err = conf.WithStatus("Maybe")
+	if err == nil {
+		t.Errorf(types.ErrorWantNonNil, "invalid status")
+	}
+}
@@ -0,0 +1,54 @@
+package getconfiguration
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		Key: wire.Strings(m.Key),
+	}
+}
+
+// WithKey returns a copy of the message with Key set to value. The copy is
+// validated by Req.
+func (m ReqMessage) WithKey(value []string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Key = value
+
+	return Req(input)
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	return ConfInput{
+		ConfigurationKey: wire.KeyValueInputs(m.ConfigurationKey),
+		UnknownKey:       wire.Strings(m.UnknownKey),
+	}
+}
+
+// WithConfigurationKey returns a copy of the message with ConfigurationKey
+// set to value. The copy is validated by Conf.
+func (m ConfMessage) WithConfigurationKey(
+	value []types.KeyValueInput,
+) (ConfMessage, error) {
+	input := m.ToInput()
+	input.ConfigurationKey = value
+
+	return Conf(input)
+}
+
+// WithUnknownKey returns a copy of the message with UnknownKey set to value.
+// The copy is validated by Conf.
+func (m ConfMessage) WithUnknownKey(value []string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.UnknownKey = value
+
+	return Conf(input)
+}
@@ -0,0 +1,57 @@
+package getconfiguration
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of GetConfiguration.req.
+type reqWire struct {
+	Key []string `json:"key,omitempty"`
+}
+
+// confWire is the OCPP-J payload of GetConfiguration.conf.
+type confWire struct {
+	ConfigurationKey []wire.KeyValue `json:"configurationKey,omitempty"`
+	UnknownKey       []string        `json:"unknownKey,omitempty"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J GetConfiguration.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		Key: wire.Strings(m.Key),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J GetConfiguration.req payload and validates it
+// with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J GetConfiguration.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		ConfigurationKey: wire.NewKeyValues(m.ConfigurationKey),
+		UnknownKey:       wire.Strings(m.UnknownKey),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J GetConfiguration.conf payload and validates
+// it with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package getconfiguration_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/getconfiguration"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+func TestConfMessage_ToInput(t *testing.T) {
+	t.Parallel()
+
+	value := "300"
+	input := getconfiguration.ConfInput{
+		ConfigurationKey: []types.KeyValueInput{
+			{Key: "HeartbeatInterval", Readonly: false, Value: &value},
+			{Key: "NumberOfConnectors", Readonly: true, Value: nil},
+		},
+		UnknownKey: []string{"VendorKey"},
+	}
+
+	conf, err := getconfiguration.Conf(input)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	got := conf.ToInput()
+	if !reflect.DeepEqual(got, input) {
+		t.Errorf(types.ErrorMismatch, input, got)
+	}
+
+	updated, err := conf.WithUnknownKey(nil)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if len(updated.UnknownKey) != 0 || len(conf.UnknownKey) != 1 {
+		t.Errorf(types.ErrorMismatch, conf.UnknownKey, updated.UnknownKey)
+	}
+}
+
+func TestReqMessage_WithKey_Invalid(t *testing.T) {
+	t.Parallel()
+
+	req, err := getconfiguration.Req(getconfiguration.ReqInput{Key: nil})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	_, err = req.WithKey([]string{""})
+	if err == nil {
+		t.Errorf(types.ErrorWantNonNil, "empty key")
+	}
+}
@@ -0,0 +1,79 @@
+package getdiagnostics
+
+import "github.com/aasanchez/ocpp16messages/internal/wire"
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		Location:      m.Location.String(),
+		Retries:       wire.OptionalInt(m.Retries),
+		RetryInterval: wire.OptionalInt(m.RetryInterval),
+		StartTime:     wire.OptionalString(m.StartTime),
+		StopTime:      wire.OptionalString(m.StopTime),
+	}
+}
+
+// WithLocation returns a copy of the message with Location set to value. The
+// copy is validated by Req.
+func (m ReqMessage) WithLocation(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Location = value
+
+	return Req(input)
+}
+
+// WithRetries returns a copy of the message with Retries set to value. The
+// copy is validated by Req.
+func (m ReqMessage) WithRetries(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Retries = &value
+
+	return Req(input)
+}
+
+// WithRetryInterval returns a copy of the message with RetryInterval set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithRetryInterval(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.RetryInterval = &value
+
+	return Req(input)
+}
+
+// WithStartTime returns a copy of the message with StartTime set to value.
+// The copy is validated by Req.
+func (m ReqMessage) WithStartTime(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.StartTime = &value
+
+	return Req(input)
+}
+
+// WithStopTime returns a copy of the message with StopTime set to value. The
+// copy is validated by Req.
+func (m ReqMessage) WithStopTime(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.StopTime = &value
+
+	return Req(input)
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	return ConfInput{
+		FileName: wire.OptionalString(m.FileName),
+	}
+}
+
+// WithFileName returns a copy of the message with FileName set to value. The
+// copy is validated by Conf.
+func (m ConfMessage) WithFileName(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.FileName = &value
+
+	return Conf(input)
+}
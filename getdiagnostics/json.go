@@ -0,0 +1,63 @@
+package getdiagnostics
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of GetDiagnostics.req.
+type reqWire struct {
+	Location      string  `json:"location"`
+	Retries       *uint16 `json:"retries,omitempty"`
+	RetryInterval *uint16 `json:"retryInterval,omitempty"`
+	StartTime     *string `json:"startTime,omitempty"`
+	StopTime      *string `json:"stopTime,omitempty"`
+}
+
+// confWire is the OCPP-J payload of GetDiagnostics.conf.
+type confWire struct {
+	FileName *string `json:"fileName,omitempty"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J GetDiagnostics.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		Location:      m.Location.String(),
+		Retries:       wire.OptionalInteger(m.Retries),
+		RetryInterval: wire.OptionalInteger(m.RetryInterval),
+		StartTime:     wire.OptionalString(m.StartTime),
+		StopTime:      wire.OptionalString(m.StopTime),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J GetDiagnostics.req payload and validates it
+// with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J GetDiagnostics.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		FileName: wire.OptionalString(m.FileName),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J GetDiagnostics.conf payload and validates it
+// with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
@@ -240,6 +240,27 @@ func TestReq_Invalid_EmptyLocation(t *testing.T) {
 	}
 }
 
+func TestReq_Valid_RelativeLocation(t *testing.T) {
+	t.Parallel()
+
+	// The schema requires a URI; Req leaves that to schema.CrossCheck and
+	// transfer.ParseLocation.
+	req, err := gd.Req(gd.ReqInput{
+		Location:      "files/upload",
+		Retries:       nil,
+		RetryInterval: nil,
+		StartTime:     nil,
+		StopTime:      nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if req.Location.String() != "files/upload" {
+		t.Errorf(types.ErrorMismatch, "files/upload", req.Location.String())
+	}
+}
+
 func TestReq_Invalid_NegativeRetries(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,25 @@
+package getlocallistversion
+
+// ToInput returns the empty ReqInput, as GetLocalListVersion.req has no
+// fields.
+func (ReqMessage) ToInput() ReqInput {
+	return ReqInput{}
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	return ConfInput{
+		ListVersion: int(m.ListVersion.Value()),
+	}
+}
+
+// WithListVersion returns a copy of the message with ListVersion set to
+// value. The copy is validated by Conf.
+func (m ConfMessage) WithListVersion(value int) (ConfMessage, error) {
+	input := m.ToInput()
+	input.ListVersion = value
+
+	return Conf(input)
+}
@@ -0,0 +1,53 @@
+package getlocallistversion
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of GetLocalListVersion.req.
+type reqWire struct{}
+
+// confWire is the OCPP-J payload of GetLocalListVersion.conf.
+type confWire struct {
+	ListVersion int32 `json:"listVersion"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J GetLocalListVersion.req
+// payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{})
+}
+
+// UnmarshalJSON decodes an OCPP-J GetLocalListVersion.req payload and validates
+// it with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J GetLocalListVersion.conf
+// payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		ListVersion: m.ListVersion.Value(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J GetLocalListVersion.conf payload and
+// validates it with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package heartbeat
+
+// ToInput returns the empty ReqInput, as Heartbeat.req has no fields.
+func (ReqMessage) ToInput() ReqInput {
+	return ReqInput{}
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	return ConfInput{
+		CurrentTime: m.CurrentTime.String(),
+	}
+}
+
+// WithCurrentTime returns a copy of the message with CurrentTime set to
+// value. The copy is validated by Conf.
+func (m ConfMessage) WithCurrentTime(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.CurrentTime = value
+
+	return Conf(input)
+}
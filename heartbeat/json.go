@@ -0,0 +1,66 @@
+package heartbeat
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of Heartbeat.req.
+type reqWire struct{}
+
+// confWire is the OCPP-J payload of Heartbeat.conf.
+type confWire struct {
+	CurrentTime string `json:"currentTime"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J Heartbeat.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{})
+}
+
+// AppendJSON appends the OCPP-J Heartbeat.req payload to dst without
+// reflection. It writes the same bytes as MarshalJSON.
+func (m ReqMessage) AppendJSON(dst []byte) []byte {
+	return append(dst, '{', '}')
+}
+
+// UnmarshalJSON decodes an OCPP-J Heartbeat.req payload and validates it with
+// Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J Heartbeat.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		CurrentTime: m.CurrentTime.String(),
+	})
+}
+
+// AppendJSON appends the OCPP-J Heartbeat.conf payload to dst without
+// reflection. It writes the same bytes as MarshalJSON.
+func (m ConfMessage) AppendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	dst = wire.AppendStringField(dst, "currentTime", m.CurrentTime.String())
+
+	return append(dst, '}')
+}
+
+// UnmarshalJSON decodes an OCPP-J Heartbeat.conf payload and validates it with
+// Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
@@ -0,0 +1,395 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // SHA-1 is mandated by RFC 6455.
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// acceptGUID is the fixed GUID of RFC 6455 section 1.3.
+	acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	// version is the only protocol version defined by RFC 6455.
+	version = "13"
+	// keyLen is the number of random bytes in Sec-WebSocket-Key.
+	keyLen = 16
+
+	schemeWS  = "ws"
+	schemeWSS = "wss"
+	portWS    = "80"
+	portWSS   = "443"
+
+	headerUpgrade    = "Upgrade"
+	headerConnection = "Connection"
+	headerKey        = "Sec-Websocket-Key"
+	headerVersion    = "Sec-Websocket-Version"
+	headerProtocol   = "Sec-Websocket-Protocol"
+	headerAccept     = "Sec-Websocket-Accept"
+	tokenWebsocket   = "websocket"
+	tokenUpgrade     = "upgrade"
+	tokenSeparator   = ","
+)
+
+// Dial opens a client connection to a ws:// or wss:// URL and offers the
+// given subprotocols. User information in the URL is sent as HTTP Basic
+// authentication, as used by OCPP security profile 1. The context bounds the
+// TCP, TLS and WebSocket handshakes only.
+func Dial(
+	ctx context.Context,
+	rawURL string,
+	subprotocols []string,
+	tlsConfig *tls.Config,
+) (*Conn, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrHandshake, err)
+	}
+
+	conn, err := dialTransport(ctx, target, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	wsConn, err := clientHandshake(conn, target, subprotocols)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	if !stop() {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("%w: %w", ErrHandshake, ctx.Err())
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+
+	return wsConn, nil
+}
+
+// dialTransport opens the TCP (and for wss, TLS) connection.
+func dialTransport(
+	ctx context.Context,
+	target *url.URL,
+	tlsConfig *tls.Config,
+) (net.Conn, error) {
+	var port string
+
+	switch target.Scheme {
+	case schemeWS:
+		port = portWS
+	case schemeWSS:
+		port = portWSS
+	default:
+		return nil, fmt.Errorf(
+			"%w: unsupported scheme %q",
+			ErrHandshake,
+			target.Scheme,
+		)
+	}
+
+	address := target.Host
+	if target.Port() == "" {
+		address = net.JoinHostPort(target.Hostname(), port)
+	}
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrHandshake, err)
+	}
+
+	if target.Scheme == schemeWS {
+		return conn, nil
+	}
+
+	config := &tls.Config{ //nolint:exhaustruct // Library defaults.
+		MinVersion: tls.VersionTLS12,
+	}
+	if tlsConfig != nil {
+		config = tlsConfig.Clone()
+	}
+
+	if config.ServerName == "" {
+		config.ServerName = target.Hostname()
+	}
+
+	tlsConn := tls.Client(conn, config)
+
+	err = tlsConn.HandshakeContext(ctx)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("%w: %w", ErrHandshake, err)
+	}
+
+	return tlsConn, nil
+}
+
+// clientHandshake sends the upgrade request and validates the response.
+func clientHandshake(
+	conn net.Conn,
+	target *url.URL,
+	subprotocols []string,
+) (*Conn, error) {
+	key, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+
+	requestURL := &url.URL{ //nolint:exhaustruct // Only the request URI.
+		Path:     target.Path,
+		RawPath:  target.RawPath,
+		RawQuery: target.RawQuery,
+	}
+
+	request := &http.Request{ //nolint:exhaustruct // Client request subset.
+		Method:     http.MethodGet,
+		URL:        requestURL,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Host:       target.Host,
+	}
+
+	request.Header.Set(headerUpgrade, tokenWebsocket)
+	request.Header.Set(headerConnection, headerUpgrade)
+	request.Header.Set(headerKey, key)
+	request.Header.Set(headerVersion, version)
+
+	if len(subprotocols) > 0 {
+		request.Header.Set(headerProtocol, strings.Join(subprotocols, ", "))
+	}
+
+	if target.User != nil {
+		password, _ := target.User.Password()
+		request.SetBasicAuth(target.User.Username(), password)
+	}
+
+	err = request.Write(conn)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrHandshake, err)
+	}
+
+	reader := bufio.NewReader(conn)
+
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrHandshake, err)
+	}
+
+	_ = response.Body.Close()
+
+	subprotocol, err := checkResponse(response, key, subprotocols)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConn(conn, reader, true, subprotocol), nil
+}
+
+// checkResponse validates the server's handshake response and returns the
+// selected subprotocol.
+func checkResponse(
+	response *http.Response,
+	key string,
+	subprotocols []string,
+) (string, error) {
+	if response.StatusCode != http.StatusSwitchingProtocols {
+		return "", fmt.Errorf(
+			"%w: unexpected status %q",
+			ErrHandshake,
+			response.Status,
+		)
+	}
+
+	if !strings.EqualFold(response.Header.Get(headerUpgrade), tokenWebsocket) ||
+		!hasToken(response.Header, headerConnection, tokenUpgrade) {
+		return "", fmt.Errorf("%w: missing upgrade headers", ErrHandshake)
+	}
+
+	if response.Header.Get(headerAccept) != acceptKey(key) {
+		return "", fmt.Errorf("%w: invalid %s", ErrHandshake, headerAccept)
+	}
+
+	subprotocol := response.Header.Get(headerProtocol)
+	if subprotocol != "" && !slices.Contains(subprotocols, subprotocol) {
+		return "", fmt.Errorf(
+			"%w: unexpected subprotocol %q",
+			ErrHandshake,
+			subprotocol,
+		)
+	}
+
+	return subprotocol, nil
+}
+
+// Upgrade completes the server side of the handshake and takes over the
+// HTTP connection. The first subprotocol offered by the client that is also
+// listed in subprotocols is selected; when the client offers subprotocols
+// but none is supported, the request is rejected with 400 Bad Request. On
+// failure an HTTP error has already been written to w.
+func Upgrade(
+	w http.ResponseWriter,
+	r *http.Request,
+	subprotocols []string,
+) (*Conn, error) {
+	err := checkRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return nil, err
+	}
+
+	offered := splitTokens(r.Header.Values(headerProtocol))
+	subprotocol := selectSubprotocol(offered, subprotocols)
+
+	if len(offered) > 0 && subprotocol == "" {
+		err = fmt.Errorf("%w: no supported subprotocol", ErrHandshake)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return nil, err
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		err = fmt.Errorf("%w: connection cannot be hijacked", ErrHandshake)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return nil, err
+	}
+
+	conn, buffered, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrHandshake, err)
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(r.Header.Get(headerKey)) + "\r\n"
+
+	if subprotocol != "" {
+		response += "Sec-WebSocket-Protocol: " + subprotocol + "\r\n"
+	}
+
+	response += "\r\n"
+
+	_, err = buffered.WriteString(response)
+	if err == nil {
+		err = buffered.Flush()
+	}
+
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("%w: %w", ErrHandshake, err)
+	}
+
+	return newConn(conn, buffered.Reader, false, subprotocol), nil
+}
+
+// checkRequest validates the client's upgrade request.
+func checkRequest(r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return fmt.Errorf("%w: method must be GET", ErrHandshake)
+	}
+
+	if !hasToken(r.Header, headerConnection, tokenUpgrade) ||
+		!hasToken(r.Header, headerUpgrade, tokenWebsocket) {
+		return fmt.Errorf("%w: missing upgrade headers", ErrHandshake)
+	}
+
+	if r.Header.Get(headerVersion) != version {
+		return fmt.Errorf("%w: unsupported version", ErrHandshake)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(r.Header.Get(headerKey))
+	if err != nil || len(key) != keyLen {
+		return fmt.Errorf("%w: invalid %s", ErrHandshake, headerKey)
+	}
+
+	return nil
+}
+
+// selectSubprotocol returns the first offered subprotocol that is supported.
+func selectSubprotocol(offered, supported []string) string {
+	for _, candidate := range offered {
+		if slices.Contains(supported, candidate) {
+			return candidate
+		}
+	}
+
+	return ""
+}
+
+// newKey returns a random Sec-WebSocket-Key.
+func newKey() (string, error) {
+	raw := make([]byte, keyLen)
+
+	_, err := rand.Read(raw)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrHandshake, err)
+	}
+
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+// acceptKey computes Sec-WebSocket-Accept for a Sec-WebSocket-Key.
+func acceptKey(key string) string {
+	digest := sha1.Sum([]byte(key + acceptGUID)) //nolint:gosec // RFC 6455.
+
+	return base64.StdEncoding.EncodeToString(digest[:])
+}
+
+// hasToken reports whether a comma separated header contains token,
+// ignoring case.
+func hasToken(header http.Header, name, token string) bool {
+	for _, value := range splitTokens(header.Values(name)) {
+		if strings.EqualFold(value, token) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitTokens splits comma separated header values into trimmed tokens.
+func splitTokens(values []string) []string {
+	var tokens []string
+
+	for _, value := range values {
+		for _, token := range strings.Split(value, tokenSeparator) {
+			token = strings.TrimSpace(token)
+			if token != "" {
+				tokens = append(tokens, token)
+			}
+		}
+	}
+
+	return tokens
+}
@@ -0,0 +1,149 @@
+package websocket_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/internal/websocket"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	testTimeout     = 5 * time.Second
+	testSubprotocol = "ocpp1.6"
+	// largeMessage needs the 64-bit payload length encoding.
+	largeMessage = 70000
+)
+
+// echoServer upgrades every request and echoes messages until the client
+// closes.
+func echoServer(t *testing.T) string {
+	t.Helper()
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := websocket.Upgrade(w, r, []string{testSubprotocol})
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			for {
+				data, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+
+				err = conn.WriteMessage(data)
+				if err != nil {
+					return
+				}
+			}
+		}),
+	)
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http") + "/ocpp/CP001"
+}
+
+func dial(t *testing.T, url string, subprotocols []string) *websocket.Conn {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	conn, err := websocket.Dial(ctx, url, subprotocols, nil)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestDial_Subprotocol(t *testing.T) {
+	t.Parallel()
+
+	conn := dial(t, echoServer(t), []string{"ocpp2.0.1", testSubprotocol})
+
+	if conn.Subprotocol() != testSubprotocol {
+		t.Errorf(types.ErrorMismatchValue, testSubprotocol, conn.Subprotocol())
+	}
+}
+
+func TestDial_UnsupportedSubprotocol(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	_, err := websocket.Dial(ctx, echoServer(t), []string{"ocpp2.0.1"}, nil)
+	if !errors.Is(err, websocket.ErrHandshake) {
+		t.Errorf(types.ErrorWrapping, err, websocket.ErrHandshake)
+	}
+}
+
+func TestConn_Echo(t *testing.T) {
+	t.Parallel()
+
+	conn := dial(t, echoServer(t), []string{testSubprotocol})
+
+	for _, message := range [][]byte{
+		[]byte(`[2,"1","Heartbeat",{}]`),
+		bytes.Repeat([]byte("a"), 200),
+		bytes.Repeat([]byte("b"), largeMessage),
+	} {
+		err := conn.WriteMessage(message)
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+
+		got, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+
+		if !bytes.Equal(got, message) {
+			t.Errorf(types.ErrorMismatchValue, len(message), len(got))
+		}
+	}
+}
+
+func TestConn_Close(t *testing.T) {
+	t.Parallel()
+
+	conn := dial(t, echoServer(t), []string{testSubprotocol})
+
+	err := conn.Close()
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	err = conn.WriteMessage([]byte("late"))
+	if !errors.Is(err, websocket.ErrClosed) {
+		t.Errorf(types.ErrorWrapping, err, websocket.ErrClosed)
+	}
+}
+
+func TestUpgrade_NotWebSocket(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/ocpp/CP001", nil)
+
+	_, err := websocket.Upgrade(recorder, request, []string{testSubprotocol})
+	if !errors.Is(err, websocket.ErrHandshake) {
+		t.Errorf(types.ErrorWrapping, err, websocket.ErrHandshake)
+	}
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf(types.ErrorMismatchValue, http.StatusBadRequest, recorder.Code)
+	}
+}
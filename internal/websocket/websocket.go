@@ -0,0 +1,408 @@
+// Package websocket is a minimal RFC 6455 WebSocket implementation covering
+// what OCPP-J needs: the opening handshake with subprotocol negotiation on
+// both the client and the server side, text and binary messages (including
+// fragmented ones), ping/pong and the closing handshake. Extensions such as
+// permessage-deflate are not negotiated.
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+
+	finBit      = 0x80
+	rsvBits     = 0x70
+	opcodeBits  = 0x0F
+	maskBit     = 0x80
+	lengthBits  = 0x7F
+	controlBit  = 0x08
+	length16    = 126
+	length64    = 127
+	maxControl  = 125
+	maxLength16 = 0xFFFF
+	maskKeyLen  = 4
+	headerLen   = 2
+	closeLen    = 2
+
+	// CloseNormal is the status code sent when a connection is closed
+	// deliberately.
+	CloseNormal = 1000
+	// CloseProtocolError is the status code sent when the peer violates the
+	// framing rules.
+	CloseProtocolError = 1002
+	// CloseMessageTooBig is the status code sent when a message exceeds the
+	// configured maximum size.
+	CloseMessageTooBig = 1009
+
+	// DefaultMaxMessageSize bounds the size of a single reassembled message.
+	// Large SendLocalList payloads fit comfortably.
+	DefaultMaxMessageSize = 32 << 20
+)
+
+var (
+	// ErrClosed is returned once the connection has been closed by either
+	// side.
+	ErrClosed = errors.New("websocket: connection closed")
+	// ErrHandshake is returned when the opening handshake fails.
+	ErrHandshake = errors.New("websocket: handshake failed")
+	// ErrProtocol is returned when the peer violates RFC 6455 framing.
+	ErrProtocol = errors.New("websocket: protocol error")
+	// ErrMessageTooLarge is returned when a message exceeds MaxMessageSize.
+	ErrMessageTooLarge = errors.New("websocket: message too large")
+)
+
+// Conn is an established WebSocket connection. ReadMessage must be called
+// from a single goroutine; WriteMessage and Close are safe for concurrent
+// use.
+type Conn struct {
+	conn        net.Conn
+	reader      *bufio.Reader
+	client      bool
+	subprotocol string
+
+	// MaxMessageSize bounds the size of a single reassembled message.
+	MaxMessageSize int64
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+	closeSent bool
+}
+
+// newConn wraps a connection whose handshake has completed.
+func newConn(
+	conn net.Conn,
+	reader *bufio.Reader,
+	client bool,
+	subprotocol string,
+) *Conn {
+	return &Conn{
+		conn:           conn,
+		reader:         reader,
+		client:         client,
+		subprotocol:    subprotocol,
+		MaxMessageSize: DefaultMaxMessageSize,
+		writeMu:        sync.Mutex{},
+		closeOnce:      sync.Once{},
+		closeSent:      false,
+	}
+}
+
+// Subprotocol returns the subprotocol agreed during the handshake, or an
+// empty string when none was selected.
+func (c *Conn) Subprotocol() string {
+	return c.subprotocol
+}
+
+// RemoteAddr returns the address of the peer.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// ReadMessage returns the next text or binary message. Ping frames are
+// answered and pong frames discarded transparently. It returns ErrClosed
+// once the peer sent a close frame or the connection went away.
+func (c *Conn) ReadMessage() ([]byte, error) {
+	var (
+		message    []byte
+		fragmented bool
+	)
+
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, c.fail(err)
+		}
+
+		switch opcode {
+		case opPing:
+			err = c.writeFrame(opPong, payload)
+			if err != nil {
+				return nil, c.fail(err)
+			}
+
+			continue
+		case opPong:
+			continue
+		case opClose:
+			c.replyClose(payload)
+
+			return nil, ErrClosed
+		case opText, opBinary:
+			if fragmented {
+				return nil, c.fail(fmt.Errorf(
+					"%w: new message inside a fragmented one",
+					ErrProtocol,
+				))
+			}
+
+			message = payload
+			fragmented = !fin
+		case opContinuation:
+			if !fragmented {
+				return nil, c.fail(fmt.Errorf(
+					"%w: unexpected continuation frame",
+					ErrProtocol,
+				))
+			}
+
+			message = append(message, payload...)
+			fragmented = !fin
+		default:
+			return nil, c.fail(fmt.Errorf(
+				"%w: unknown opcode %d",
+				ErrProtocol,
+				opcode,
+			))
+		}
+
+		if int64(len(message)) > c.MaxMessageSize {
+			return nil, c.fail(ErrMessageTooLarge)
+		}
+
+		if !fragmented {
+			return message, nil
+		}
+	}
+}
+
+// WriteMessage sends data as a single text frame.
+func (c *Conn) WriteMessage(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+// Ping sends a ping control frame. The pong is consumed by ReadMessage.
+func (c *Conn) Ping(data []byte) error {
+	if len(data) > maxControl {
+		return fmt.Errorf("%w: ping payload too long", ErrProtocol)
+	}
+
+	return c.writeFrame(opPing, data)
+}
+
+// Close sends a normal close frame and closes the underlying connection.
+// It is safe to call more than once.
+func (c *Conn) Close() error {
+	return c.closeWith(CloseNormal)
+}
+
+// closeWith sends a close frame with the given status code and closes the
+// underlying connection.
+func (c *Conn) closeWith(code uint16) error {
+	var err error
+
+	c.closeOnce.Do(func() {
+		payload := make([]byte, closeLen)
+		binary.BigEndian.PutUint16(payload, code)
+
+		_ = c.writeFrame(opClose, payload)
+		err = c.conn.Close()
+	})
+
+	if err != nil {
+		return fmt.Errorf("websocket: close: %w", err)
+	}
+
+	return nil
+}
+
+// replyClose answers a close frame from the peer and closes the connection.
+func (c *Conn) replyClose(payload []byte) {
+	code := uint16(CloseNormal)
+	if len(payload) >= closeLen {
+		code = binary.BigEndian.Uint16(payload)
+	}
+
+	_ = c.closeWith(code)
+}
+
+// fail closes the connection after a read error and maps connection loss to
+// ErrClosed.
+func (c *Conn) fail(err error) error {
+	switch {
+	case errors.Is(err, ErrProtocol):
+		_ = c.closeWith(CloseProtocolError)
+
+		return err
+	case errors.Is(err, ErrMessageTooLarge):
+		_ = c.closeWith(CloseMessageTooBig)
+
+		return err
+	case errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, net.ErrClosed):
+		_ = c.conn.Close()
+
+		return ErrClosed
+	default:
+		_ = c.conn.Close()
+
+		return fmt.Errorf("websocket: read: %w", err)
+	}
+}
+
+// readFrame reads a single frame and unmasks its payload.
+func (c *Conn) readFrame() (bool, byte, []byte, error) {
+	var header [headerLen]byte
+
+	_, err := io.ReadFull(c.reader, header[:])
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&finBit != 0
+	opcode := header[0] & opcodeBits
+	masked := header[1]&maskBit != 0
+
+	if header[0]&rsvBits != 0 {
+		return false, 0, nil, fmt.Errorf("%w: reserved bits set", ErrProtocol)
+	}
+
+	// Clients must mask every frame, servers must not.
+	if masked == c.client {
+		return false, 0, nil, fmt.Errorf("%w: invalid masking", ErrProtocol)
+	}
+
+	length, err := c.readLength(header[1] & lengthBits)
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	if opcode&controlBit != 0 && (!fin || length > maxControl) {
+		return false, 0, nil, fmt.Errorf(
+			"%w: invalid control frame",
+			ErrProtocol,
+		)
+	}
+
+	if length > uint64(c.MaxMessageSize) {
+		return false, 0, nil, ErrMessageTooLarge
+	}
+
+	var maskKey [maskKeyLen]byte
+
+	if masked {
+		_, err = io.ReadFull(c.reader, maskKey[:])
+		if err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+
+	_, err = io.ReadFull(c.reader, payload)
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	if masked {
+		applyMask(payload, maskKey)
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// readLength decodes the 7-bit, 16-bit or 64-bit payload length.
+func (c *Conn) readLength(short byte) (uint64, error) {
+	switch short {
+	case length16:
+		var extended [2]byte
+
+		_, err := io.ReadFull(c.reader, extended[:])
+		if err != nil {
+			return 0, err
+		}
+
+		return uint64(binary.BigEndian.Uint16(extended[:])), nil
+	case length64:
+		var extended [8]byte
+
+		_, err := io.ReadFull(c.reader, extended[:])
+		if err != nil {
+			return 0, err
+		}
+
+		return binary.BigEndian.Uint64(extended[:]), nil
+	default:
+		return uint64(short), nil
+	}
+}
+
+// writeFrame sends a single unfragmented frame, masking it when acting as a
+// client.
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closeSent {
+		return ErrClosed
+	}
+
+	if opcode == opClose {
+		c.closeSent = true
+	}
+
+	frame := make([]byte, 0, len(payload)+headerLen+8+maskKeyLen)
+	frame = append(frame, finBit|opcode)
+
+	var maskFlag byte
+	if c.client {
+		maskFlag = maskBit
+	}
+
+	length := len(payload)
+
+	switch {
+	case length <= maxControl:
+		frame = append(frame, maskFlag|byte(length))
+	case length <= maxLength16:
+		frame = append(frame, maskFlag|length16)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(length))
+	default:
+		frame = append(frame, maskFlag|length64)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(length))
+	}
+
+	if c.client {
+		var maskKey [maskKeyLen]byte
+
+		_, _ = rand.Read(maskKey[:])
+		frame = append(frame, maskKey[:]...)
+		start := len(frame)
+		frame = append(frame, payload...)
+		applyMask(frame[start:], maskKey)
+	} else {
+		frame = append(frame, payload...)
+	}
+
+	_, err := c.conn.Write(frame)
+	if err != nil {
+		if errors.Is(err, net.ErrClosed) {
+			return ErrClosed
+		}
+
+		return fmt.Errorf("websocket: write: %w", err)
+	}
+
+	return nil
+}
+
+// applyMask XORs data with the masking key in place.
+func applyMask(data []byte, key [maskKeyLen]byte) {
+	for i := range data {
+		data[i] ^= key[i%maskKeyLen]
+	}
+}
@@ -0,0 +1,218 @@
+package wire
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	// hexDigits are the digits of \u escapes.
+	hexDigits = "0123456789abcdef"
+	// decimal is the base of integer literals.
+	decimal = 10
+)
+
+// replacementChar is how encoding/json writes a byte that is not valid
+// UTF-8: the escape \ufffd or the raw U+FFFD, depending on the Go release.
+// AppendString writes the same, so both stay byte-equal.
+var replacementChar = sync.OnceValue(func() string {
+	data, err := json.Marshal("\xff")
+	if err != nil {
+		return `\ufffd`
+	}
+
+	return string(data[1 : len(data)-1])
+})
+
+// The AppendX functions write the wire form of a message without
+// reflection, producing the same bytes as json.Marshal of the message. The
+// ...Field variants write a property, preceded by a comma unless it is the
+// first property of the enclosing object.
+
+// AppendString appends s as a JSON string, escaped as by encoding/json.
+func AppendString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+
+	for i := 0; i < len(s); {
+		char := s[i]
+		if char < utf8.RuneSelf {
+			if char >= ' ' && char != '"' && char != '\\' &&
+				char != '<' && char != '>' && char != '&' {
+				i++
+
+				continue
+			}
+
+			dst = append(dst, s[start:i]...)
+			dst = appendEscape(dst, char)
+			i++
+			start = i
+
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, replacementChar()...)
+			i += size
+			start = i
+
+			continue
+		}
+
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, `\u202`...)
+			dst = append(dst, hexDigits[r&0xF])
+			i += size
+			start = i
+
+			continue
+		}
+
+		i += size
+	}
+
+	dst = append(dst, s[start:]...)
+
+	return append(dst, '"')
+}
+
+// appendEscape appends the escape sequence of an ASCII character.
+func appendEscape(dst []byte, char byte) []byte {
+	switch char {
+	case '"', '\\':
+		return append(dst, '\\', char)
+	case '\n':
+		return append(dst, '\\', 'n')
+	case '\r':
+		return append(dst, '\\', 'r')
+	case '\t':
+		return append(dst, '\\', 't')
+	case '\b':
+		return append(dst, '\\', 'b')
+	case '\f':
+		return append(dst, '\\', 'f')
+	default:
+		return append(
+			dst,
+			'\\', 'u', '0', '0',
+			hexDigits[char>>4], hexDigits[char&0xF],
+		)
+	}
+}
+
+// AppendKey appends the name of a property and its colon.
+func AppendKey(dst []byte, name string) []byte {
+	if len(dst) > 0 && dst[len(dst)-1] != '{' {
+		dst = append(dst, ',')
+	}
+
+	dst = append(dst, '"')
+	dst = append(dst, name...)
+
+	return append(dst, '"', ':')
+}
+
+// AppendStringField appends a string property.
+func AppendStringField(dst []byte, name, value string) []byte {
+	return AppendString(AppendKey(dst, name), value)
+}
+
+// AppendOptionalField appends the string form of an optional property, or
+// nothing when it is nil.
+func AppendOptionalField[T fmt.Stringer](
+	dst []byte,
+	name string,
+	value *T,
+) []byte {
+	if value == nil {
+		return dst
+	}
+
+	return AppendStringField(dst, name, (*value).String())
+}
+
+// AppendIntegerField appends an Integer property.
+func AppendIntegerField(dst []byte, name string, value types.Integer) []byte {
+	dst = AppendKey(dst, name)
+
+	return strconv.AppendUint(dst, uint64(value.Value()), decimal)
+}
+
+// AppendOptionalIntegerField appends an optional Integer property, or
+// nothing when it is nil.
+func AppendOptionalIntegerField(
+	dst []byte,
+	name string,
+	value *types.Integer,
+) []byte {
+	if value == nil {
+		return dst
+	}
+
+	return AppendIntegerField(dst, name, *value)
+}
+
+// AppendMeterValuesField appends a list of types.MeterValue property. A nil
+// list is written as null, as by encoding/json.
+func AppendMeterValuesField(
+	dst []byte,
+	name string,
+	meterValues []types.MeterValue,
+) []byte {
+	dst = AppendKey(dst, name)
+	if meterValues == nil {
+		return append(dst, "null"...)
+	}
+
+	dst = append(dst, '[')
+
+	for i, meterValue := range meterValues {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+
+		dst = append(dst, '{')
+		dst = AppendStringField(
+			dst,
+			"timestamp",
+			meterValue.Timestamp().String(),
+		)
+		dst = AppendKey(dst, "sampledValue")
+		dst = append(dst, '[')
+
+		for j, sampled := range meterValue.SampledValue() {
+			if j > 0 {
+				dst = append(dst, ',')
+			}
+
+			dst = appendSampledValue(dst, sampled)
+		}
+
+		dst = append(dst, ']', '}')
+	}
+
+	return append(dst, ']')
+}
+
+// appendSampledValue appends a types.SampledValue object.
+func appendSampledValue(dst []byte, sampled types.SampledValue) []byte {
+	dst = append(dst, '{')
+	dst = AppendStringField(dst, "value", sampled.Value().String())
+	dst = AppendOptionalField(dst, "context", sampled.Context())
+	dst = AppendOptionalField(dst, "format", sampled.Format())
+	dst = AppendOptionalField(dst, "measurand", sampled.Measurand())
+	dst = AppendOptionalField(dst, "phase", sampled.Phase())
+	dst = AppendOptionalField(dst, "location", sampled.Location())
+	dst = AppendOptionalField(dst, "unit", sampled.Unit())
+
+	return append(dst, '}')
+}
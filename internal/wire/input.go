@@ -0,0 +1,159 @@
+package wire
+
+import (
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// Copy returns a copy of an optional value, or nil, so that an Input does not
+// share memory with the message it was taken from.
+func Copy[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+
+	copied := *value
+
+	return &copied
+}
+
+// OptionalInt returns the input form of an optional Integer, or nil.
+func OptionalInt(value *types.Integer) *int {
+	if value == nil {
+		return nil
+	}
+
+	number := int(value.Value())
+
+	return &number
+}
+
+// IdTagInfoInput converts a types.IdTagInfo back to its input form.
+func IdTagInfoInput(info types.IdTagInfo) types.IdTagInfoInput {
+	return types.IdTagInfoInput{
+		Status:      info.Status().String(),
+		ExpiryDate:  OptionalString(info.ExpiryDate()),
+		ParentIdTag: OptionalString(info.ParentIdTag()),
+	}
+}
+
+// MeterValueInputs converts a list of types.MeterValue back to its input
+// form.
+func MeterValueInputs(meterValues []types.MeterValue) []types.MeterValueInput {
+	if meterValues == nil {
+		return nil
+	}
+
+	inputs := make([]types.MeterValueInput, len(meterValues))
+	for i, meterValue := range meterValues {
+		sampled := meterValue.SampledValue()
+		sampledValues := make([]types.SampledValueInput, len(sampled))
+
+		for j, sampledValue := range sampled {
+			sampledValues[j] = types.SampledValueInput{
+				Value:     sampledValue.Value().String(),
+				Context:   OptionalString(sampledValue.Context()),
+				Format:    OptionalString(sampledValue.Format()),
+				Measurand: OptionalString(sampledValue.Measurand()),
+				Phase:     OptionalString(sampledValue.Phase()),
+				Location:  OptionalString(sampledValue.Location()),
+				Unit:      OptionalString(sampledValue.Unit()),
+			}
+		}
+
+		inputs[i] = types.MeterValueInput{
+			Timestamp:    meterValue.Timestamp().String(),
+			SampledValue: sampledValues,
+		}
+	}
+
+	return inputs
+}
+
+// ChargingScheduleInput converts a types.ChargingSchedule back to its input
+// form.
+func ChargingScheduleInput(
+	schedule types.ChargingSchedule,
+) types.ChargingScheduleInput {
+	source := schedule.ChargingSchedulePeriod()
+	periods := make([]types.ChargingSchedulePeriodInput, len(source))
+
+	for i, period := range source {
+		periods[i] = types.ChargingSchedulePeriodInput{
+			StartPeriod:  int(period.StartPeriod().Value()),
+			Limit:        period.Limit(),
+			NumberPhases: OptionalInt(period.NumberPhases()),
+		}
+	}
+
+	return types.ChargingScheduleInput{
+		Duration:               OptionalInt(schedule.Duration()),
+		StartSchedule:          OptionalString(schedule.StartSchedule()),
+		ChargingRateUnit:       schedule.ChargingRateUnit().String(),
+		ChargingSchedulePeriod: periods,
+		MinChargingRate:        schedule.MinChargingRate(),
+	}
+}
+
+// ChargingProfileInput converts a types.ChargingProfile back to its input
+// form.
+func ChargingProfileInput(
+	profile types.ChargingProfile,
+) types.ChargingProfileInput {
+	return types.ChargingProfileInput{
+		ChargingProfileId:      int(profile.ChargingProfileId().Value()),
+		TransactionId:          OptionalInt(profile.TransactionId()),
+		StackLevel:             int(profile.StackLevel().Value()),
+		ChargingProfilePurpose: profile.ChargingProfilePurpose().String(),
+		ChargingProfileKind:    profile.ChargingProfileKind().String(),
+		RecurrencyKind:         OptionalString(profile.RecurrencyKind()),
+		ValidFrom:              OptionalString(profile.ValidFrom()),
+		ValidTo:                OptionalString(profile.ValidTo()),
+		ChargingSchedule: ChargingScheduleInput(
+			profile.ChargingSchedule(),
+		),
+	}
+}
+
+// AuthorizationDataInputs converts a local authorization list back to its
+// input form.
+func AuthorizationDataInputs(
+	list []types.AuthorizationData,
+) []types.AuthorizationDataInput {
+	if list == nil {
+		return nil
+	}
+
+	inputs := make([]types.AuthorizationDataInput, len(list))
+	for i, data := range list {
+		inputs[i] = types.AuthorizationDataInput{
+			IdTag:     data.IdTag().String(),
+			IdTagInfo: nil,
+		}
+
+		if info := data.IdTagInfo(); info != nil {
+			converted := IdTagInfoInput(*info)
+			inputs[i].IdTagInfo = &converted
+		}
+	}
+
+	return inputs
+}
+
+// KeyValueInputs converts a list of configuration keys back to its input
+// form.
+func KeyValueInputs(keyValues []types.KeyValue) []types.KeyValueInput {
+	if keyValues == nil {
+		return nil
+	}
+
+	inputs := make([]types.KeyValueInput, len(keyValues))
+	for i, keyValue := range keyValues {
+		inputs[i] = types.KeyValueInput{
+			Key:      keyValue.Key().String(),
+			Readonly: keyValue.Readonly(),
+			Value:    OptionalString(keyValue.Value()),
+		}
+	}
+
+	return inputs
+}
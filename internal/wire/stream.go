@@ -0,0 +1,248 @@
+package wire
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// streamState is the position of a Stream in its payload.
+type streamState int
+
+const (
+	// beforeObject is the state before the opening brace.
+	beforeObject streamState = iota
+	// inObject is the state between properties outside the list.
+	inObject
+	// inList is the state between items of the list.
+	inList
+	// done is the state after the closing brace.
+	done
+)
+
+// Stream reads an OCPP-J payload holding one large list property, such as
+// the meterValue of MeterValues.req, from an io.Reader one item at a time.
+// Only the current item and the other properties are held in memory.
+type Stream struct {
+	decoder *json.Decoder
+	list    string
+	rest    map[string]json.RawMessage
+	state   streamState
+	index   int
+	listed  bool
+	err     error
+}
+
+// NewStream returns a Stream over the list property named list of the
+// payload read from r.
+func NewStream(r io.Reader, list string) *Stream {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	return &Stream{
+		decoder: decoder,
+		list:    list,
+		rest:    map[string]json.RawMessage{},
+		state:   beforeObject,
+		index:   0,
+		listed:  false,
+		err:     nil,
+	}
+}
+
+// Next decodes the next list item into item, a pointer to an Input struct,
+// with the strict rules of Unmarshal and returns its index. It returns
+// io.EOF once the payload is read to its end. The error of an invalid item
+// is prefixed with the list name and index, e.g. meterValue[3], and Next
+// may be called again; a malformed payload ends the stream and its error is
+// returned by every later call.
+func (s *Stream) Next(item any) (int, error) {
+	err := s.advance()
+	if err != nil {
+		return 0, err
+	}
+
+	var raw json.RawMessage
+
+	err = s.decoder.Decode(&raw)
+	if err != nil {
+		return 0, s.fail(err)
+	}
+
+	index := s.index
+	s.index++
+
+	err = Unmarshal(raw, item)
+	if err != nil {
+		return index, fmt.Errorf("%s[%d]: %w", s.list, index, err)
+	}
+
+	return index, nil
+}
+
+// Rest reads the payload to its end, skipping the list items Next did not
+// return, and decodes the other properties into input, a pointer to an
+// Input struct whose list field is left empty. It returns the number of
+// list items.
+func (s *Stream) Rest(input any) (int, error) {
+	for {
+		var skipped json.RawMessage
+
+		_, err := s.Next(&skipped)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil && s.err != nil {
+			return s.index, err
+		}
+	}
+
+	data, err := json.Marshal(s.rest)
+	if err != nil {
+		return s.index, fmt.Errorf("payload: %w", err)
+	}
+
+	return s.index, Unmarshal(data, input)
+}
+
+// advance moves the stream to the next list item. It returns io.EOF when
+// the payload has no more items.
+func (s *Stream) advance() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if s.state == beforeObject {
+		err := s.open()
+		if err != nil {
+			return s.fail(err)
+		}
+	}
+
+	for {
+		switch s.state {
+		case inList:
+			if s.decoder.More() {
+				return nil
+			}
+
+			_, err := s.decoder.Token()
+			if err != nil {
+				return s.fail(err)
+			}
+
+			s.state = inObject
+		case inObject:
+			err := s.property()
+			if err != nil {
+				return s.fail(err)
+			}
+		default:
+			return io.EOF
+		}
+	}
+}
+
+// open reads the opening brace of the payload.
+func (s *Stream) open() error {
+	token, err := s.decoder.Token()
+	if err != nil {
+		return err //nolint:wrapcheck // Wrapped by fail.
+	}
+
+	if token != json.Delim('{') {
+		return unexpected(token, reflect.TypeFor[map[string]any]())
+	}
+
+	s.state = inObject
+
+	return nil
+}
+
+// property reads the next property outside the list: the opening bracket
+// of the list, another property kept for Rest or the closing brace.
+func (s *Stream) property() error {
+	if !s.decoder.More() {
+		_, err := s.decoder.Token()
+		if err != nil {
+			return err //nolint:wrapcheck // Wrapped by fail.
+		}
+
+		s.state = done
+
+		return s.end()
+	}
+
+	token, err := s.decoder.Token()
+	if err != nil {
+		return err //nolint:wrapcheck // Wrapped by fail.
+	}
+
+	key, _ := token.(string)
+
+	_, seen := s.rest[key]
+	if seen || (key == s.list && s.listed) {
+		return fmt.Errorf("%w: /%s", ErrDuplicateKey, key)
+	}
+
+	if key != s.list {
+		var raw json.RawMessage
+
+		err = s.decoder.Decode(&raw)
+		s.rest[key] = raw
+
+		return err //nolint:wrapcheck // Wrapped by fail.
+	}
+
+	s.listed = true
+
+	token, err = s.decoder.Token()
+	if err != nil {
+		return err //nolint:wrapcheck // Wrapped by fail.
+	}
+
+	if token != json.Delim('[') {
+		return unexpected(token, reflect.TypeFor[[]any]())
+	}
+
+	s.state = inList
+
+	return nil
+}
+
+// end rejects data after the closing brace.
+func (s *Stream) end() error {
+	_, err := s.decoder.Token()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	if err != nil {
+		return err //nolint:wrapcheck // Wrapped by fail.
+	}
+
+	return fmt.Errorf("%w: data after the payload", ErrTrailingData)
+}
+
+// fail ends the stream with err.
+func (s *Stream) fail(err error) error {
+	s.err = fmt.Errorf("payload: %w", err)
+	s.state = done
+
+	return s.err
+}
+
+// unexpected returns the type error of a token found where a value of
+// goType was expected.
+func unexpected(token json.Token, goType reflect.Type) error {
+	return &json.UnmarshalTypeError{
+		Value:  fmt.Sprint(token),
+		Type:   goType,
+		Offset: 0,
+		Struct: "",
+		Field:  "",
+	}
+}
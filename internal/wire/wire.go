@@ -0,0 +1,501 @@
+// Package wire holds the OCPP-J (JSON over WebSocket) representations of the
+// composite ocpp16types values shared by several messages. The message
+// packages use it to implement json.Marshaler and json.Unmarshaler with the
+// property names of the official OCPP 1.6 JSON schemas, and to convert those
+// values back to the Input structs of ocpp16types.
+package wire
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	types "github.com/aasanchez/ocpp16types"
+)
+
+var (
+	// ErrDuplicateKey is returned for an object holding the same property
+	// twice.
+	ErrDuplicateKey = errors.New("duplicate key")
+	// ErrUnknownProperty is returned for a property the payload does not
+	// define.
+	ErrUnknownProperty = errors.New("unknown property")
+	// ErrTrailingData is returned for a streamed payload followed by more
+	// JSON values.
+	ErrTrailingData = errors.New("trailing data")
+	// ErrMissingProperty is returned for an object lacking a required
+	// property. It wraps types.ErrEmptyValue, as a missing value is.
+	ErrMissingProperty = fmt.Errorf(
+		"required property missing: %w",
+		types.ErrEmptyValue,
+	)
+)
+
+// Marshal encodes a wire representation.
+func Marshal(value any) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("payload: %w", err)
+	}
+
+	return data, nil
+}
+
+// Unmarshal decodes an OCPP-J payload into an Input struct. Decoding is
+// strict, as the schemas set additionalProperties to false: every property
+// must match the name of an Input field, which follow the schema property
+// names, with the case of the schema. Duplicate keys are rejected with
+// ErrDuplicateKey, other properties with ErrUnknownProperty, and numbers with
+// a fraction or exponent for integer fields, e.g. 1.0, with a
+// *json.UnmarshalTypeError. Properties decoded into a field that is neither
+// a pointer nor a list are required, as in the schemas, and rejected with
+// ErrMissingProperty when absent, rather than decoded as the zero value.
+func Unmarshal(data []byte, input any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	err := checkValue(decoder, reflect.TypeOf(input), "")
+	if err == nil {
+		err = json.Unmarshal(data, input)
+	}
+
+	if err != nil {
+		return fmt.Errorf("payload: %w", err)
+	}
+
+	return nil
+}
+
+// checkValue reads the next value of a payload and checks it against the Go
+// type it decodes into. A nil goType only checks for duplicate keys.
+func checkValue(decoder *json.Decoder, goType reflect.Type, path string) error {
+	for goType != nil && goType.Kind() == reflect.Pointer {
+		goType = goType.Elem()
+	}
+
+	token, err := decoder.Token()
+	if err != nil {
+		return err //nolint:wrapcheck // Wrapped by Unmarshal.
+	}
+
+	switch typed := token.(type) {
+	case json.Delim:
+		if typed == '{' {
+			return checkObject(decoder, goType, path)
+		}
+
+		if typed == '[' {
+			return checkArray(decoder, goType, path)
+		}
+	case json.Number:
+		return checkNumber(typed, goType, path)
+	}
+
+	return nil
+}
+
+// checkObject checks the properties of an object up to its closing brace.
+func checkObject(
+	decoder *json.Decoder,
+	goType reflect.Type,
+	path string,
+) error {
+	seen := map[string]bool{}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err //nolint:wrapcheck // Wrapped by Unmarshal.
+		}
+
+		key, _ := token.(string)
+		if seen[key] {
+			return fmt.Errorf("%w: %s/%s", ErrDuplicateKey, path, key)
+		}
+
+		seen[key] = true
+
+		var fieldType reflect.Type
+
+		if goType != nil && goType.Kind() == reflect.Struct {
+			field, ok := fieldByProperty(goType, key)
+			if !ok {
+				return fmt.Errorf("%w: %s/%s", ErrUnknownProperty, path, key)
+			}
+
+			fieldType = field.Type
+		}
+
+		err = checkValue(decoder, fieldType, path+"/"+key)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := decoder.Token()
+	if err != nil {
+		return err //nolint:wrapcheck // Wrapped by Unmarshal.
+	}
+
+	return checkRequired(goType, seen, path)
+}
+
+// checkRequired rejects an object lacking the property of a required field
+// of the struct it decodes into: a field that is neither a pointer nor a
+// list, and whose json tag, if any, has no omitempty option.
+func checkRequired(
+	goType reflect.Type,
+	seen map[string]bool,
+	path string,
+) error {
+	if goType == nil || goType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := range goType.NumField() {
+		field := goType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+			continue
+		default:
+		}
+
+		name, options := propertyOf(field)
+		if name == "-" || strings.Contains(options, "omitempty") {
+			continue
+		}
+
+		if !seen[name] {
+			return fmt.Errorf("%w: %s/%s", ErrMissingProperty, path, name)
+		}
+	}
+
+	return nil
+}
+
+// checkArray checks the items of an array up to its closing bracket.
+func checkArray(decoder *json.Decoder, goType reflect.Type, path string) error {
+	var itemType reflect.Type
+	if goType != nil && goType.Kind() == reflect.Slice {
+		itemType = goType.Elem()
+	}
+
+	for i := 0; decoder.More(); i++ {
+		err := checkValue(decoder, itemType, fmt.Sprintf("%s/%d", path, i))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := decoder.Token()
+
+	return err //nolint:wrapcheck // Wrapped by Unmarshal.
+}
+
+// checkNumber rejects a number literal with a fraction or exponent for an
+// integer field, which encoding/json would otherwise report without the
+// property path.
+func checkNumber(number json.Number, goType reflect.Type, path string) error {
+	if goType == nil || !isInteger(goType.Kind()) ||
+		!strings.ContainsAny(number.String(), ".eE") {
+		return nil
+	}
+
+	return &json.UnmarshalTypeError{
+		Value:  "number " + number.String(),
+		Type:   goType,
+		Offset: 0,
+		Struct: "",
+		Field:  strings.TrimPrefix(path, "/"),
+	}
+}
+
+// fieldByProperty returns the exported field of an Input struct decoding a
+// property: the field with the json tag of that name or, without a tag, the
+// field whose name is the property name with an upper case first letter.
+func fieldByProperty(
+	goType reflect.Type,
+	property string,
+) (reflect.StructField, bool) {
+	for i := range goType.NumField() {
+		field := goType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if name, _ := propertyOf(field); name == property {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// propertyOf returns the property name of an Input struct field and the
+// options of its json tag: the name of the tag or, without one, the field
+// name with a lower case first letter.
+func propertyOf(field reflect.StructField) (string, string) {
+	name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		first, size := utf8.DecodeRuneInString(field.Name)
+		name = string(unicode.ToLower(first)) + field.Name[size:]
+	}
+
+	return name, options
+}
+
+// isInteger reports whether kind is a Go integer kind.
+func isInteger(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16,
+		reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// OptionalString returns the string form of an optional value, or nil.
+func OptionalString[T fmt.Stringer](value *T) *string {
+	if value == nil {
+		return nil
+	}
+
+	str := (*value).String()
+
+	return &str
+}
+
+// OptionalInteger returns the numeric value of an optional Integer, or nil.
+func OptionalInteger(value *types.Integer) *uint16 {
+	if value == nil {
+		return nil
+	}
+
+	number := value.Value()
+
+	return &number
+}
+
+// Strings returns the string form of every value in a list.
+func Strings[T fmt.Stringer](values []T) []string {
+	if values == nil {
+		return nil
+	}
+
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = value.String()
+	}
+
+	return strs
+}
+
+// IdTagInfo is the wire form of types.IdTagInfo.
+type IdTagInfo struct {
+	ExpiryDate  *string `json:"expiryDate,omitempty"`
+	ParentIdTag *string `json:"parentIdTag,omitempty"`
+	Status      string  `json:"status"`
+}
+
+// NewIdTagInfo converts a types.IdTagInfo to its wire form.
+func NewIdTagInfo(info types.IdTagInfo) IdTagInfo {
+	return IdTagInfo{
+		ExpiryDate:  OptionalString(info.ExpiryDate()),
+		ParentIdTag: OptionalString(info.ParentIdTag()),
+		Status:      info.Status().String(),
+	}
+}
+
+// SampledValue is the wire form of types.SampledValue.
+type SampledValue struct {
+	Value     string  `json:"value"`
+	Context   *string `json:"context,omitempty"`
+	Format    *string `json:"format,omitempty"`
+	Measurand *string `json:"measurand,omitempty"`
+	Phase     *string `json:"phase,omitempty"`
+	Location  *string `json:"location,omitempty"`
+	Unit      *string `json:"unit,omitempty"`
+}
+
+// MeterValue is the wire form of types.MeterValue.
+type MeterValue struct {
+	Timestamp    string         `json:"timestamp"`
+	SampledValue []SampledValue `json:"sampledValue"`
+}
+
+// NewMeterValues converts a list of types.MeterValue to its wire form.
+func NewMeterValues(meterValues []types.MeterValue) []MeterValue {
+	if meterValues == nil {
+		return nil
+	}
+
+	values := make([]MeterValue, len(meterValues))
+	for i, meterValue := range meterValues {
+		sampled := meterValue.SampledValue()
+		sampledValues := make([]SampledValue, len(sampled))
+
+		for j, sampledValue := range sampled {
+			sampledValues[j] = SampledValue{
+				Value:     sampledValue.Value().String(),
+				Context:   OptionalString(sampledValue.Context()),
+				Format:    OptionalString(sampledValue.Format()),
+				Measurand: OptionalString(sampledValue.Measurand()),
+				Phase:     OptionalString(sampledValue.Phase()),
+				Location:  OptionalString(sampledValue.Location()),
+				Unit:      OptionalString(sampledValue.Unit()),
+			}
+		}
+
+		values[i] = MeterValue{
+			Timestamp:    meterValue.Timestamp().String(),
+			SampledValue: sampledValues,
+		}
+	}
+
+	return values
+}
+
+// ChargingSchedulePeriod is the wire form of types.ChargingSchedulePeriod.
+type ChargingSchedulePeriod struct {
+	StartPeriod  uint16  `json:"startPeriod"`
+	Limit        float64 `json:"limit"`
+	NumberPhases *uint16 `json:"numberPhases,omitempty"`
+}
+
+// ChargingSchedule is the wire form of types.ChargingSchedule.
+type ChargingSchedule struct {
+	Duration               *uint16                  `json:"duration,omitempty"`
+	StartSchedule          *string                  `json:"startSchedule,omitempty"`
+	ChargingRateUnit       string                   `json:"chargingRateUnit"`
+	ChargingSchedulePeriod []ChargingSchedulePeriod `json:"chargingSchedulePeriod"`
+	MinChargingRate        *float64                 `json:"minChargingRate,omitempty"`
+}
+
+// NewChargingSchedule converts a types.ChargingSchedule to its wire form.
+func NewChargingSchedule(schedule types.ChargingSchedule) ChargingSchedule {
+	source := schedule.ChargingSchedulePeriod()
+	periods := make([]ChargingSchedulePeriod, len(source))
+
+	for i, period := range source {
+		periods[i] = ChargingSchedulePeriod{
+			StartPeriod:  period.StartPeriod().Value(),
+			Limit:        period.Limit(),
+			NumberPhases: OptionalInteger(period.NumberPhases()),
+		}
+	}
+
+	return ChargingSchedule{
+		Duration:               OptionalInteger(schedule.Duration()),
+		StartSchedule:          OptionalString(schedule.StartSchedule()),
+		ChargingRateUnit:       schedule.ChargingRateUnit().String(),
+		ChargingSchedulePeriod: periods,
+		MinChargingRate:        schedule.MinChargingRate(),
+	}
+}
+
+// ChargingProfile is the wire form of types.ChargingProfile.
+type ChargingProfile struct {
+	ChargingProfileId      uint16           `json:"chargingProfileId"`
+	TransactionId          *uint16          `json:"transactionId,omitempty"`
+	StackLevel             uint16           `json:"stackLevel"`
+	ChargingProfilePurpose string           `json:"chargingProfilePurpose"`
+	ChargingProfileKind    string           `json:"chargingProfileKind"`
+	RecurrencyKind         *string          `json:"recurrencyKind,omitempty"`
+	ValidFrom              *string          `json:"validFrom,omitempty"`
+	ValidTo                *string          `json:"validTo,omitempty"`
+	ChargingSchedule       ChargingSchedule `json:"chargingSchedule"`
+}
+
+// NewChargingProfile converts a types.ChargingProfile to its wire form.
+func NewChargingProfile(profile types.ChargingProfile) ChargingProfile {
+	return ChargingProfile{
+		ChargingProfileId:      profile.ChargingProfileId().Value(),
+		TransactionId:          OptionalInteger(profile.TransactionId()),
+		StackLevel:             profile.StackLevel().Value(),
+		ChargingProfilePurpose: profile.ChargingProfilePurpose().String(),
+		ChargingProfileKind:    profile.ChargingProfileKind().String(),
+		RecurrencyKind:         OptionalString(profile.RecurrencyKind()),
+		ValidFrom:              OptionalString(profile.ValidFrom()),
+		ValidTo:                OptionalString(profile.ValidTo()),
+		ChargingSchedule:       NewChargingSchedule(profile.ChargingSchedule()),
+	}
+}
+
+// AuthorizationData is the wire form of types.AuthorizationData.
+type AuthorizationData struct {
+	IdTag     string     `json:"idTag"`
+	IdTagInfo *IdTagInfo `json:"idTagInfo,omitempty"`
+}
+
+// NewAuthorizationList converts a local authorization list to its wire form.
+func NewAuthorizationList(list []types.AuthorizationData) []AuthorizationData {
+	if list == nil {
+		return nil
+	}
+
+	entries := make([]AuthorizationData, len(list))
+	for i, data := range list {
+		entries[i] = AuthorizationData{
+			IdTag:     data.IdTag().String(),
+			IdTagInfo: nil,
+		}
+
+		if info := data.IdTagInfo(); info != nil {
+			converted := NewIdTagInfo(*info)
+			entries[i].IdTagInfo = &converted
+		}
+	}
+
+	return entries
+}
+
+// KeyValue is the wire form of types.KeyValue.
+type KeyValue struct {
+	Key      string  `json:"key"`
+	Readonly bool    `json:"readonly"`
+	Value    *string `json:"value,omitempty"`
+}
+
+// NewKeyValues converts a list of configuration keys to its wire form.
+func NewKeyValues(keyValues []types.KeyValue) []KeyValue {
+	if keyValues == nil {
+		return nil
+	}
+
+	entries := make([]KeyValue, len(keyValues))
+	for i, keyValue := range keyValues {
+		entries[i] = KeyValue{
+			Key:      keyValue.Key().String(),
+			Readonly: keyValue.Readonly(),
+			Value:    OptionalString(keyValue.Value()),
+		}
+	}
+
+	return entries
+}
+
+// Decode unmarshals an OCPP-J payload into the Input struct accepted by build
+// and returns the validated message built from it.
+func Decode[I, M any](data []byte, build func(I) (M, error)) (M, error) {
+	var input I
+
+	err := Unmarshal(data, &input)
+	if err != nil {
+		var zero M
+
+		return zero, err
+	}
+
+	return build(input)
+}
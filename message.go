@@ -0,0 +1,121 @@
+package ocpp16messages
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Kind tells a request (.req) from a confirmation (.conf).
+type Kind int
+
+const (
+	// Request is the kind of the ReqMessage types, sent in CALL frames.
+	Request Kind = iota
+	// Confirmation is the kind of the ConfMessage types, sent in CALLRESULT
+	// frames.
+	Confirmation
+)
+
+// String returns the suffix used by the specification, req or conf.
+func (k Kind) String() string {
+	if k == Confirmation {
+		return "conf"
+	}
+
+	return "req"
+}
+
+// Direction is the way a message travels between the two sides.
+type Direction int
+
+const (
+	// ChargePointToCentralSystem messages are sent by the Charge Point.
+	ChargePointToCentralSystem Direction = iota + 1
+	// CentralSystemToChargePoint messages are sent by the Central System.
+	CentralSystemToChargePoint
+	// Bidirectional messages, those of DataTransfer, may be sent by either
+	// side.
+	Bidirectional
+)
+
+// String returns a short description of the direction, e.g. CP->CS.
+func (d Direction) String() string {
+	switch d {
+	case ChargePointToCentralSystem:
+		return "CP->CS"
+	case CentralSystemToChargePoint:
+		return "CS->CP"
+	case Bidirectional:
+		return "CP<->CS"
+	default:
+		return "Unknown"
+	}
+}
+
+// FeatureProfile is the OCPP 1.6 feature profile an action belongs to, as
+// listed in the SupportedFeatureProfiles configuration key.
+type FeatureProfile string
+
+// Feature profiles of the OCPP 1.6 specification, section 3.
+const (
+	Core                    FeatureProfile = "Core"
+	FirmwareManagement      FeatureProfile = "FirmwareManagement"
+	LocalAuthListManagement FeatureProfile = "LocalAuthListManagement"
+	Reservation             FeatureProfile = "Reservation"
+	SmartCharging           FeatureProfile = "SmartCharging"
+	RemoteTrigger           FeatureProfile = "RemoteTrigger"
+)
+
+// Message is implemented by the ReqMessage and ConfMessage types of every
+// action package, e.g. authorize.ReqMessage.
+type Message interface {
+	json.Marshaler
+
+	// Action returns the action name sent in CALL frames, e.g. Authorize.
+	Action() string
+	// Kind tells a request from a confirmation.
+	Kind() Kind
+	// Direction returns the way the message travels.
+	Direction() Direction
+	// FeatureProfile returns the feature profile of the action.
+	FeatureProfile() FeatureProfile
+}
+
+// Decode decodes an OCPP-J payload into the message type T, e.g.
+// Decode[authorize.ReqMessage](payload). The payload is validated by the
+// constructor of T, so only valid messages are returned.
+func Decode[T Message](payload []byte) (T, error) {
+	var msg T
+
+	err := json.Unmarshal(payload, &msg)
+	if err != nil {
+		return msg, fmt.Errorf("%s.%s: %w", msg.Action(), msg.Kind(), err)
+	}
+
+	return msg, nil
+}
+
+// Encode encodes a message as its OCPP-J payload.
+func Encode(msg Message) ([]byte, error) {
+	data, err := msg.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("%s.%s: %w", msg.Action(), msg.Kind(), err)
+	}
+
+	return data, nil
+}
+
+// Validate checks a message that did not come from a constructor, such as a
+// zero value or a message assembled field by field, by running it through
+// the constructor of its type. Messages built by the constructors are always
+// valid.
+func Validate[T Message](msg T) error {
+	data, err := Encode(msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = Decode[T](data)
+
+	return err
+}
@@ -50,4 +50,21 @@
 // MeterValues.conf. Sanity checks MAY be applied, but SHALL NOT prevent
 // sending the confirmation. Failure to respond would cause the Charge Point
 // to retry the message according to transaction-related error handling.
+//
+// # Streaming
+//
+// A Charge Point that was offline for long uploads large batches. ReqDecoder
+// reads a MeterValues.req payload from an io.Reader and validates one
+// MeterValue at a time; Message returns the other fields once the entries
+// are read:
+//
+//	decoder := metervalues.NewReqDecoder(body)
+//	for {
+//		meterValue, err := decoder.Next()
+//		if errors.Is(err, io.EOF) {
+//			break
+//		}
+//		// err names the invalid entry, e.g. meterValue[3]
+//	}
+//	msg, err := decoder.Message()
 package metervalues
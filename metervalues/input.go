@@ -0,0 +1,51 @@
+package metervalues
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		ConnectorId:   int(m.ConnectorId.Value()),
+		TransactionId: wire.OptionalInt(m.TransactionId),
+		MeterValue:    wire.MeterValueInputs(m.MeterValue),
+	}
+}
+
+// WithConnectorId returns a copy of the message with ConnectorId set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithConnectorId(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ConnectorId = value
+
+	return Req(input)
+}
+
+// WithTransactionId returns a copy of the message with TransactionId set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithTransactionId(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.TransactionId = &value
+
+	return Req(input)
+}
+
+// WithMeterValue returns a copy of the message with MeterValue set to value.
+// The copy is validated by Req.
+func (m ReqMessage) WithMeterValue(
+	value []types.MeterValueInput,
+) (ReqMessage, error) {
+	input := m.ToInput()
+	input.MeterValue = value
+
+	return Req(input)
+}
+
+// ToInput returns the empty ConfInput, as MeterValues.conf has no fields.
+func (ConfMessage) ToInput() ConfInput {
+	return ConfInput{}
+}
@@ -0,0 +1,72 @@
+package metervalues
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of MeterValues.req.
+type reqWire struct {
+	ConnectorId   uint16            `json:"connectorId"`
+	TransactionId *uint16           `json:"transactionId,omitempty"`
+	MeterValue    []wire.MeterValue `json:"meterValue"`
+}
+
+// confWire is the OCPP-J payload of MeterValues.conf.
+type confWire struct{}
+
+// MarshalJSON encodes the message as its OCPP-J MeterValues.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		ConnectorId:   m.ConnectorId.Value(),
+		TransactionId: wire.OptionalInteger(m.TransactionId),
+		MeterValue:    wire.NewMeterValues(m.MeterValue),
+	})
+}
+
+// AppendJSON appends the OCPP-J MeterValues.req payload to dst without
+// reflection. It writes the same bytes as MarshalJSON.
+func (m ReqMessage) AppendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	dst = wire.AppendIntegerField(dst, "connectorId", m.ConnectorId)
+	dst = wire.AppendOptionalIntegerField(dst, "transactionId", m.TransactionId)
+	dst = wire.AppendMeterValuesField(dst, "meterValue", m.MeterValue)
+
+	return append(dst, '}')
+}
+
+// UnmarshalJSON decodes an OCPP-J MeterValues.req payload and validates it with
+// Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J MeterValues.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{})
+}
+
+// AppendJSON appends the OCPP-J MeterValues.conf payload to dst without
+// reflection. It writes the same bytes as MarshalJSON.
+func (m ConfMessage) AppendJSON(dst []byte) []byte {
+	return append(dst, '{', '}')
+}
+
+// UnmarshalJSON decodes an OCPP-J MeterValues.conf payload and validates it
+// with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
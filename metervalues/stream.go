@@ -0,0 +1,93 @@
+package metervalues
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// ReqDecoder reads an OCPP-J MeterValues.req payload from an io.Reader and
+// validates its meterValue entries one at a time, so batches uploaded after
+// a long offline period are never held in memory as a whole.
+type ReqDecoder struct {
+	stream *wire.Stream
+}
+
+// NewReqDecoder returns a ReqDecoder reading the payload from r.
+func NewReqDecoder(r io.Reader) *ReqDecoder {
+	return &ReqDecoder{stream: wire.NewStream(r, "meterValue")}
+}
+
+// Next returns the next meterValue entry, validated as by Req. It returns
+// io.EOF after the last entry. An invalid entry yields an error prefixed
+// with its index, e.g. meterValue[3], and Next may be called again; a
+// malformed payload ends the stream.
+func (d *ReqDecoder) Next() (types.MeterValue, error) {
+	var input types.MeterValueInput
+
+	index, err := d.stream.Next(&input)
+	if err != nil {
+		return types.MeterValue{}, err //nolint:wrapcheck // Already wrapped.
+	}
+
+	meterValue, err := types.NewMeterValue(input)
+	if err != nil {
+		return types.MeterValue{}, fmt.Errorf(
+			"meterValue[%d]: %w",
+			index,
+			err,
+		)
+	}
+
+	return meterValue, nil
+}
+
+// Message reads the payload to its end, skipping the entries Next did not
+// return, and returns the message with ConnectorId and TransactionId
+// validated as by Req. Its MeterValue is nil: the entries are the ones
+// returned by Next. Returns an error if the payload is malformed, a field is
+// invalid or the payload holds no meterValue entry.
+func (d *ReqDecoder) Message() (ReqMessage, error) {
+	var input ReqInput
+
+	entries, err := d.stream.Rest(&input)
+	if err != nil {
+		return ReqMessage{}, err //nolint:wrapcheck // Already wrapped.
+	}
+
+	var (
+		errs      []error
+		validated reqValidation
+	)
+
+	validated.connectorId, errs = validateReqConnectorId(
+		input.ConnectorId,
+		errs,
+	)
+
+	if input.TransactionId != nil {
+		validated.transactionId, errs = validateReqTransactionId(
+			*input.TransactionId,
+			errs,
+		)
+	}
+
+	if entries == metervaluesLenZero {
+		errs = append(errs, fmt.Errorf(
+			types.ErrorFieldFormat, "MeterValue", types.ErrEmptyValue,
+		))
+	}
+
+	if errs != nil {
+		return ReqMessage{}, errors.Join(errs...)
+	}
+
+	return ReqMessage{
+		ConnectorId:   validated.connectorId,
+		TransactionId: validated.transactionId,
+		MeterValue:    nil,
+	}, nil
+}
@@ -0,0 +1,187 @@
+package metervalues_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/metervalues"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const streamedMeterValue = `{"timestamp":"2025-01-02T15:00:00Z",` +
+	`"sampledValue":[{"value":"100"}]}`
+
+// meterValuesPayload returns a MeterValues.req payload with entries meter
+// values, produced lazily by a reader.
+func meterValuesPayload(entries int) io.Reader {
+	readers := []io.Reader{strings.NewReader(`{"meterValue":[`)}
+
+	for i := range entries {
+		if i > 0 {
+			readers = append(readers, strings.NewReader(","))
+		}
+
+		readers = append(readers, strings.NewReader(streamedMeterValue))
+	}
+
+	return io.MultiReader(append(
+		readers,
+		strings.NewReader(`],"connectorId":1,"transactionId":7}`),
+	)...)
+}
+
+func TestReqDecoder_Stream(t *testing.T) {
+	t.Parallel()
+
+	const entries = 1000
+
+	decoder := metervalues.NewReqDecoder(meterValuesPayload(entries))
+
+	count := 0
+
+	for {
+		meterValue, err := decoder.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+
+		if meterValue.Timestamp().String() != validTimestampReq {
+			t.Errorf(
+				types.ErrorMismatchValue,
+				validTimestampReq,
+				meterValue.Timestamp(),
+			)
+		}
+
+		count++
+	}
+
+	if count != entries {
+		t.Errorf(types.ErrorMismatchValue, entries, count)
+	}
+
+	msg, err := decoder.Message()
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if msg.ConnectorId.Value() != validConnectorId {
+		t.Errorf(types.ErrorMismatchValue, validConnectorId, msg.ConnectorId)
+	}
+
+	if msg.TransactionId == nil || msg.TransactionId.Value() != 7 {
+		t.Errorf(types.ErrorMismatchValue, 7, msg.TransactionId)
+	}
+
+	if msg.MeterValue != nil {
+		t.Errorf(types.ErrorMismatchValue, nil, msg.MeterValue)
+	}
+}
+
+func TestReqDecoder_InvalidEntry(t *testing.T) {
+	t.Parallel()
+
+	decoder := metervalues.NewReqDecoder(strings.NewReader(
+		`{"connectorId":1,"meterValue":[` + streamedMeterValue +
+			`,{"timestamp":"yesterday","sampledValue":[{"value":"1"}]},` +
+			streamedMeterValue + `]}`,
+	))
+
+	_, err := decoder.Next()
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	_, err = decoder.Next()
+	if err == nil || !strings.Contains(err.Error(), "meterValue[1]") {
+		t.Errorf(types.ErrorWantContains, err, "meterValue[1]")
+	}
+
+	_, err = decoder.Next()
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	_, err = decoder.Next()
+	if !errors.Is(err, io.EOF) {
+		t.Errorf(types.ErrorWrapping, err, io.EOF)
+	}
+}
+
+func TestReqDecoder_Malformed(t *testing.T) {
+	t.Parallel()
+
+	decoder := metervalues.NewReqDecoder(strings.NewReader(
+		`{"connectorId":1,"meterValue":[` + streamedMeterValue + `,{`,
+	))
+
+	_, err := decoder.Next()
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	_, err = decoder.Next()
+	if err == nil || errors.Is(err, io.EOF) {
+		t.Fatalf(types.ErrorWantNonNil, "syntax error")
+	}
+
+	_, again := decoder.Next()
+	if again == nil || again.Error() != err.Error() {
+		t.Errorf(types.ErrorMismatchValue, err, again)
+	}
+
+	_, err = decoder.Message()
+	if err == nil {
+		t.Errorf(types.ErrorWantNonNil, "error")
+	}
+}
+
+func TestReqDecoder_Message(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload string
+		want    error
+	}{
+		{
+			"skips unread entries",
+			`{"connectorId":1,"meterValue":[` + streamedMeterValue + `]}`,
+			nil,
+		},
+		{
+			"no entries",
+			`{"connectorId":1,"meterValue":[]}`,
+			types.ErrEmptyValue,
+		},
+		{"no list", `{"connectorId":1}`, types.ErrEmptyValue},
+		{
+			"invalid connectorId",
+			`{"connectorId":-1,"meterValue":[` + streamedMeterValue + `]}`,
+			types.ErrInvalidValue,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			decoder := metervalues.NewReqDecoder(strings.NewReader(tc.payload))
+
+			_, err := decoder.Message()
+			if tc.want == nil && err != nil {
+				t.Errorf(types.ErrorUnexpectedError, err)
+			}
+
+			if tc.want != nil && !errors.Is(err, tc.want) {
+				t.Errorf(types.ErrorWrapping, err, tc.want)
+			}
+		})
+	}
+}
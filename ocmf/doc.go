@@ -0,0 +1,40 @@
+// Package ocmf decodes and verifies Open Charge Metering Format (OCMF)
+// signed meter values carried in OCPP 1.6 MeterValues.
+//
+// German calibration law (Eichrecht) compliant Charge Points report billing
+// relevant energy readings as a SampledValue with format "SignedData". The
+// value of such a SampledValue is an OCMF string made of three sections
+// separated by a pipe character:
+//
+//	OCMF|{payload}|{signature}
+//
+// The payload section is a JSON object describing the meter, the
+// identification used for the session and one or more readings. The
+// signature section is a JSON object holding an ECDSA signature over the
+// exact bytes of the payload section, as transmitted.
+//
+// # Usage
+//
+// Parse decodes an OCMF string, FromSampledValue and FromMeterValues extract
+// OCMF documents from validated MeterValues.req meterValue elements or
+// StopTransaction.req transactionData elements. A Document is only
+// trustworthy after Verify succeeded against the public key of the meter:
+//
+//	docs, err := ocmf.FromMeterValues(req.MeterValue)
+//	if err != nil {
+//		// Handle malformed SignedData values
+//	}
+//
+//	for _, signed := range docs {
+//		if err := signed.Document.Verify(meterKey); err != nil {
+//			// Reject the reading: ocmf.ErrInvalidSignature
+//		}
+//	}
+//
+// # Supported Algorithms
+//
+// Only the ECDSA curves available in the Go standard library are supported:
+// secp256r1 (P-256, the OCMF default), secp384r1 (P-384) and secp521r1
+// (P-521). Documents signed with other curves, such as brainpool or
+// secp256k1, are reported with ErrUnsupportedAlgorithm.
+package ocmf
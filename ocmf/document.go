@@ -0,0 +1,139 @@
+package ocmf
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	// header is the first section of every OCMF document.
+	header = "OCMF"
+	// separator separates the OCMF sections.
+	separator = "|"
+	// indexNotFound is returned by strings.LastIndex when nothing matches.
+	indexNotFound = -1
+)
+
+// Document is a parsed OCMF string. The sections are kept exactly as
+// transmitted because the signature is computed over the payload bytes.
+type Document struct {
+	Payload      Payload
+	Signature    Signature
+	rawPayload   string
+	rawSignature string
+}
+
+// Parse decodes an OCMF string of the form "OCMF|{payload}|{signature}".
+// It validates the structure and mandatory payload fields but does not verify
+// the signature; call Verify before trusting the readings. Returns an error
+// wrapping types.ErrInvalidValue or types.ErrEmptyValue if:
+//   - The value does not start with "OCMF|"
+//   - The payload or signature section is missing or is not a JSON object
+//   - Mandatory payload fields (PG, MS, RD and per reading TM, RU, ST) are
+//     missing, or a reading time is malformed
+//   - The signature data (SD) is missing
+func Parse(value string) (Document, error) {
+	rawPayload, rawSignature, err := split(value)
+	if err != nil {
+		return Document{}, err
+	}
+
+	var errs []error
+
+	var payload Payload
+
+	err = json.Unmarshal([]byte(rawPayload), &payload)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("payload: %w", types.ErrInvalidValue))
+	} else if err = validatePayload(payload); err != nil {
+		errs = append(errs, fmt.Errorf("payload: %w", err))
+	}
+
+	var signature Signature
+
+	err = json.Unmarshal([]byte(rawSignature), &signature)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("signature: %w", types.ErrInvalidValue))
+	} else if signature.SD == "" {
+		errs = append(errs, fmt.Errorf("signature: SD: %w", types.ErrEmptyValue))
+	}
+
+	if errs != nil {
+		return Document{}, errors.Join(errs...)
+	}
+
+	return Document{
+		Payload:      payload,
+		Signature:    signature,
+		rawPayload:   rawPayload,
+		rawSignature: rawSignature,
+	}, nil
+}
+
+// split returns the payload and signature sections of an OCMF string.
+func split(value string) (string, string, error) {
+	rest, found := strings.CutPrefix(value, header+separator)
+	if !found {
+		return "", "", fmt.Errorf("header: %w", types.ErrInvalidValue)
+	}
+
+	index := strings.LastIndex(rest, separator)
+	if index == indexNotFound {
+		return "", "", fmt.Errorf("signature: %w", types.ErrEmptyValue)
+	}
+
+	rawPayload := rest[:index]
+	rawSignature := rest[index+len(separator):]
+
+	if rawPayload == "" {
+		return "", "", fmt.Errorf("payload: %w", types.ErrEmptyValue)
+	}
+
+	if rawSignature == "" {
+		return "", "", fmt.Errorf("signature: %w", types.ErrEmptyValue)
+	}
+
+	return rawPayload, rawSignature, nil
+}
+
+// Verify checks the document signature against the meter public key. It
+// returns ErrInvalidSignature when the payload was not signed by the key and
+// ErrUnsupportedAlgorithm when the signature cannot be checked with it.
+func (d Document) Verify(publicKey *ecdsa.PublicKey) error {
+	return d.Signature.verify(publicKey, []byte(d.rawPayload))
+}
+
+// Readings returns a copy of the readings of the document. The readings are
+// only trustworthy after Verify succeeded.
+func (d Document) Readings() []Reading {
+	readings := make([]Reading, len(d.Payload.RD))
+	copy(readings, d.Payload.RD)
+
+	return readings
+}
+
+// String returns the document exactly as it was parsed.
+func (d Document) String() string {
+	return header + separator + d.rawPayload + separator + d.rawSignature
+}
+
+// ParseAndVerify parses an OCMF string and verifies its signature in one
+// step. The returned Document is only populated when both succeed.
+func ParseAndVerify(value string, publicKey *ecdsa.PublicKey) (Document, error) {
+	doc, err := Parse(value)
+	if err != nil {
+		return Document{}, err
+	}
+
+	err = doc.Verify(publicKey)
+	if err != nil {
+		return Document{}, err
+	}
+
+	return doc, nil
+}
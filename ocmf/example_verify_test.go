@@ -0,0 +1,52 @@
+package ocmf_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/aasanchez/ocpp16messages/ocmf"
+)
+
+// ExampleParseAndVerify demonstrates verifying an OCMF string reported as a
+// SignedData sampled value by an Eichrecht meter.
+func ExampleParseAndVerify() {
+	payload := `{"FV":"1.0","PG":"T1","MS":"BQ27400330016","IS":true,` +
+		`"IT":"ISO14443","ID":"1F2D3A4F5506C7","RD":[` +
+		`{"TM":"2018-07-24T13:22:04,000+0200 S","TX":"B","RV":2935.6,` +
+		`"RI":"1-b:1.8.0","RU":"kWh","ST":"G"}]}`
+
+	// In production the key is the meter's published public key, see
+	// ocmf.ParsePublicKey.
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	digest := sha256.Sum256([]byte(payload))
+	signature, _ := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	value := "OCMF|" + payload + `|{"SD":"` + hex.EncodeToString(signature) + `"}`
+
+	doc, err := ocmf.ParseAndVerify(value, &key.PublicKey)
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	for _, reading := range doc.Readings() {
+		fmt.Println("Reading:", reading.RV, reading.RU)
+	}
+	// Output:
+	// Reading: 2935.6 kWh
+}
+
+// ExampleParse_invalidHeader demonstrates the error returned when a
+// SignedData value is not in OCMF format.
+func ExampleParse_invalidHeader() {
+	_, err := ocmf.Parse("3045022100ab")
+	if err != nil {
+		fmt.Println(err)
+	}
+	// Output:
+	// header: invalid value
+}
@@ -0,0 +1,75 @@
+package ocmf
+
+import (
+	"errors"
+	"fmt"
+
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// ErrNotSignedData is returned by FromSampledValue when the SampledValue
+// format is not "SignedData".
+var ErrNotSignedData = errors.New("ocmf: sampled value is not SignedData")
+
+// SignedValue is an OCMF document found in a list of meter values, together
+// with its position in that list.
+type SignedValue struct {
+	MeterValueIndex   int
+	SampledValueIndex int
+	Timestamp         types.DateTime
+	Document          Document
+}
+
+// FromSampledValue parses the OCMF document carried by a SampledValue. It
+// returns ErrNotSignedData if the SampledValue format is absent or "Raw",
+// and the Parse errors if the value is not a valid OCMF string.
+func FromSampledValue(sampledValue types.SampledValue) (Document, error) {
+	format := sampledValue.Format()
+	if format == nil || *format != types.ValueFormatSignedData {
+		return Document{}, ErrNotSignedData
+	}
+
+	return Parse(sampledValue.Value().String())
+}
+
+// FromMeterValues extracts every OCMF document from the given meter values,
+// such as metervalues.ReqMessage.MeterValue or
+// stoptransaction.ReqMessage.TransactionData. SampledValues that are not
+// "SignedData" are skipped. Documents that fail to parse are reported with
+// their position (e.g. "meterValue[0].sampledValue[2]: ...") and all errors
+// are returned together alongside the documents that did parse.
+func FromMeterValues(meterValues []types.MeterValue) ([]SignedValue, error) {
+	var (
+		signed []SignedValue
+		errs   []error
+	)
+
+	for i, meterValue := range meterValues {
+		for j, sampledValue := range meterValue.SampledValue() {
+			doc, err := FromSampledValue(sampledValue)
+			if errors.Is(err, ErrNotSignedData) {
+				continue
+			}
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf(
+					"meterValue[%d].sampledValue[%d]: %w",
+					i,
+					j,
+					err,
+				))
+
+				continue
+			}
+
+			signed = append(signed, SignedValue{
+				MeterValueIndex:   i,
+				SampledValueIndex: j,
+				Timestamp:         meterValue.Timestamp(),
+				Document:          doc,
+			})
+		}
+	}
+
+	return signed, errors.Join(errs...)
+}
@@ -0,0 +1,140 @@
+package ocmf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	// timeLayout is the OCMF reading time layout without the trailing
+	// synchronization flag, e.g. "2018-07-24T13:22:04,000+0200".
+	timeLayout = "2006-01-02T15:04:05,000-0700"
+	// timeFlagSeparator separates the timestamp from its synchronization flag.
+	timeFlagSeparator = " "
+	// readingsLenZero is the empty readings count.
+	readingsLenZero = 0
+)
+
+// Time synchronization flags appended to the OCMF reading time.
+const (
+	// TimeInformative marks a time without synchronization ("I").
+	TimeInformative = "I"
+	// TimeSynchronized marks a time synchronized with a reliable source ("S").
+	TimeSynchronized = "S"
+	// TimeRelative marks a time relative to the meter start ("R").
+	TimeRelative = "R"
+	// TimeUnsynchronized marks a time known to be unsynchronized ("U").
+	TimeUnsynchronized = "U"
+)
+
+// Payload is the decoded payload section of an OCMF document. Field names
+// follow the two-letter OCMF keys; the comment on each field gives the name
+// used by the OCMF specification.
+type Payload struct {
+	FV string            `json:"FV,omitempty"` // Format Version
+	GI string            `json:"GI,omitempty"` // Gateway Identification
+	GS string            `json:"GS,omitempty"` // Gateway Serial
+	GV string            `json:"GV,omitempty"` // Gateway Version
+	PG string            `json:"PG"`           // Pagination (e.g. "T1", "F1")
+	MV string            `json:"MV,omitempty"` // Meter Vendor
+	MM string            `json:"MM,omitempty"` // Meter Model
+	MS string            `json:"MS"`           // Meter Serial
+	MF string            `json:"MF,omitempty"` // Meter Firmware
+	IS bool              `json:"IS"`           // Identification Status
+	IL string            `json:"IL,omitempty"` // Identification Level
+	IF []string          `json:"IF,omitempty"` // Identification Flags
+	IT string            `json:"IT,omitempty"` // Identification Type
+	ID string            `json:"ID,omitempty"` // Identification Data
+	TT string            `json:"TT,omitempty"` // Tariff Text
+	LC *LossCompensation `json:"LC,omitempty"` // Loss Compensation
+	CT string            `json:"CT,omitempty"` // Charge Point Id Type
+	CI string            `json:"CI,omitempty"` // Charge Point Identification
+	RD []Reading         `json:"RD"`           // Readings
+}
+
+// LossCompensation describes the cable loss compensation applied by the meter.
+type LossCompensation struct {
+	LN string  `json:"LN,omitempty"` // Loss Compensation Naming
+	LI int     `json:"LI,omitempty"` // Loss Compensation Identification
+	LR float64 `json:"LR"`           // Loss Compensation Cable Resistance
+	LU string  `json:"LU"`           // Loss Compensation Unit
+}
+
+// Reading is a single signed meter reading of an OCMF payload.
+type Reading struct {
+	TM string  `json:"TM"`           // Time, e.g. "2018-07-24T13:22:04,000+0200 S"
+	TX string  `json:"TX,omitempty"` // Transaction (B, C, X, E, L, R, A, P, S, T)
+	RV float64 `json:"RV"`           // Reading Value
+	RI string  `json:"RI,omitempty"` // Reading Identification (OBIS code)
+	RU string  `json:"RU"`           // Reading Unit (e.g. "kWh", "Wh")
+	RT string  `json:"RT,omitempty"` // Reading Current Type ("AC" or "DC")
+	CL float64 `json:"CL,omitempty"` // Cumulated Loss
+	EF string  `json:"EF,omitempty"` // Error Flags
+	ST string  `json:"ST"`           // Status (e.g. "G" for good)
+}
+
+// Time parses the reading time and returns it in UTC together with the
+// synchronization flag (TimeSynchronized, TimeUnsynchronized, ...). The flag
+// is empty when the Charge Point omitted it.
+func (r Reading) Time() (time.Time, string, error) {
+	value, flag, _ := strings.Cut(r.TM, timeFlagSeparator)
+
+	parsed, err := time.Parse(timeLayout, value)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("TM: %w", types.ErrInvalidValue)
+	}
+
+	return parsed.UTC(), flag, nil
+}
+
+// validatePayload checks the fields the OCMF specification marks as
+// mandatory and returns all problems found.
+func validatePayload(payload Payload) error {
+	var errs []error
+
+	if payload.PG == "" {
+		errs = append(errs, fmt.Errorf("PG: %w", types.ErrEmptyValue))
+	}
+
+	if payload.MS == "" {
+		errs = append(errs, fmt.Errorf("MS: %w", types.ErrEmptyValue))
+	}
+
+	if len(payload.RD) == readingsLenZero {
+		errs = append(errs, fmt.Errorf("RD: %w", types.ErrEmptyValue))
+	}
+
+	for i, reading := range payload.RD {
+		err := validateReading(reading)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("RD[%d]: %w", i, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateReading checks the mandatory fields of a single reading.
+func validateReading(reading Reading) error {
+	var errs []error
+
+	if reading.TM == "" {
+		errs = append(errs, fmt.Errorf("TM: %w", types.ErrEmptyValue))
+	} else if _, _, err := reading.Time(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if reading.RU == "" {
+		errs = append(errs, fmt.Errorf("RU: %w", types.ErrEmptyValue))
+	}
+
+	if reading.ST == "" {
+		errs = append(errs, fmt.Errorf("ST: %w", types.ErrEmptyValue))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,159 @@
+package ocmf
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// Signature algorithms defined by OCMF that this package can verify.
+const (
+	// AlgorithmSecp256r1SHA256 is the OCMF default signature algorithm.
+	AlgorithmSecp256r1SHA256 = "ECDSA-secp256r1-SHA256"
+	// AlgorithmSecp384r1SHA256 signs a SHA-256 digest with a P-384 key.
+	AlgorithmSecp384r1SHA256 = "ECDSA-secp384r1-SHA256"
+	// AlgorithmSecp384r1SHA384 signs a SHA-384 digest with a P-384 key.
+	AlgorithmSecp384r1SHA384 = "ECDSA-secp384r1-SHA384"
+	// AlgorithmSecp521r1SHA512 signs a SHA-512 digest with a P-521 key.
+	AlgorithmSecp521r1SHA512 = "ECDSA-secp521r1-SHA512"
+)
+
+// Signature data encodings defined by OCMF.
+const (
+	// EncodingHex is the OCMF default signature data encoding.
+	EncodingHex = "hex"
+	// EncodingBase64 encodes the signature data with standard base64.
+	EncodingBase64 = "base64"
+)
+
+// MimeTypeDER is the OCMF default (and only supported) signature MIME type:
+// an ASN.1 DER encoded ECDSA signature.
+const MimeTypeDER = "application/x-der"
+
+var (
+	// ErrInvalidSignature is returned when the signature does not match the
+	// payload for the given public key.
+	ErrInvalidSignature = errors.New("ocmf: invalid signature")
+	// ErrUnsupportedAlgorithm is returned when the signature algorithm,
+	// encoding or MIME type cannot be verified, or the public key does not
+	// use the curve required by the algorithm.
+	ErrUnsupportedAlgorithm = errors.New("ocmf: unsupported algorithm")
+)
+
+// Signature is the decoded signature section of an OCMF document.
+type Signature struct {
+	SA string `json:"SA,omitempty"` // Signature Algorithm
+	SE string `json:"SE,omitempty"` // Signature Encoding
+	SM string `json:"SM,omitempty"` // Signature MIME Type
+	SD string `json:"SD"`           // Signature Data
+}
+
+// algorithm describes how to verify one OCMF signature algorithm.
+type algorithm struct {
+	curve   elliptic.Curve
+	newHash func() hash.Hash
+}
+
+// algorithms maps the supported OCMF algorithm names to their parameters.
+var algorithms = map[string]algorithm{
+	AlgorithmSecp256r1SHA256: {curve: elliptic.P256(), newHash: sha256.New},
+	AlgorithmSecp384r1SHA256: {curve: elliptic.P384(), newHash: sha256.New},
+	AlgorithmSecp384r1SHA384: {curve: elliptic.P384(), newHash: sha512.New384},
+	AlgorithmSecp521r1SHA512: {curve: elliptic.P521(), newHash: sha512.New},
+}
+
+// Algorithm returns the signature algorithm, applying the OCMF default when
+// the Charge Point omitted SA.
+func (s Signature) Algorithm() string {
+	if s.SA == "" {
+		return AlgorithmSecp256r1SHA256
+	}
+
+	return s.SA
+}
+
+// Bytes decodes the signature data according to SE and SM.
+func (s Signature) Bytes() ([]byte, error) {
+	if s.SM != "" && s.SM != MimeTypeDER {
+		return nil, fmt.Errorf("SM %q: %w", s.SM, ErrUnsupportedAlgorithm)
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+
+	switch s.SE {
+	case "", EncodingHex:
+		data, err = hex.DecodeString(s.SD)
+	case EncodingBase64:
+		data, err = base64.StdEncoding.DecodeString(s.SD)
+	default:
+		return nil, fmt.Errorf("SE %q: %w", s.SE, ErrUnsupportedAlgorithm)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("SD: %w", types.ErrInvalidValue)
+	}
+
+	return data, nil
+}
+
+// verify checks the signature over the raw payload section bytes.
+func (s Signature) verify(publicKey *ecdsa.PublicKey, payload []byte) error {
+	name := s.Algorithm()
+
+	algo, ok := algorithms[name]
+	if !ok {
+		return fmt.Errorf("SA %q: %w", name, ErrUnsupportedAlgorithm)
+	}
+
+	if publicKey == nil || publicKey.Curve != algo.curve {
+		return fmt.Errorf("public key curve for %q: %w", name, ErrUnsupportedAlgorithm)
+	}
+
+	signature, err := s.Bytes()
+	if err != nil {
+		return err
+	}
+
+	digest := algo.newHash()
+	_, _ = digest.Write(payload)
+
+	if !ecdsa.VerifyASN1(publicKey, digest.Sum(nil), signature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// ParsePublicKey parses a hex encoded DER SubjectPublicKeyInfo, the format
+// in which Eichrecht meters publish their public keys (typically starting
+// with "3059301306072A8648CE3D0201").
+func ParsePublicKey(encoded string) (*ecdsa.PublicKey, error) {
+	der, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("public key: %w", types.ErrInvalidValue)
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("public key: %w", types.ErrInvalidValue)
+	}
+
+	publicKey, ok := parsed.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key: %w", ErrUnsupportedAlgorithm)
+	}
+
+	return publicKey, nil
+}
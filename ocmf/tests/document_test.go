@@ -0,0 +1,167 @@
+package ocmf_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/ocmf"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	readingsCount   = 2
+	firstReading    = 0
+	expectedReading = 2935.6
+)
+
+func TestParse_Valid(t *testing.T) {
+	t.Parallel()
+
+	value := signedOCMF(t, newKey(t), testPayload)
+
+	doc, err := ocmf.Parse(value)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if doc.Payload.MS != "BQ27400330016" {
+		t.Errorf(types.ErrorMismatch, "BQ27400330016", doc.Payload.MS)
+	}
+
+	if len(doc.Readings()) != readingsCount {
+		t.Fatalf(types.ErrorMismatch, readingsCount, len(doc.Readings()))
+	}
+
+	if doc.Readings()[firstReading].RV != expectedReading {
+		t.Errorf(
+			types.ErrorMismatch,
+			expectedReading,
+			doc.Readings()[firstReading].RV,
+		)
+	}
+
+	if doc.String() != value {
+		t.Errorf(types.ErrorMismatch, value, doc.String())
+	}
+}
+
+func TestParse_MissingHeader(t *testing.T) {
+	t.Parallel()
+
+	_, err := ocmf.Parse(strings.TrimPrefix(
+		signedOCMF(t, newKey(t), testPayload),
+		"OCMF|",
+	))
+	if !errors.Is(err, types.ErrInvalidValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrInvalidValue)
+	}
+}
+
+func TestParse_MissingSignature(t *testing.T) {
+	t.Parallel()
+
+	_, err := ocmf.Parse("OCMF|" + testPayload)
+	if !errors.Is(err, types.ErrEmptyValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrEmptyValue)
+	}
+}
+
+func TestParse_EmptySignatureData(t *testing.T) {
+	t.Parallel()
+
+	_, err := ocmf.Parse("OCMF|" + testPayload + `|{"SA":"ECDSA-secp256r1-SHA256"}`)
+	if !errors.Is(err, types.ErrEmptyValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrEmptyValue)
+	}
+
+	if !strings.Contains(err.Error(), "SD") {
+		t.Errorf(types.ErrorWantContains, err, "SD")
+	}
+}
+
+func TestParse_PayloadNotJSON(t *testing.T) {
+	t.Parallel()
+
+	_, err := ocmf.Parse(`OCMF|not-json|{"SD":"00"}`)
+	if !errors.Is(err, types.ErrInvalidValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrInvalidValue)
+	}
+}
+
+func TestParse_MissingMandatoryFields(t *testing.T) {
+	t.Parallel()
+
+	_, err := ocmf.Parse(`OCMF|{"FV":"1.0","RD":[{"RV":1}]}|{"SD":"00"}`)
+	if !errors.Is(err, types.ErrEmptyValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrEmptyValue)
+	}
+
+	for _, field := range []string{"PG", "MS", "RD[0]: TM", "RU", "ST"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf(types.ErrorWantContains, err, field)
+		}
+	}
+}
+
+func TestParse_EmptyReadings(t *testing.T) {
+	t.Parallel()
+
+	_, err := ocmf.Parse(`OCMF|{"PG":"T1","MS":"1","RD":[]}|{"SD":"00"}`)
+	if !errors.Is(err, types.ErrEmptyValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrEmptyValue)
+	}
+}
+
+func TestParse_InvalidReadingTime(t *testing.T) {
+	t.Parallel()
+
+	_, err := ocmf.Parse(
+		`OCMF|{"PG":"T1","MS":"1","RD":[` +
+			`{"TM":"2018-07-24T13:22:04Z","RV":1,"RU":"kWh","ST":"G"}]}` +
+			`|{"SD":"00"}`,
+	)
+	if !errors.Is(err, types.ErrInvalidValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrInvalidValue)
+	}
+}
+
+func TestReading_Time(t *testing.T) {
+	t.Parallel()
+
+	doc, err := ocmf.Parse(signedOCMF(t, newKey(t), testPayload))
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	parsed, flag, err := doc.Readings()[firstReading].Time()
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	expected := time.Date(2018, time.July, 24, 11, 22, 4, 0, time.UTC)
+	if !parsed.Equal(expected) || parsed.Location() != time.UTC {
+		t.Errorf(types.ErrorMismatch, expected, parsed)
+	}
+
+	if flag != ocmf.TimeSynchronized {
+		t.Errorf(types.ErrorMismatch, ocmf.TimeSynchronized, flag)
+	}
+}
+
+func TestDocument_ReadingsIsCopy(t *testing.T) {
+	t.Parallel()
+
+	doc, err := ocmf.Parse(signedOCMF(t, newKey(t), testPayload))
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	readings := doc.Readings()
+	readings[firstReading].RV = 0
+
+	if doc.Readings()[firstReading].RV != expectedReading {
+		t.Error("Readings() exposed the internal slice")
+	}
+}
@@ -0,0 +1,55 @@
+package ocmf_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+const (
+	testPayload = `{"FV":"1.0","GI":"ABL SBC-301","GS":"808829900001",` +
+		`"PG":"T1","MV":"Phoenix Contact","MM":"EEM-350-D-MCB",` +
+		`"MS":"BQ27400330016","IS":true,"IL":"VERIFIED","IT":"ISO14443",` +
+		`"ID":"1F2D3A4F5506C7","RD":[` +
+		`{"TM":"2018-07-24T13:22:04,000+0200 S","TX":"B","RV":2935.6,` +
+		`"RI":"1-b:1.8.0","RU":"kWh","RT":"AC","EF":"","ST":"G"},` +
+		`{"TM":"2018-07-24T14:02:10,000+0200 S","TX":"E","RV":2947.8,` +
+		`"RI":"1-b:1.8.0","RU":"kWh","RT":"AC","EF":"","ST":"G"}]}`
+)
+
+// newKey returns a freshly generated P-256 key pair for signing test payloads.
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey: %v", err)
+	}
+
+	return key
+}
+
+// sign returns the hex encoded DER ECDSA signature of the SHA-256 digest of
+// payload.
+func sign(t *testing.T, key *ecdsa.PrivateKey, payload string) string {
+	t.Helper()
+
+	digest := sha256.Sum256([]byte(payload))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("ecdsa.SignASN1: %v", err)
+	}
+
+	return hex.EncodeToString(signature)
+}
+
+// signedOCMF returns a complete OCMF string for payload signed by key.
+func signedOCMF(t *testing.T, key *ecdsa.PrivateKey, payload string) string {
+	t.Helper()
+
+	return "OCMF|" + payload + `|{"SD":"` + sign(t, key, payload) + `"}`
+}
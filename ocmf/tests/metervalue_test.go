@@ -0,0 +1,122 @@
+package ocmf_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/metervalues"
+	"github.com/aasanchez/ocpp16messages/ocmf"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	formatRaw        = "Raw"
+	formatSignedData = "SignedData"
+	timestamp        = "2018-07-24T12:02:10Z"
+	signedCount      = 1
+)
+
+// sampledValue returns a SampledValueInput with the given value and format.
+func sampledValue(value string, format *string) types.SampledValueInput {
+	return types.SampledValueInput{
+		Value:     value,
+		Context:   nil,
+		Format:    format,
+		Measurand: nil,
+		Phase:     nil,
+		Location:  nil,
+		Unit:      nil,
+	}
+}
+
+func TestFromSampledValue_RawFormat(t *testing.T) {
+	t.Parallel()
+
+	raw := formatRaw
+
+	value, err := types.NewSampledValue(sampledValue("2935.6", &raw))
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	_, err = ocmf.FromSampledValue(value)
+	if !errors.Is(err, ocmf.ErrNotSignedData) {
+		t.Errorf(types.ErrorWrapping, err, ocmf.ErrNotSignedData)
+	}
+}
+
+func TestFromMeterValues_MeterValuesReq(t *testing.T) {
+	t.Parallel()
+
+	key := newKey(t)
+	signed := formatSignedData
+
+	req, err := metervalues.Req(metervalues.ReqInput{
+		ConnectorId:   1,
+		TransactionId: nil,
+		MeterValue: []types.MeterValueInput{
+			{
+				Timestamp: timestamp,
+				SampledValue: []types.SampledValueInput{
+					sampledValue("2947.8", nil),
+					sampledValue(signedOCMF(t, key, testPayload), &signed),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	docs, err := ocmf.FromMeterValues(req.MeterValue)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if len(docs) != signedCount {
+		t.Fatalf(types.ErrorMismatch, signedCount, len(docs))
+	}
+
+	if docs[0].SampledValueIndex != 1 {
+		t.Errorf(types.ErrorMismatch, 1, docs[0].SampledValueIndex)
+	}
+
+	if docs[0].Timestamp.String() != timestamp {
+		t.Errorf(types.ErrorMismatch, timestamp, docs[0].Timestamp.String())
+	}
+
+	err = docs[0].Document.Verify(&key.PublicKey)
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+}
+
+func TestFromMeterValues_ReportsPosition(t *testing.T) {
+	t.Parallel()
+
+	signed := formatSignedData
+
+	meterValue, err := types.NewMeterValue(types.MeterValueInput{
+		Timestamp: timestamp,
+		SampledValue: []types.SampledValueInput{
+			sampledValue("not-ocmf", &signed),
+		},
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	docs, err := ocmf.FromMeterValues([]types.MeterValue{meterValue})
+	if docs != nil {
+		t.Errorf(types.ErrorMismatch, nil, docs)
+	}
+
+	if !errors.Is(err, types.ErrInvalidValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrInvalidValue)
+	}
+
+	if !strings.Contains(err.Error(), "meterValue[0].sampledValue[0]") {
+		t.Errorf(types.ErrorWantContains, err, "meterValue[0].sampledValue[0]")
+	}
+}
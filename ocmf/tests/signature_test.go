@@ -0,0 +1,202 @@
+package ocmf_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/ocmf"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+func TestVerify_Valid(t *testing.T) {
+	t.Parallel()
+
+	key := newKey(t)
+
+	doc, err := ocmf.Parse(signedOCMF(t, key, testPayload))
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	err = doc.Verify(&key.PublicKey)
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+}
+
+func TestVerify_TamperedPayload(t *testing.T) {
+	t.Parallel()
+
+	key := newKey(t)
+	value := signedOCMF(t, key, testPayload)
+	tampered := strings.Replace(value, `"RV":2947.8`, `"RV":2940.8`, 1)
+
+	doc, err := ocmf.Parse(tampered)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	err = doc.Verify(&key.PublicKey)
+	if !errors.Is(err, ocmf.ErrInvalidSignature) {
+		t.Errorf(types.ErrorWrapping, err, ocmf.ErrInvalidSignature)
+	}
+}
+
+func TestVerify_WrongKey(t *testing.T) {
+	t.Parallel()
+
+	doc, err := ocmf.Parse(signedOCMF(t, newKey(t), testPayload))
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	err = doc.Verify(&newKey(t).PublicKey)
+	if !errors.Is(err, ocmf.ErrInvalidSignature) {
+		t.Errorf(types.ErrorWrapping, err, ocmf.ErrInvalidSignature)
+	}
+}
+
+func TestVerify_NilKey(t *testing.T) {
+	t.Parallel()
+
+	doc, err := ocmf.Parse(signedOCMF(t, newKey(t), testPayload))
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	err = doc.Verify(nil)
+	if !errors.Is(err, ocmf.ErrUnsupportedAlgorithm) {
+		t.Errorf(types.ErrorWrapping, err, ocmf.ErrUnsupportedAlgorithm)
+	}
+}
+
+func TestVerify_Secp384r1Base64(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey: %v", err)
+	}
+
+	digest := sha256.Sum256([]byte(testPayload))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("ecdsa.SignASN1: %v", err)
+	}
+
+	value := "OCMF|" + testPayload + `|{"SA":"ECDSA-secp384r1-SHA256",` +
+		`"SE":"base64","SM":"application/x-der","SD":"` +
+		base64.StdEncoding.EncodeToString(signature) + `"}`
+
+	_, err = ocmf.ParseAndVerify(value, &key.PublicKey)
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+}
+
+func TestVerify_CurveMismatch(t *testing.T) {
+	t.Parallel()
+
+	key := newKey(t)
+	value := "OCMF|" + testPayload + `|{"SA":"ECDSA-secp384r1-SHA256","SD":"` +
+		sign(t, key, testPayload) + `"}`
+
+	_, err := ocmf.ParseAndVerify(value, &key.PublicKey)
+	if !errors.Is(err, ocmf.ErrUnsupportedAlgorithm) {
+		t.Errorf(types.ErrorWrapping, err, ocmf.ErrUnsupportedAlgorithm)
+	}
+}
+
+func TestVerify_UnsupportedAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	key := newKey(t)
+	value := "OCMF|" + testPayload +
+		`|{"SA":"ECDSA-brainpool256r1-SHA256","SD":"` +
+		sign(t, key, testPayload) + `"}`
+
+	_, err := ocmf.ParseAndVerify(value, &key.PublicKey)
+	if !errors.Is(err, ocmf.ErrUnsupportedAlgorithm) {
+		t.Errorf(types.ErrorWrapping, err, ocmf.ErrUnsupportedAlgorithm)
+	}
+}
+
+func TestVerify_UnsupportedEncoding(t *testing.T) {
+	t.Parallel()
+
+	key := newKey(t)
+	value := "OCMF|" + testPayload + `|{"SE":"base32","SD":"` +
+		sign(t, key, testPayload) + `"}`
+
+	_, err := ocmf.ParseAndVerify(value, &key.PublicKey)
+	if !errors.Is(err, ocmf.ErrUnsupportedAlgorithm) {
+		t.Errorf(types.ErrorWrapping, err, ocmf.ErrUnsupportedAlgorithm)
+	}
+}
+
+func TestVerify_MalformedSignatureData(t *testing.T) {
+	t.Parallel()
+
+	key := newKey(t)
+
+	_, err := ocmf.ParseAndVerify(
+		"OCMF|"+testPayload+`|{"SD":"zz"}`,
+		&key.PublicKey,
+	)
+	if !errors.Is(err, types.ErrInvalidValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrInvalidValue)
+	}
+}
+
+func TestParsePublicKey_Valid(t *testing.T) {
+	t.Parallel()
+
+	key := newKey(t)
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("x509.MarshalPKIXPublicKey: %v", err)
+	}
+
+	publicKey, err := ocmf.ParsePublicKey(strings.ToUpper(hex.EncodeToString(der)))
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	_, err = ocmf.ParseAndVerify(signedOCMF(t, key, testPayload), publicKey)
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+}
+
+func TestParsePublicKey_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := ocmf.ParsePublicKey("3059")
+	if !errors.Is(err, types.ErrInvalidValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrInvalidValue)
+	}
+}
+
+func TestSignature_DefaultAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	signature := ocmf.Signature{SA: "", SE: "", SM: "", SD: "00"}
+
+	if signature.Algorithm() != ocmf.AlgorithmSecp256r1SHA256 {
+		t.Errorf(
+			types.ErrorMismatch,
+			ocmf.AlgorithmSecp256r1SHA256,
+			signature.Algorithm(),
+		)
+	}
+}
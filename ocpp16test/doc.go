@@ -0,0 +1,53 @@
+// Package ocpp16test provides an in-process fake Central System for
+// integration tests of OCPP 1.6 JSON Charge Points.
+//
+// A Harness listens on a local port, accepts Charge Point connections over
+// OCPP-J, records every decoded message and answers each CALL with a
+// scripted or default confirmation. It is closed automatically when the test
+// ends.
+//
+// # Usage
+//
+//	func TestFirmwareCharges(t *testing.T) {
+//		harness := ocpp16test.New(t)
+//
+//		harness.OnAuthorize(func(req authorize.ReqMessage) authorize.ConfMessage {
+//			conf, _ := authorize.Conf(authorize.ConfInput{Status: "Accepted"})
+//
+//			return conf
+//		})
+//
+//		startFirmware(t, harness.URL()) // connects as "CP001"
+//
+//		harness.ExpectStatusNotification(1, "Charging", 5*time.Second)
+//	}
+//
+// # Responses
+//
+// Without a hook every Charge Point initiated action is answered with a
+// valid default: BootNotification is Accepted with a 300 second interval,
+// Authorize and StartTransaction accept every idTag and StartTransaction
+// hands out transaction ids counting up from 1. The On... methods replace
+// the answer of one action; RespondError answers an action with a
+// CALLERROR instead.
+//
+// # Recording and Expectations
+//
+// Messages returns every CALL exchanged so far, in both directions, with the
+// decoded request and confirmation. Expect waits until a recorded message
+// matches and fails the test otherwise; ExpectRequest is its typed variant
+// and ExpectStatusNotification covers the most common case. Messages
+// recorded before the call to Expect count, so expectations cannot miss a
+// message that arrived early; use Clear to forget them.
+//
+// Call sends a CALL to a connected Charge Point, e.g. a
+// RemoteStartTransaction, and decodes the validated confirmation.
+//
+// # File Servers
+//
+// NewHTTPFileServer and NewFTPFileServer start a local FileServer for the
+// firmware a Charge Point downloads after an UpdateFirmware.req and the
+// diagnostics it uploads after a GetDiagnostics.req. Put adds a file to
+// download, Upload returns an uploaded one and FailNext makes transfers fail
+// to exercise retries.
+package ocpp16test
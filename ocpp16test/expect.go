@@ -0,0 +1,158 @@
+package ocpp16test
+
+import (
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+)
+
+// Expect waits until a recorded CALL of action satisfies match and returns
+// it. match may be nil to accept any CALL of action and must not call
+// methods of the Harness. The test fails when no such message is recorded
+// within the given duration.
+func (h *Harness) Expect(
+	action string,
+	within time.Duration,
+	match func(Message) bool,
+) Message {
+	h.tb.Helper()
+
+	var found Message
+
+	ok := h.wait(within, func() bool {
+		for _, message := range h.messages {
+			if message.Action == action && (match == nil || match(message)) {
+				found = message
+
+				return true
+			}
+		}
+
+		return false
+	})
+	if !ok {
+		h.tb.Fatalf("ocpp16test: no matching %s within %s", action, within)
+	}
+
+	return found
+}
+
+// ExpectRequest waits until a recorded request of type T, e.g.
+// bootnotification.ReqMessage, satisfies match and returns it. match may be
+// nil to accept any request of type T. The test fails when no such request
+// is recorded within the given duration.
+func ExpectRequest[T any](
+	harness *Harness,
+	within time.Duration,
+	match func(T) bool,
+) T {
+	harness.tb.Helper()
+
+	var found T
+
+	ok := harness.wait(within, func() bool {
+		for _, message := range harness.messages {
+			req, isT := message.Request.(T)
+			if isT && (match == nil || match(req)) {
+				found = req
+
+				return true
+			}
+		}
+
+		return false
+	})
+	if !ok {
+		harness.tb.Fatalf("ocpp16test: no matching %T within %s", found, within)
+	}
+
+	return found
+}
+
+// ExpectStatusNotification waits until a Charge Point reports status on
+// connectorId, e.g. "Charging" on connector 1, and returns the request.
+func (h *Harness) ExpectStatusNotification(
+	connectorId int,
+	status string,
+	within time.Duration,
+) statusnotification.ReqMessage {
+	h.tb.Helper()
+
+	message := h.Expect(
+		ocppj.ActionStatusNotification,
+		within,
+		func(message Message) bool {
+			req, ok := message.Request.(statusnotification.ReqMessage)
+
+			return ok &&
+				int(req.ConnectorId.Value()) == connectorId &&
+				req.Status.String() == status
+		},
+	)
+
+	found, _ := message.Request.(statusnotification.ReqMessage)
+
+	return found
+}
+
+// ExpectConnected waits until the Charge Point connects.
+func (h *Harness) ExpectConnected(chargePointId string, within time.Duration) {
+	h.tb.Helper()
+
+	ok := h.wait(within, func() bool {
+		_, connected := h.conns[chargePointId]
+
+		return connected
+	})
+	if !ok {
+		h.tb.Fatalf(
+			"ocpp16test: %s did not connect within %s",
+			chargePointId,
+			within,
+		)
+	}
+}
+
+// ExpectNoMessage fails the test when a CALL of action is recorded within
+// the given duration.
+func (h *Harness) ExpectNoMessage(action string, within time.Duration) {
+	h.tb.Helper()
+
+	seen := h.wait(within, func() bool {
+		for _, message := range h.messages {
+			if message.Action == action {
+				return true
+			}
+		}
+
+		return false
+	})
+	if seen {
+		h.tb.Fatalf("ocpp16test: unexpected %s", action)
+	}
+}
+
+// wait evaluates condition, holding h.mu, every time the recorded state
+// changes until it holds or within elapses.
+func (h *Harness) wait(within time.Duration, condition func() bool) bool {
+	timer := time.NewTimer(within)
+	defer timer.Stop()
+
+	for {
+		h.mu.Lock()
+		ok := condition()
+		changed := h.changed
+		h.mu.Unlock()
+
+		if ok {
+			return true
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return false
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package ocpp16test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// File server credentials embedded in the URL of an FTP FileServer.
+const (
+	FTPUser     = "ocpp"
+	FTPPassword = "ocpp"
+)
+
+// maxUploadMemory is the part of a multipart upload kept in memory.
+const maxUploadMemory = 32 << 20
+
+// FileServer is a local HTTP or FTP server for the firmware downloads and
+// diagnostics uploads of a Charge Point under test. Files added with Put
+// are served at URL() + name; uploaded files are kept by name and returned
+// by Upload. Create it with NewHTTPFileServer or NewFTPFileServer.
+type FileServer struct {
+	tb    testing.TB
+	url   string
+	close func()
+
+	mu       sync.Mutex
+	files    map[string][]byte
+	uploads  map[string][]byte
+	failures int
+}
+
+// NewHTTPFileServer starts an HTTP FileServer on a local port. A GET
+// downloads a file; a multipart/form-data POST to any path uploads the
+// files of the form under their file names, and a PUT uploads the body
+// under the last element of the path. It is closed when the test ends.
+func NewHTTPFileServer(tb testing.TB) *FileServer {
+	tb.Helper()
+
+	server := newFileServer(tb)
+	httpServer := httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	server.url = httpServer.URL + "/"
+	server.close = httpServer.Close
+
+	tb.Cleanup(server.Close)
+
+	return server
+}
+
+// NewFTPFileServer starts an FTP FileServer on a local port, accepting
+// FTPUser and FTPPassword, which URL embeds. It supports the passive mode
+// commands a Charge Point needs to RETR firmware and STOR diagnostics. It
+// is closed when the test ends.
+func NewFTPFileServer(tb testing.TB) *FileServer {
+	tb.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatalf("ocpp16test: %v", err)
+	}
+
+	server := newFileServer(tb)
+	ftp := &ftpServer{
+		files:    server,
+		listener: listener,
+		mu:       sync.Mutex{},
+		conns:    map[net.Conn]struct{}{},
+		wg:       sync.WaitGroup{},
+	}
+
+	server.url = "ftp://" + FTPUser + ":" + FTPPassword + "@" +
+		listener.Addr().String() + "/"
+	server.close = ftp.close
+
+	ftp.wg.Add(1)
+
+	go ftp.serve()
+
+	tb.Cleanup(server.Close)
+
+	return server
+}
+
+// newFileServer returns a FileServer without a listener.
+func newFileServer(tb testing.TB) *FileServer {
+	return &FileServer{
+		tb:       tb,
+		url:      "",
+		close:    nil,
+		mu:       sync.Mutex{},
+		files:    map[string][]byte{},
+		uploads:  map[string][]byte{},
+		failures: 0,
+	}
+}
+
+// URL returns the base URL of the server, ending with a slash, e.g.
+// "http://127.0.0.1:41234/". Use it as the location of a GetDiagnostics.req,
+// or append the name of a file added with Put for an UpdateFirmware.req.
+func (s *FileServer) URL() string {
+	return s.url
+}
+
+// Put makes a file available for download under name.
+func (s *FileServer) Put(name string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files[strings.TrimPrefix(name, "/")] = data
+}
+
+// Upload returns the content of the file uploaded under name, and whether
+// there is one.
+func (s *FileServer) Upload(name string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.uploads[strings.TrimPrefix(name, "/")]
+
+	return data, ok
+}
+
+// FailNext makes the next n downloads or uploads fail, to exercise the
+// retries of a Charge Point.
+func (s *FileServer) FailNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = n
+}
+
+// Close stops the server.
+func (s *FileServer) Close() {
+	s.close()
+}
+
+// fail reports whether the current transfer must fail.
+func (s *FileServer) fail() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures == 0 {
+		return false
+	}
+
+	s.failures--
+
+	return true
+}
+
+// file returns a file added with Put.
+func (s *FileServer) file(name string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.files[strings.TrimPrefix(name, "/")]
+
+	return data, ok
+}
+
+// store keeps an uploaded file.
+func (s *FileServer) store(name string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.uploads[strings.TrimPrefix(name, "/")] = data
+}
+
+// serveHTTP answers the requests of an HTTP FileServer.
+func (s *FileServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.fail() {
+		http.Error(w, "failing on purpose", http.StatusServiceUnavailable)
+
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		data, ok := s.file(r.URL.Path)
+		if !ok {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		_, _ = w.Write(data)
+	case http.MethodPost:
+		s.receiveForm(w, r)
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		s.store(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], data)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.Header().Set("Allow", "GET, POST, PUT")
+		http.Error(w, "", http.StatusMethodNotAllowed)
+	}
+}
+
+// receiveForm stores the files of a multipart/form-data POST.
+func (s *FileServer) receiveForm(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseMultipartForm(maxUploadMemory)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	for _, headers := range r.MultipartForm.File {
+		for _, header := range headers {
+			file, openErr := header.Open()
+			if openErr != nil {
+				http.Error(w, openErr.Error(), http.StatusBadRequest)
+
+				return
+			}
+
+			data, readErr := io.ReadAll(file)
+			_ = file.Close()
+
+			if readErr != nil {
+				http.Error(w, readErr.Error(), http.StatusBadRequest)
+
+				return
+			}
+
+			s.store(header.Filename, data)
+		}
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
@@ -0,0 +1,264 @@
+package ocpp16test
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ftpDataTimeout bounds the wait for the data connection of a transfer.
+const ftpDataTimeout = 10 * time.Second
+
+// ftpServer is the FTP side of a FileServer.
+type ftpServer struct {
+	files    *FileServer
+	listener net.Listener
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+// ftpSession is the state of one FTP control connection.
+type ftpSession struct {
+	text     *textproto.Conn
+	user     string
+	loggedIn bool
+	passive  net.Listener
+}
+
+// serve accepts control connections until the listener closes.
+func (f *ftpServer) serve() {
+	defer f.wg.Done()
+
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		f.mu.Lock()
+		f.conns[conn] = struct{}{}
+		f.mu.Unlock()
+
+		f.wg.Add(1)
+
+		go f.handle(conn)
+	}
+}
+
+// close stops listening and ends every session.
+func (f *ftpServer) close() {
+	_ = f.listener.Close()
+
+	f.mu.Lock()
+	for conn := range f.conns {
+		_ = conn.Close()
+	}
+	f.mu.Unlock()
+
+	f.wg.Wait()
+}
+
+// handle runs one control connection.
+func (f *ftpServer) handle(conn net.Conn) {
+	session := &ftpSession{
+		text:     textproto.NewConn(conn),
+		user:     "",
+		loggedIn: false,
+		passive:  nil,
+	}
+
+	defer func() {
+		if session.passive != nil {
+			_ = session.passive.Close()
+		}
+
+		_ = session.text.Close()
+
+		f.mu.Lock()
+		delete(f.conns, conn)
+		f.mu.Unlock()
+
+		f.wg.Done()
+	}()
+
+	_ = session.text.PrintfLine("220 ocpp16test FTP ready")
+
+	for {
+		line, err := session.text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command, argument, _ := strings.Cut(line, " ")
+		if !f.command(session, strings.ToUpper(command), argument) {
+			return
+		}
+	}
+}
+
+// command answers one command and reports whether the session goes on.
+func (f *ftpServer) command(
+	session *ftpSession,
+	command string,
+	argument string,
+) bool {
+	reply := session.text.PrintfLine
+
+	switch {
+	case command == "QUIT":
+		_ = reply("221 Bye")
+
+		return false
+	case command == "USER":
+		session.user = argument
+		_ = reply("331 Password required")
+	case command == "PASS":
+		session.loggedIn = session.user == FTPUser && argument == FTPPassword
+		if !session.loggedIn {
+			_ = reply("530 Login incorrect")
+
+			return true
+		}
+
+		_ = reply("230 Logged in")
+	case !session.loggedIn:
+		_ = reply("530 Not logged in")
+	case command == "SYST":
+		_ = reply("215 UNIX Type: L8")
+	case command == "TYPE":
+		_ = reply("200 Type set")
+	case command == "PWD":
+		_ = reply(`257 "/"`)
+	case command == "CWD":
+		_ = reply("250 Directory changed")
+	case command == "EPSV", command == "PASV":
+		f.openPassive(session, command)
+	case command == "RETR":
+		f.retrieve(session, argument)
+	case command == "STOR":
+		f.store(session, argument)
+	default:
+		_ = reply("502 Command not implemented")
+	}
+
+	return true
+}
+
+// openPassive opens the listener of the next data connection.
+func (f *ftpServer) openPassive(session *ftpSession, command string) {
+	if session.passive != nil {
+		_ = session.passive.Close()
+	}
+
+	listener, err := net.ListenTCP(
+		"tcp",
+		&net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 0, Zone: ""},
+	)
+	if err != nil {
+		_ = session.text.PrintfLine("425 %v", err)
+
+		return
+	}
+
+	_ = listener.SetDeadline(time.Now().Add(ftpDataTimeout))
+	session.passive = listener
+
+	//nolint:forcetypeassert // A TCP listener has a TCP address.
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	if command == "EPSV" {
+		_ = session.text.PrintfLine(
+			"229 Entering Extended Passive Mode (|||%d|)",
+			port,
+		)
+
+		return
+	}
+
+	_ = session.text.PrintfLine(
+		"227 Entering Passive Mode (127,0,0,1,%d,%d)",
+		port>>8,
+		port&0xff,
+	)
+}
+
+// retrieve sends a file over the data connection.
+func (f *ftpServer) retrieve(session *ftpSession, name string) {
+	data, ok := f.files.file(name)
+
+	switch {
+	case f.files.fail():
+		_ = session.text.PrintfLine("451 Failing on purpose")
+	case !ok:
+		_ = session.text.PrintfLine("550 %s: not found", name)
+	default:
+		f.transfer(session, func(conn net.Conn) error {
+			_, err := conn.Write(data)
+
+			return err //nolint:wrapcheck // Reported on the control connection.
+		})
+	}
+}
+
+// store receives a file over the data connection.
+func (f *ftpServer) store(session *ftpSession, name string) {
+	if f.files.fail() {
+		_ = session.text.PrintfLine("451 Failing on purpose")
+
+		return
+	}
+
+	f.transfer(session, func(conn net.Conn) error {
+		data, err := io.ReadAll(conn)
+		if err != nil {
+			return err //nolint:wrapcheck // Reported on the control connection.
+		}
+
+		f.files.store(name, data)
+
+		return nil
+	})
+}
+
+// transfer runs a data connection on the passive listener.
+func (f *ftpServer) transfer(
+	session *ftpSession,
+	move func(conn net.Conn) error,
+) {
+	if session.passive == nil {
+		_ = session.text.PrintfLine("425 Use PASV or EPSV first")
+
+		return
+	}
+
+	listener := session.passive
+	session.passive = nil
+
+	defer listener.Close()
+
+	_ = session.text.PrintfLine("150 Opening data connection")
+
+	conn, err := listener.Accept()
+	if err == nil {
+		err = move(conn)
+
+		closeErr := conn.Close()
+		if err == nil && !errors.Is(closeErr, net.ErrClosed) {
+			err = closeErr
+		}
+	}
+
+	if err != nil {
+		_ = session.text.PrintfLine("426 %v", err)
+
+		return
+	}
+
+	_ = session.text.PrintfLine("226 Transfer complete")
+}
@@ -0,0 +1,280 @@
+package ocpp16test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+)
+
+// ErrNotConnected is returned by Call when the Charge Point has no open
+// connection.
+var ErrNotConnected = errors.New("ocpp16test: charge point not connected")
+
+// Direction tells which side sent the CALL of a recorded Message.
+type Direction int
+
+const (
+	// FromChargePoint marks a CALL sent by a Charge Point.
+	FromChargePoint Direction = iota + 1
+	// FromCentralSystem marks a CALL sent by the Harness.
+	FromCentralSystem
+)
+
+// Message is one recorded CALL and its answer.
+type Message struct {
+	// ChargePointId is the identity of the Charge Point connection.
+	ChargePointId string
+	// Direction tells which side sent the CALL.
+	Direction Direction
+	// Action is the OCPP action name, e.g. "StatusNotification".
+	Action string
+	// Request is the validated ReqMessage, e.g. statusnotification.ReqMessage.
+	Request any
+	// Confirmation is the ConfMessage answered, nil when Err is set.
+	Confirmation any
+	// Err is the CALLERROR answered, or the error of a failed Call.
+	Err error
+	// Time is when the answer was sent or received.
+	Time time.Time
+}
+
+// responder answers one CALL.
+type responder func(chargePointId string, request any) (any, error)
+
+// Harness is an in-process fake Central System. Create it with New.
+type Harness struct {
+	tb     testing.TB
+	server *httptest.Server
+
+	mu         sync.Mutex
+	conns      map[string]*ocppj.Conn
+	responders map[string]responder
+	messages   []Message
+	changed    chan struct{}
+	nextTxId   int
+}
+
+// New starts a Harness on a local port. It is closed when the test ends.
+func New(tb testing.TB) *Harness {
+	tb.Helper()
+
+	harness := &Harness{
+		tb:         tb,
+		server:     nil,
+		mu:         sync.Mutex{},
+		conns:      map[string]*ocppj.Conn{},
+		responders: map[string]responder{},
+		messages:   nil,
+		changed:    make(chan struct{}),
+		nextTxId:   firstTransactionId,
+	}
+
+	harness.server = httptest.NewServer(http.HandlerFunc(harness.serve))
+	tb.Cleanup(harness.Close)
+
+	return harness
+}
+
+// URL returns the Central System URL to configure on the Charge Point,
+// e.g. "ws://127.0.0.1:41234/ocpp". The Charge Point appends its identity.
+func (h *Harness) URL() string {
+	return "ws" + strings.TrimPrefix(h.server.URL, "http") + urlPath
+}
+
+// Close disconnects every Charge Point and stops listening.
+func (h *Harness) Close() {
+	h.mu.Lock()
+	conns := make([]*ocppj.Conn, 0, len(h.conns))
+
+	for _, conn := range h.conns {
+		conns = append(conns, conn)
+	}
+	h.mu.Unlock()
+
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+
+	h.server.Close()
+}
+
+// Disconnect closes the connection of a Charge Point, e.g. to test
+// reconnection.
+func (h *Harness) Disconnect(chargePointId string) {
+	h.mu.Lock()
+	conn, ok := h.conns[chargePointId]
+	h.mu.Unlock()
+
+	if ok {
+		_ = conn.Close()
+	}
+}
+
+// Connected reports whether a Charge Point is connected.
+func (h *Harness) Connected(chargePointId string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, ok := h.conns[chargePointId]
+
+	return ok
+}
+
+// Call sends a CALL to a connected Charge Point and waits for the answer.
+// When confirmation is a pointer to the matching ConfMessage the answer is
+// validated and decoded into it. A CALLERROR is returned as *ocppj.Error.
+// The exchange is recorded.
+func (h *Harness) Call(
+	ctx context.Context,
+	chargePointId string,
+	action string,
+	request any,
+	confirmation any,
+) error {
+	h.mu.Lock()
+	conn, ok := h.conns[chargePointId]
+	h.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%s: %w", chargePointId, ErrNotConnected)
+	}
+
+	err := conn.Call(ctx, action, request, confirmation)
+
+	h.record(Message{
+		ChargePointId: chargePointId,
+		Direction:     FromCentralSystem,
+		Action:        action,
+		Request:       request,
+		Confirmation:  dereference(confirmation, err),
+		Err:           err,
+		Time:          time.Now(),
+	})
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", chargePointId, err)
+	}
+
+	return nil
+}
+
+// Messages returns a copy of the recorded messages in order.
+func (h *Harness) Messages() []Message {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]Message(nil), h.messages...)
+}
+
+// Clear forgets the recorded messages.
+func (h *Harness) Clear() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.messages = nil
+}
+
+// serve accepts one Charge Point connection and serves it until it closes.
+func (h *Harness) serve(w http.ResponseWriter, r *http.Request) {
+	transport, err := ocppj.Accept(w, r)
+	if err != nil {
+		return
+	}
+
+	chargePointId := ocppj.ChargePointId(r)
+
+	conn := ocppj.NewConn(
+		transport,
+		ocppj.RoleCentralSystem,
+		func(_ context.Context, action string, request any) (any, error) {
+			return h.answer(chargePointId, action, request)
+		},
+	)
+
+	h.mu.Lock()
+	previous, replaced := h.conns[chargePointId]
+	h.conns[chargePointId] = conn
+	h.notify()
+	h.mu.Unlock()
+
+	if replaced {
+		_ = previous.Close()
+	}
+
+	_ = conn.Run(context.WithoutCancel(r.Context()))
+
+	h.mu.Lock()
+	if h.conns[chargePointId] == conn {
+		delete(h.conns, chargePointId)
+		h.notify()
+	}
+	h.mu.Unlock()
+}
+
+// answer runs the responder of an action and records the exchange.
+func (h *Harness) answer(
+	chargePointId string,
+	action string,
+	request any,
+) (any, error) {
+	h.mu.Lock()
+	respond, ok := h.responders[action]
+	h.mu.Unlock()
+
+	if !ok {
+		respond = h.defaultResponder(action)
+	}
+
+	confirmation, err := respond(chargePointId, request)
+
+	h.record(Message{
+		ChargePointId: chargePointId,
+		Direction:     FromChargePoint,
+		Action:        action,
+		Request:       request,
+		Confirmation:  confirmation,
+		Err:           err,
+		Time:          time.Now(),
+	})
+
+	return confirmation, err
+}
+
+// record appends a message and wakes up waiting expectations.
+func (h *Harness) record(message Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.messages = append(h.messages, message)
+	h.notify()
+}
+
+// notify wakes up waiting expectations. The caller holds h.mu.
+func (h *Harness) notify() {
+	close(h.changed)
+	h.changed = make(chan struct{})
+}
+
+// dereference returns the ConfMessage a Call decoded into, so recorded
+// messages hold values in both directions.
+func dereference(confirmation any, err error) any {
+	if err != nil || confirmation == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(confirmation)
+	if value.Kind() == reflect.Pointer && !value.IsNil() {
+		return value.Elem().Interface()
+	}
+
+	return confirmation
+}
@@ -0,0 +1,220 @@
+package ocpp16test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/datatransfer"
+	"github.com/aasanchez/ocpp16messages/diagnosticsstatusnotification"
+	"github.com/aasanchez/ocpp16messages/firmwarestatusnotification"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/metervalues"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/starttransaction"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	"github.com/aasanchez/ocpp16messages/stoptransaction"
+)
+
+const (
+	// DefaultHeartbeatInterval is the interval in seconds of the default
+	// BootNotification confirmation.
+	DefaultHeartbeatInterval = 300
+
+	urlPath            = "/ocpp"
+	firstTransactionId = 1
+	statusAccepted     = "Accepted"
+	statusUnknownVend  = "UnknownVendor"
+	timestampLayout    = "2006-01-02T15:04:05Z"
+)
+
+// OnBootNotification scripts the answer to BootNotification.
+func (h *Harness) OnBootNotification(
+	fn func(bootnotification.ReqMessage) bootnotification.ConfMessage,
+) {
+	h.on(ocppj.ActionBootNotification, typed(fn))
+}
+
+// OnHeartbeat scripts the answer to Heartbeat.
+func (h *Harness) OnHeartbeat(
+	fn func(heartbeat.ReqMessage) heartbeat.ConfMessage,
+) {
+	h.on(ocppj.ActionHeartbeat, typed(fn))
+}
+
+// OnAuthorize scripts the answer to Authorize.
+func (h *Harness) OnAuthorize(
+	fn func(authorize.ReqMessage) authorize.ConfMessage,
+) {
+	h.on(ocppj.ActionAuthorize, typed(fn))
+}
+
+// OnStartTransaction scripts the answer to StartTransaction.
+func (h *Harness) OnStartTransaction(
+	fn func(starttransaction.ReqMessage) starttransaction.ConfMessage,
+) {
+	h.on(ocppj.ActionStartTransaction, typed(fn))
+}
+
+// OnStopTransaction scripts the answer to StopTransaction.
+func (h *Harness) OnStopTransaction(
+	fn func(stoptransaction.ReqMessage) stoptransaction.ConfMessage,
+) {
+	h.on(ocppj.ActionStopTransaction, typed(fn))
+}
+
+// OnMeterValues scripts the answer to MeterValues.
+func (h *Harness) OnMeterValues(
+	fn func(metervalues.ReqMessage) metervalues.ConfMessage,
+) {
+	h.on(ocppj.ActionMeterValues, typed(fn))
+}
+
+// OnStatusNotification scripts the answer to StatusNotification.
+func (h *Harness) OnStatusNotification(
+	fn func(statusnotification.ReqMessage) statusnotification.ConfMessage,
+) {
+	h.on(ocppj.ActionStatusNotification, typed(fn))
+}
+
+// OnDataTransfer scripts the answer to DataTransfer.
+func (h *Harness) OnDataTransfer(
+	fn func(datatransfer.ReqMessage) datatransfer.ConfMessage,
+) {
+	h.on(ocppj.ActionDataTransfer, typed(fn))
+}
+
+// OnDiagnosticsStatusNotification scripts the answer to
+// DiagnosticsStatusNotification.
+func (h *Harness) OnDiagnosticsStatusNotification(
+	fn func(
+		diagnosticsstatusnotification.ReqMessage,
+	) diagnosticsstatusnotification.ConfMessage,
+) {
+	h.on(ocppj.ActionDiagnosticsStatusNotification, typed(fn))
+}
+
+// OnFirmwareStatusNotification scripts the answer to
+// FirmwareStatusNotification.
+func (h *Harness) OnFirmwareStatusNotification(
+	fn func(
+		firmwarestatusnotification.ReqMessage,
+	) firmwarestatusnotification.ConfMessage,
+) {
+	h.on(ocppj.ActionFirmwareStatusNotification, typed(fn))
+}
+
+// RespondError answers every CALL of action with a CALLERROR, e.g.
+// ocppj.NewError(ocppj.InternalError, "database down").
+func (h *Harness) RespondError(action string, callErr *ocppj.Error) {
+	h.on(action, func(string, any) (any, error) {
+		return nil, callErr
+	})
+}
+
+// ResetResponses restores the default answers of every action.
+func (h *Harness) ResetResponses() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.responders = map[string]responder{}
+}
+
+// on installs the responder of an action.
+func (h *Harness) on(action string, respond responder) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.responders[action] = respond
+}
+
+// typed adapts a hook taking and returning message types to a responder.
+func typed[Req, Conf any](fn func(Req) Conf) responder {
+	return func(_ string, request any) (any, error) {
+		req, ok := request.(Req)
+		if !ok {
+			return nil, ocppj.NewError(
+				ocppj.InternalError,
+				fmt.Sprintf("unexpected request type %T", request),
+			)
+		}
+
+		return fn(req), nil
+	}
+}
+
+// defaultResponder returns the answer used when no hook is installed.
+//
+//nolint:cyclop // One case per Charge Point initiated action.
+func (h *Harness) defaultResponder(action string) responder {
+	return func(string, any) (any, error) {
+		switch action {
+		case ocppj.ActionBootNotification:
+			return bootnotification.Conf(bootnotification.ConfInput{
+				Status:      statusAccepted,
+				CurrentTime: now(),
+				Interval:    DefaultHeartbeatInterval,
+			})
+		case ocppj.ActionHeartbeat:
+			return heartbeat.Conf(heartbeat.ConfInput{CurrentTime: now()})
+		case ocppj.ActionAuthorize:
+			return authorize.Conf(authorize.ConfInput{
+				Status:      statusAccepted,
+				ExpiryDate:  nil,
+				ParentIdTag: nil,
+			})
+		case ocppj.ActionStartTransaction:
+			return starttransaction.Conf(starttransaction.ConfInput{
+				TransactionId: h.transactionId(),
+				Status:        statusAccepted,
+				ExpiryDate:    nil,
+				ParentIdTag:   nil,
+			})
+		case ocppj.ActionStopTransaction:
+			return stoptransaction.Conf(stoptransaction.ConfInput{
+				Status:      nil,
+				ExpiryDate:  nil,
+				ParentIdTag: nil,
+			})
+		case ocppj.ActionMeterValues:
+			return metervalues.Conf(metervalues.ConfInput{})
+		case ocppj.ActionStatusNotification:
+			return statusnotification.Conf(statusnotification.ConfInput{})
+		case ocppj.ActionDataTransfer:
+			return datatransfer.Conf(
+				datatransfer.ConfInput{Status: statusUnknownVend, Data: nil},
+			)
+		case ocppj.ActionDiagnosticsStatusNotification:
+			return diagnosticsstatusnotification.Conf(
+				diagnosticsstatusnotification.ConfInput{},
+			)
+		case ocppj.ActionFirmwareStatusNotification:
+			return firmwarestatusnotification.Conf(
+				firmwarestatusnotification.ConfInput{},
+			)
+		default:
+			return nil, ocppj.NewError(
+				ocppj.NotSupported,
+				fmt.Sprintf("ocpp16test: no answer for %s", action),
+			)
+		}
+	}
+}
+
+// transactionId returns the next transaction id of the default
+// StartTransaction answer.
+func (h *Harness) transactionId() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := h.nextTxId
+	h.nextTxId++
+
+	return id
+}
+
+// now returns the current time as an OCPP DateTime string.
+func now() string {
+	return time.Now().UTC().Format(timestampLayout)
+}
@@ -0,0 +1,137 @@
+package ocpp16test_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/ocpp16test"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+func httpDo(
+	t *testing.T,
+	method string,
+	target string,
+	body []byte,
+) (int, []byte) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(
+		context.Background(),
+		method,
+		target,
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return resp.StatusCode, data
+}
+
+func TestFileServer_HTTP(t *testing.T) {
+	t.Parallel()
+
+	server := ocpp16test.NewHTTPFileServer(t)
+	server.Put("firmware.bin", []byte("firmware"))
+
+	status, data := httpDo(t, http.MethodGet, server.URL()+"firmware.bin", nil)
+	if status != http.StatusOK || string(data) != "firmware" {
+		t.Errorf(types.ErrorMismatch, "firmware", string(data))
+	}
+
+	status, _ = httpDo(t, http.MethodGet, server.URL()+"missing.bin", nil)
+	if status != http.StatusNotFound {
+		t.Errorf(types.ErrorMismatchValue, http.StatusNotFound, status)
+	}
+
+	server.FailNext(1)
+
+	status, _ = httpDo(t, http.MethodGet, server.URL()+"firmware.bin", nil)
+	if status != http.StatusServiceUnavailable {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			http.StatusServiceUnavailable,
+			status,
+		)
+	}
+
+	status, _ = httpDo(
+		t,
+		http.MethodPut,
+		server.URL()+"logs/diagnostics.log",
+		[]byte("log"),
+	)
+	if status != http.StatusCreated {
+		t.Errorf(types.ErrorMismatchValue, http.StatusCreated, status)
+	}
+
+	uploaded, ok := server.Upload("diagnostics.log")
+	if !ok || string(uploaded) != "log" {
+		t.Errorf(types.ErrorMismatch, "log", string(uploaded))
+	}
+}
+
+func TestFileServer_FTPLogin(t *testing.T) {
+	t.Parallel()
+
+	server := ocpp16test.NewFTPFileServer(t)
+
+	location, err := url.Parse(server.URL())
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	password, _ := location.User.Password()
+	if location.User.Username() != ocpp16test.FTPUser ||
+		password != ocpp16test.FTPPassword {
+		t.Errorf(types.ErrorMismatch, ocpp16test.FTPUser, location.User)
+	}
+
+	conn, err := net.Dial("tcp", location.Host)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	replies := []string{"220", "331", "530", "530"}
+
+	for i, command := range []string{"", "USER anonymous", "PASS x", "PWD"} {
+		if command != "" {
+			_, err = conn.Write([]byte(command + "\r\n"))
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+		}
+
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil {
+			t.Fatalf(types.ErrorUnexpectedError, readErr)
+		}
+
+		if !strings.HasPrefix(line, replies[i]) {
+			t.Errorf(types.ErrorMismatch, replies[i], line)
+		}
+	}
+}
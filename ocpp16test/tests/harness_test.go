@@ -0,0 +1,362 @@
+package ocpp16test_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/ocpp16test"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/starttransaction"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	within          = 5 * time.Second
+	shortly         = 50 * time.Millisecond
+	testChargePoint = "CP001"
+	testIdTag       = "RFID-TAG-12345"
+	testTimestamp   = "2025-01-02T15:00:00Z"
+)
+
+// chargePoint connects a bare OCPP-J Charge Point to the harness. handler
+// answers the CALLs of the harness and may be nil.
+func chargePoint(
+	t *testing.T,
+	harness *ocpp16test.Harness,
+	handler ocppj.Handler,
+) *ocppj.Conn {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), within)
+	t.Cleanup(cancel)
+
+	transport, err := ocppj.Dial(ctx, harness.URL(), testChargePoint, nil)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	conn := ocppj.NewConn(transport, ocppj.RoleChargePoint, handler)
+
+	go func() { _ = conn.Run(context.Background()) }()
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+// sendStatus sends a StatusNotification from the Charge Point.
+func sendStatus(
+	t *testing.T,
+	conn *ocppj.Conn,
+	connectorId int,
+	status string,
+) {
+	t.Helper()
+
+	timestamp := testTimestamp
+
+	req, err := statusnotification.Req(statusnotification.ReqInput{
+		ConnectorId:     connectorId,
+		ErrorCode:       "NoError",
+		Status:          status,
+		Info:            nil,
+		Timestamp:       &timestamp,
+		VendorId:        nil,
+		VendorErrorCode: nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	var conf statusnotification.ConfMessage
+
+	err = conn.Call(
+		context.Background(),
+		ocppj.ActionStatusNotification,
+		req,
+		&conf,
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+}
+
+func TestHarness_DefaultResponses(t *testing.T) {
+	t.Parallel()
+
+	harness := ocpp16test.New(t)
+	conn := chargePoint(t, harness, nil)
+
+	req, err := bootnotification.Req(bootnotification.ReqInput{
+		ChargePointVendor:       "Vendor",
+		ChargePointModel:        "Model",
+		ChargePointSerialNumber: nil,
+		ChargeBoxSerialNumber:   nil,
+		FirmwareVersion:         nil,
+		Iccid:                   nil,
+		Imsi:                    nil,
+		MeterType:               nil,
+		MeterSerialNumber:       nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	var conf bootnotification.ConfMessage
+
+	err = conn.Call(
+		context.Background(),
+		ocppj.ActionBootNotification,
+		req,
+		&conf,
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if conf.Status.String() != "Accepted" {
+		t.Errorf(types.ErrorMismatchValue, "Accepted", conf.Status.String())
+	}
+
+	if int(conf.Interval.Value()) != ocpp16test.DefaultHeartbeatInterval {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			ocpp16test.DefaultHeartbeatInterval,
+			conf.Interval.Value(),
+		)
+	}
+
+	boot := ocpp16test.ExpectRequest(
+		harness,
+		within,
+		func(bootnotification.ReqMessage) bool { return true },
+	)
+	if boot.ChargePointVendor.String() != "Vendor" {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			"Vendor",
+			boot.ChargePointVendor.String(),
+		)
+	}
+}
+
+func TestHarness_OnAuthorize(t *testing.T) {
+	t.Parallel()
+
+	harness := ocpp16test.New(t)
+	harness.OnAuthorize(
+		func(req authorize.ReqMessage) authorize.ConfMessage {
+			conf, _ := authorize.Conf(authorize.ConfInput{
+				Status:      "Blocked",
+				ExpiryDate:  nil,
+				ParentIdTag: nil,
+			})
+
+			if req.IdTag.String() != testIdTag {
+				t.Errorf(types.ErrorMismatchValue, testIdTag, req.IdTag.String())
+			}
+
+			return conf
+		},
+	)
+
+	conn := chargePoint(t, harness, nil)
+
+	req, err := authorize.Req(authorize.ReqInput{IdTag: testIdTag})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	var conf authorize.ConfMessage
+
+	err = conn.Call(context.Background(), ocppj.ActionAuthorize, req, &conf)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if conf.IdTagInfo.Status().String() != "Blocked" {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			"Blocked",
+			conf.IdTagInfo.Status().String(),
+		)
+	}
+
+	message := harness.Expect(ocppj.ActionAuthorize, within, nil)
+	if message.ChargePointId != testChargePoint ||
+		message.Direction != ocpp16test.FromChargePoint {
+		t.Errorf(types.ErrorMismatchValue, testChargePoint, message)
+	}
+}
+
+func TestHarness_RespondError(t *testing.T) {
+	t.Parallel()
+
+	harness := ocpp16test.New(t)
+	harness.RespondError(
+		ocppj.ActionStartTransaction,
+		ocppj.NewError(ocppj.InternalError, "database down"),
+	)
+
+	conn := chargePoint(t, harness, nil)
+
+	req, err := starttransaction.Req(starttransaction.ReqInput{
+		ConnectorId:   1,
+		IdTag:         testIdTag,
+		MeterStart:    0,
+		Timestamp:     testTimestamp,
+		ReservationId: nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	err = conn.Call(
+		context.Background(),
+		ocppj.ActionStartTransaction,
+		req,
+		nil,
+	)
+	if !errors.Is(err, ocppj.NewError(ocppj.InternalError, "")) {
+		t.Errorf(types.ErrorWrapping, err, ocppj.InternalError)
+	}
+}
+
+func TestHarness_ExpectStatusNotification(t *testing.T) {
+	t.Parallel()
+
+	harness := ocpp16test.New(t)
+	conn := chargePoint(t, harness, nil)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		time.Sleep(shortly)
+		sendStatus(t, conn, 1, "Preparing")
+		sendStatus(t, conn, 1, "Charging")
+	}()
+
+	req := harness.ExpectStatusNotification(1, "Charging", within)
+	<-done
+
+	if req.Status.String() != "Charging" {
+		t.Errorf(types.ErrorMismatchValue, "Charging", req.Status.String())
+	}
+
+	harness.Clear()
+
+	if len(harness.Messages()) != 0 {
+		t.Errorf(types.ErrorMismatchValue, 0, len(harness.Messages()))
+	}
+}
+
+func TestHarness_Call(t *testing.T) {
+	t.Parallel()
+
+	harness := ocpp16test.New(t)
+	chargePoint(
+		t,
+		harness,
+		func(_ context.Context, _ string, _ any) (any, error) {
+			return remotestarttransaction.Conf(
+				remotestarttransaction.ConfInput{Status: "Accepted"},
+			)
+		},
+	)
+
+	harness.ExpectConnected(testChargePoint, within)
+
+	req, err := remotestarttransaction.Req(remotestarttransaction.ReqInput{
+		IdTag:           testIdTag,
+		ConnectorId:     nil,
+		ChargingProfile: nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	var conf remotestarttransaction.ConfMessage
+
+	err = harness.Call(
+		context.Background(),
+		testChargePoint,
+		ocppj.ActionRemoteStartTransaction,
+		req,
+		&conf,
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	message := harness.Expect(ocppj.ActionRemoteStartTransaction, within, nil)
+	if message.Direction != ocpp16test.FromCentralSystem {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			ocpp16test.FromCentralSystem,
+			message.Direction,
+		)
+	}
+
+	recorded, ok := message.Confirmation.(remotestarttransaction.ConfMessage)
+	if !ok || recorded.Status.String() != "Accepted" {
+		t.Errorf(types.ErrorMismatchValue, "Accepted", message.Confirmation)
+	}
+}
+
+func TestHarness_CallNotConnected(t *testing.T) {
+	t.Parallel()
+
+	harness := ocpp16test.New(t)
+
+	err := harness.Call(
+		context.Background(),
+		testChargePoint,
+		ocppj.ActionReset,
+		nil,
+		nil,
+	)
+	if !errors.Is(err, ocpp16test.ErrNotConnected) {
+		t.Errorf(types.ErrorWrapping, err, ocpp16test.ErrNotConnected)
+	}
+}
+
+// recordingTB captures Fatalf instead of stopping the test.
+type recordingTB struct {
+	testing.TB
+
+	mu       sync.Mutex
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestHarness_ExpectTimeout(t *testing.T) {
+	t.Parallel()
+
+	recorder := &recordingTB{TB: t, mu: sync.Mutex{}, failures: nil}
+	harness := ocpp16test.New(recorder)
+
+	harness.ExpectStatusNotification(1, "Charging", shortly)
+	harness.ExpectNoMessage(ocppj.ActionHeartbeat, shortly)
+
+	if len(recorder.failures) != 1 {
+		t.Errorf(types.ErrorMismatchValue, 1, len(recorder.failures))
+	}
+}
@@ -0,0 +1,314 @@
+package ocppj
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/cancelreservation"
+	"github.com/aasanchez/ocpp16messages/changeavailability"
+	"github.com/aasanchez/ocpp16messages/changeconfiguration"
+	"github.com/aasanchez/ocpp16messages/clearcache"
+	"github.com/aasanchez/ocpp16messages/clearchargingprofile"
+	"github.com/aasanchez/ocpp16messages/datatransfer"
+	"github.com/aasanchez/ocpp16messages/diagnosticsstatusnotification"
+	"github.com/aasanchez/ocpp16messages/firmwarestatusnotification"
+	"github.com/aasanchez/ocpp16messages/getcompositeschedule"
+	"github.com/aasanchez/ocpp16messages/getconfiguration"
+	"github.com/aasanchez/ocpp16messages/getdiagnostics"
+	"github.com/aasanchez/ocpp16messages/getlocallistversion"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/metervalues"
+	"github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/remotestoptransaction"
+	"github.com/aasanchez/ocpp16messages/reservenow"
+	"github.com/aasanchez/ocpp16messages/reset"
+	"github.com/aasanchez/ocpp16messages/sendlocallist"
+	"github.com/aasanchez/ocpp16messages/setchargingprofile"
+	"github.com/aasanchez/ocpp16messages/starttransaction"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	"github.com/aasanchez/ocpp16messages/stoptransaction"
+	"github.com/aasanchez/ocpp16messages/triggermessage"
+	"github.com/aasanchez/ocpp16messages/unlockconnector"
+	"github.com/aasanchez/ocpp16messages/updatefirmware"
+)
+
+// Role is the side of an OCPP connection.
+type Role int
+
+const (
+	// RoleChargePoint is the Charge Point (EVSE) side.
+	RoleChargePoint Role = iota + 1
+	// RoleCentralSystem is the Central System (CSMS) side.
+	RoleCentralSystem
+)
+
+// String returns the role name used by the specification.
+func (r Role) String() string {
+	switch r {
+	case RoleChargePoint:
+		return "ChargePoint"
+	case RoleCentralSystem:
+		return "CentralSystem"
+	default:
+		return "Unknown"
+	}
+}
+
+// Action names as sent in CALL frames.
+const (
+	ActionAuthorize                     = authorize.Action
+	ActionBootNotification              = bootnotification.Action
+	ActionCancelReservation             = cancelreservation.Action
+	ActionChangeAvailability            = changeavailability.Action
+	ActionChangeConfiguration           = changeconfiguration.Action
+	ActionClearCache                    = clearcache.Action
+	ActionClearChargingProfile          = clearchargingprofile.Action
+	ActionDataTransfer                  = datatransfer.Action
+	ActionDiagnosticsStatusNotification = diagnosticsstatusnotification.Action
+	ActionFirmwareStatusNotification    = firmwarestatusnotification.Action
+	ActionGetCompositeSchedule          = getcompositeschedule.Action
+	ActionGetConfiguration              = getconfiguration.Action
+	ActionGetDiagnostics                = getdiagnostics.Action
+	ActionGetLocalListVersion           = getlocallistversion.Action
+	ActionHeartbeat                     = heartbeat.Action
+	ActionMeterValues                   = metervalues.Action
+	ActionRemoteStartTransaction        = remotestarttransaction.Action
+	ActionRemoteStopTransaction         = remotestoptransaction.Action
+	ActionReserveNow                    = reservenow.Action
+	ActionReset                         = reset.Action
+	ActionSendLocalList                 = sendlocallist.Action
+	ActionSetChargingProfile            = setchargingprofile.Action
+	ActionStartTransaction              = starttransaction.Action
+	ActionStatusNotification            = statusnotification.Action
+	ActionStopTransaction               = stoptransaction.Action
+	ActionTriggerMessage                = triggermessage.Action
+	ActionUnlockConnector               = unlockconnector.Action
+	ActionUpdateFirmware                = updatefirmware.Action
+)
+
+// decoder decodes a payload into a validated message value.
+type decoder func(payload []byte) (any, error)
+
+// action describes who sends an action and how to decode its payloads.
+type action struct {
+	initiator    Role
+	request      decoder
+	confirmation decoder
+}
+
+// actions maps every OCPP 1.6 action to its message packages. DataTransfer
+// may be initiated by either side and is registered for the Charge Point;
+// see Initiates.
+var actions = map[string]action{
+	ActionAuthorize: {
+		RoleChargePoint,
+		decode[authorize.ReqMessage],
+		decode[authorize.ConfMessage],
+	},
+	ActionBootNotification: {
+		RoleChargePoint,
+		decode[bootnotification.ReqMessage],
+		decode[bootnotification.ConfMessage],
+	},
+	ActionCancelReservation: {
+		RoleCentralSystem,
+		decode[cancelreservation.ReqMessage],
+		decode[cancelreservation.ConfMessage],
+	},
+	ActionChangeAvailability: {
+		RoleCentralSystem,
+		decode[changeavailability.ReqMessage],
+		decode[changeavailability.ConfMessage],
+	},
+	ActionChangeConfiguration: {
+		RoleCentralSystem,
+		decode[changeconfiguration.ReqMessage],
+		decode[changeconfiguration.ConfMessage],
+	},
+	ActionClearCache: {
+		RoleCentralSystem,
+		decode[clearcache.ReqMessage],
+		decode[clearcache.ConfMessage],
+	},
+	ActionClearChargingProfile: {
+		RoleCentralSystem,
+		decode[clearchargingprofile.ReqMessage],
+		decode[clearchargingprofile.ConfMessage],
+	},
+	ActionDataTransfer: {
+		RoleChargePoint,
+		decode[datatransfer.ReqMessage],
+		decode[datatransfer.ConfMessage],
+	},
+	ActionDiagnosticsStatusNotification: {
+		RoleChargePoint,
+		decode[diagnosticsstatusnotification.ReqMessage],
+		decode[diagnosticsstatusnotification.ConfMessage],
+	},
+	ActionFirmwareStatusNotification: {
+		RoleChargePoint,
+		decode[firmwarestatusnotification.ReqMessage],
+		decode[firmwarestatusnotification.ConfMessage],
+	},
+	ActionGetCompositeSchedule: {
+		RoleCentralSystem,
+		decode[getcompositeschedule.ReqMessage],
+		decode[getcompositeschedule.ConfMessage],
+	},
+	ActionGetConfiguration: {
+		RoleCentralSystem,
+		decode[getconfiguration.ReqMessage],
+		decode[getconfiguration.ConfMessage],
+	},
+	ActionGetDiagnostics: {
+		RoleCentralSystem,
+		decode[getdiagnostics.ReqMessage],
+		decode[getdiagnostics.ConfMessage],
+	},
+	ActionGetLocalListVersion: {
+		RoleCentralSystem,
+		decode[getlocallistversion.ReqMessage],
+		decode[getlocallistversion.ConfMessage],
+	},
+	ActionHeartbeat: {
+		RoleChargePoint,
+		decode[heartbeat.ReqMessage],
+		decode[heartbeat.ConfMessage],
+	},
+	ActionMeterValues: {
+		RoleChargePoint,
+		decode[metervalues.ReqMessage],
+		decode[metervalues.ConfMessage],
+	},
+	ActionRemoteStartTransaction: {
+		RoleCentralSystem,
+		decode[remotestarttransaction.ReqMessage],
+		decode[remotestarttransaction.ConfMessage],
+	},
+	ActionRemoteStopTransaction: {
+		RoleCentralSystem,
+		decode[remotestoptransaction.ReqMessage],
+		decode[remotestoptransaction.ConfMessage],
+	},
+	ActionReserveNow: {
+		RoleCentralSystem,
+		decode[reservenow.ReqMessage],
+		decode[reservenow.ConfMessage],
+	},
+	ActionReset: {
+		RoleCentralSystem,
+		decode[reset.ReqMessage],
+		decode[reset.ConfMessage],
+	},
+	ActionSendLocalList: {
+		RoleCentralSystem,
+		decode[sendlocallist.ReqMessage],
+		decode[sendlocallist.ConfMessage],
+	},
+	ActionSetChargingProfile: {
+		RoleCentralSystem,
+		decode[setchargingprofile.ReqMessage],
+		decode[setchargingprofile.ConfMessage],
+	},
+	ActionStartTransaction: {
+		RoleChargePoint,
+		decode[starttransaction.ReqMessage],
+		decode[starttransaction.ConfMessage],
+	},
+	ActionStatusNotification: {
+		RoleChargePoint,
+		decode[statusnotification.ReqMessage],
+		decode[statusnotification.ConfMessage],
+	},
+	ActionStopTransaction: {
+		RoleChargePoint,
+		decode[stoptransaction.ReqMessage],
+		decode[stoptransaction.ConfMessage],
+	},
+	ActionTriggerMessage: {
+		RoleCentralSystem,
+		decode[triggermessage.ReqMessage],
+		decode[triggermessage.ConfMessage],
+	},
+	ActionUnlockConnector: {
+		RoleCentralSystem,
+		decode[unlockconnector.ReqMessage],
+		decode[unlockconnector.ConfMessage],
+	},
+	ActionUpdateFirmware: {
+		RoleCentralSystem,
+		decode[updatefirmware.ReqMessage],
+		decode[updatefirmware.ConfMessage],
+	},
+}
+
+// decode unmarshals a payload into a message type whose UnmarshalJSON
+// validates it.
+func decode[T any](payload []byte) (any, error) {
+	var msg T
+
+	err := json.Unmarshal(payload, &msg)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Classified by ErrorFor.
+	}
+
+	return msg, nil
+}
+
+// Actions returns the names of all OCPP 1.6 actions in alphabetical order.
+func Actions() []string {
+	names := make([]string, 0, len(actions))
+	for name := range actions {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// Initiates reports whether role may send a CALL for the action. DataTransfer
+// may be sent by both roles.
+func Initiates(role Role, actionName string) bool {
+	registered, ok := actions[actionName]
+	if !ok {
+		return false
+	}
+
+	return registered.initiator == role || actionName == ActionDataTransfer
+}
+
+// DecodeRequest decodes and validates the payload of a CALL into the
+// ReqMessage of the action's package, e.g. authorize.ReqMessage. Unknown
+// actions yield a NotImplemented *Error; validation errors can be
+// classified with ErrorFor.
+func DecodeRequest(actionName string, payload []byte) (any, error) {
+	registered, ok := actions[actionName]
+	if !ok {
+		return nil, notImplemented(actionName)
+	}
+
+	return registered.request(payload)
+}
+
+// DecodeConfirmation decodes and validates the payload of a CALLRESULT into
+// the ConfMessage of the action's package, e.g. authorize.ConfMessage.
+func DecodeConfirmation(actionName string, payload []byte) (any, error) {
+	registered, ok := actions[actionName]
+	if !ok {
+		return nil, notImplemented(actionName)
+	}
+
+	return registered.confirmation(payload)
+}
+
+// notImplemented returns the error for an unknown action.
+func notImplemented(actionName string) *Error {
+	return NewError(
+		NotImplemented,
+		fmt.Sprintf("unknown action %q", actionName),
+	)
+}
@@ -0,0 +1,344 @@
+package ocppj
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// idPrefixLen is the number of random bytes prefixed to generated unique
+// ids, keeping them unique across reconnects.
+const idPrefixLen = 8
+
+// Transport carries OCPP-J frames, one message per frame. The WebSocket
+// connections returned by Dial and Accept implement it; tests may supply an
+// in-memory implementation.
+type Transport interface {
+	ReadMessage() ([]byte, error)
+	WriteMessage(data []byte) error
+	Close() error
+}
+
+// Handler answers a CALL received from the peer. request is the validated
+// ReqMessage of the action's package (e.g. reset.ReqMessage) and the
+// returned value is encoded as the CALLRESULT payload, normally the
+// matching ConfMessage. Returning an error sends a CALLERROR classified by
+// ErrorFor instead; return an *Error to choose the code, or ErrNoAnswer to
+// send nothing.
+type Handler func(ctx context.Context, action string, request any) (any, error)
+
+// Conn is an OCPP-J endpoint over a Transport. It matches CALLRESULT and
+// CALLERROR frames to outgoing calls and dispatches incoming CALLs to a
+// Handler, answering malformed or invalid CALLs with the CALLERROR the
+// specification prescribes.
+type Conn struct {
+	transport Transport
+	role      Role
+	handler   Handler
+
+	callMu  sync.Mutex
+	mu      sync.Mutex
+	pending *pendingCall
+
+	closed    chan struct{}
+	closeOnce sync.Once
+	idPrefix  string
+	idCounter atomic.Uint64
+}
+
+// pendingCall is the single outstanding CALL of a Conn.
+type pendingCall struct {
+	uniqueId string
+	result   chan Frame
+}
+
+// NewConn returns a Conn acting as role. handler may be nil, in which case
+// every incoming CALL is answered with NotSupported. Call Run to start
+// processing frames.
+func NewConn(transport Transport, role Role, handler Handler) *Conn {
+	prefix := make([]byte, idPrefixLen)
+	_, _ = rand.Read(prefix)
+
+	return &Conn{
+		transport: transport,
+		role:      role,
+		handler:   handler,
+		callMu:    sync.Mutex{},
+		mu:        sync.Mutex{},
+		pending:   nil,
+		closed:    make(chan struct{}),
+		closeOnce: sync.Once{},
+		idPrefix:  hex.EncodeToString(prefix),
+		idCounter: atomic.Uint64{},
+	}
+}
+
+// Role returns the side this Conn acts as.
+func (c *Conn) Role() Role {
+	return c.role
+}
+
+// Done is closed once the Conn is closed.
+func (c *Conn) Done() <-chan struct{} {
+	return c.closed
+}
+
+// Close closes the transport and fails any outstanding Call with ErrClosed.
+func (c *Conn) Close() error {
+	var err error
+
+	c.closeOnce.Do(func() {
+		close(c.closed)
+
+		err = c.transport.Close()
+	})
+
+	if err != nil {
+		return fmt.Errorf("ocppj: close: %w", err)
+	}
+
+	return nil
+}
+
+// Run reads frames until the transport fails or ctx is cancelled, then
+// closes the Conn. Incoming CALLs are handled concurrently so a Handler may
+// itself issue Calls; the context passed to a Handler is cancelled when the
+// Conn closes. The returned error wraps ErrClosed.
+func (c *Conn) Run(ctx context.Context) error {
+	// Handlers are waited for after ctx is cancelled, so that a Handler
+	// blocked on its context returns once the connection is gone.
+	var handlers sync.WaitGroup
+	defer handlers.Wait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(ctx, func() { _ = c.Close() })
+	defer stop()
+
+	for {
+		data, err := c.transport.ReadMessage()
+		if err != nil {
+			_ = c.Close()
+
+			return fmt.Errorf("%w: %w", ErrClosed, err)
+		}
+
+		frame, err := ParseFrame(data)
+		if err != nil {
+			c.rejectFrame(frame, err)
+
+			continue
+		}
+
+		switch frame.Type {
+		case MessageTypeCall:
+			handlers.Add(1)
+
+			go func() {
+				defer handlers.Done()
+
+				c.serve(ctx, frame)
+			}()
+		case MessageTypeCallResult, MessageTypeCallError:
+			c.resolve(frame)
+		}
+	}
+}
+
+// Call sends a CALL and waits for the answer. request is encoded with
+// encoding/json, so any ReqMessage of this module can be passed directly.
+// When confirmation is non-nil the CALLRESULT payload is decoded into it;
+// passing a pointer to the matching ConfMessage validates the answer.
+// A CALLERROR from the peer is returned as an *Error. Calls are serialized:
+// per the specification only one CALL may be outstanding at a time.
+func (c *Conn) Call(
+	ctx context.Context,
+	action string,
+	request any,
+	confirmation any,
+) error {
+	c.callMu.Lock()
+	defer c.callMu.Unlock()
+
+	uniqueId := c.idPrefix + "-" +
+		strconv.FormatUint(c.idCounter.Add(1), 10)
+
+	frame, err := NewCall(uniqueId, action, request)
+	if err != nil {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+
+	result, err := c.register(uniqueId)
+	if err != nil {
+		return err
+	}
+	defer c.unregister()
+
+	err = c.write(frame)
+	if err != nil {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+
+	select {
+	case answer := <-result:
+		return decodeAnswer(action, answer, confirmation)
+	case <-ctx.Done():
+		return fmt.Errorf("%s: %w", action, ctx.Err())
+	case <-c.closed:
+		return fmt.Errorf("%s: %w", action, ErrClosed)
+	}
+}
+
+// decodeAnswer turns the answer to a CALL into Call's result.
+func decodeAnswer(action string, answer Frame, confirmation any) error {
+	if answer.Type == MessageTypeCallError {
+		return answer.Err()
+	}
+
+	if confirmation == nil {
+		return nil
+	}
+
+	err := json.Unmarshal(answer.Payload, confirmation)
+	if err != nil {
+		return fmt.Errorf("%s.conf: %w", action, err)
+	}
+
+	return nil
+}
+
+// register records the outstanding CALL.
+func (c *Conn) register(uniqueId string) (chan Frame, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	select {
+	case <-c.closed:
+		return nil, ErrClosed
+	default:
+	}
+
+	result := make(chan Frame, 1)
+	c.pending = &pendingCall{uniqueId: uniqueId, result: result}
+
+	return result, nil
+}
+
+// unregister forgets the outstanding CALL.
+func (c *Conn) unregister() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pending = nil
+}
+
+// resolve delivers a CALLRESULT or CALLERROR to the outstanding CALL.
+// Answers that match no outstanding CALL are dropped.
+func (c *Conn) resolve(frame Frame) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pending == nil || c.pending.uniqueId != frame.UniqueId {
+		return
+	}
+
+	select {
+	case c.pending.result <- frame:
+	default:
+	}
+}
+
+// rejectFrame answers a malformed frame. CALLs get a CALLERROR, malformed
+// answers to the outstanding CALL fail it, anything else is dropped.
+func (c *Conn) rejectFrame(frame Frame, err error) {
+	callErr := ErrorFor(err)
+
+	switch frame.Type {
+	case MessageTypeCall:
+		_ = c.write(NewCallError(frame.UniqueId, callErr))
+	case MessageTypeCallResult, MessageTypeCallError:
+		c.resolve(NewCallError(frame.UniqueId, callErr))
+	}
+}
+
+// serve answers an incoming CALL.
+func (c *Conn) serve(ctx context.Context, call Frame) {
+	confirmation, err := c.dispatch(ctx, call)
+	if errors.Is(err, ErrNoAnswer) {
+		return
+	}
+
+	if err != nil {
+		_ = c.write(NewCallError(call.UniqueId, ErrorFor(err)))
+
+		return
+	}
+
+	result, err := NewCallResult(call.UniqueId, confirmation)
+	if err != nil {
+		_ = c.write(NewCallError(
+			call.UniqueId,
+			NewError(InternalError, err.Error()),
+		))
+
+		return
+	}
+
+	_ = c.write(result)
+}
+
+// dispatch validates an incoming CALL and runs the handler.
+func (c *Conn) dispatch(ctx context.Context, call Frame) (any, error) {
+	if _, known := actions[call.Action]; !known {
+		return nil, notImplemented(call.Action)
+	}
+
+	if !Initiates(c.peerRole(), call.Action) || c.handler == nil {
+		return nil, NewError(
+			NotSupported,
+			fmt.Sprintf("action %q is not supported", call.Action),
+		)
+	}
+
+	request, err := DecodeRequest(call.Action, call.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.handler(ctx, call.Action, request)
+}
+
+// peerRole returns the role of the other side.
+func (c *Conn) peerRole() Role {
+	if c.role == RoleChargePoint {
+		return RoleCentralSystem
+	}
+
+	return RoleChargePoint
+}
+
+// write encodes and sends a frame.
+func (c *Conn) write(frame Frame) error {
+	data, err := json.Marshal(frame)
+	if err != nil {
+		return fmt.Errorf("frame: %w", err)
+	}
+
+	err = c.transport.WriteMessage(data)
+	if err != nil {
+		if errors.Is(err, ErrClosed) {
+			return err
+		}
+
+		return fmt.Errorf("%w: %w", ErrClosed, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+// Package ocppj implements OCPP-J, the JSON over WebSocket transport of
+// OCPP 1.6, on top of the message packages of this module.
+//
+// # Frames
+//
+// Every OCPP-J message is a JSON array whose first element is the message
+// type:
+//   - CALL: [2, "<uniqueId>", "<action>", {<payload>}]
+//   - CALLRESULT: [3, "<uniqueId>", {<payload>}]
+//   - CALLERROR: [4, "<uniqueId>", "<errorCode>", "<errorDescription>",
+//     {<errorDetails>}]
+//
+// ParseFrame and Frame.MarshalJSON convert between the wire form and Frame.
+// Payloads are encoded by the MarshalJSON method of each ReqMessage and
+// ConfMessage, and decoded through their UnmarshalJSON methods, which run
+// the Req/Conf constructors so only valid messages are produced.
+// DecodeRequest and DecodeConfirmation pick the message type from the
+// action name.
+//
+// # Errors
+//
+// Error carries a CALLERROR code. ErrorFor maps decoding and validation
+// errors onto the codes of the specification: malformed JSON, duplicate keys
+// and unknown properties become FormationViolation, wrong JSON types,
+// including 1.0 for an integer, TypeConstraintViolation, missing values
+// OccurenceConstraintViolation and invalid values
+// PropertyConstraintViolation.
+//
+// # Connections
+//
+// Conn is an OCPP-J endpoint. It sends CALLs with Call, waits for the
+// matching answer and dispatches incoming CALLs to a Handler. Dial connects
+// a Charge Point to a Central System over WebSocket with the "ocpp1.6"
+// subprotocol; Accept upgrades an incoming HTTP request on the Central
+// System side.
+package ocppj
@@ -0,0 +1,139 @@
+package ocppj
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// ErrorCode is the errorCode element of a CALLERROR frame.
+type ErrorCode string
+
+// Error codes defined by the OCPP-J 1.6 specification, section 4.2.3.
+const (
+	// NotImplemented means the requested action is not known by the
+	// receiver.
+	NotImplemented ErrorCode = "NotImplemented"
+	// NotSupported means the requested action is recognized but not
+	// supported by the receiver.
+	NotSupported ErrorCode = "NotSupported"
+	// InternalError means an internal error occurred and the receiver was
+	// not able to process the requested action successfully.
+	InternalError ErrorCode = "InternalError"
+	// ProtocolError means the payload for the action is incomplete.
+	ProtocolError ErrorCode = "ProtocolError"
+	// SecurityError means a security issue occurred during processing.
+	SecurityError ErrorCode = "SecurityError"
+	// FormationViolation means the payload is syntactically incorrect or not
+	// conform the PDU structure for the action.
+	FormationViolation ErrorCode = "FormationViolation"
+	// PropertyConstraintViolation means the payload is syntactically correct
+	// but at least one field contains an invalid value.
+	PropertyConstraintViolation ErrorCode = "PropertyConstraintViolation"
+	// OccurenceConstraintViolation means the payload is syntactically
+	// correct but at least one field violates occurrence constraints. The
+	// misspelling is the one used on the wire by the specification.
+	OccurenceConstraintViolation ErrorCode = "OccurenceConstraintViolation"
+	// TypeConstraintViolation means the payload is syntactically correct but
+	// at least one field violates data type constraints (e.g. "somestring"
+	// for an integer).
+	TypeConstraintViolation ErrorCode = "TypeConstraintViolation"
+	// GenericError is any other error.
+	GenericError ErrorCode = "GenericError"
+)
+
+var (
+	// ErrInvalidFrame is returned when a Frame cannot be encoded.
+	ErrInvalidFrame = errors.New("ocppj: invalid frame")
+	// ErrClosed is returned by Call when the connection closes before the
+	// answer arrives, and by Run once the transport is gone.
+	ErrClosed = errors.New("ocppj: connection closed")
+	// ErrNoAnswer is returned by a Handler to leave a CALL unanswered, so
+	// that the call of the peer times out.
+	ErrNoAnswer = errors.New("ocppj: no answer")
+	// ErrDuplicateKey is wrapped by decoding errors of payloads holding a
+	// property twice.
+	ErrDuplicateKey = wire.ErrDuplicateKey
+	// ErrUnknownProperty is wrapped by decoding errors of payloads holding a
+	// property their schema does not define.
+	ErrUnknownProperty = wire.ErrUnknownProperty
+	// ErrMissingProperty is wrapped by decoding errors of payloads lacking a
+	// required property. It wraps types.ErrEmptyValue.
+	ErrMissingProperty = wire.ErrMissingProperty
+)
+
+// Error is an OCPP-J error, as carried by a CALLERROR frame. Handlers return
+// it to control the CALLERROR sent back, and Call returns it when the peer
+// answered with a CALLERROR.
+type Error struct {
+	Code        ErrorCode
+	Description string
+	Details     json.RawMessage
+}
+
+// NewError returns an *Error without details.
+func NewError(code ErrorCode, description string) *Error {
+	return &Error{Code: code, Description: description, Details: nil}
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Description == "" {
+		return string(e.Code)
+	}
+
+	return string(e.Code) + ": " + e.Description
+}
+
+// Is reports whether target is an *Error with the same code, so that
+// errors.Is(err, ocppj.NewError(ocppj.NotSupported, "")) matches any
+// NotSupported error.
+func (e *Error) Is(target error) bool {
+	var other *Error
+	if !errors.As(target, &other) {
+		return false
+	}
+
+	return e.Code == other.Code
+}
+
+// ErrorFor classifies err into the CALLERROR to send back:
+//   - An *Error anywhere in the chain is returned as is
+//   - JSON syntax errors, including data after a streamed payload,
+//     duplicate keys (ErrDuplicateKey) and unknown properties
+//     (ErrUnknownProperty) map to FormationViolation
+//   - JSON type mismatches map to TypeConstraintViolation
+//   - Missing required values (types.ErrEmptyValue) map to
+//     OccurenceConstraintViolation
+//   - Invalid values (types.ErrInvalidValue) map to
+//     PropertyConstraintViolation
+//   - Anything else maps to InternalError
+func ErrorFor(err error) *Error {
+	var (
+		callErr   *Error
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &callErr):
+		return callErr
+	case errors.As(err, &syntaxErr),
+		errors.Is(err, ErrDuplicateKey),
+		errors.Is(err, ErrUnknownProperty),
+		errors.Is(err, wire.ErrTrailingData):
+		return NewError(FormationViolation, err.Error())
+	case errors.As(err, &typeErr):
+		return NewError(TypeConstraintViolation, err.Error())
+	case errors.Is(err, types.ErrEmptyValue):
+		return NewError(OccurenceConstraintViolation, err.Error())
+	case errors.Is(err, types.ErrInvalidValue):
+		return NewError(PropertyConstraintViolation, err.Error())
+	default:
+		return NewError(InternalError, err.Error())
+	}
+}
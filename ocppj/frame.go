@@ -0,0 +1,287 @@
+package ocppj
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MessageType is the first element of every OCPP-J frame.
+type MessageType int
+
+const (
+	// MessageTypeCall identifies a request frame: [2, id, action, payload].
+	MessageTypeCall MessageType = 2
+	// MessageTypeCallResult identifies a response frame: [3, id, payload].
+	MessageTypeCallResult MessageType = 3
+	// MessageTypeCallError identifies an error frame:
+	// [4, id, errorCode, errorDescription, errorDetails].
+	MessageTypeCallError MessageType = 4
+)
+
+const (
+	// MaxUniqueIdLength is the maximum length of a frame's unique id.
+	MaxUniqueIdLength = 36
+
+	callLen       = 4
+	callResultLen = 3
+	callErrorLen  = 5
+
+	indexType        = 0
+	indexUniqueId    = 1
+	indexAction      = 2
+	indexCallPayload = 3
+	indexResult      = 2
+	indexErrorCode   = 2
+	indexErrorDesc   = 3
+	indexErrorDetail = 4
+)
+
+// emptyObject is the payload of messages without fields and the default
+// CALLERROR details.
+var emptyObject = json.RawMessage("{}")
+
+// Frame is a single OCPP-J message. Action is only set on CALL frames,
+// Payload on CALL and CALLRESULT frames and the Error* fields on CALLERROR
+// frames.
+type Frame struct {
+	Type             MessageType
+	UniqueId         string
+	Action           string
+	Payload          json.RawMessage
+	ErrorCode        ErrorCode
+	ErrorDescription string
+	ErrorDetails     json.RawMessage
+}
+
+// NewCall builds a CALL frame, encoding request with encoding/json. Message
+// types of this module encode to their OCPP-J payload.
+func NewCall(uniqueId, action string, request any) (Frame, error) {
+	payload, err := encodePayload(request)
+	if err != nil {
+		return Frame{}, err
+	}
+
+	return Frame{
+		Type:             MessageTypeCall,
+		UniqueId:         uniqueId,
+		Action:           action,
+		Payload:          payload,
+		ErrorCode:        "",
+		ErrorDescription: "",
+		ErrorDetails:     nil,
+	}, nil
+}
+
+// NewCallResult builds a CALLRESULT frame answering the CALL with uniqueId.
+func NewCallResult(uniqueId string, confirmation any) (Frame, error) {
+	payload, err := encodePayload(confirmation)
+	if err != nil {
+		return Frame{}, err
+	}
+
+	return Frame{
+		Type:             MessageTypeCallResult,
+		UniqueId:         uniqueId,
+		Action:           "",
+		Payload:          payload,
+		ErrorCode:        "",
+		ErrorDescription: "",
+		ErrorDetails:     nil,
+	}, nil
+}
+
+// NewCallError builds a CALLERROR frame answering the CALL with uniqueId.
+func NewCallError(uniqueId string, callErr *Error) Frame {
+	details := callErr.Details
+	if len(details) == 0 {
+		details = emptyObject
+	}
+
+	return Frame{
+		Type:             MessageTypeCallError,
+		UniqueId:         uniqueId,
+		Action:           "",
+		Payload:          nil,
+		ErrorCode:        callErr.Code,
+		ErrorDescription: callErr.Description,
+		ErrorDetails:     details,
+	}
+}
+
+// Err returns the error carried by a CALLERROR frame, or nil for other
+// frames.
+func (f Frame) Err() *Error {
+	if f.Type != MessageTypeCallError {
+		return nil
+	}
+
+	return &Error{
+		Code:        f.ErrorCode,
+		Description: f.ErrorDescription,
+		Details:     f.ErrorDetails,
+	}
+}
+
+// MarshalJSON encodes the frame as an OCPP-J array.
+func (f Frame) MarshalJSON() ([]byte, error) {
+	var elements []any
+
+	switch f.Type {
+	case MessageTypeCall:
+		elements = []any{f.Type, f.UniqueId, f.Action, payloadOrEmpty(f.Payload)}
+	case MessageTypeCallResult:
+		elements = []any{f.Type, f.UniqueId, payloadOrEmpty(f.Payload)}
+	case MessageTypeCallError:
+		elements = []any{
+			f.Type,
+			f.UniqueId,
+			f.ErrorCode,
+			f.ErrorDescription,
+			payloadOrEmpty(f.ErrorDetails),
+		}
+	default:
+		return nil, fmt.Errorf("messageTypeId %d: %w", f.Type, ErrInvalidFrame)
+	}
+
+	data, err := json.Marshal(elements)
+	if err != nil {
+		return nil, fmt.Errorf("frame: %w", err)
+	}
+
+	return data, nil
+}
+
+// ParseFrame decodes an OCPP-J array. When the frame is malformed the
+// returned error is an *Error with code FormationViolation (or
+// ProtocolError for an unknown message type) and the returned Frame carries
+// the unique id whenever it could be read, so the receiver can answer with
+// a CALLERROR.
+func ParseFrame(data []byte) (Frame, error) {
+	var elements []json.RawMessage
+
+	err := json.Unmarshal(data, &elements)
+	if err != nil {
+		return Frame{}, NewError(FormationViolation, "frame is not a JSON array")
+	}
+
+	if len(elements) <= indexUniqueId {
+		return Frame{}, NewError(FormationViolation, "frame is too short")
+	}
+
+	var frame Frame
+
+	err = json.Unmarshal(elements[indexType], &frame.Type)
+	if err != nil {
+		return Frame{}, NewError(FormationViolation, "invalid messageTypeId")
+	}
+
+	err = json.Unmarshal(elements[indexUniqueId], &frame.UniqueId)
+	if err != nil || frame.UniqueId == "" ||
+		len(frame.UniqueId) > MaxUniqueIdLength {
+		return Frame{}, NewError(FormationViolation, "invalid uniqueId")
+	}
+
+	switch frame.Type {
+	case MessageTypeCall:
+		err = parseCall(&frame, elements)
+	case MessageTypeCallResult:
+		err = parseCallResult(&frame, elements)
+	case MessageTypeCallError:
+		err = parseCallError(&frame, elements)
+	default:
+		err = NewError(ProtocolError, "unknown messageTypeId")
+	}
+
+	return frame, err
+}
+
+// parseCall fills the CALL specific fields.
+func parseCall(frame *Frame, elements []json.RawMessage) error {
+	if len(elements) != callLen {
+		return NewError(FormationViolation, "CALL must have 4 elements")
+	}
+
+	err := json.Unmarshal(elements[indexAction], &frame.Action)
+	if err != nil || frame.Action == "" {
+		return NewError(FormationViolation, "invalid action")
+	}
+
+	if !isObject(elements[indexCallPayload]) {
+		return NewError(FormationViolation, "payload must be a JSON object")
+	}
+
+	frame.Payload = elements[indexCallPayload]
+
+	return nil
+}
+
+// parseCallResult fills the CALLRESULT specific fields.
+func parseCallResult(frame *Frame, elements []json.RawMessage) error {
+	if len(elements) != callResultLen {
+		return NewError(FormationViolation, "CALLRESULT must have 3 elements")
+	}
+
+	if !isObject(elements[indexResult]) {
+		return NewError(FormationViolation, "payload must be a JSON object")
+	}
+
+	frame.Payload = elements[indexResult]
+
+	return nil
+}
+
+// parseCallError fills the CALLERROR specific fields.
+func parseCallError(frame *Frame, elements []json.RawMessage) error {
+	if len(elements) != callErrorLen {
+		return NewError(FormationViolation, "CALLERROR must have 5 elements")
+	}
+
+	err := json.Unmarshal(elements[indexErrorCode], &frame.ErrorCode)
+	if err != nil {
+		return NewError(FormationViolation, "invalid errorCode")
+	}
+
+	err = json.Unmarshal(elements[indexErrorDesc], &frame.ErrorDescription)
+	if err != nil {
+		return NewError(FormationViolation, "invalid errorDescription")
+	}
+
+	if !isObject(elements[indexErrorDetail]) {
+		return NewError(FormationViolation, "errorDetails must be an object")
+	}
+
+	frame.ErrorDetails = elements[indexErrorDetail]
+
+	return nil
+}
+
+// encodePayload marshals a request or confirmation payload.
+func encodePayload(value any) (json.RawMessage, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("payload: %w", err)
+	}
+
+	if !isObject(payload) {
+		return nil, fmt.Errorf("payload: %w", ErrInvalidFrame)
+	}
+
+	return payload, nil
+}
+
+// payloadOrEmpty substitutes an empty object for a missing payload.
+func payloadOrEmpty(payload json.RawMessage) json.RawMessage {
+	if len(payload) == 0 {
+		return emptyObject
+	}
+
+	return payload
+}
+
+// isObject reports whether raw JSON is an object.
+func isObject(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
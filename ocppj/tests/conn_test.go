@@ -0,0 +1,356 @@
+package ocppj_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/reset"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	testTimeout     = 5 * time.Second
+	testCurrentTime = "2025-01-02T15:00:00Z"
+	testChargePoint = "CP001"
+)
+
+var errPipeClosed = errors.New("pipe closed")
+
+// pipe is one end of an in-memory Transport pair.
+type pipe struct {
+	in     chan []byte
+	out    chan []byte
+	closed chan struct{}
+	once   *sync.Once
+}
+
+// newPipe returns two connected in-memory transports.
+func newPipe() (pipe, pipe) {
+	aToB := make(chan []byte, 8)
+	bToA := make(chan []byte, 8)
+	closed := make(chan struct{})
+	once := &sync.Once{}
+
+	return pipe{in: bToA, out: aToB, closed: closed, once: once},
+		pipe{in: aToB, out: bToA, closed: closed, once: once}
+}
+
+func (p pipe) ReadMessage() ([]byte, error) {
+	select {
+	case data := <-p.in:
+		return data, nil
+	case <-p.closed:
+		return nil, errPipeClosed
+	}
+}
+
+func (p pipe) WriteMessage(data []byte) error {
+	select {
+	case p.out <- data:
+		return nil
+	case <-p.closed:
+		return errPipeClosed
+	}
+}
+
+func (p pipe) Close() error {
+	p.once.Do(func() { close(p.closed) })
+
+	return nil
+}
+
+// centralSystem answers Authorize and Heartbeat.
+func centralSystem(
+	_ context.Context,
+	action string,
+	_ any,
+) (any, error) {
+	switch action {
+	case ocppj.ActionAuthorize:
+		return authorize.Conf(authorize.ConfInput{
+			Status:      "Accepted",
+			ExpiryDate:  nil,
+			ParentIdTag: nil,
+		})
+	case ocppj.ActionHeartbeat:
+		return heartbeat.Conf(heartbeat.ConfInput{CurrentTime: testCurrentTime})
+	default:
+		return nil, ocppj.NewError(ocppj.NotSupported, action)
+	}
+}
+
+// connect runs a Charge Point and a Central System Conn over a pipe.
+func connect(t *testing.T) (*ocppj.Conn, pipe) {
+	t.Helper()
+
+	cpSide, csSide := newPipe()
+
+	chargePoint := ocppj.NewConn(cpSide, ocppj.RoleChargePoint, nil)
+	server := ocppj.NewConn(csSide, ocppj.RoleCentralSystem, centralSystem)
+
+	go func() { _ = chargePoint.Run(context.Background()) }()
+	go func() { _ = server.Run(context.Background()) }()
+
+	t.Cleanup(func() { _ = chargePoint.Close() })
+
+	return chargePoint, cpSide
+}
+
+func TestConn_Call(t *testing.T) {
+	t.Parallel()
+
+	chargePoint, _ := connect(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	req, err := authorize.Req(authorize.ReqInput{IdTag: testIdTag})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	var conf authorize.ConfMessage
+
+	err = chargePoint.Call(ctx, ocppj.ActionAuthorize, req, &conf)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if conf.IdTagInfo.Status().String() != "Accepted" {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			"Accepted",
+			conf.IdTagInfo.Status().String(),
+		)
+	}
+}
+
+func TestConn_Call_Sequential(t *testing.T) {
+	t.Parallel()
+
+	chargePoint, _ := connect(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	req, err := heartbeat.Req(heartbeat.ReqInput{})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	var wg sync.WaitGroup
+
+	for range 5 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			var conf heartbeat.ConfMessage
+
+			callErr := chargePoint.Call(ctx, ocppj.ActionHeartbeat, req, &conf)
+			if callErr != nil {
+				t.Errorf(types.ErrorUnexpectedError, callErr)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestConn_WrongDirection(t *testing.T) {
+	t.Parallel()
+
+	chargePoint, _ := connect(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	req, err := reset.Req(reset.ReqInput{Type: "Soft"})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	err = chargePoint.Call(ctx, ocppj.ActionReset, req, nil)
+	if !errors.Is(err, ocppj.NewError(ocppj.NotSupported, "")) {
+		t.Errorf(types.ErrorWrapping, err, ocppj.NotSupported)
+	}
+}
+
+func TestConn_RejectsInvalidCall(t *testing.T) {
+	t.Parallel()
+
+	cpSide, csSide := newPipe()
+	server := ocppj.NewConn(csSide, ocppj.RoleCentralSystem, centralSystem)
+
+	go func() { _ = server.Run(context.Background()) }()
+
+	t.Cleanup(func() { _ = server.Close() })
+
+	tests := []struct {
+		frame string
+		code  ocppj.ErrorCode
+	}{
+		{`[2,"1","Authorize",{}]`, ocppj.OccurenceConstraintViolation},
+		{`[2,"2","Authorize",{"idTag":1}]`, ocppj.TypeConstraintViolation},
+		{`[2,"3","SignCertificate",{}]`, ocppj.NotImplemented},
+		{`[2,"4","Reset",{"type":"Hard"}]`, ocppj.NotSupported},
+	}
+
+	for _, tt := range tests {
+		err := cpSide.WriteMessage([]byte(tt.frame))
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+
+		data, err := cpSide.ReadMessage()
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+
+		frame, err := ocppj.ParseFrame(data)
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+
+		if frame.Type != ocppj.MessageTypeCallError ||
+			frame.ErrorCode != tt.code {
+			t.Errorf(types.ErrorMismatchValue, tt.code, string(data))
+		}
+	}
+}
+
+func TestConn_NoAnswer(t *testing.T) {
+	t.Parallel()
+
+	cpSide, csSide := newPipe()
+	server := ocppj.NewConn(
+		csSide,
+		ocppj.RoleCentralSystem,
+		func(_ context.Context, _ string, _ any) (any, error) {
+			return nil, ocppj.ErrNoAnswer
+		},
+	)
+
+	go func() { _ = server.Run(context.Background()) }()
+
+	t.Cleanup(func() { _ = server.Close() })
+
+	for _, frame := range []string{
+		`[2,"1","Heartbeat",{}]`,
+		`[2,"2","Authorize",{}]`,
+	} {
+		err := cpSide.WriteMessage([]byte(frame))
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+	}
+
+	data, err := cpSide.ReadMessage()
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	frame, err := ocppj.ParseFrame(data)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if frame.UniqueId != "2" {
+		t.Errorf(types.ErrorMismatchValue, "2", frame.UniqueId)
+	}
+}
+
+func TestConn_CloseFailsCall(t *testing.T) {
+	t.Parallel()
+
+	cpSide, _ := newPipe()
+	chargePoint := ocppj.NewConn(cpSide, ocppj.RoleChargePoint, nil)
+
+	go func() { _ = chargePoint.Run(context.Background()) }()
+
+	req, err := heartbeat.Req(heartbeat.ReqInput{})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	time.AfterFunc(10*time.Millisecond, func() { _ = chargePoint.Close() })
+
+	err = chargePoint.Call(context.Background(), ocppj.ActionHeartbeat, req, nil)
+	if !errors.Is(err, ocppj.ErrClosed) {
+		t.Errorf(types.ErrorWrapping, err, ocppj.ErrClosed)
+	}
+}
+
+func TestDialAccept(t *testing.T) {
+	t.Parallel()
+
+	ids := make(chan string, 1)
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			transport, err := ocppj.Accept(w, r)
+			if err != nil {
+				return
+			}
+
+			ids <- ocppj.ChargePointId(r)
+
+			conn := ocppj.NewConn(
+				transport,
+				ocppj.RoleCentralSystem,
+				centralSystem,
+			)
+			_ = conn.Run(r.Context())
+		}),
+	)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ocpp"
+
+	transport, err := ocppj.Dial(ctx, url, testChargePoint, nil)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	chargePoint := ocppj.NewConn(transport, ocppj.RoleChargePoint, nil)
+	defer chargePoint.Close()
+
+	go func() { _ = chargePoint.Run(ctx) }()
+
+	if id := <-ids; id != testChargePoint {
+		t.Errorf(types.ErrorMismatchValue, testChargePoint, id)
+	}
+
+	req, err := heartbeat.Req(heartbeat.ReqInput{})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	var conf heartbeat.ConfMessage
+
+	err = chargePoint.Call(ctx, ocppj.ActionHeartbeat, req, &conf)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if conf.CurrentTime.String() != testCurrentTime {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			testCurrentTime,
+			conf.CurrentTime.String(),
+		)
+	}
+}
@@ -0,0 +1,110 @@
+package ocppj_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// longIdTag exceeds the 20 characters of an IdToken.
+const longIdTag = "RFID-TAG-1234567890123"
+
+var errTest = errors.New("test")
+
+func TestErrorFor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload string
+		code    ocppj.ErrorCode
+	}{
+		{"syntax", `{"idTag":`, ocppj.FormationViolation},
+		{"type", `{"idTag":42}`, ocppj.TypeConstraintViolation},
+		{
+			"unknown property",
+			`{"idTag":"RFID-ABC123","foo":1}`,
+			ocppj.FormationViolation,
+		},
+		{
+			"property case",
+			`{"IdTag":"RFID-ABC123"}`,
+			ocppj.FormationViolation,
+		},
+		{
+			"duplicate key",
+			`{"idTag":"RFID-ABC123","idTag":"RFID-XYZ"}`,
+			ocppj.FormationViolation,
+		},
+		{"missing", `{}`, ocppj.OccurenceConstraintViolation},
+		{
+			"invalid",
+			`{"idTag":"` + longIdTag + `"}`,
+			ocppj.PropertyConstraintViolation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ocppj.DecodeRequest(
+				ocppj.ActionAuthorize,
+				[]byte(tt.payload),
+			)
+			if err == nil {
+				t.Fatalf(types.ErrorWantNonNil, "error")
+			}
+
+			callErr := ocppj.ErrorFor(err)
+			if callErr.Code != tt.code {
+				t.Errorf(types.ErrorMismatchValue, tt.code, callErr.Code)
+			}
+		})
+	}
+}
+
+func TestErrorFor_KeepsError(t *testing.T) {
+	t.Parallel()
+
+	want := ocppj.NewError(ocppj.SecurityError, "not allowed")
+
+	got := ocppj.ErrorFor(fmt.Errorf("wrapped: %w", want))
+	if got != want {
+		t.Errorf(types.ErrorMismatchValue, want, got)
+	}
+}
+
+func TestErrorFor_Other(t *testing.T) {
+	t.Parallel()
+
+	got := ocppj.ErrorFor(errTest)
+	if got.Code != ocppj.InternalError {
+		t.Errorf(types.ErrorMismatchValue, ocppj.InternalError, got.Code)
+	}
+
+	if ocppj.ErrorFor(nil) != nil {
+		t.Error("ErrorFor(nil) must be nil")
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	t.Parallel()
+
+	err := fmt.Errorf(
+		"%s: %w",
+		ocppj.ActionAuthorize,
+		ocppj.NewError(ocppj.NotSupported, "no"),
+	)
+
+	if !errors.Is(err, ocppj.NewError(ocppj.NotSupported, "")) {
+		t.Errorf(types.ErrorWrapping, err, ocppj.NotSupported)
+	}
+
+	if errors.Is(err, ocppj.NewError(ocppj.InternalError, "")) {
+		t.Errorf("errors.Is(%v, InternalError) = true", err)
+	}
+}
@@ -0,0 +1,196 @@
+package ocppj_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	testUniqueId = "19223201"
+	testIdTag    = "RFID-TAG-12345"
+)
+
+func TestNewCall_Encode(t *testing.T) {
+	t.Parallel()
+
+	req, err := authorize.Req(authorize.ReqInput{IdTag: testIdTag})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	frame, err := ocppj.NewCall(testUniqueId, ocppj.ActionAuthorize, req)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	data, err := json.Marshal(frame)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	want := `[2,"19223201","Authorize",{"idTag":"RFID-TAG-12345"}]`
+	if string(data) != want {
+		t.Errorf(types.ErrorMismatchValue, want, string(data))
+	}
+}
+
+func TestNewCall_EmptyPayload(t *testing.T) {
+	t.Parallel()
+
+	req, err := heartbeat.Req(heartbeat.ReqInput{})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	frame, err := ocppj.NewCall(testUniqueId, ocppj.ActionHeartbeat, req)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	data, err := json.Marshal(frame)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	want := `[2,"19223201","Heartbeat",{}]`
+	if string(data) != want {
+		t.Errorf(types.ErrorMismatchValue, want, string(data))
+	}
+}
+
+func TestNewCallError_Encode(t *testing.T) {
+	t.Parallel()
+
+	frame := ocppj.NewCallError(
+		testUniqueId,
+		ocppj.NewError(ocppj.NotImplemented, "unknown action"),
+	)
+
+	data, err := json.Marshal(frame)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	want := `[4,"19223201","NotImplemented","unknown action",{}]`
+	if string(data) != want {
+		t.Errorf(types.ErrorMismatchValue, want, string(data))
+	}
+}
+
+func TestParseFrame_Call(t *testing.T) {
+	t.Parallel()
+
+	frame, err := ocppj.ParseFrame(
+		[]byte(`[2, "19223201", "Authorize", {"idTag": "RFID-TAG-12345"}]`),
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if frame.Type != ocppj.MessageTypeCall {
+		t.Errorf(types.ErrorMismatchValue, ocppj.MessageTypeCall, frame.Type)
+	}
+
+	if frame.UniqueId != testUniqueId {
+		t.Errorf(types.ErrorMismatchValue, testUniqueId, frame.UniqueId)
+	}
+
+	if frame.Action != ocppj.ActionAuthorize {
+		t.Errorf(types.ErrorMismatchValue, ocppj.ActionAuthorize, frame.Action)
+	}
+
+	request, err := ocppj.DecodeRequest(frame.Action, frame.Payload)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	req, ok := request.(authorize.ReqMessage)
+	if !ok {
+		t.Fatalf("request = %T, want authorize.ReqMessage", request)
+	}
+
+	if req.IdTag.String() != testIdTag {
+		t.Errorf(types.ErrorMismatchValue, testIdTag, req.IdTag.String())
+	}
+}
+
+func TestParseFrame_CallError(t *testing.T) {
+	t.Parallel()
+
+	frame, err := ocppj.ParseFrame(
+		[]byte(`[4, "19223201", "NotSupported", "no", {}]`),
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	callErr := frame.Err()
+	if callErr == nil || callErr.Code != ocppj.NotSupported {
+		t.Errorf(types.ErrorMismatchValue, ocppj.NotSupported, callErr)
+	}
+}
+
+func TestParseFrame_Malformed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data string
+		code ocppj.ErrorCode
+	}{
+		{"not an array", `{"a":1}`, ocppj.FormationViolation},
+		{"too short", `[2]`, ocppj.FormationViolation},
+		{"empty id", `[2,"","Heartbeat",{}]`, ocppj.FormationViolation},
+		{"unknown type", `[7,"1","Heartbeat",{}]`, ocppj.ProtocolError},
+		{"array payload", `[2,"1","Heartbeat",[]]`, ocppj.FormationViolation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ocppj.ParseFrame([]byte(tt.data))
+
+			var callErr *ocppj.Error
+			if !errors.As(err, &callErr) {
+				t.Fatalf(types.ErrorWantNonNil, "*ocppj.Error")
+			}
+
+			if callErr.Code != tt.code {
+				t.Errorf(types.ErrorMismatchValue, tt.code, callErr.Code)
+			}
+		})
+	}
+}
+
+func TestDecodeRequest_UnknownAction(t *testing.T) {
+	t.Parallel()
+
+	_, err := ocppj.DecodeRequest("SignCertificate", []byte(`{}`))
+	if !errors.Is(err, ocppj.NewError(ocppj.NotImplemented, "")) {
+		t.Errorf(types.ErrorWrapping, err, ocppj.NotImplemented)
+	}
+}
+
+func TestInitiates(t *testing.T) {
+	t.Parallel()
+
+	if !ocppj.Initiates(ocppj.RoleChargePoint, ocppj.ActionBootNotification) {
+		t.Error("BootNotification must be initiated by the Charge Point")
+	}
+
+	if ocppj.Initiates(ocppj.RoleChargePoint, ocppj.ActionReset) {
+		t.Error("Reset must not be initiated by the Charge Point")
+	}
+
+	if !ocppj.Initiates(ocppj.RoleCentralSystem, ocppj.ActionDataTransfer) ||
+		!ocppj.Initiates(ocppj.RoleChargePoint, ocppj.ActionDataTransfer) {
+		t.Error("DataTransfer must be initiated by both roles")
+	}
+}
@@ -0,0 +1,77 @@
+package ocppj
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/aasanchez/ocpp16messages/internal/websocket"
+)
+
+// Subprotocol is the WebSocket subprotocol of OCPP 1.6 JSON.
+const Subprotocol = "ocpp1.6"
+
+// Dial connects a Charge Point to a Central System. The charge point
+// identity is appended to centralSystemURL as the last path segment, e.g.
+// "ws://csms.example.com/ocpp" and "CP001" dial
+// "ws://csms.example.com/ocpp/CP001". Credentials in the URL are sent with
+// HTTP Basic authentication. tlsConfig is used for wss:// URLs and may be
+// nil.
+func Dial(
+	ctx context.Context,
+	centralSystemURL string,
+	chargePointId string,
+	tlsConfig *tls.Config,
+) (Transport, error) {
+	target, err := url.Parse(centralSystemURL)
+	if err != nil {
+		return nil, fmt.Errorf("centralSystemURL: %w", err)
+	}
+
+	target = target.JoinPath(chargePointId)
+
+	conn, err := websocket.Dial(
+		ctx,
+		target.String(),
+		[]string{Subprotocol},
+		tlsConfig,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", chargePointId, err)
+	}
+
+	if conn.Subprotocol() != Subprotocol {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf(
+			"dial %s: %w: subprotocol %q not accepted",
+			chargePointId,
+			websocket.ErrHandshake,
+			Subprotocol,
+		)
+	}
+
+	return conn, nil
+}
+
+// Accept upgrades an HTTP request from a Charge Point to an OCPP-J
+// transport. On failure an HTTP error response has already been written.
+// Use ChargePointId to read the identity from the request path.
+func Accept(w http.ResponseWriter, r *http.Request) (Transport, error) {
+	conn, err := websocket.Upgrade(w, r, []string{Subprotocol})
+	if err != nil {
+		return nil, fmt.Errorf("accept: %w", err)
+	}
+
+	return conn, nil
+}
+
+// ChargePointId returns the charge point identity, the last segment of the
+// request path.
+func ChargePointId(r *http.Request) string {
+	return path.Base(strings.TrimSuffix(r.URL.Path, "/"))
+}
@@ -0,0 +1,25 @@
+package registration
+
+import "time"
+
+// Clock is the source of time of a Service. Tests inject a fake clock to
+// drive the boot and heartbeat timers without waiting.
+type Clock interface {
+	// Now returns the current local time.
+	Now() time.Time
+	// After returns a channel that receives the time once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock of the time package.
+type SystemClock struct{}
+
+// Now returns time.Now().
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After returns time.After(d).
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
@@ -0,0 +1,40 @@
+// Package registration implements both sides of the boot and heartbeat cycle
+// of OCPP 1.6.
+//
+// A Service sends the BootNotification.req of the Charge Point until the
+// Central System accepts it. While the BootNotification.conf status is
+// Pending or Rejected it waits the interval of the confirmation before
+// trying again. Once Accepted, it sends a Heartbeat.req whenever no other
+// request has been sent for the heartbeat interval, as the specification
+// lets any traffic stand in for a heartbeat:
+//
+//	service := registration.New(conn, registration.Config{
+//		BootNotification:  boot,
+//		RetryInterval:     0,
+//		HeartbeatInterval: 0,
+//		Clock:             nil,
+//	})
+//
+//	go service.Run(ctx)
+//
+//	// Other requests go through the Service to postpone the heartbeat.
+//	err := service.Call(ctx, statusnotification.Action, req, &conf)
+//
+// The currentTime of every BootNotification.conf and Heartbeat.conf is
+// compared with the local clock; Offset reports the difference and Now the
+// local time corrected by it. The Clock of Config can be replaced to drive
+// the timers in tests.
+//
+// On the Central System side, a Registry remembers the status of the
+// BootNotification.conf sent to each Charge Point. Its Handler wraps the
+// ocppj.Handler of a connection and refuses the requests of a Pending or
+// Rejected Charge Point with a CALLERROR, or leaves them unanswered with
+// PolicyDrop; its Guard refuses the requests the Central System may not
+// send in those states:
+//
+//	registry := registration.NewRegistry(registration.PolicyCallError)
+//
+//	conn := ocppj.NewConn(transport, ocppj.RoleCentralSystem,
+//		registry.Handler(chargePointId, handle))
+//	calls := registry.Guard(chargePointId, conn)
+package registration
@@ -0,0 +1,222 @@
+package registration
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/remotestoptransaction"
+	"github.com/aasanchez/ocpp16messages/triggermessage"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// Policy selects how a Registry treats a request from a Charge Point that
+// the Central System has not accepted.
+type Policy int
+
+const (
+	// PolicyCallError answers the request with a SecurityError CALLERROR.
+	PolicyCallError Policy = iota
+	// PolicyDrop leaves the request unanswered, as if the Central System
+	// had not received it.
+	PolicyDrop
+)
+
+// Registry is the Central System side of registration. It remembers the
+// status of the last BootNotification.conf sent to each Charge Point and,
+// as the specification requires, refuses other requests of a Charge Point
+// that is Pending or Rejected, keeping it from e.g. starting transactions.
+// While Pending, a request the Central System asked for with an accepted
+// TriggerMessage.req is let through once. A Charge Point the Registry has
+// no status for is treated as accepted, so that a restarted Central System
+// does not lock out Charge Points that reconnect without booting; use
+// SetStatus to restore statuses from storage. A Registry is safe for
+// concurrent use.
+type Registry struct {
+	policy Policy
+
+	mu        sync.Mutex
+	statuses  map[string]types.RegistrationStatus
+	triggered map[string][]string
+}
+
+// NewRegistry returns an empty Registry refusing requests with policy.
+func NewRegistry(policy Policy) *Registry {
+	return &Registry{
+		policy:    policy,
+		mu:        sync.Mutex{},
+		statuses:  map[string]types.RegistrationStatus{},
+		triggered: map[string][]string{},
+	}
+}
+
+// Status returns the status of the last BootNotification.conf sent to a
+// Charge Point, and whether there was one.
+func (r *Registry) Status(
+	chargePointId string,
+) (types.RegistrationStatus, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status, ok := r.statuses[chargePointId]
+
+	return status, ok
+}
+
+// SetStatus records the status of a Charge Point, as if a
+// BootNotification.conf with it had been sent.
+func (r *Registry) SetStatus(
+	chargePointId string,
+	status types.RegistrationStatus,
+) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.statuses[chargePointId] = status
+	delete(r.triggered, chargePointId)
+}
+
+// Forget drops what the Registry knows about a Charge Point.
+func (r *Registry) Forget(chargePointId string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.statuses, chargePointId)
+	delete(r.triggered, chargePointId)
+}
+
+// Handler wraps the ocppj.Handler of the connection of a Charge Point. It
+// records the status of every BootNotification.conf next returns, and
+// refuses other requests according to the Policy while the Charge Point is
+// Pending or Rejected.
+func (r *Registry) Handler(
+	chargePointId string,
+	next ocppj.Handler,
+) ocppj.Handler {
+	return func(ctx context.Context, action string, request any) (any, error) {
+		if action != bootnotification.Action {
+			err := r.admit(chargePointId, action)
+			if err != nil {
+				return nil, err
+			}
+
+			return next(ctx, action, request)
+		}
+
+		confirmation, err := next(ctx, action, request)
+		if err != nil {
+			return confirmation, err
+		}
+
+		switch conf := confirmation.(type) {
+		case bootnotification.ConfMessage:
+			r.SetStatus(chargePointId, conf.Status)
+		case *bootnotification.ConfMessage:
+			r.SetStatus(chargePointId, conf.Status)
+		}
+
+		return confirmation, nil
+	}
+}
+
+// admit reports whether a request of a Charge Point other than
+// BootNotification may be handled.
+func (r *Registry) admit(chargePointId, action string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status, known := r.statuses[chargePointId]
+	if !known || status == types.RegistrationStatusAccepted {
+		return nil
+	}
+
+	triggered := r.triggered[chargePointId]
+	if index := slices.Index(triggered, action); index >= 0 &&
+		status == types.RegistrationStatusPending {
+		r.triggered[chargePointId] = slices.Delete(triggered, index, index+1)
+
+		return nil
+	}
+
+	if r.policy == PolicyDrop {
+		return ocppj.ErrNoAnswer
+	}
+
+	return ocppj.NewError(
+		ocppj.SecurityError,
+		fmt.Sprintf("%s: charge point %s is %s", action, chargePointId, status),
+	)
+}
+
+// Guard returns a Caller that sends the requests of the Central System to a
+// Charge Point through next, refusing those the specification forbids for
+// its registration status.
+func (r *Registry) Guard(chargePointId string, next Caller) *Guard {
+	return &Guard{registry: r, chargePointId: chargePointId, next: next}
+}
+
+// Guard is a Caller checking requests against the registration status of a
+// Charge Point, as returned by Registry.Guard.
+type Guard struct {
+	registry      *Registry
+	chargePointId string
+	next          Caller
+}
+
+// Call sends a request through the next Caller, unless the Charge Point is
+// Rejected, or Pending and the request is a RemoteStartTransaction.req or
+// RemoteStopTransaction.req, in which case it returns an error wrapping
+// ErrNotRegistered. An accepted TriggerMessage.req lets the requested
+// message through the Handler of the Registry while the Charge Point is
+// Pending.
+func (g *Guard) Call(
+	ctx context.Context,
+	action string,
+	request any,
+	confirmation any,
+) error {
+	status, known := g.registry.Status(g.chargePointId)
+
+	switch {
+	case !known || status == types.RegistrationStatusAccepted:
+	case status == types.RegistrationStatusPending &&
+		action != remotestarttransaction.Action &&
+		action != remotestoptransaction.Action:
+	default:
+		return fmt.Errorf(
+			"%s: %w: charge point %s is %s",
+			action,
+			ErrNotRegistered,
+			g.chargePointId,
+			status,
+		)
+	}
+
+	err := g.next.Call(ctx, action, request, confirmation)
+	if err != nil {
+		return err //nolint:wrapcheck // Returned as the next Caller made it.
+	}
+
+	req, isTrigger := request.(triggermessage.ReqMessage)
+	conf, ok := confirmation.(*triggermessage.ConfMessage)
+
+	if isTrigger && ok && conf.Status == types.TriggerMessageStatusAccepted {
+		g.registry.trigger(g.chargePointId, req.RequestedMessage.String())
+	}
+
+	return nil
+}
+
+// trigger lets one request of a Pending Charge Point through.
+func (r *Registry) trigger(chargePointId, action string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.statuses[chargePointId] == types.RegistrationStatusPending {
+		r.triggered[chargePointId] = append(r.triggered[chargePointId], action)
+	}
+}
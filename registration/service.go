@@ -0,0 +1,335 @@
+package registration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// Defaults applied by New to a zero Config.
+const (
+	// DefaultRetryInterval is the wait before re-sending a
+	// BootNotification.req when the Central System gives no interval or
+	// the call fails.
+	DefaultRetryInterval = time.Minute
+	// DefaultHeartbeatInterval is used when the Central System accepts the
+	// Charge Point with an interval of 0.
+	DefaultHeartbeatInterval = 5 * time.Minute
+)
+
+// ErrNotRegistered is returned for a request the registration status of the
+// Charge Point does not allow: by Service.Call before the Central System has
+// accepted the Charge Point, and by Guard.Call.
+var ErrNotRegistered = errors.New("registration: not registered")
+
+// Caller sends a CALL and decodes its answer. *ocppj.Conn implements it.
+type Caller interface {
+	Call(ctx context.Context, action string, request, confirmation any) error
+}
+
+// Config configures a Service.
+type Config struct {
+	// BootNotification is the request the Charge Point registers with.
+	BootNotification bootnotification.ReqMessage
+	// RetryInterval is the wait before re-sending the BootNotification.req
+	// after a Pending or Rejected status with an interval of 0, or after a
+	// failed call. Zero selects DefaultRetryInterval.
+	RetryInterval time.Duration
+	// HeartbeatInterval is used when the Central System accepts with an
+	// interval of 0. Zero selects DefaultHeartbeatInterval.
+	HeartbeatInterval time.Duration
+	// Clock is the source of time. Nil selects SystemClock.
+	Clock Clock
+}
+
+// Service runs the boot and heartbeat loop of a Charge Point: it registers
+// with a BootNotification.req, honours the Pending and Rejected retry
+// intervals, then sends a Heartbeat.req whenever no other request has been
+// sent for the heartbeat interval. Each BootNotification.conf and
+// Heartbeat.conf updates the offset between the local clock and the clock
+// of the Central System. A Service is safe for concurrent use.
+type Service struct {
+	caller Caller
+	config Config
+	sent   chan struct{}
+
+	mu       sync.Mutex
+	status   types.RegistrationStatus
+	interval time.Duration
+	lastSent time.Time
+	offset   time.Duration
+	synced   bool
+}
+
+// New returns a Service sending through caller. Other requests of the Charge
+// Point must be sent through Service.Call so that they postpone the next
+// heartbeat.
+func New(caller Caller, config Config) *Service {
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = DefaultRetryInterval
+	}
+
+	if config.HeartbeatInterval <= 0 {
+		config.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+
+	if config.Clock == nil {
+		config.Clock = SystemClock{}
+	}
+
+	return &Service{
+		caller:   caller,
+		config:   config,
+		sent:     make(chan struct{}, 1),
+		mu:       sync.Mutex{},
+		status:   "",
+		interval: 0,
+		lastSent: time.Time{},
+		offset:   0,
+		synced:   false,
+	}
+}
+
+// Status returns the registration status of the last BootNotification.conf,
+// or "" before the first one.
+func (s *Service) Status() types.RegistrationStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.status
+}
+
+// Interval returns the heartbeat interval in use, or 0 before the Charge
+// Point is accepted.
+func (s *Service) Interval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.interval
+}
+
+// SetInterval changes the heartbeat interval, e.g. after the Central System
+// changed the HeartbeatInterval configuration key. It takes effect from the
+// next heartbeat on.
+func (s *Service) SetInterval(interval time.Duration) {
+	s.mu.Lock()
+	s.interval = interval
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// Offset returns how far the clock of the Central System is ahead of the
+// local clock, as measured by the last BootNotification.conf or
+// Heartbeat.conf, and whether any was received. The request round trip is
+// assumed symmetric.
+func (s *Service) Offset() (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.offset, s.synced
+}
+
+// Now returns the local time corrected by Offset, that is the time of the
+// Central System.
+func (s *Service) Now() time.Time {
+	offset, _ := s.Offset()
+
+	return s.config.Clock.Now().Add(offset)
+}
+
+// Call sends a request of the Charge Point and postpones the next heartbeat.
+// Until the Central System has answered a BootNotification.req with
+// Accepted or Pending, requests other than BootNotification are refused
+// with ErrNotRegistered. While Pending the specification only allows the
+// requests the Central System asks for, e.g. with a TriggerMessage.req;
+// that is left to the caller.
+func (s *Service) Call(
+	ctx context.Context,
+	action string,
+	request any,
+	confirmation any,
+) error {
+	status := s.Status()
+	if action != bootnotification.Action &&
+		status != types.RegistrationStatusAccepted &&
+		status != types.RegistrationStatusPending {
+		return fmt.Errorf("%s: %w", action, ErrNotRegistered)
+	}
+
+	err := s.caller.Call(ctx, action, request, confirmation)
+
+	s.mu.Lock()
+	s.lastSent = s.config.Clock.Now()
+	s.mu.Unlock()
+
+	s.notify()
+
+	return err //nolint:wrapcheck // Returned as the Caller made it.
+}
+
+// Run registers the Charge Point, then sends heartbeats until ctx is done
+// or the connection closes. It returns ctx.Err() or an error wrapping
+// ocppj.ErrClosed. Failed calls other than those are retried: a
+// BootNotification.req after RetryInterval, a Heartbeat.req after the
+// heartbeat interval.
+func (s *Service) Run(ctx context.Context) error {
+	err := s.register(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.drain()
+
+	for {
+		wait := s.untilHeartbeat()
+		if wait <= 0 {
+			err = s.heartbeat(ctx)
+			if fatal(ctx, err) {
+				return err
+			}
+
+			s.drain()
+
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err() //nolint:wrapcheck // The caller's own error.
+		case <-s.config.Clock.After(wait):
+		case <-s.sent:
+		}
+	}
+}
+
+// register sends BootNotification.req until the Central System accepts the
+// Charge Point.
+func (s *Service) register(ctx context.Context) error {
+	for {
+		retry, err := s.boot(ctx)
+		if fatal(ctx, err) {
+			return err
+		}
+
+		if err == nil && s.Status() == types.RegistrationStatusAccepted {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err() //nolint:wrapcheck // The caller's own error.
+		case <-s.config.Clock.After(retry):
+		}
+	}
+}
+
+// boot sends one BootNotification.req and returns the wait before the next
+// one.
+func (s *Service) boot(ctx context.Context) (time.Duration, error) {
+	var conf bootnotification.ConfMessage
+
+	sentAt := s.config.Clock.Now()
+
+	err := s.Call(ctx, bootnotification.Action, s.config.BootNotification,
+		&conf)
+	if err != nil {
+		return s.config.RetryInterval, err
+	}
+
+	interval := time.Duration(conf.Interval.Value()) * time.Second
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status = conf.Status
+	s.sync(sentAt, conf.CurrentTime)
+
+	if conf.Status == types.RegistrationStatusAccepted {
+		if interval == 0 {
+			interval = s.config.HeartbeatInterval
+		}
+
+		s.interval = interval
+	}
+
+	if interval == 0 {
+		return s.config.RetryInterval, nil
+	}
+
+	return interval, nil
+}
+
+// heartbeat sends one Heartbeat.req.
+func (s *Service) heartbeat(ctx context.Context) error {
+	req, err := heartbeat.Req(heartbeat.ReqInput{})
+	if err != nil {
+		return fmt.Errorf("registration: %w", err)
+	}
+
+	var conf heartbeat.ConfMessage
+
+	sentAt := s.config.Clock.Now()
+
+	err = s.Call(ctx, heartbeat.Action, req, &conf)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sync(sentAt, conf.CurrentTime)
+
+	return nil
+}
+
+// sync updates the clock offset from the currentTime of a confirmation to
+// a request sent at sentAt. The caller holds s.mu.
+func (s *Service) sync(sentAt time.Time, currentTime types.DateTime) {
+	received := s.config.Clock.Now()
+	midpoint := sentAt.Add(received.Sub(sentAt) / 2)
+
+	s.offset = currentTime.Value().Sub(midpoint)
+	s.synced = true
+}
+
+// untilHeartbeat returns the time left before the next heartbeat is due.
+func (s *Service) untilHeartbeat() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.interval <= 0 {
+		return s.config.HeartbeatInterval
+	}
+
+	return s.lastSent.Add(s.interval).Sub(s.config.Clock.Now())
+}
+
+// notify wakes Run to reschedule the next heartbeat.
+func (s *Service) notify() {
+	select {
+	case s.sent <- struct{}{}:
+	default:
+	}
+}
+
+// drain discards a pending wake-up caused by the Service's own requests.
+func (s *Service) drain() {
+	select {
+	case <-s.sent:
+	default:
+	}
+}
+
+// fatal reports whether an error ends Run.
+func fatal(ctx context.Context, err error) bool {
+	return err != nil && (ctx.Err() != nil || errors.Is(err, ocppj.ErrClosed))
+}
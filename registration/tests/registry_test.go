@@ -0,0 +1,225 @@
+package registration_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/registration"
+	"github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/starttransaction"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	"github.com/aasanchez/ocpp16messages/triggermessage"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const testChargePoint = "CP001"
+
+// bootHandler answers BootNotification.req with status and every other
+// request with an empty answer.
+func bootHandler(status string) ocppj.Handler {
+	return func(_ context.Context, action string, _ any) (any, error) {
+		if action != bootnotification.Action {
+			return struct{}{}, nil
+		}
+
+		return bootnotification.Conf(bootnotification.ConfInput{
+			Status:      status,
+			CurrentTime: "2025-01-15T10:00:00Z",
+			Interval:    60,
+		})
+	}
+}
+
+// acceptingCaller accepts every TriggerMessage.req.
+type acceptingCaller struct {
+	calls []string
+}
+
+func (a *acceptingCaller) Call(
+	_ context.Context,
+	action string,
+	_ any,
+	confirmation any,
+) error {
+	a.calls = append(a.calls, action)
+
+	if conf, ok := confirmation.(*triggermessage.ConfMessage); ok {
+		conf.Status = types.TriggerMessageStatusAccepted
+	}
+
+	return nil
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		status string
+		policy registration.Policy
+		want   error
+	}{
+		{
+			name:   "accepted",
+			status: "Accepted",
+			policy: registration.PolicyCallError,
+			want:   nil,
+		},
+		{
+			name:   "pending",
+			status: "Pending",
+			policy: registration.PolicyCallError,
+			want:   ocppj.NewError(ocppj.SecurityError, ""),
+		},
+		{
+			name:   "rejected",
+			status: "Rejected",
+			policy: registration.PolicyCallError,
+			want:   ocppj.NewError(ocppj.SecurityError, ""),
+		},
+		{
+			name:   "rejected dropped",
+			status: "Rejected",
+			policy: registration.PolicyDrop,
+			want:   ocppj.ErrNoAnswer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := registration.NewRegistry(tt.policy)
+			handler := registry.Handler(
+				testChargePoint,
+				bootHandler(tt.status),
+			)
+
+			_, err := handler(
+				context.Background(),
+				bootnotification.Action,
+				bootRequest(t),
+			)
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+
+			status, _ := registry.Status(testChargePoint)
+			if status.String() != tt.status {
+				t.Errorf(types.ErrorMismatch, tt.status, status)
+			}
+
+			_, err = handler(
+				context.Background(),
+				starttransaction.Action,
+				nil,
+			)
+			if tt.want == nil && err != nil {
+				t.Errorf(types.ErrorUnexpectedError, err)
+			}
+
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf(types.ErrorWrapping, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_Handler_unknown(t *testing.T) {
+	t.Parallel()
+
+	registry := registration.NewRegistry(registration.PolicyCallError)
+	handler := registry.Handler(testChargePoint, bootHandler("Rejected"))
+
+	_, err := handler(context.Background(), starttransaction.Action, nil)
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+}
+
+func TestRegistry_Guard(t *testing.T) {
+	t.Parallel()
+
+	registry := registration.NewRegistry(registration.PolicyCallError)
+	caller := new(acceptingCaller)
+	guard := registry.Guard(testChargePoint, caller)
+
+	registry.SetStatus(testChargePoint, types.RegistrationStatusPending)
+
+	err := guard.Call(
+		context.Background(),
+		remotestarttransaction.Action,
+		nil,
+		nil,
+	)
+	if !errors.Is(err, registration.ErrNotRegistered) {
+		t.Errorf(types.ErrorWrapping, err, registration.ErrNotRegistered)
+	}
+
+	registry.SetStatus(testChargePoint, types.RegistrationStatusRejected)
+
+	err = guard.Call(context.Background(), triggermessage.Action, nil, nil)
+	if !errors.Is(err, registration.ErrNotRegistered) {
+		t.Errorf(types.ErrorWrapping, err, registration.ErrNotRegistered)
+	}
+
+	if len(caller.calls) != 0 {
+		t.Errorf(types.ErrorMismatch, 0, len(caller.calls))
+	}
+}
+
+func TestRegistry_Guard_trigger(t *testing.T) {
+	t.Parallel()
+
+	registry := registration.NewRegistry(registration.PolicyCallError)
+	guard := registry.Guard(testChargePoint, new(acceptingCaller))
+	handler := registry.Handler(testChargePoint, bootHandler("Pending"))
+
+	_, err := handler(
+		context.Background(),
+		bootnotification.Action,
+		bootRequest(t),
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	req, err := triggermessage.Req(triggermessage.ReqInput{
+		RequestedMessage: "StatusNotification",
+		ConnectorId:      nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	err = guard.Call(
+		context.Background(),
+		triggermessage.Action,
+		req,
+		new(triggermessage.ConfMessage),
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	_, err = handler(
+		context.Background(),
+		statusnotification.Action,
+		statusNotification(t),
+	)
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+
+	_, err = handler(
+		context.Background(),
+		statusnotification.Action,
+		statusNotification(t),
+	)
+	if !errors.Is(err, ocppj.NewError(ocppj.SecurityError, "")) {
+		t.Errorf(types.ErrorWrapping, err, ocppj.SecurityError)
+	}
+}
@@ -0,0 +1,444 @@
+package registration_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/registration"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const waitTimeout = 5 * time.Second
+
+// waiter is a pending fakeClock.After.
+type waiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// fakeClock only moves when Advance is called. Every call to After is
+// reported on added, so that a test knows when the Service is waiting.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []waiter
+	added   chan time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		mu:      sync.Mutex{},
+		now:     time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC),
+		waiters: nil,
+		added:   make(chan time.Duration, 64),
+	}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, waiter{deadline: c.now.Add(d), ch: ch})
+	c.mu.Unlock()
+
+	c.added <- d
+
+	return ch
+}
+
+// Advance moves the clock forward and fires the waiters that are due.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	pending := c.waiters[:0]
+
+	for _, w := range c.waiters {
+		if w.deadline.After(c.now) {
+			pending = append(pending, w)
+
+			continue
+		}
+
+		w.ch <- c.now
+	}
+
+	c.waiters = pending
+}
+
+// waitAfter returns the duration of the next call to After.
+func (c *fakeClock) waitAfter(t *testing.T) time.Duration {
+	t.Helper()
+
+	select {
+	case d := <-c.added:
+		return d
+	case <-time.After(waitTimeout):
+		t.Fatal("Service did not wait on the clock")
+
+		return 0
+	}
+}
+
+// fakeCaller answers BootNotification.req with the scripted statuses and
+// Heartbeat.req with the time of the clock plus offset. Each call takes
+// roundTrip on the clock.
+type fakeCaller struct {
+	clock     *fakeClock
+	offset    time.Duration
+	roundTrip time.Duration
+	err       error
+	calls     chan string
+
+	mu    sync.Mutex
+	boots []bootnotification.ConfInput
+}
+
+func newFakeCaller(
+	clock *fakeClock,
+	boots ...bootnotification.ConfInput,
+) *fakeCaller {
+	return &fakeCaller{
+		clock:     clock,
+		offset:    0,
+		roundTrip: 0,
+		err:       nil,
+		calls:     make(chan string, 64),
+		mu:        sync.Mutex{},
+		boots:     boots,
+	}
+}
+
+func (f *fakeCaller) Call(
+	_ context.Context,
+	action string,
+	_ any,
+	confirmation any,
+) error {
+	f.calls <- action
+
+	if f.err != nil {
+		return f.err
+	}
+
+	csTime := f.clock.Now().Add(f.roundTrip / 2).Add(f.offset).
+		Format(time.RFC3339)
+	f.clock.Advance(f.roundTrip)
+
+	var err error
+
+	switch conf := confirmation.(type) {
+	case *bootnotification.ConfMessage:
+		f.mu.Lock()
+		input := f.boots[0]
+		f.boots = f.boots[1:]
+		f.mu.Unlock()
+
+		input.CurrentTime = csTime
+		*conf, err = bootnotification.Conf(input)
+	case *heartbeat.ConfMessage:
+		*conf, err = heartbeat.Conf(heartbeat.ConfInput{CurrentTime: csTime})
+	}
+
+	return err
+}
+
+// waitCall returns the action of the next call.
+func (f *fakeCaller) waitCall(t *testing.T) string {
+	t.Helper()
+
+	select {
+	case action := <-f.calls:
+		return action
+	case <-time.After(waitTimeout):
+		t.Fatal("Service sent no request")
+
+		return ""
+	}
+}
+
+// noCall fails the test if a call is pending.
+func (f *fakeCaller) noCall(t *testing.T) {
+	t.Helper()
+
+	select {
+	case action := <-f.calls:
+		t.Fatalf(types.ErrorMismatch, "no call", action)
+	default:
+	}
+}
+
+func boot(status string, interval int) bootnotification.ConfInput {
+	return bootnotification.ConfInput{
+		Status:      status,
+		CurrentTime: "",
+		Interval:    interval,
+	}
+}
+
+func bootRequest(t *testing.T) bootnotification.ReqMessage {
+	t.Helper()
+
+	req, err := bootnotification.Req(bootnotification.ReqInput{
+		ChargePointVendor:       "Vendor",
+		ChargePointModel:        "Model",
+		ChargePointSerialNumber: nil,
+		ChargeBoxSerialNumber:   nil,
+		FirmwareVersion:         nil,
+		Iccid:                   nil,
+		Imsi:                    nil,
+		MeterType:               nil,
+		MeterSerialNumber:       nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return req
+}
+
+// start runs a Service until the test ends and returns the error of Run.
+func start(
+	t *testing.T,
+	clock *fakeClock,
+	caller *fakeCaller,
+) (*registration.Service, <-chan error) {
+	t.Helper()
+
+	service := registration.New(caller, registration.Config{
+		BootNotification:  bootRequest(t),
+		RetryInterval:     10 * time.Second,
+		HeartbeatInterval: 0,
+		Clock:             clock,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- service.Run(ctx) }()
+
+	t.Cleanup(cancel)
+
+	return service, done
+}
+
+func statusNotification(t *testing.T) statusnotification.ReqMessage {
+	t.Helper()
+
+	req, err := statusnotification.Req(statusnotification.ReqInput{
+		ConnectorId:     1,
+		ErrorCode:       "NoError",
+		Status:          "Available",
+		Info:            nil,
+		Timestamp:       nil,
+		VendorId:        nil,
+		VendorErrorCode: nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return req
+}
+
+func TestService_Run_pendingThenAccepted(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	caller := newFakeCaller(
+		clock,
+		boot("Pending", 30),
+		boot("Rejected", 0),
+		boot("Accepted", 60),
+	)
+	service, _ := start(t, clock, caller)
+
+	if action := caller.waitCall(t); action != bootnotification.Action {
+		t.Fatalf(types.ErrorMismatch, bootnotification.Action, action)
+	}
+
+	if d := clock.waitAfter(t); d != 30*time.Second {
+		t.Fatalf(types.ErrorMismatch, 30*time.Second, d)
+	}
+
+	if service.Status() != types.RegistrationStatusPending {
+		t.Fatalf(
+			types.ErrorMismatch,
+			types.RegistrationStatusPending,
+			service.Status(),
+		)
+	}
+
+	clock.Advance(30 * time.Second)
+	caller.waitCall(t)
+
+	if d := clock.waitAfter(t); d != 10*time.Second {
+		t.Fatalf(types.ErrorMismatch, 10*time.Second, d)
+	}
+
+	clock.Advance(10 * time.Second)
+	caller.waitCall(t)
+
+	if d := clock.waitAfter(t); d != time.Minute {
+		t.Fatalf(types.ErrorMismatch, time.Minute, d)
+	}
+
+	if service.Status() != types.RegistrationStatusAccepted {
+		t.Fatalf(
+			types.ErrorMismatch,
+			types.RegistrationStatusAccepted,
+			service.Status(),
+		)
+	}
+
+	if service.Interval() != time.Minute {
+		t.Errorf(types.ErrorMismatch, time.Minute, service.Interval())
+	}
+}
+
+func TestService_Run_heartbeat(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	caller := newFakeCaller(clock, boot("Accepted", 60))
+	_, _ = start(t, clock, caller)
+
+	caller.waitCall(t)
+	clock.waitAfter(t)
+
+	clock.Advance(59 * time.Second)
+	caller.noCall(t)
+
+	clock.Advance(time.Second)
+
+	if action := caller.waitCall(t); action != heartbeat.Action {
+		t.Fatalf(types.ErrorMismatch, heartbeat.Action, action)
+	}
+
+	if d := clock.waitAfter(t); d != time.Minute {
+		t.Errorf(types.ErrorMismatch, time.Minute, d)
+	}
+}
+
+func TestService_Call_postponesHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	caller := newFakeCaller(clock, boot("Accepted", 60))
+	service, _ := start(t, clock, caller)
+
+	caller.waitCall(t)
+	clock.waitAfter(t)
+	clock.Advance(40 * time.Second)
+
+	err := service.Call(
+		context.Background(),
+		statusnotification.Action,
+		statusNotification(t),
+		new(statusnotification.ConfMessage),
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	caller.waitCall(t)
+
+	if d := clock.waitAfter(t); d != time.Minute {
+		t.Fatalf(types.ErrorMismatch, time.Minute, d)
+	}
+
+	clock.Advance(20 * time.Second)
+	caller.noCall(t)
+
+	clock.Advance(40 * time.Second)
+
+	if action := caller.waitCall(t); action != heartbeat.Action {
+		t.Errorf(types.ErrorMismatch, heartbeat.Action, action)
+	}
+}
+
+func TestService_Offset(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	caller := newFakeCaller(clock, boot("Accepted", 60))
+	caller.offset = 10 * time.Second
+	caller.roundTrip = 2 * time.Second
+	service, _ := start(t, clock, caller)
+
+	if _, synced := service.Offset(); synced {
+		t.Fatal("Offset reported before any confirmation")
+	}
+
+	caller.waitCall(t)
+	clock.waitAfter(t)
+
+	offset, synced := service.Offset()
+	if !synced || offset != 10*time.Second {
+		t.Fatalf(types.ErrorMismatch, 10*time.Second, offset)
+	}
+
+	want := clock.Now().Add(10 * time.Second)
+	if !service.Now().Equal(want) {
+		t.Errorf(types.ErrorMismatch, want, service.Now())
+	}
+}
+
+func TestService_Call_notRegistered(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	caller := newFakeCaller(clock)
+	service := registration.New(caller, registration.Config{
+		BootNotification:  bootRequest(t),
+		RetryInterval:     0,
+		HeartbeatInterval: 0,
+		Clock:             clock,
+	})
+
+	err := service.Call(
+		context.Background(),
+		statusnotification.Action,
+		statusNotification(t),
+		new(statusnotification.ConfMessage),
+	)
+	if !errors.Is(err, registration.ErrNotRegistered) {
+		t.Fatalf(types.ErrorWrapping, err, registration.ErrNotRegistered)
+	}
+
+	caller.noCall(t)
+}
+
+func TestService_Run_closed(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	caller := newFakeCaller(clock)
+	caller.err = fmt.Errorf("ocppj: %w", ocppj.ErrClosed)
+	_, done := start(t, clock, caller)
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ocppj.ErrClosed) {
+			t.Errorf(types.ErrorWrapping, err, ocppj.ErrClosed)
+		}
+	case <-time.After(waitTimeout):
+		t.Fatal("Run did not return")
+	}
+}
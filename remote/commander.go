@@ -0,0 +1,229 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+)
+
+// ErrNotConnected is returned for a command to a Charge Point no Caller is
+// attached for.
+var ErrNotConnected = errors.New("remote: charge point not connected")
+
+// Caller sends a CALL and decodes its answer. *ocppj.Conn implements it.
+type Caller interface {
+	Call(ctx context.Context, action string, request, confirmation any) error
+}
+
+// Outcome is how a command ended.
+type Outcome string
+
+const (
+	// OutcomeAccepted means the Charge Point accepted the command and its
+	// effect was not waited for.
+	OutcomeAccepted Outcome = "Accepted"
+	// OutcomeCompleted means the Charge Point accepted the command and
+	// reported its effect, e.g. the StartTransaction.req of a remote start.
+	OutcomeCompleted Outcome = "Completed"
+	// OutcomeRejected means the Charge Point refused the command.
+	OutcomeRejected Outcome = "Rejected"
+	// OutcomeTimedOut means the Charge Point accepted the command but did
+	// not report its effect in time.
+	OutcomeTimedOut Outcome = "TimedOut"
+	// OutcomeFailed means the Charge Point accepted the command and later
+	// reported that it failed, e.g. with a DownloadFailed firmware status.
+	OutcomeFailed Outcome = "Failed"
+)
+
+// Config bounds the wait for the effect of each command. A zero duration
+// returns OutcomeAccepted as soon as the Charge Point accepts the command.
+type Config struct {
+	// StartTimeout is the wait for the StartTransaction.req following an
+	// accepted RemoteStartTransaction.req.
+	StartTimeout time.Duration
+	// ResetTimeout is the wait for the BootNotification.req following an
+	// accepted Reset.req.
+	ResetTimeout time.Duration
+	// FirmwareTimeout is the wait for the Installed, DownloadFailed or
+	// InstallationFailed FirmwareStatusNotification.req following an
+	// UpdateFirmware.req. It includes the wait for the retrieveDate.
+	FirmwareTimeout time.Duration
+}
+
+// event is a request of a Charge Point and the confirmation the Central
+// System answered it with.
+type event struct {
+	action       string
+	request      any
+	confirmation any
+}
+
+// waiter waits for a request of a Charge Point.
+type waiter struct {
+	chargePointId string
+	match         func(event) bool
+	found         chan event
+}
+
+// Commander sends commands of the Central System to Charge Points and waits
+// for the requests that report their effect. The connection of each Charge
+// Point is attached with Attach for sending, and its ocppj.Handler wrapped
+// with Handler for observing. A Commander is safe for concurrent use.
+type Commander struct {
+	config Config
+
+	mu      sync.Mutex
+	callers map[string]Caller
+	waiters []*waiter
+}
+
+// New returns a Commander without connections.
+func New(config Config) *Commander {
+	return &Commander{
+		config:  config,
+		mu:      sync.Mutex{},
+		callers: map[string]Caller{},
+		waiters: nil,
+	}
+}
+
+// Attach sets the Caller commands to a Charge Point are sent through,
+// replacing the one of a previous connection.
+func (c *Commander) Attach(chargePointId string, caller Caller) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.callers[chargePointId] = caller
+}
+
+// Detach removes the Caller of a Charge Point if it is still caller, so that
+// a connection closing after its replacement attached does not detach it.
+func (c *Commander) Detach(chargePointId string, caller Caller) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.callers[chargePointId] == caller {
+		delete(c.callers, chargePointId)
+	}
+}
+
+// Handler wraps the ocppj.Handler of the connection of a Charge Point so
+// that the requests it answers complete the commands waiting for them.
+func (c *Commander) Handler(
+	chargePointId string,
+	next ocppj.Handler,
+) ocppj.Handler {
+	return func(ctx context.Context, action string, request any) (any, error) {
+		confirmation, err := next(ctx, action, request)
+		if err == nil {
+			c.observe(chargePointId, event{
+				action:       action,
+				request:      request,
+				confirmation: confirmation,
+			})
+		}
+
+		return confirmation, err
+	}
+}
+
+// call sends a command to a Charge Point.
+func (c *Commander) call(
+	ctx context.Context,
+	chargePointId string,
+	action string,
+	request any,
+	confirmation any,
+) error {
+	c.mu.Lock()
+	caller, ok := c.callers[chargePointId]
+	c.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%s: %w: %s", action, ErrNotConnected, chargePointId)
+	}
+
+	err := caller.Call(ctx, action, request, confirmation)
+	if err != nil {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+
+	return nil
+}
+
+// watch starts waiting for a request of a Charge Point. It is called before
+// the command is sent, so that a request racing the confirmation is not
+// missed.
+func (c *Commander) watch(
+	chargePointId string,
+	match func(event) bool,
+) *waiter {
+	w := &waiter{
+		chargePointId: chargePointId,
+		match:         match,
+		found:         make(chan event, 1),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.waiters = append(c.waiters, w)
+
+	return w
+}
+
+// unwatch stops waiting.
+func (c *Commander) unwatch(w *waiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.waiters = slices.DeleteFunc(c.waiters, func(other *waiter) bool {
+		return other == w
+	})
+}
+
+// observe completes the waiters a request matches.
+func (c *Commander) observe(chargePointId string, e event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.waiters = slices.DeleteFunc(c.waiters, func(w *waiter) bool {
+		if w.chargePointId != chargePointId || !w.match(e) {
+			return false
+		}
+
+		w.found <- e
+
+		return true
+	})
+}
+
+// await waits up to timeout for the request a waiter matches. When ctx ends
+// first, it returns OutcomeTimedOut and ctx.Err().
+func await(
+	ctx context.Context,
+	w *waiter,
+	timeout time.Duration,
+) (event, Outcome, error) {
+	if timeout <= 0 {
+		return event{}, OutcomeAccepted, nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case e := <-w.found:
+		return e, OutcomeCompleted, nil
+	case <-timer.C:
+		return event{}, OutcomeTimedOut, nil
+	case <-ctx.Done():
+		//nolint:wrapcheck // The caller's own error.
+		return event{}, OutcomeTimedOut, ctx.Err()
+	}
+}
@@ -0,0 +1,264 @@
+package remote
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/firmwarestatusnotification"
+	"github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/reset"
+	"github.com/aasanchez/ocpp16messages/starttransaction"
+	"github.com/aasanchez/ocpp16messages/unlockconnector"
+	"github.com/aasanchez/ocpp16messages/updatefirmware"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// RemoteStartResult is the result of Commander.RemoteStart.
+type RemoteStartResult struct {
+	// Outcome is OutcomeCompleted once the transaction started.
+	Outcome Outcome
+	// Conf is the answer of the Charge Point.
+	Conf remotestarttransaction.ConfMessage
+	// StartTransaction is the StartTransaction.req of the started
+	// transaction, set when Outcome is OutcomeCompleted.
+	StartTransaction *starttransaction.ReqMessage
+	// Transaction is the StartTransaction.conf the Central System answered
+	// it with, holding the transactionId, when the Handler returned one.
+	Transaction *starttransaction.ConfMessage
+}
+
+// ResetResult is the result of Commander.Reset.
+type ResetResult struct {
+	// Outcome is OutcomeCompleted once the Charge Point booted again.
+	Outcome Outcome
+	// Conf is the answer of the Charge Point.
+	Conf reset.ConfMessage
+	// BootNotification is the BootNotification.req sent after the reset,
+	// set when Outcome is OutcomeCompleted.
+	BootNotification *bootnotification.ReqMessage
+}
+
+// UnlockResult is the result of Commander.UnlockConnector.
+type UnlockResult struct {
+	// Outcome is OutcomeCompleted when the connector was unlocked, and
+	// OutcomeFailed or OutcomeRejected for UnlockFailed and NotSupported.
+	Outcome Outcome
+	// Conf is the answer of the Charge Point.
+	Conf unlockconnector.ConfMessage
+}
+
+// FirmwareResult is the result of Commander.UpdateFirmware.
+type FirmwareResult struct {
+	// Outcome is OutcomeCompleted once the firmware is Installed and
+	// OutcomeFailed after a DownloadFailed or InstallationFailed status.
+	Outcome Outcome
+	// Conf is the answer of the Charge Point.
+	Conf updatefirmware.ConfMessage
+	// Status is the last firmware status reported, or "" when none was
+	// waited for.
+	Status types.FirmwareStatus
+}
+
+// RemoteStart asks a Charge Point to start a transaction. Once it accepts,
+// RemoteStart waits up to Config.StartTimeout for a StartTransaction.req
+// with the same idTag, on the requested connector when the request names
+// one. An error is returned when the command could not be sent or
+// answered, or when ctx ends while waiting.
+func (c *Commander) RemoteStart(
+	ctx context.Context,
+	chargePointId string,
+	request remotestarttransaction.ReqMessage,
+) (RemoteStartResult, error) {
+	var result RemoteStartResult
+
+	w := c.watch(chargePointId, func(e event) bool {
+		req, ok := e.request.(starttransaction.ReqMessage)
+
+		return ok && startedBy(request, req)
+	})
+	defer c.unwatch(w)
+
+	err := c.call(
+		ctx,
+		chargePointId,
+		remotestarttransaction.Action,
+		request,
+		&result.Conf,
+	)
+	if err != nil {
+		return result, err
+	}
+
+	if result.Conf.Status != types.RemoteStartTransactionStatusAccepted {
+		result.Outcome = OutcomeRejected
+
+		return result, nil
+	}
+
+	e, outcome, err := await(ctx, w, c.config.StartTimeout)
+	result.Outcome = outcome
+
+	if outcome == OutcomeCompleted {
+		req, _ := e.request.(starttransaction.ReqMessage)
+		result.StartTransaction = &req
+		result.Transaction = confirmationOf[starttransaction.ConfMessage](e)
+	}
+
+	return result, err
+}
+
+// Reset asks a Charge Point to reset. Once it accepts, Reset waits up to
+// Config.ResetTimeout for the BootNotification.req of the restarted Charge
+// Point, on this or a new connection. An error is returned when the command
+// could not be sent or answered, or when ctx ends while waiting.
+func (c *Commander) Reset(
+	ctx context.Context,
+	chargePointId string,
+	request reset.ReqMessage,
+) (ResetResult, error) {
+	var result ResetResult
+
+	w := c.watch(chargePointId, func(e event) bool {
+		return e.action == bootnotification.Action
+	})
+	defer c.unwatch(w)
+
+	err := c.call(ctx, chargePointId, reset.Action, request, &result.Conf)
+	if err != nil {
+		return result, err
+	}
+
+	if result.Conf.Status != types.ResetStatusAccepted {
+		result.Outcome = OutcomeRejected
+
+		return result, nil
+	}
+
+	e, outcome, err := await(ctx, w, c.config.ResetTimeout)
+	result.Outcome = outcome
+
+	if outcome == OutcomeCompleted {
+		req, _ := e.request.(bootnotification.ReqMessage)
+		result.BootNotification = &req
+	}
+
+	return result, err
+}
+
+// UnlockConnector asks a Charge Point to unlock a connector. The answer is
+// the effect, so nothing is waited for. An error is returned when the
+// command could not be sent or answered.
+func (c *Commander) UnlockConnector(
+	ctx context.Context,
+	chargePointId string,
+	request unlockconnector.ReqMessage,
+) (UnlockResult, error) {
+	var result UnlockResult
+
+	err := c.call(
+		ctx,
+		chargePointId,
+		unlockconnector.Action,
+		request,
+		&result.Conf,
+	)
+	if err != nil {
+		return result, err
+	}
+
+	switch result.Conf.Status {
+	case types.UnlockStatusUnlocked:
+		result.Outcome = OutcomeCompleted
+	case types.UnlockStatusUnlockFailed:
+		result.Outcome = OutcomeFailed
+	default:
+		result.Outcome = OutcomeRejected
+	}
+
+	return result, nil
+}
+
+// UpdateFirmware asks a Charge Point to update its firmware, which it
+// always accepts. UpdateFirmware then waits up to Config.FirmwareTimeout for
+// a FirmwareStatusNotification.req reporting Installed, DownloadFailed or
+// InstallationFailed. An error is returned when the command could not be
+// sent or answered, or when ctx ends while waiting.
+func (c *Commander) UpdateFirmware(
+	ctx context.Context,
+	chargePointId string,
+	request updatefirmware.ReqMessage,
+) (FirmwareResult, error) {
+	var result FirmwareResult
+
+	w := c.watch(chargePointId, func(e event) bool {
+		req, ok := e.request.(firmwarestatusnotification.ReqMessage)
+
+		return ok && firmwareDone(req.Status)
+	})
+	defer c.unwatch(w)
+
+	err := c.call(
+		ctx,
+		chargePointId,
+		updatefirmware.Action,
+		request,
+		&result.Conf,
+	)
+	if err != nil {
+		return result, err
+	}
+
+	e, outcome, err := await(ctx, w, c.config.FirmwareTimeout)
+	result.Outcome = outcome
+
+	if outcome == OutcomeCompleted {
+		req, _ := e.request.(firmwarestatusnotification.ReqMessage)
+		result.Status = req.Status
+
+		if req.Status != types.FirmwareStatusInstalled {
+			result.Outcome = OutcomeFailed
+		}
+	}
+
+	return result, err
+}
+
+// startedBy reports whether a StartTransaction.req may follow a
+// RemoteStartTransaction.req. IdTags compare case-insensitively.
+func startedBy(
+	remote remotestarttransaction.ReqMessage,
+	start starttransaction.ReqMessage,
+) bool {
+	if !strings.EqualFold(remote.IdTag.String(), start.IdTag.String()) {
+		return false
+	}
+
+	return remote.ConnectorId == nil ||
+		remote.ConnectorId.Value() == start.ConnectorId.Value()
+}
+
+// firmwareDone reports whether a firmware status ends an update.
+func firmwareDone(status types.FirmwareStatus) bool {
+	switch status {
+	case types.FirmwareStatusInstalled,
+		types.FirmwareStatusDownloadFailed,
+		types.FirmwareStatusInstallationFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// confirmationOf returns the confirmation of an event when the Handler
+// returned a T or a *T.
+func confirmationOf[T any](e event) *T {
+	switch conf := e.confirmation.(type) {
+	case T:
+		return &conf
+	case *T:
+		return conf
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,34 @@
+// Package remote sends the commands of a Central System to Charge Points and
+// follows them through to their effect, instead of stopping at the
+// confirmation.
+//
+// An accepted RemoteStartTransaction.req only means the Charge Point will
+// try; the transaction exists once it sends a StartTransaction.req.
+// Commander.RemoteStart sends the command, then waits for that request, and
+// returns a typed Outcome: OutcomeCompleted with the StartTransaction.req,
+// OutcomeTimedOut when the Charge Point accepted but started nothing in
+// time, or OutcomeRejected. Reset waits for the BootNotification.req of the
+// restarted Charge Point, UpdateFirmware for the final
+// FirmwareStatusNotification.req, and UnlockConnector maps the answer onto
+// an Outcome.
+//
+// A Commander learns of the requests of a Charge Point through the
+// ocppj.Handler its Handler method wraps, and sends through the Caller
+// attached for it:
+//
+//	commander := remote.New(remote.Config{
+//		StartTimeout:    time.Minute,
+//		ResetTimeout:    5 * time.Minute,
+//		FirmwareTimeout: time.Hour,
+//	})
+//
+//	conn := ocppj.NewConn(transport, ocppj.RoleCentralSystem,
+//		commander.Handler(chargePointId, handle))
+//	commander.Attach(chargePointId, conn)
+//	defer commander.Detach(chargePointId, conn)
+//
+//	result, err := commander.RemoteStart(ctx, chargePointId, req)
+//	if err == nil && result.Outcome == remote.OutcomeCompleted {
+//		// result.Transaction.TransactionId
+//	}
+package remote
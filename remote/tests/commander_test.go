@@ -0,0 +1,389 @@
+package remote_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/firmwarestatusnotification"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/remote"
+	"github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/reset"
+	"github.com/aasanchez/ocpp16messages/starttransaction"
+	"github.com/aasanchez/ocpp16messages/unlockconnector"
+	"github.com/aasanchez/ocpp16messages/updatefirmware"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	testChargePoint = "CP001"
+	testIdTag       = "TAG001"
+	testTimeout     = 5 * time.Second
+	testTime        = "2025-01-15T10:00:00Z"
+)
+
+// fakeChargePoint answers commands with status and then sends the follow-up
+// requests through the Handler of the Commander, as a Charge Point would on
+// its connection.
+type fakeChargePoint struct {
+	status   string
+	handler  ocppj.Handler
+	followUp []followUp
+}
+
+// followUp is a request a fakeChargePoint sends after its confirmation.
+type followUp struct {
+	action  string
+	request any
+}
+
+func (f *fakeChargePoint) Call(
+	_ context.Context,
+	_ string,
+	_ any,
+	confirmation any,
+) error {
+	var err error
+
+	switch conf := confirmation.(type) {
+	case *remotestarttransaction.ConfMessage:
+		*conf, err = remotestarttransaction.Conf(
+			remotestarttransaction.ConfInput{Status: f.status},
+		)
+	case *reset.ConfMessage:
+		*conf, err = reset.Conf(reset.ConfInput{Status: f.status})
+	case *unlockconnector.ConfMessage:
+		*conf, err = unlockconnector.Conf(
+			unlockconnector.ConfInput{Status: f.status},
+		)
+	}
+
+	go func() {
+		for _, request := range f.followUp {
+			_, _ = f.handler(
+				context.Background(),
+				request.action,
+				request.request,
+			)
+		}
+	}()
+
+	return err
+}
+
+// centralSystem answers StartTransaction.req with transactionId 42 and
+// every other request with an empty answer.
+func centralSystem(_ context.Context, action string, _ any) (any, error) {
+	if action != starttransaction.Action {
+		return struct{}{}, nil
+	}
+
+	return starttransaction.Conf(starttransaction.ConfInput{
+		TransactionId: 42,
+		Status:        "Accepted",
+		ExpiryDate:    nil,
+		ParentIdTag:   nil,
+	})
+}
+
+// connect attaches a fakeChargePoint to a new Commander.
+func connect(
+	config remote.Config,
+	status string,
+	followUps ...followUp,
+) *remote.Commander {
+	commander := remote.New(config)
+	chargePoint := &fakeChargePoint{
+		status:   status,
+		handler:  commander.Handler(testChargePoint, centralSystem),
+		followUp: followUps,
+	}
+
+	commander.Attach(testChargePoint, chargePoint)
+
+	return commander
+}
+
+func remoteStart(
+	t *testing.T,
+	connectorId *int,
+) remotestarttransaction.ReqMessage {
+	t.Helper()
+
+	req, err := remotestarttransaction.Req(remotestarttransaction.ReqInput{
+		IdTag:           testIdTag,
+		ConnectorId:     connectorId,
+		ChargingProfile: nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return req
+}
+
+func startTransaction(t *testing.T, connectorId int) followUp {
+	t.Helper()
+
+	req, err := starttransaction.Req(starttransaction.ReqInput{
+		ConnectorId:   connectorId,
+		IdTag:         testIdTag,
+		MeterStart:    0,
+		Timestamp:     testTime,
+		ReservationId: nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return followUp{action: starttransaction.Action, request: req}
+}
+
+func TestCommander_RemoteStart(t *testing.T) {
+	t.Parallel()
+
+	connectorId := 2
+	commander := connect(
+		remote.Config{
+			StartTimeout:    testTimeout,
+			ResetTimeout:    0,
+			FirmwareTimeout: 0,
+		},
+		"Accepted",
+		startTransaction(t, 1),
+		startTransaction(t, 2),
+	)
+
+	result, err := commander.RemoteStart(
+		context.Background(),
+		testChargePoint,
+		remoteStart(t, &connectorId),
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if result.Outcome != remote.OutcomeCompleted {
+		t.Fatalf(types.ErrorMismatch, remote.OutcomeCompleted, result.Outcome)
+	}
+
+	if result.StartTransaction.ConnectorId.Value() != 2 {
+		t.Errorf(
+			types.ErrorMismatch,
+			2,
+			result.StartTransaction.ConnectorId.Value(),
+		)
+	}
+
+	if result.Transaction == nil ||
+		result.Transaction.TransactionId.Value() != 42 {
+		t.Errorf(types.ErrorMismatch, 42, result.Transaction)
+	}
+}
+
+func TestCommander_RemoteStart_outcomes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		status  string
+		timeout time.Duration
+		want    remote.Outcome
+	}{
+		{"rejected", "Rejected", testTimeout, remote.OutcomeRejected},
+		{"not waited", "Accepted", 0, remote.OutcomeAccepted},
+		{"no start", "Accepted", 20 * time.Millisecond, remote.OutcomeTimedOut},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			commander := connect(
+				remote.Config{
+					StartTimeout:    tt.timeout,
+					ResetTimeout:    0,
+					FirmwareTimeout: 0,
+				},
+				tt.status,
+			)
+
+			result, err := commander.RemoteStart(
+				context.Background(),
+				testChargePoint,
+				remoteStart(t, nil),
+			)
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+
+			if result.Outcome != tt.want {
+				t.Errorf(types.ErrorMismatch, tt.want, result.Outcome)
+			}
+		})
+	}
+}
+
+func TestCommander_Reset(t *testing.T) {
+	t.Parallel()
+
+	boot, err := bootnotification.Req(bootnotification.ReqInput{
+		ChargePointVendor:       "Vendor",
+		ChargePointModel:        "Model",
+		ChargePointSerialNumber: nil,
+		ChargeBoxSerialNumber:   nil,
+		FirmwareVersion:         nil,
+		Iccid:                   nil,
+		Imsi:                    nil,
+		MeterType:               nil,
+		MeterSerialNumber:       nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	commander := connect(
+		remote.Config{
+			StartTimeout:    0,
+			ResetTimeout:    testTimeout,
+			FirmwareTimeout: 0,
+		},
+		"Accepted",
+		followUp{action: bootnotification.Action, request: boot},
+	)
+
+	req, err := reset.Req(reset.ReqInput{Type: "Hard"})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	result, err := commander.Reset(context.Background(), testChargePoint, req)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if result.Outcome != remote.OutcomeCompleted ||
+		result.BootNotification == nil {
+		t.Errorf(types.ErrorMismatch, remote.OutcomeCompleted, result.Outcome)
+	}
+}
+
+func TestCommander_UnlockConnector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status string
+		want   remote.Outcome
+	}{
+		{"Unlocked", remote.OutcomeCompleted},
+		{"UnlockFailed", remote.OutcomeFailed},
+		{"NotSupported", remote.OutcomeRejected},
+	}
+
+	req, err := unlockconnector.Req(unlockconnector.ReqInput{ConnectorId: 1})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	for _, tt := range tests {
+		commander := connect(remote.Config{
+			StartTimeout:    0,
+			ResetTimeout:    0,
+			FirmwareTimeout: 0,
+		}, tt.status)
+
+		result, err := commander.UnlockConnector(
+			context.Background(),
+			testChargePoint,
+			req,
+		)
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+
+		if result.Outcome != tt.want {
+			t.Errorf(types.ErrorMismatch, tt.want, result.Outcome)
+		}
+	}
+}
+
+func TestCommander_UpdateFirmware(t *testing.T) {
+	t.Parallel()
+
+	var followUps []followUp
+
+	for _, status := range []string{
+		"Downloading",
+		"Downloaded",
+		"Installing",
+		"InstallationFailed",
+	} {
+		req, err := firmwarestatusnotification.Req(
+			firmwarestatusnotification.ReqInput{Status: status},
+		)
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+
+		followUps = append(followUps, followUp{
+			action:  firmwarestatusnotification.Action,
+			request: req,
+		})
+	}
+
+	commander := connect(
+		remote.Config{
+			StartTimeout:    0,
+			ResetTimeout:    0,
+			FirmwareTimeout: testTimeout,
+		},
+		"",
+		followUps...,
+	)
+
+	req, err := updatefirmware.Req(updatefirmware.ReqInput{
+		Location:      "https://example.com/firmware.bin",
+		RetrieveDate:  testTime,
+		Retries:       nil,
+		RetryInterval: nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	result, err := commander.UpdateFirmware(
+		context.Background(),
+		testChargePoint,
+		req,
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if result.Outcome != remote.OutcomeFailed ||
+		result.Status != types.FirmwareStatusInstallationFailed {
+		t.Errorf(types.ErrorMismatch, remote.OutcomeFailed, result)
+	}
+}
+
+func TestCommander_notConnected(t *testing.T) {
+	t.Parallel()
+
+	commander := remote.New(remote.Config{
+		StartTimeout:    0,
+		ResetTimeout:    0,
+		FirmwareTimeout: 0,
+	})
+
+	_, err := commander.RemoteStart(
+		context.Background(),
+		testChargePoint,
+		remoteStart(t, nil),
+	)
+	if !errors.Is(err, remote.ErrNotConnected) {
+		t.Errorf(types.ErrorWrapping, err, remote.ErrNotConnected)
+	}
+}
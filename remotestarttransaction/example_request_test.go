@@ -16,8 +16,9 @@ const (
 // with only the required idTag field.
 func ExampleReq() {
 	req, err := rst.Req(rst.ReqInput{
-		IdTag:       testExampleValidIdTag,
-		ConnectorId: nil,
+		IdTag:           testExampleValidIdTag,
+		ConnectorId:     nil,
+		ChargingProfile: nil,
 	})
 	if err != nil {
 		fmt.Println(err)
@@ -36,8 +37,9 @@ func ExampleReq_withConnectorId() {
 	connectorId := connectorIdOne
 
 	req, err := rst.Req(rst.ReqInput{
-		IdTag:       testExampleValidIdTag,
-		ConnectorId: &connectorId,
+		IdTag:           testExampleValidIdTag,
+		ConnectorId:     &connectorId,
+		ChargingProfile: nil,
 	})
 	if err != nil {
 		fmt.Println(err)
@@ -55,7 +57,11 @@ func ExampleReq_withConnectorId() {
 // ExampleReq_emptyIdTag demonstrates the error returned when
 // an empty idTag is provided.
 func ExampleReq_emptyIdTag() {
-	_, err := rst.Req(rst.ReqInput{IdTag: "", ConnectorId: nil})
+	_, err := rst.Req(rst.ReqInput{
+		IdTag:           "",
+		ConnectorId:     nil,
+		ChargingProfile: nil,
+	})
 	if err != nil {
 		fmt.Println(err)
 	}
@@ -68,8 +74,9 @@ func ExampleReq_emptyIdTag() {
 func ExampleReq_idTagTooLong() {
 	// 23 chars, max is 20
 	_, err := rst.Req(rst.ReqInput{
-		IdTag:       "RFID-ABC123456789012345",
-		ConnectorId: nil,
+		IdTag:           "RFID-ABC123456789012345",
+		ConnectorId:     nil,
+		ChargingProfile: nil,
 	})
 	if err != nil {
 		fmt.Println("idTag: exceeds maximum length")
@@ -84,8 +91,9 @@ func ExampleReq_invalidConnectorId() {
 	connectorId := connectorIdNegative
 
 	_, err := rst.Req(rst.ReqInput{
-		IdTag:       testExampleValidIdTag,
-		ConnectorId: &connectorId,
+		IdTag:           testExampleValidIdTag,
+		ConnectorId:     &connectorId,
+		ChargingProfile: nil,
 	})
 	if err != nil {
 		fmt.Println("connectorId: invalid value")
@@ -0,0 +1,78 @@
+package remotestarttransaction
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		IdTag:           m.IdTag.String(),
+		ConnectorId:     wire.OptionalInt(m.ConnectorId),
+		ChargingProfile: chargingProfileInput(m.ChargingProfile),
+	}
+}
+
+// chargingProfileInput converts an optional charging profile back to its
+// input form.
+func chargingProfileInput(
+	profile *types.ChargingProfile,
+) *types.ChargingProfileInput {
+	if profile == nil {
+		return nil
+	}
+
+	input := wire.ChargingProfileInput(*profile)
+
+	return &input
+}
+
+// WithIdTag returns a copy of the message with IdTag set to value. The copy
+// is validated by Req.
+func (m ReqMessage) WithIdTag(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.IdTag = value
+
+	return Req(input)
+}
+
+// WithConnectorId returns a copy of the message with ConnectorId set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithConnectorId(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ConnectorId = &value
+
+	return Req(input)
+}
+
+// WithChargingProfile returns a copy of the message with ChargingProfile set
+// to value. The copy is validated by Req.
+func (m ReqMessage) WithChargingProfile(
+	value types.ChargingProfileInput,
+) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ChargingProfile = &value
+
+	return Req(input)
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	return ConfInput{
+		Status: m.Status.String(),
+	}
+}
+
+// WithStatus returns a copy of the message with Status set to value. The
+// copy is validated by Conf.
+func (m ConfMessage) WithStatus(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.Status = value
+
+	return Conf(input)
+}
@@ -0,0 +1,85 @@
+package remotestarttransaction
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// reqWire is the OCPP-J payload of RemoteStartTransaction.req.
+type reqWire struct {
+	ConnectorId     *uint16               `json:"connectorId,omitempty"`
+	IdTag           string                `json:"idTag"`
+	ChargingProfile *wire.ChargingProfile `json:"chargingProfile,omitempty"`
+}
+
+// confWire is the OCPP-J payload of RemoteStartTransaction.conf.
+type confWire struct {
+	Status string `json:"status"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J RemoteStartTransaction.req
+// payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	var chargingProfile *wire.ChargingProfile
+
+	if m.ChargingProfile != nil {
+		profile := wire.NewChargingProfile(*m.ChargingProfile)
+		chargingProfile = &profile
+	}
+
+	return wire.Marshal(reqWire{
+		ConnectorId:     wire.OptionalInteger(m.ConnectorId),
+		IdTag:           m.IdTag.String(),
+		ChargingProfile: chargingProfile,
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J RemoteStartTransaction.req payload and
+// validates it with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, reqFromWire)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J RemoteStartTransaction.conf
+// payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		Status: m.Status.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J RemoteStartTransaction.conf payload and
+// validates it with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// reqPayload is a decoded RemoteStartTransaction.req payload.
+type reqPayload struct {
+	IdTag           string
+	ConnectorId     *int
+	ChargingProfile *types.ChargingProfileInput
+}
+
+// reqFromWire converts a decoded payload into ReqInput and validates it.
+func reqFromWire(payload reqPayload) (ReqMessage, error) {
+	return Req(ReqInput{
+		IdTag:           payload.IdTag,
+		ConnectorId:     payload.ConnectorId,
+		ChargingProfile: payload.ChargingProfile,
+	})
+}
@@ -21,12 +21,16 @@ type ReqInput struct {
 	// Optional: The connector on which to start the transaction.
 	// If not provided, the Charge Point will choose an available connector.
 	ConnectorId *int
+	// Optional: The charging profile to use for the transaction. The
+	// specification requires its purpose to be TxProfile.
+	ChargingProfile *types.ChargingProfileInput
 }
 
 // ReqMessage represents an OCPP 1.6 RemoteStartTransaction.req message.
 type ReqMessage struct {
-	IdTag       types.CiString20Type
-	ConnectorId *types.Integer
+	IdTag           types.CiString20Type
+	ConnectorId     *types.Integer
+	ChargingProfile *types.ChargingProfile
 }
 
 // Req creates a RemoteStartTransaction.req message from the given input.
@@ -36,6 +40,8 @@ type ReqMessage struct {
 //   - IdTag exceeds 20 characters
 //   - IdTag contains non-printable ASCII characters
 //   - ConnectorId (if provided) is negative or exceeds uint16 max value (65535)
+//   - ChargingProfile (if provided) is invalid, as SetChargingProfile
+//     validates it
 func Req(input ReqInput) (ReqMessage, error) {
 	var errs []error
 
@@ -50,13 +56,23 @@ func Req(input ReqInput) (ReqMessage, error) {
 		connectorId, errs = validateConnectorId(*input.ConnectorId, errs)
 	}
 
+	var chargingProfile *types.ChargingProfile
+
+	if input.ChargingProfile != nil {
+		chargingProfile, errs = validateChargingProfile(
+			*input.ChargingProfile,
+			errs,
+		)
+	}
+
 	if len(errs) > errCountZero {
 		return ReqMessage{}, errors.Join(errs...)
 	}
 
 	return ReqMessage{
-		IdTag:       idTag,
-		ConnectorId: connectorId,
+		IdTag:           idTag,
+		ConnectorId:     connectorId,
+		ChargingProfile: chargingProfile,
 	}, nil
 }
 
@@ -72,3 +88,16 @@ func validateConnectorId(
 
 	return &val, errs
 }
+
+// validateChargingProfile validates the chargingProfile field.
+func validateChargingProfile(
+	input types.ChargingProfileInput,
+	errs []error,
+) (*types.ChargingProfile, []error) {
+	profile, err := types.NewChargingProfile(input)
+	if err != nil {
+		return nil, append(errs, fmt.Errorf("chargingProfile: %w", err))
+	}
+
+	return &profile, errs
+}
@@ -18,6 +18,7 @@ const (
 	testConnectorIdNeg    = -1
 	errIdTag              = "idTag"
 	errConnectorId        = "connectorId"
+	errChargingProfile    = "chargingProfile"
 	errExceedsMaxLength   = "exceeds maximum length"
 	errNonPrintableASCII  = "non-printable ASCII"
 	fieldNameConnectorId  = "ConnectorId"
@@ -28,8 +29,9 @@ func TestReq_Valid_IdTagOnly(t *testing.T) {
 	t.Parallel()
 
 	req, err := rst.Req(rst.ReqInput{
-		IdTag:       testValidIdTag,
-		ConnectorId: nil,
+		IdTag:           testValidIdTag,
+		ConnectorId:     nil,
+		ChargingProfile: nil,
 	})
 	if err != nil {
 		t.Errorf(types.ErrorUnexpectedError, err)
@@ -50,8 +52,9 @@ func TestReq_Valid_WithConnectorId(t *testing.T) {
 	connectorId := testConnectorIdOne
 
 	req, err := rst.Req(rst.ReqInput{
-		IdTag:       testValidIdTag,
-		ConnectorId: &connectorId,
+		IdTag:           testValidIdTag,
+		ConnectorId:     &connectorId,
+		ChargingProfile: nil,
 	})
 	if err != nil {
 		t.Errorf(types.ErrorUnexpectedError, err)
@@ -80,8 +83,9 @@ func TestReq_Valid_ConnectorIdZero(t *testing.T) {
 	connectorId := testConnectorIdZero
 
 	req, err := rst.Req(rst.ReqInput{
-		IdTag:       testValidIdTag,
-		ConnectorId: &connectorId,
+		IdTag:           testValidIdTag,
+		ConnectorId:     &connectorId,
+		ChargingProfile: nil,
 	})
 	if err != nil {
 		t.Errorf(types.ErrorUnexpectedError, err)
@@ -106,8 +110,9 @@ func TestReq_Valid_ConnectorIdMax(t *testing.T) {
 	connectorId := testConnectorIdMax
 
 	req, err := rst.Req(rst.ReqInput{
-		IdTag:       testValidIdTag,
-		ConnectorId: &connectorId,
+		IdTag:           testValidIdTag,
+		ConnectorId:     &connectorId,
+		ChargingProfile: nil,
 	})
 	if err != nil {
 		t.Errorf(types.ErrorUnexpectedError, err)
@@ -129,7 +134,11 @@ func TestReq_Valid_ConnectorIdMax(t *testing.T) {
 func TestReq_EmptyIdTag(t *testing.T) {
 	t.Parallel()
 
-	_, err := rst.Req(rst.ReqInput{IdTag: "", ConnectorId: nil})
+	_, err := rst.Req(rst.ReqInput{
+		IdTag:           "",
+		ConnectorId:     nil,
+		ChargingProfile: nil,
+	})
 	if err == nil {
 		t.Errorf(types.ErrorWantNil, "empty idTag")
 	}
@@ -144,8 +153,9 @@ func TestReq_IdTagTooLong(t *testing.T) {
 
 	// 23 chars, max is 20
 	_, err := rst.Req(rst.ReqInput{
-		IdTag:       "RFID-ABC123456789012345",
-		ConnectorId: nil,
+		IdTag:           "RFID-ABC123456789012345",
+		ConnectorId:     nil,
+		ChargingProfile: nil,
 	})
 	if err == nil {
 		t.Errorf(types.ErrorWantNil, "IdTag too long")
@@ -160,7 +170,11 @@ func TestReq_IdTagInvalidCharacters(t *testing.T) {
 	t.Parallel()
 
 	// Contains null byte
-	_, err := rst.Req(rst.ReqInput{IdTag: "RFID\x00ABC", ConnectorId: nil})
+	_, err := rst.Req(rst.ReqInput{
+		IdTag:           "RFID\x00ABC",
+		ConnectorId:     nil,
+		ChargingProfile: nil,
+	})
 	if err == nil {
 		t.Errorf(types.ErrorWantNil, "non-printable chars in idTag")
 	}
@@ -176,8 +190,9 @@ func TestReq_ConnectorIdNegative(t *testing.T) {
 	connectorId := testConnectorIdNeg
 
 	_, err := rst.Req(rst.ReqInput{
-		IdTag:       testValidIdTag,
-		ConnectorId: &connectorId,
+		IdTag:           testValidIdTag,
+		ConnectorId:     &connectorId,
+		ChargingProfile: nil,
 	})
 	if err == nil {
 		t.Errorf(types.ErrorWantNil, "negative connectorId")
@@ -194,8 +209,9 @@ func TestReq_ConnectorIdExceedsMax(t *testing.T) {
 	connectorId := testConnectorIdOver
 
 	_, err := rst.Req(rst.ReqInput{
-		IdTag:       testValidIdTag,
-		ConnectorId: &connectorId,
+		IdTag:           testValidIdTag,
+		ConnectorId:     &connectorId,
+		ChargingProfile: nil,
 	})
 	if err == nil {
 		t.Errorf(types.ErrorWantNil, "connectorId exceeds max")
@@ -212,8 +228,9 @@ func TestReq_MultipleErrors_EmptyIdTagAndInvalidConnectorId(t *testing.T) {
 	connectorId := testConnectorIdNeg
 
 	_, err := rst.Req(rst.ReqInput{
-		IdTag:       "",
-		ConnectorId: &connectorId,
+		IdTag:           "",
+		ConnectorId:     &connectorId,
+		ChargingProfile: nil,
 	})
 	if err == nil {
 		t.Errorf(types.ErrorWantNil, "empty idTag and negative connectorId")
@@ -228,3 +245,81 @@ func TestReq_MultipleErrors_EmptyIdTagAndInvalidConnectorId(t *testing.T) {
 		t.Errorf(types.ErrorWantContains, err, errConnectorId)
 	}
 }
+
+func txChargingProfileInput(limit float64) types.ChargingProfileInput {
+	return types.ChargingProfileInput{
+		ChargingProfileId:      testConnectorIdOne,
+		TransactionId:          nil,
+		StackLevel:             testConnectorIdZero,
+		ChargingProfilePurpose: "TxProfile",
+		ChargingProfileKind:    "Relative",
+		RecurrencyKind:         nil,
+		ValidFrom:              nil,
+		ValidTo:                nil,
+		ChargingSchedule: types.ChargingScheduleInput{
+			Duration:         nil,
+			ChargingRateUnit: "A",
+			ChargingSchedulePeriod: []types.ChargingSchedulePeriodInput{
+				{
+					StartPeriod:  testConnectorIdZero,
+					Limit:        limit,
+					NumberPhases: nil,
+				},
+			},
+			MinChargingRate: nil,
+			StartSchedule:   nil,
+		},
+	}
+}
+
+func TestReq_Valid_WithChargingProfile(t *testing.T) {
+	t.Parallel()
+
+	profile := txChargingProfileInput(16)
+
+	req, err := rst.Req(rst.ReqInput{
+		IdTag:           testValidIdTag,
+		ConnectorId:     nil,
+		ChargingProfile: &profile,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if req.ChargingProfile == nil {
+		t.Fatalf(types.ErrorWantNonNil, "ChargingProfile")
+	}
+
+	purpose := req.ChargingProfile.ChargingProfilePurpose().String()
+	if purpose != "TxProfile" {
+		t.Errorf(types.ErrorMismatch, "TxProfile", purpose)
+	}
+
+	roundTrip, err := rst.Req(req.ToInput())
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if !roundTrip.Equal(req) {
+		t.Errorf(types.ErrorMismatch, req, roundTrip)
+	}
+}
+
+func TestReq_InvalidChargingProfile(t *testing.T) {
+	t.Parallel()
+
+	profile := txChargingProfileInput(-1)
+
+	_, err := rst.Req(rst.ReqInput{
+		IdTag:           testValidIdTag,
+		ConnectorId:     nil,
+		ChargingProfile: &profile,
+	})
+	if err == nil {
+		t.Fatalf(types.ErrorWantNil, "invalid chargingProfile")
+	}
+
+	if !strings.Contains(err.Error(), errChargingProfile) {
+		t.Errorf(types.ErrorWantContains, err, errChargingProfile)
+	}
+}
@@ -0,0 +1,79 @@
+package reservenow
+
+import "github.com/aasanchez/ocpp16messages/internal/wire"
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		ReservationId: int(m.ReservationId.Value()),
+		ConnectorId:   int(m.ConnectorId.Value()),
+		IdTag:         m.IdTag.String(),
+		ExpiryDate:    m.ExpiryDate.String(),
+		ParentIdTag:   wire.OptionalString(m.ParentIdTag),
+	}
+}
+
+// WithReservationId returns a copy of the message with ReservationId set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithReservationId(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ReservationId = value
+
+	return Req(input)
+}
+
+// WithConnectorId returns a copy of the message with ConnectorId set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithConnectorId(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ConnectorId = value
+
+	return Req(input)
+}
+
+// WithIdTag returns a copy of the message with IdTag set to value. The copy
+// is validated by Req.
+func (m ReqMessage) WithIdTag(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.IdTag = value
+
+	return Req(input)
+}
+
+// WithExpiryDate returns a copy of the message with ExpiryDate set to value.
+// The copy is validated by Req.
+func (m ReqMessage) WithExpiryDate(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ExpiryDate = value
+
+	return Req(input)
+}
+
+// WithParentIdTag returns a copy of the message with ParentIdTag set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithParentIdTag(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ParentIdTag = &value
+
+	return Req(input)
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	return ConfInput{
+		Status: m.Status.String(),
+	}
+}
+
+// WithStatus returns a copy of the message with Status set to value. The
+// copy is validated by Conf.
+func (m ConfMessage) WithStatus(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.Status = value
+
+	return Conf(input)
+}
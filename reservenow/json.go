@@ -0,0 +1,63 @@
+package reservenow
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of ReserveNow.req.
+type reqWire struct {
+	ConnectorId   uint16  `json:"connectorId"`
+	ExpiryDate    string  `json:"expiryDate"`
+	IdTag         string  `json:"idTag"`
+	ParentIdTag   *string `json:"parentIdTag,omitempty"`
+	ReservationId uint16  `json:"reservationId"`
+}
+
+// confWire is the OCPP-J payload of ReserveNow.conf.
+type confWire struct {
+	Status string `json:"status"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J ReserveNow.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		ConnectorId:   m.ConnectorId.Value(),
+		ExpiryDate:    m.ExpiryDate.String(),
+		IdTag:         m.IdTag.String(),
+		ParentIdTag:   wire.OptionalString(m.ParentIdTag),
+		ReservationId: m.ReservationId.Value(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J ReserveNow.req payload and validates it with
+// Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J ReserveNow.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		Status: m.Status.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J ReserveNow.conf payload and validates it with
+// Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+)
+
+// ErrMismatch is wrapped by *Mismatch.
+var ErrMismatch = errors.New("schema: schema and message package disagree")
+
+// Mismatch reports a payload accepted by only one of the schema and the
+// message package. Exactly one of SchemaErr and LibraryErr is nil.
+type Mismatch struct {
+	Action string
+	Kind   Kind
+	// SchemaErr is the Validate error, nil when the schema accepts.
+	SchemaErr error
+	// LibraryErr is the decoding error of the message package, nil when the
+	// Req or Conf constructor accepts.
+	LibraryErr error
+}
+
+// Error describes which side rejected the payload.
+func (m *Mismatch) Error() string {
+	if m.SchemaErr == nil {
+		return fmt.Sprintf(
+			"%v: %s%s: schema accepts, library rejects: %v",
+			ErrMismatch,
+			m.Action,
+			m.Kind,
+			m.LibraryErr,
+		)
+	}
+
+	return fmt.Sprintf(
+		"%v: %s%s: library accepts, schema rejects: %v",
+		ErrMismatch,
+		m.Action,
+		m.Kind,
+		m.SchemaErr,
+	)
+}
+
+// Is reports whether target is ErrMismatch.
+func (m *Mismatch) Is(target error) bool {
+	return target == ErrMismatch
+}
+
+// CrossCheck validates a payload with the schema and decodes it with the
+// message package of the action. It returns nil when both accept or both
+// reject the payload, and a *Mismatch otherwise.
+func CrossCheck(action string, kind Kind, payload []byte) error {
+	schemaErr := Validate(action, kind, payload)
+	if errors.Is(schemaErr, ErrUnknownAction) {
+		return schemaErr
+	}
+
+	_, libraryErr := decode(action, kind, payload)
+
+	if (schemaErr == nil) == (libraryErr == nil) {
+		return nil
+	}
+
+	return &Mismatch{
+		Action:     action,
+		Kind:       kind,
+		SchemaErr:  schemaErr,
+		LibraryErr: libraryErr,
+	}
+}
+
+// Decode validates a payload against the schema and, when it conforms,
+// decodes it into the ReqMessage or ConfMessage of the action's package,
+// e.g. authorize.ReqMessage.
+func Decode(action string, kind Kind, payload []byte) (any, error) {
+	err := Validate(action, kind, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(action, kind, payload)
+}
+
+// decode decodes a payload with the message package of the action.
+func decode(action string, kind Kind, payload []byte) (any, error) {
+	var (
+		msg any
+		err error
+	)
+
+	if kind == Confirmation {
+		msg, err = ocppj.DecodeConfirmation(action, payload)
+	} else {
+		msg, err = ocppj.DecodeRequest(action, payload)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s%s: %w", action, kind, err)
+	}
+
+	return msg, nil
+}
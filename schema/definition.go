@@ -0,0 +1,23 @@
+package schema
+
+// Definition is a JSON Schema draft-04 document or subschema, limited to the
+// keywords of the official OCPP 1.6 schemas and of Generate.
+type Definition struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Id                   string                 `json:"id,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	MultipleOf           *float64               `json:"multipleOf,omitempty"`
+	Items                *Definition            `json:"items,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	Properties           map[string]*Definition `json:"properties,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+}
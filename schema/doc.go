@@ -0,0 +1,64 @@
+// Package schema validates raw OCPP-J payloads against the official OCPP 1.6
+// JSON schemas, cross-checks them with the message packages and generates
+// JSON Schema and OpenAPI contracts from the message types.
+//
+// The schemas published by the Open Charge Alliance (Authorize.json,
+// AuthorizeResponse.json, ...) are embedded in the package. A payload can be
+// validated before it reaches the Req or Conf constructor of its message
+// package:
+//
+//	err := schema.Validate(ocppj.ActionAuthorize, schema.Request, payload)
+//	if err != nil {
+//		// err wraps ErrInvalid and one sentinel per violation, e.g.
+//		// ErrAdditionalProperty or ErrMaxLength
+//	}
+//
+// Decode validates a payload and then decodes it with ocppj.DecodeRequest or
+// ocppj.DecodeConfirmation, so only payloads accepted by both reach the
+// application.
+//
+// # Cross-check
+//
+// CrossCheck runs a payload through both paths and reports a *Mismatch when
+// the schema and the message package disagree. It is meant for tests and
+// fuzzing: it proves that the library accepts exactly what the
+// specification accepts. Known differences are:
+//   - ocpp16types limits integers to 0..65535 and DateTime to UTC, where the
+//     schemas only require an integer and a date-time
+//   - CiString types reject empty and non printable ASCII strings, where the
+//     schemas only limit their length
+//   - the location of GetDiagnostics.req and UpdateFirmware.req is any
+//     CiString255 for the constructors, where the schemas require a URI;
+//     transfer.ParseLocation checks it before a file is transferred
+//
+// # Lenient decoding
+//
+// Chargers in the field send timestamps with an offset or without a time
+// zone, connector ids as strings, over-length info texts and lower case
+// enumeration values. A Decoder in Lenient mode normalizes these deviations
+// against the official schema, then decodes strictly, and reports each
+// normalization as a Warning so non-compliance can be measured while the
+// traffic is accepted:
+//
+//	decoder := schema.Decoder{Mode: schema.Lenient}
+//	msg, warnings, err := decoder.Decode(action, schema.Request, payload)
+//	for _, warning := range warnings {
+//		// e.g. /status: enum value case-folded: "accepted" -> "Accepted"
+//	}
+//
+// The zero Decoder is Strict and behaves as Decode.
+//
+// # Generation
+//
+// Generate derives the JSON Schema of a payload from the ReqMessage or
+// ConfMessage of its package and the ocpp16types values it holds, so the
+// contract states exactly what the library validates: CiString length
+// limits and printable ASCII, Integer ranges, UTC DateTime, enumeration
+// values and required versus optional properties. OpenAPI wraps the
+// generated schemas as OpenAPI 3 components. Both are written to files by
+// the ocpp16-schema command. A generated Definition validates payloads like
+// the official schemas:
+//
+//	definition, _ := schema.Generate(ocppj.ActionAuthorize, schema.Request)
+//	err := definition.Validate(payload)
+package schema
@@ -0,0 +1,396 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	draft04    = "http://json-schema.org/draft-04/schema#"
+	idPrefix   = "urn:ocpp16messages:1.6:"
+	openAPI    = "3.0.3"
+	apiTitle   = "OCPP 1.6 messages"
+	apiVersion = "1.6"
+
+	// ciStringPattern matches the printable ASCII characters accepted by
+	// the CiString types.
+	ciStringPattern = "^[ -~]*$"
+	// utcPattern matches the UTC designator required by types.DateTime.
+	utcPattern = "Z$"
+
+	ciString20  = 20
+	ciString25  = 25
+	ciString50  = 50
+	ciString255 = 255
+	ciString500 = 500
+	minLength   = 1
+	minItems    = 1
+)
+
+// errUnsupportedType is returned for a Go type Generate cannot describe.
+var errUnsupportedType = errors.New("schema: unsupported type")
+
+// nonEmptyLists are the list properties whose constructors reject an empty
+// list. Other lists may be omitted.
+var nonEmptyLists = []string{
+	"meterValue",
+	"sampledValue",
+	"chargingSchedulePeriod",
+}
+
+// composites are the ocpp16types values described by their getters.
+var composites = []reflect.Type{
+	reflect.TypeFor[types.IdTagInfo](),
+	reflect.TypeFor[types.MeterValue](),
+	reflect.TypeFor[types.SampledValue](),
+	reflect.TypeFor[types.KeyValue](),
+	reflect.TypeFor[types.AuthorizationData](),
+	reflect.TypeFor[types.ChargingProfile](),
+	reflect.TypeFor[types.ChargingSchedule](),
+	reflect.TypeFor[types.ChargingSchedulePeriod](),
+}
+
+// leaves describes the ocpp16types scalar types by their validation rules.
+var leaves = map[reflect.Type]func() *Definition{
+	reflect.TypeFor[types.CiString20Type]():  ciString(ciString20),
+	reflect.TypeFor[types.CiString25Type]():  ciString(ciString25),
+	reflect.TypeFor[types.CiString50Type]():  ciString(ciString50),
+	reflect.TypeFor[types.CiString255Type](): ciString(ciString255),
+	reflect.TypeFor[types.CiString500Type](): ciString(ciString500),
+	reflect.TypeFor[types.IdToken]():         ciString(ciString20),
+	reflect.TypeFor[types.Integer]():         integer(0, math.MaxUint16),
+	reflect.TypeFor[types.ListVersionNumber](): integer(
+		math.MinInt32,
+		math.MaxInt32,
+	),
+	reflect.TypeFor[types.DateTime](): dateTime,
+}
+
+// Generate returns the JSON Schema of an action's payload as validated by
+// its message package, e.g. authorize.ReqMessage for Authorize requests.
+//
+// The schema is derived from the message type: every exported field, and
+// every getter of the ocpp16types values it holds, becomes a property named
+// as on the wire. Pointers are optional, other fields are required. CiString
+// types carry their length limits and character set, Integer its uint16
+// range, DateTime its UTC designator and enumerations the values of the
+// official schemas their IsValid method accepts.
+func Generate(action string, kind Kind) (*Definition, error) {
+	registered, ok := messages[action]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAction, action)
+	}
+
+	messageType := registered.request
+	if kind == Confirmation {
+		messageType = registered.confirmation
+	}
+
+	definition, err := describe(messageType)
+	if err != nil {
+		return nil, fmt.Errorf("%s%s: %w", action, kind, err)
+	}
+
+	definition.Schema = draft04
+	definition.Id = idPrefix + action + kind.String()
+	definition.Title = action + kind.String()
+
+	return definition, nil
+}
+
+// GenerateAll returns the schemas of every action, the request before the
+// confirmation, in alphabetical order of action.
+func GenerateAll() ([]*Definition, error) {
+	actionNames := ocppj.Actions()
+	definitions := make([]*Definition, 0, 2*len(actionNames))
+
+	for _, action := range actionNames {
+		for _, kind := range []Kind{Request, Confirmation} {
+			definition, err := Generate(action, kind)
+			if err != nil {
+				return nil, err
+			}
+
+			definitions = append(definitions, definition)
+		}
+	}
+
+	return definitions, nil
+}
+
+// OpenAPI returns an OpenAPI 3 document whose components.schemas holds the
+// generated schema of every payload, keyed by title, e.g.
+// #/components/schemas/AuthorizeRequest.
+func OpenAPI() ([]byte, error) {
+	definitions, err := GenerateAll()
+	if err != nil {
+		return nil, err
+	}
+
+	components := make(map[string]*Definition, len(definitions))
+
+	for _, definition := range definitions {
+		component := *definition
+		component.Schema = ""
+		component.Id = ""
+		components[definition.Title] = &component
+	}
+
+	document := struct {
+		OpenAPI    string `json:"openapi"`
+		Info       any    `json:"info"`
+		Paths      any    `json:"paths"`
+		Components any    `json:"components"`
+	}{
+		OpenAPI:    openAPI,
+		Info:       map[string]string{"title": apiTitle, "version": apiVersion},
+		Paths:      map[string]any{},
+		Components: map[string]any{"schemas": components},
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+
+	return data, nil
+}
+
+// describe returns the definition of a Go type.
+func describe(goType reflect.Type) (*Definition, error) {
+	leaf, ok := leaves[goType]
+	if ok {
+		return leaf(), nil
+	}
+
+	switch goType.Kind() {
+	case reflect.Pointer:
+		return describe(goType.Elem())
+	case reflect.Slice:
+		items, err := describe(goType.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return &Definition{Type: "array", Items: items}, nil
+	case reflect.String:
+		return describeString(goType), nil
+	case reflect.Bool:
+		return &Definition{Type: "boolean"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Definition{Type: "number"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16,
+		reflect.Uint32, reflect.Uint64:
+		return &Definition{Type: "integer"}, nil
+	case reflect.Struct:
+		return describeStruct(goType)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedType, goType)
+	}
+}
+
+// describeString returns the definition of a string type, an enumeration
+// when the type has an IsValid method. The values are listed in the order of
+// the official enumeration with the same values, if any.
+func describeString(goType reflect.Type) *Definition {
+	_, ok := goType.MethodByName("IsValid")
+	if !ok {
+		return &Definition{Type: "string"}
+	}
+
+	valid := func(candidate string) bool {
+		value := reflect.New(goType).Elem()
+		value.SetString(candidate)
+
+		return value.MethodByName("IsValid").Call(nil)[0].Bool()
+	}
+
+	var values []string
+
+	for _, candidate := range slices.Concat(officialEnums()...) {
+		if valid(candidate) && !slices.Contains(values, candidate) {
+			values = append(values, candidate)
+		}
+	}
+
+	for _, enum := range officialEnums() {
+		if len(enum) == len(values) && !slices.ContainsFunc(
+			enum,
+			func(value string) bool { return !valid(value) },
+		) {
+			return &Definition{Type: "string", Enum: slices.Clone(enum)}
+		}
+	}
+
+	return &Definition{Type: "string", Enum: values}
+}
+
+// describeStruct returns the definition of a message type from its exported
+// fields, or of an ocpp16types value from its getters. Other ocpp16types
+// values, such as the value of a SampledValue, are strings.
+func describeStruct(goType reflect.Type) (*Definition, error) {
+	fromTypes := goType.PkgPath() == reflect.TypeFor[types.Integer]().PkgPath()
+	if fromTypes && !slices.Contains(composites, goType) {
+		return &Definition{Type: "string"}, nil
+	}
+
+	closed := false
+	definition := &Definition{
+		Type:                 "object",
+		Properties:           map[string]*Definition{},
+		AdditionalProperties: &closed,
+	}
+
+	var err error
+
+	if fromTypes {
+		for i := range goType.NumMethod() {
+			method := goType.Method(i)
+			if method.Name == "String" || method.Type.NumIn() != 1 ||
+				method.Type.NumOut() != 1 {
+				continue
+			}
+
+			err = addProperty(definition, method.Name, method.Type.Out(0))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return definition, nil
+	}
+
+	for i := range goType.NumField() {
+		field := goType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		err = addProperty(definition, field.Name, field.Type)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return definition, nil
+}
+
+// addProperty adds the property of a field or getter. Pointers and lists
+// that may be empty are optional.
+func addProperty(parent *Definition, goName string, goType reflect.Type) error {
+	name := propertyName(goName)
+
+	property, err := describe(goType)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	parent.Properties[name] = property
+
+	if goType.Kind() == reflect.Pointer {
+		return nil
+	}
+
+	if goType.Kind() == reflect.Slice {
+		if !slices.Contains(nonEmptyLists, name) {
+			return nil
+		}
+
+		items := minItems
+		property.MinItems = &items
+	}
+
+	parent.Required = append(parent.Required, name)
+
+	return nil
+}
+
+// propertyName returns the wire name of a Go field or getter, e.g. idTag
+// for IdTag.
+func propertyName(goName string) string {
+	first, size := utf8.DecodeRuneInString(goName)
+
+	return string(unicode.ToLower(first)) + goName[size:]
+}
+
+// officialEnums returns the enumerations of the official schemas.
+var officialEnums = sync.OnceValue(func() [][]string {
+	var enums [][]string
+
+	for _, action := range ocppj.Actions() {
+		for _, kind := range []Kind{Request, Confirmation} {
+			root, err := load(action, kind)
+			if err == nil {
+				enums = collectEnums(root, enums)
+			}
+		}
+	}
+
+	return enums
+})
+
+// collectEnums appends the enumerations of a definition and its subschemas.
+func collectEnums(definition *Definition, enums [][]string) [][]string {
+	if definition.Enum != nil {
+		enums = append(enums, definition.Enum)
+	}
+
+	if definition.Items != nil {
+		enums = collectEnums(definition.Items, enums)
+	}
+
+	names := make([]string, 0, len(definition.Properties))
+	for name := range definition.Properties {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		enums = collectEnums(definition.Properties[name], enums)
+	}
+
+	return enums
+}
+
+// ciString returns the definition of a CiString type of maximum length.
+func ciString(maxLength int) func() *Definition {
+	return func() *Definition {
+		minimum := minLength
+		maximum := maxLength
+
+		return &Definition{
+			Type:      "string",
+			Pattern:   ciStringPattern,
+			MinLength: &minimum,
+			MaxLength: &maximum,
+		}
+	}
+}
+
+// integer returns the definition of an integer type of bounded range.
+func integer(minimum, maximum float64) func() *Definition {
+	return func() *Definition {
+		return &Definition{
+			Type:    "integer",
+			Minimum: &minimum,
+			Maximum: &maximum,
+		}
+	}
+}
+
+// dateTime returns the definition of types.DateTime.
+func dateTime() *Definition {
+	return &Definition{Type: "string", Format: "date-time", Pattern: utcPattern}
+}
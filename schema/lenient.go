@@ -0,0 +1,356 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Mode selects how a Decoder treats payloads that deviate from the
+// specification.
+type Mode int
+
+const (
+	// Strict accepts only payloads that conform to the official schema and
+	// are accepted by the message package, like Decode.
+	Strict Mode = iota
+	// Lenient normalizes the known deviations of real-world chargers before
+	// decoding strictly, and reports each normalization as a Warning.
+	Lenient
+)
+
+// Fix names a normalization applied in Lenient mode.
+type Fix string
+
+const (
+	// FixDateTime converts a date-time with an offset, or without a time
+	// zone, to UTC. A date-time without a time zone is taken as UTC.
+	FixDateTime Fix = "date-time converted to UTC"
+	// FixNumericString converts a string holding a number, e.g. a
+	// connectorId sent as "1", to a number.
+	FixNumericString Fix = "numeric string converted to number"
+	// FixIntegral converts an integral number with a fraction or exponent,
+	// e.g. 1.0, to an integer.
+	FixIntegral Fix = "integral number converted to integer"
+	// FixEnumCase replaces an enumeration value by the value of the schema
+	// it matches case-insensitively, e.g. accepted by Accepted.
+	FixEnumCase Fix = "enum value case-folded"
+	// FixTruncated truncates an over-length free text property, such as the
+	// CiString50 info of StatusNotification, to its maxLength.
+	FixTruncated Fix = "string truncated to maxLength"
+	// FixUnknownProperty removes a property the schema does not define.
+	FixUnknownProperty Fix = "unknown property removed"
+)
+
+// truncatable are the free text properties Lenient mode may truncate. Other
+// over-length strings carry identifiers and are still rejected.
+var truncatable = []string{"info"}
+
+// naiveDateTimes are the layouts of date-times without a time zone accepted
+// in Lenient mode.
+var naiveDateTimes = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// Warning reports a deviation normalized in Lenient mode.
+type Warning struct {
+	// Path is the JSON pointer of the normalized value, e.g. /timestamp.
+	Path string
+	// Fix is the normalization applied.
+	Fix Fix
+	// Original and Normalized are the JSON encodings of the value before
+	// and after the normalization. Normalized is empty for a removed
+	// property.
+	Original   string
+	Normalized string
+}
+
+// String describes the warning, e.g.
+// /status: enum value case-folded: "accepted" -> "Accepted".
+func (w Warning) String() string {
+	if w.Normalized == "" {
+		return fmt.Sprintf("%s: %s: %s", w.Path, w.Fix, w.Original)
+	}
+
+	return fmt.Sprintf(
+		"%s: %s: %s -> %s",
+		w.Path,
+		w.Fix,
+		w.Original,
+		w.Normalized,
+	)
+}
+
+// Decoder decodes payloads into the message types of the action packages in
+// the selected Mode. The zero value decodes strictly.
+type Decoder struct {
+	Mode Mode
+}
+
+// Decode decodes a payload into the ReqMessage or ConfMessage of the
+// action's package, e.g. authorize.ReqMessage. In Strict mode it behaves as
+// the package level Decode and never returns warnings. In Lenient mode the
+// payload is first normalized against the official schema; the returned
+// warnings list every normalization, also when decoding then fails.
+func (d Decoder) Decode(
+	action string,
+	kind Kind,
+	payload []byte,
+) (any, []Warning, error) {
+	if d.Mode != Lenient {
+		msg, err := Decode(action, kind, payload)
+
+		return msg, nil, err
+	}
+
+	normalized, warnings, err := Normalize(action, kind, payload)
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	msg, err := Decode(action, kind, normalized)
+
+	return msg, warnings, err
+}
+
+// Normalize applies the Lenient mode normalizations to a payload and
+// returns the normalized payload with one Warning per normalization. A
+// conforming payload is returned unchanged, without warnings.
+func Normalize(action string, kind Kind, payload []byte) (
+	[]byte,
+	[]Warning,
+	error,
+) {
+	root, err := load(action, kind)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	value, err := parse(payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s: %w", ErrInvalid, root.Title, err)
+	}
+
+	var warnings []Warning
+
+	value = root.normalize(value, "", "", &warnings)
+	if len(warnings) == 0 {
+		return payload, nil, nil
+	}
+
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return nil, warnings, fmt.Errorf("schema: %w", err)
+	}
+
+	return normalized, warnings, nil
+}
+
+// normalize returns value, named name and located at path, with the
+// deviations from the definition fixed.
+func (d *Definition) normalize(
+	value any,
+	name, path string,
+	warnings *[]Warning,
+) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		return d.normalizeObject(typed, path, warnings)
+	case []any:
+		if d.Items == nil {
+			return typed
+		}
+
+		for i, item := range typed {
+			typed[i] = d.Items.normalize(
+				item,
+				name,
+				fmt.Sprintf("%s/%d", path, i),
+				warnings,
+			)
+		}
+
+		return typed
+	case string:
+		return d.normalizeString(typed, name, path, warnings)
+	case json.Number:
+		return d.normalizeNumber(typed, path, warnings)
+	default:
+		return value
+	}
+}
+
+// normalizeObject removes unknown properties and normalizes the others.
+func (d *Definition) normalizeObject(
+	object map[string]any,
+	path string,
+	warnings *[]Warning,
+) map[string]any {
+	if d.Type != "object" {
+		return object
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		property, ok := d.Properties[name]
+		if ok {
+			object[name] = property.normalize(
+				object[name],
+				name,
+				path+"/"+name,
+				warnings,
+			)
+
+			continue
+		}
+
+		if d.AdditionalProperties != nil && !*d.AdditionalProperties {
+			warn(warnings, path+"/"+name, FixUnknownProperty, object[name], nil)
+			delete(object, name)
+		}
+	}
+
+	return object
+}
+
+// jsonNumber matches a number literal of the JSON grammar (RFC 8259,
+// section 6), which excludes the Inf and NaN strconv.ParseFloat accepts.
+var jsonNumber = regexp.MustCompile(
+	`^-?(?:0|[1-9][0-9]*)(?:\.[0-9]+)?(?:[eE][+-]?[0-9]+)?$`,
+)
+
+// normalizeString fixes numeric strings, date-times, enumeration casing and
+// over-length free text.
+func (d *Definition) normalizeString(
+	str, name, path string,
+	warnings *[]Warning,
+) any {
+	switch {
+	case d.Type == "integer" || d.Type == "number":
+		if !jsonNumber.MatchString(str) {
+			return str
+		}
+
+		number := json.Number(str)
+		warn(warnings, path, FixNumericString, str, number)
+
+		return d.normalizeNumber(number, path, warnings)
+	case d.Format == "date-time":
+		return normalizeDateTime(str, path, warnings)
+	case d.Enum != nil && !slices.Contains(d.Enum, str):
+		index := slices.IndexFunc(d.Enum, func(value string) bool {
+			return strings.EqualFold(value, str)
+		})
+		if index < 0 {
+			return str
+		}
+
+		warn(warnings, path, FixEnumCase, str, d.Enum[index])
+
+		return d.Enum[index]
+	case d.MaxLength != nil && slices.Contains(truncatable, name) &&
+		utf8.RuneCountInString(str) > *d.MaxLength:
+		truncated := string([]rune(str)[:*d.MaxLength])
+		warn(warnings, path, FixTruncated, str, truncated)
+
+		return truncated
+	default:
+		return str
+	}
+}
+
+// normalizeNumber converts an integral number to an integer literal where
+// the schema requires an integer.
+func (d *Definition) normalizeNumber(
+	number json.Number,
+	path string,
+	warnings *[]Warning,
+) json.Number {
+	if d.Type != "integer" || !strings.ContainsAny(number.String(), ".eE") {
+		return number
+	}
+
+	value, err := number.Float64()
+	if err != nil || value != float64(int64(value)) {
+		return number
+	}
+
+	integer := json.Number(strconv.FormatInt(int64(value), 10))
+	warn(warnings, path, FixIntegral, number, integer)
+
+	return integer
+}
+
+// normalizeDateTime converts a date-time to UTC. Date-times without a time
+// zone are taken as UTC.
+func normalizeDateTime(str, path string, warnings *[]Warning) string {
+	parsed, err := time.Parse(time.RFC3339Nano, str)
+	if err == nil && strings.HasSuffix(str, "Z") {
+		return str
+	}
+
+	for _, layout := range naiveDateTimes {
+		if err == nil {
+			break
+		}
+
+		parsed, err = time.Parse(layout, str)
+	}
+
+	if err != nil {
+		return str
+	}
+
+	normalized := parsed.UTC().Format(time.RFC3339Nano)
+	warn(warnings, path, FixDateTime, str, normalized)
+
+	return normalized
+}
+
+// warn appends the warning of a normalization. A nil normalized value
+// records a removal.
+func warn(
+	warnings *[]Warning,
+	path string,
+	fix Fix,
+	original, normalized any,
+) {
+	if path == "" {
+		path = "/"
+	}
+
+	warning := Warning{
+		Path:       path,
+		Fix:        fix,
+		Original:   encode(original),
+		Normalized: "",
+	}
+
+	if normalized != nil {
+		warning.Normalized = encode(normalized)
+	}
+
+	*warnings = append(*warnings, warning)
+}
+
+// encode returns the JSON encoding of a parsed value.
+func encode(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(data)
+}
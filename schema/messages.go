@@ -0,0 +1,175 @@
+package schema
+
+import (
+	"reflect"
+
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/cancelreservation"
+	"github.com/aasanchez/ocpp16messages/changeavailability"
+	"github.com/aasanchez/ocpp16messages/changeconfiguration"
+	"github.com/aasanchez/ocpp16messages/clearcache"
+	"github.com/aasanchez/ocpp16messages/clearchargingprofile"
+	"github.com/aasanchez/ocpp16messages/datatransfer"
+	"github.com/aasanchez/ocpp16messages/diagnosticsstatusnotification"
+	"github.com/aasanchez/ocpp16messages/firmwarestatusnotification"
+	"github.com/aasanchez/ocpp16messages/getcompositeschedule"
+	"github.com/aasanchez/ocpp16messages/getconfiguration"
+	"github.com/aasanchez/ocpp16messages/getdiagnostics"
+	"github.com/aasanchez/ocpp16messages/getlocallistversion"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/metervalues"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/remotestoptransaction"
+	"github.com/aasanchez/ocpp16messages/reservenow"
+	"github.com/aasanchez/ocpp16messages/reset"
+	"github.com/aasanchez/ocpp16messages/sendlocallist"
+	"github.com/aasanchez/ocpp16messages/setchargingprofile"
+	"github.com/aasanchez/ocpp16messages/starttransaction"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	"github.com/aasanchez/ocpp16messages/stoptransaction"
+	"github.com/aasanchez/ocpp16messages/triggermessage"
+	"github.com/aasanchez/ocpp16messages/unlockconnector"
+	"github.com/aasanchez/ocpp16messages/updatefirmware"
+)
+
+// messageTypes are the ReqMessage and ConfMessage types of an action.
+type messageTypes struct {
+	request      reflect.Type
+	confirmation reflect.Type
+}
+
+// messages maps every OCPP 1.6 action to its message types.
+var messages = map[string]messageTypes{
+	ocppj.ActionAuthorize: {
+		reflect.TypeFor[authorize.ReqMessage](),
+		reflect.TypeFor[authorize.ConfMessage](),
+	},
+	ocppj.ActionBootNotification: {
+		reflect.TypeFor[bootnotification.ReqMessage](),
+		reflect.TypeFor[bootnotification.ConfMessage](),
+	},
+	ocppj.ActionCancelReservation: {
+		reflect.TypeFor[cancelreservation.ReqMessage](),
+		reflect.TypeFor[cancelreservation.ConfMessage](),
+	},
+	ocppj.ActionChangeAvailability: {
+		reflect.TypeFor[changeavailability.ReqMessage](),
+		reflect.TypeFor[changeavailability.ConfMessage](),
+	},
+	ocppj.ActionChangeConfiguration: {
+		reflect.TypeFor[changeconfiguration.ReqMessage](),
+		reflect.TypeFor[changeconfiguration.ConfMessage](),
+	},
+	ocppj.ActionClearCache: {
+		reflect.TypeFor[clearcache.ReqMessage](),
+		reflect.TypeFor[clearcache.ConfMessage](),
+	},
+	ocppj.ActionClearChargingProfile: {
+		reflect.TypeFor[clearchargingprofile.ReqMessage](),
+		reflect.TypeFor[clearchargingprofile.ConfMessage](),
+	},
+	ocppj.ActionDataTransfer: {
+		reflect.TypeFor[datatransfer.ReqMessage](),
+		reflect.TypeFor[datatransfer.ConfMessage](),
+	},
+	ocppj.ActionDiagnosticsStatusNotification: {
+		reflect.TypeFor[diagnosticsstatusnotification.ReqMessage](),
+		reflect.TypeFor[diagnosticsstatusnotification.ConfMessage](),
+	},
+	ocppj.ActionFirmwareStatusNotification: {
+		reflect.TypeFor[firmwarestatusnotification.ReqMessage](),
+		reflect.TypeFor[firmwarestatusnotification.ConfMessage](),
+	},
+	ocppj.ActionGetCompositeSchedule: {
+		reflect.TypeFor[getcompositeschedule.ReqMessage](),
+		reflect.TypeFor[getcompositeschedule.ConfMessage](),
+	},
+	ocppj.ActionGetConfiguration: {
+		reflect.TypeFor[getconfiguration.ReqMessage](),
+		reflect.TypeFor[getconfiguration.ConfMessage](),
+	},
+	ocppj.ActionGetDiagnostics: {
+		reflect.TypeFor[getdiagnostics.ReqMessage](),
+		reflect.TypeFor[getdiagnostics.ConfMessage](),
+	},
+	ocppj.ActionGetLocalListVersion: {
+		reflect.TypeFor[getlocallistversion.ReqMessage](),
+		reflect.TypeFor[getlocallistversion.ConfMessage](),
+	},
+	ocppj.ActionHeartbeat: {
+		reflect.TypeFor[heartbeat.ReqMessage](),
+		reflect.TypeFor[heartbeat.ConfMessage](),
+	},
+	ocppj.ActionMeterValues: {
+		reflect.TypeFor[metervalues.ReqMessage](),
+		reflect.TypeFor[metervalues.ConfMessage](),
+	},
+	ocppj.ActionRemoteStartTransaction: {
+		reflect.TypeFor[remotestarttransaction.ReqMessage](),
+		reflect.TypeFor[remotestarttransaction.ConfMessage](),
+	},
+	ocppj.ActionRemoteStopTransaction: {
+		reflect.TypeFor[remotestoptransaction.ReqMessage](),
+		reflect.TypeFor[remotestoptransaction.ConfMessage](),
+	},
+	ocppj.ActionReserveNow: {
+		reflect.TypeFor[reservenow.ReqMessage](),
+		reflect.TypeFor[reservenow.ConfMessage](),
+	},
+	ocppj.ActionReset: {
+		reflect.TypeFor[reset.ReqMessage](),
+		reflect.TypeFor[reset.ConfMessage](),
+	},
+	ocppj.ActionSendLocalList: {
+		reflect.TypeFor[sendlocallist.ReqMessage](),
+		reflect.TypeFor[sendlocallist.ConfMessage](),
+	},
+	ocppj.ActionSetChargingProfile: {
+		reflect.TypeFor[setchargingprofile.ReqMessage](),
+		reflect.TypeFor[setchargingprofile.ConfMessage](),
+	},
+	ocppj.ActionStartTransaction: {
+		reflect.TypeFor[starttransaction.ReqMessage](),
+		reflect.TypeFor[starttransaction.ConfMessage](),
+	},
+	ocppj.ActionStatusNotification: {
+		reflect.TypeFor[statusnotification.ReqMessage](),
+		reflect.TypeFor[statusnotification.ConfMessage](),
+	},
+	ocppj.ActionStopTransaction: {
+		reflect.TypeFor[stoptransaction.ReqMessage](),
+		reflect.TypeFor[stoptransaction.ConfMessage](),
+	},
+	ocppj.ActionTriggerMessage: {
+		reflect.TypeFor[triggermessage.ReqMessage](),
+		reflect.TypeFor[triggermessage.ConfMessage](),
+	},
+	ocppj.ActionUnlockConnector: {
+		reflect.TypeFor[unlockconnector.ReqMessage](),
+		reflect.TypeFor[unlockconnector.ConfMessage](),
+	},
+	ocppj.ActionUpdateFirmware: {
+		reflect.TypeFor[updatefirmware.ReqMessage](),
+		reflect.TypeFor[updatefirmware.ConfMessage](),
+	},
+}
+
+// Identify returns the action and kind of a message value, e.g. Authorize
+// and Request for an authorize.ReqMessage. It reports false for any other
+// value.
+func Identify(msg any) (string, Kind, bool) {
+	msgType := reflect.TypeOf(msg)
+
+	for action, registered := range messages {
+		switch msgType {
+		case registered.request:
+			return action, Request, true
+		case registered.confirmation:
+			return action, Confirmation, true
+		}
+	}
+
+	return "", Request, false
+}
@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync"
+)
+
+// ErrUnknownAction is returned for an action without an OCPP 1.6 schema.
+var ErrUnknownAction = errors.New("schema: unknown action")
+
+// Kind selects the request or confirmation schema of an action.
+type Kind int
+
+const (
+	// Request selects the schema of the CALL payload, e.g. Authorize.json.
+	Request Kind = iota
+	// Confirmation selects the schema of the CALLRESULT payload, e.g.
+	// AuthorizeResponse.json.
+	Confirmation
+)
+
+// String returns the suffix of the schema title, Request or Response.
+func (k Kind) String() string {
+	if k == Confirmation {
+		return "Response"
+	}
+
+	return "Request"
+}
+
+//go:embed json/*.json
+var files embed.FS
+
+// compiled caches the parsed schemas by file name.
+var compiled sync.Map
+
+// Schema returns the official JSON schema document of an action's payload.
+func Schema(action string, kind Kind) ([]byte, error) {
+	data, err := files.ReadFile(fileName(action, kind))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAction, action)
+	}
+
+	return data, nil
+}
+
+// Files returns the embedded schema documents, named as published by the
+// Open Charge Alliance.
+func Files() fs.FS {
+	sub, err := fs.Sub(files, "json")
+	if err != nil {
+		panic(err) // The embedded directory always exists.
+	}
+
+	return sub
+}
+
+// fileName returns the name of the schema file of an action's payload.
+func fileName(action string, kind Kind) string {
+	if kind == Confirmation {
+		return "json/" + action + "Response.json"
+	}
+
+	return "json/" + action + ".json"
+}
+
+// load returns the parsed schema of an action's payload.
+func load(action string, kind Kind) (*Definition, error) {
+	name := fileName(action, kind)
+
+	cached, ok := compiled.Load(name)
+	if ok {
+		//nolint:forcetypeassert // Only *Definition is stored.
+		return cached.(*Definition), nil
+	}
+
+	data, err := Schema(action, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var root Definition
+
+	err = json.Unmarshal(data, &root)
+	if err != nil {
+		return nil, fmt.Errorf("schema: %s: %w", name, err)
+	}
+
+	cached, _ = compiled.LoadOrStore(name, &root)
+
+	//nolint:forcetypeassert // Only *Definition is stored.
+	return cached.(*Definition), nil
+}
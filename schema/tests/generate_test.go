@@ -0,0 +1,361 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	rst "github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/schema"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+func TestGenerate_EveryAction(t *testing.T) {
+	t.Parallel()
+
+	definitions, err := schema.GenerateAll()
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if len(definitions) != schemaFiles {
+		t.Fatalf(types.ErrorMismatchValue, schemaFiles, len(definitions))
+	}
+
+	for _, tc := range conforming {
+		for kind, payload := range map[schema.Kind]string{
+			schema.Request:      tc.request,
+			schema.Confirmation: tc.confirmation,
+		} {
+			definition, err := schema.Generate(tc.action, kind)
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+
+			if definition.Title != tc.action+kind.String() {
+				t.Errorf(
+					types.ErrorMismatchValue,
+					tc.action+kind.String(),
+					definition.Title,
+				)
+			}
+
+			err = definition.Validate([]byte(payload))
+			if err != nil {
+				t.Errorf(types.ErrorUnexpectedError, err)
+			}
+		}
+	}
+}
+
+func TestGenerate_MatchesOfficialSchemas(t *testing.T) {
+	t.Parallel()
+
+	definitions, err := schema.GenerateAll()
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	for _, generated := range definitions {
+		var official schema.Definition
+
+		action, kind := splitTitle(generated.Title)
+
+		data, err := schema.Schema(action, kind)
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+
+		err = json.Unmarshal(data, &official)
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+
+		compareDefinitions(t, generated.Title, generated, &official)
+	}
+}
+
+func TestGenerate_EveryOptionalProperty(t *testing.T) {
+	t.Parallel()
+
+	definitions, err := schema.GenerateAll()
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	for _, generated := range definitions {
+		var official schema.Definition
+
+		action, kind := splitTitle(generated.Title)
+
+		data, err := schema.Schema(action, kind)
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+
+		err = json.Unmarshal(data, &official)
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+
+		checkOptional(t, generated.Title, generated, &official)
+	}
+}
+
+func TestGenerate_StricterThanOfficial(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		action  string
+		payload string
+		want    error
+	}{
+		{"empty CiString", "Authorize", `{"idTag":""}`, schema.ErrMinLength},
+		{
+			"non printable CiString",
+			"Authorize",
+			`{"idTag":"café"}`,
+			schema.ErrPattern,
+		},
+		{
+			"Integer above uint16",
+			"UnlockConnector",
+			`{"connectorId":65536}`,
+			schema.ErrRange,
+		},
+		{
+			"offset DateTime",
+			"StartTransaction",
+			`{"connectorId":1,"idTag":"A","meterStart":0,` +
+				`"timestamp":"2025-01-02T15:00:00+01:00"}`,
+			schema.ErrPattern,
+		},
+		{
+			"empty meterValue",
+			"MeterValues",
+			`{"connectorId":1,"meterValue":[]}`,
+			schema.ErrMinItems,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := schema.ValidateRequest(tc.action, []byte(tc.payload))
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+
+			definition, err := schema.Generate(tc.action, schema.Request)
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+
+			err = definition.Validate([]byte(tc.payload))
+			if !errors.Is(err, tc.want) {
+				t.Errorf(types.ErrorWrapping, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_RemoteStartOnConnectorZero(t *testing.T) {
+	t.Parallel()
+
+	connectorId := 0
+
+	req, err := rst.Req(rst.ReqInput{
+		IdTag:           "A",
+		ConnectorId:     &connectorId,
+		ChargingProfile: nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	definition, err := schema.Generate("RemoteStartTransaction", schema.Request)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	err = definition.Validate(payload)
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+}
+
+func TestGenerate_UnknownAction(t *testing.T) {
+	t.Parallel()
+
+	_, err := schema.Generate("Foo", schema.Request)
+	if !errors.Is(err, schema.ErrUnknownAction) {
+		t.Errorf(types.ErrorWrapping, err, schema.ErrUnknownAction)
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	data, err := schema.OpenAPI()
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	var document struct {
+		OpenAPI    string `json:"openapi"`
+		Components struct {
+			Schemas map[string]map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+
+	err = json.Unmarshal(data, &document)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if document.OpenAPI != "3.0.3" {
+		t.Errorf(types.ErrorMismatchValue, "3.0.3", document.OpenAPI)
+	}
+
+	if len(document.Components.Schemas) != schemaFiles {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			schemaFiles,
+			len(document.Components.Schemas),
+		)
+	}
+
+	component, ok := document.Components.Schemas["AuthorizeRequest"]
+	if !ok {
+		t.Fatalf(types.ErrorWantNonNil, "AuthorizeRequest component")
+	}
+
+	if _, ok := component["$schema"]; ok {
+		t.Errorf(types.ErrorMismatchValue, "no $schema", component["$schema"])
+	}
+}
+
+// splitTitle returns the action and kind of a schema title.
+func splitTitle(title string) (string, schema.Kind) {
+	for _, kind := range []schema.Kind{schema.Confirmation, schema.Request} {
+		suffix := kind.String()
+		action, ok := strings.CutSuffix(title, suffix)
+		if ok && action != "" {
+			return action, kind
+		}
+	}
+
+	return title, schema.Request
+}
+
+// compareDefinitions checks that a generated definition has the structure,
+// required properties, lengths and enumerations of the official one.
+func compareDefinitions(
+	t *testing.T,
+	path string,
+	generated, official *schema.Definition,
+) {
+	t.Helper()
+
+	if generated.Type != official.Type {
+		t.Errorf("%s: type = %s, want %s", path, generated.Type, official.Type)
+
+		return
+	}
+
+	if !slices.Equal(sorted(generated.Required), sorted(official.Required)) {
+		t.Errorf(
+			"%s: required = %v, want %v",
+			path,
+			generated.Required,
+			official.Required,
+		)
+	}
+
+	if official.MaxLength != nil &&
+		(generated.MaxLength == nil ||
+			*generated.MaxLength != *official.MaxLength) {
+		t.Errorf("%s: maxLength differs", path)
+	}
+
+	for _, value := range generated.Enum {
+		if !slices.Contains(official.Enum, value) {
+			t.Errorf(
+				"%s: enum value %q not in the official schema",
+				path,
+				value,
+			)
+		}
+	}
+
+	for name, property := range official.Properties {
+		counterpart, ok := generated.Properties[name]
+		if !ok {
+			t.Errorf("%s/%s: property missing", path, name)
+
+			continue
+		}
+
+		compareDefinitions(t, path+"/"+name, counterpart, property)
+	}
+
+	for name := range generated.Properties {
+		if _, ok := official.Properties[name]; !ok {
+			t.Errorf("%s/%s: property not in the official schema", path, name)
+		}
+	}
+
+	if official.Items != nil {
+		if generated.Items == nil {
+			t.Errorf("%s: items missing", path)
+
+			return
+		}
+
+		compareDefinitions(t, path+"/items", generated.Items, official.Items)
+	}
+}
+
+// checkOptional checks that every optional property of an official
+// definition, at any depth, is generated and left optional.
+func checkOptional(
+	t *testing.T,
+	path string,
+	generated, official *schema.Definition,
+) {
+	t.Helper()
+
+	for name, property := range official.Properties {
+		counterpart, ok := generated.Properties[name]
+		if !ok {
+			t.Errorf("%s/%s: property missing", path, name)
+
+			continue
+		}
+
+		if !slices.Contains(official.Required, name) &&
+			slices.Contains(generated.Required, name) {
+			t.Errorf("%s/%s: optional property required", path, name)
+		}
+
+		checkOptional(t, path+"/"+name, counterpart, property)
+	}
+
+	if official.Items != nil && generated.Items != nil {
+		checkOptional(t, path+"/items", generated.Items, official.Items)
+	}
+}
+
+// sorted returns a sorted copy of names.
+func sorted(names []string) []string {
+	return slices.Sorted(slices.Values(names))
+}
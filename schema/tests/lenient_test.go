@@ -0,0 +1,224 @@
+package schema_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/schema"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// quirkyStatus is a StatusNotification.req as sent by chargers in the field:
+// a string connectorId, lower case enumerations, an offset timestamp, an
+// over-length info and a vendor property.
+var quirkyStatus = `{"connectorId":"1","errorCode":"noError",` +
+	`"status":"charging","timestamp":"2025-01-02T16:00:00+01:00",` +
+	`"info":"` + strings.Repeat("i", 60) + `","firmware":"1.2"}`
+
+func TestDecoder_StrictRejectsQuirks(t *testing.T) {
+	t.Parallel()
+
+	decoder := schema.Decoder{Mode: schema.Strict}
+
+	_, warnings, err := decoder.Decode(
+		"StatusNotification",
+		schema.Request,
+		[]byte(quirkyStatus),
+	)
+	if !errors.Is(err, schema.ErrInvalid) {
+		t.Errorf(types.ErrorWrapping, err, schema.ErrInvalid)
+	}
+
+	if warnings != nil {
+		t.Errorf(types.ErrorMismatchValue, "no warnings", warnings)
+	}
+}
+
+func TestDecoder_LenientNormalizesQuirks(t *testing.T) {
+	t.Parallel()
+
+	decoder := schema.Decoder{Mode: schema.Lenient}
+
+	msg, warnings, err := decoder.Decode(
+		"StatusNotification",
+		schema.Request,
+		[]byte(quirkyStatus),
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	req, ok := msg.(statusnotification.ReqMessage)
+	if !ok {
+		t.Fatalf(types.ErrorMismatchValue, "statusnotification.ReqMessage", msg)
+	}
+
+	if req.ConnectorId.Value() != 1 {
+		t.Errorf(types.ErrorMismatchValue, 1, req.ConnectorId.Value())
+	}
+
+	if req.Status.String() != "Charging" {
+		t.Errorf(types.ErrorMismatchValue, "Charging", req.Status)
+	}
+
+	if req.Timestamp.String() != "2025-01-02T15:00:00Z" {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			"2025-01-02T15:00:00Z",
+			req.Timestamp,
+		)
+	}
+
+	if len(req.Info.String()) != 50 {
+		t.Errorf(types.ErrorMismatchValue, 50, len(req.Info.String()))
+	}
+
+	want := map[string]schema.Fix{
+		"/connectorId": schema.FixNumericString,
+		"/errorCode":   schema.FixEnumCase,
+		"/firmware":    schema.FixUnknownProperty,
+		"/info":        schema.FixTruncated,
+		"/status":      schema.FixEnumCase,
+		"/timestamp":   schema.FixDateTime,
+	}
+
+	if len(warnings) != len(want) {
+		t.Fatalf(types.ErrorMismatchValue, want, warnings)
+	}
+
+	for _, warning := range warnings {
+		if want[warning.Path] != warning.Fix {
+			t.Errorf(types.ErrorMismatchValue, want[warning.Path], warning)
+		}
+	}
+}
+
+func TestDecoder_LenientNested(t *testing.T) {
+	t.Parallel()
+
+	decoder := schema.Decoder{Mode: schema.Lenient}
+
+	msg, warnings, err := decoder.Decode(
+		"Authorize",
+		schema.Confirmation,
+		[]byte(`{"idTagInfo":{"status":"accepted",`+
+			`"expiryDate":"2025-06-30T00:00:00"}}`),
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	conf, ok := msg.(authorize.ConfMessage)
+	if !ok {
+		t.Fatalf(types.ErrorMismatchValue, "authorize.ConfMessage", msg)
+	}
+
+	if conf.IdTagInfo.Status().String() != "Accepted" {
+		t.Errorf(types.ErrorMismatchValue, "Accepted", conf.IdTagInfo.Status())
+	}
+
+	want := []string{
+		`/idTagInfo/expiryDate: date-time converted to UTC: ` +
+			`"2025-06-30T00:00:00" -> "2025-06-30T00:00:00Z"`,
+		`/idTagInfo/status: enum value case-folded: "accepted" -> "Accepted"`,
+	}
+
+	if len(warnings) != len(want) {
+		t.Fatalf(types.ErrorMismatchValue, want, warnings)
+	}
+
+	for i, warning := range warnings {
+		if warning.String() != want[i] {
+			t.Errorf(types.ErrorMismatchValue, want[i], warning.String())
+		}
+	}
+}
+
+func TestDecoder_LenientIntegral(t *testing.T) {
+	t.Parallel()
+
+	normalized, warnings, err := schema.Normalize(
+		"UnlockConnector",
+		schema.Request,
+		[]byte(`{"connectorId":1.0}`),
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if string(normalized) != `{"connectorId":1}` {
+		t.Errorf(types.ErrorMismatchValue, `{"connectorId":1}`, normalized)
+	}
+
+	if len(warnings) != 1 || warnings[0].Fix != schema.FixIntegral {
+		t.Errorf(types.ErrorMismatchValue, schema.FixIntegral, warnings)
+	}
+}
+
+func TestDecoder_LenientConforming(t *testing.T) {
+	t.Parallel()
+
+	decoder := schema.Decoder{Mode: schema.Lenient}
+
+	for _, tc := range conforming {
+		_, warnings, err := decoder.Decode(
+			tc.action,
+			schema.Request,
+			[]byte(tc.request),
+		)
+		if err != nil {
+			t.Errorf(types.ErrorUnexpectedError, err)
+		}
+
+		if warnings != nil {
+			t.Errorf("%s: unexpected warnings %v", tc.action, warnings)
+		}
+	}
+}
+
+func TestDecoder_LenientKeepsNonNumbers(t *testing.T) {
+	t.Parallel()
+
+	decoder := schema.Decoder{Mode: schema.Lenient}
+
+	for _, value := range []string{"NaN", "Inf", "-Inf", "infinity", "0x1"} {
+		_, warnings, err := decoder.Decode(
+			"UnlockConnector",
+			schema.Request,
+			[]byte(`{"connectorId":"`+value+`"}`),
+		)
+		if !errors.Is(err, schema.ErrType) {
+			t.Errorf(types.ErrorWrapping, err, schema.ErrType)
+		}
+
+		if !errors.Is(err, schema.ErrInvalid) {
+			t.Errorf(types.ErrorWrapping, err, schema.ErrInvalid)
+		}
+
+		if len(warnings) != 0 {
+			t.Errorf(types.ErrorMismatchValue, nil, warnings)
+		}
+	}
+}
+
+func TestDecoder_LenientRejectsUnfixable(t *testing.T) {
+	t.Parallel()
+
+	decoder := schema.Decoder{Mode: schema.Lenient}
+
+	_, warnings, err := decoder.Decode(
+		"Authorize",
+		schema.Request,
+		[]byte(`{"idTag":"`+strings.Repeat("A", 21)+`","extra":1}`),
+	)
+	if !errors.Is(err, schema.ErrMaxLength) {
+		t.Errorf(types.ErrorWrapping, err, schema.ErrMaxLength)
+	}
+
+	if len(warnings) != 1 || warnings[0].Fix != schema.FixUnknownProperty {
+		t.Errorf(types.ErrorMismatchValue, schema.FixUnknownProperty, warnings)
+	}
+}
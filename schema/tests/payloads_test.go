@@ -0,0 +1,159 @@
+package schema_test
+
+// payload is a conforming payload of one action.
+type payload struct {
+	action       string
+	request      string
+	confirmation string
+}
+
+const (
+	testTime      = `"2025-01-02T15:00:00Z"`
+	testIdTagInfo = `{"status":"Accepted","expiryDate":` + testTime + `}`
+	testSchedule  = `{"chargingRateUnit":"A","chargingSchedulePeriod":` +
+		`[{"startPeriod":0,"limit":16.0,"numberPhases":3}]}`
+	testProfile = `{"chargingProfileId":1,"stackLevel":0,` +
+		`"chargingProfilePurpose":"TxDefaultProfile",` +
+		`"chargingProfileKind":"Relative","chargingSchedule":` +
+		testSchedule + `}`
+	testMeterValue = `{"timestamp":` + testTime + `,"sampledValue":` +
+		`[{"value":"1234","measurand":"Energy.Active.Import.Register",` +
+		`"unit":"Wh"}]}`
+)
+
+// conforming lists a payload of every action accepted by both the schema
+// and the message package.
+var conforming = []payload{
+	{
+		"Authorize",
+		`{"idTag":"RFID-ABC123"}`,
+		`{"idTagInfo":` + testIdTagInfo + `}`,
+	},
+	{
+		"BootNotification",
+		`{"chargePointVendor":"Vendor","chargePointModel":"Model",` +
+			`"firmwareVersion":"1.0.0"}`,
+		`{"status":"Accepted","currentTime":` + testTime +
+			`,"interval":300}`,
+	},
+	{
+		"CancelReservation",
+		`{"reservationId":7}`,
+		`{"status":"Accepted"}`,
+	},
+	{
+		"ChangeAvailability",
+		`{"connectorId":1,"type":"Inoperative"}`,
+		`{"status":"Scheduled"}`,
+	},
+	{
+		"ChangeConfiguration",
+		`{"key":"HeartbeatInterval","value":"60"}`,
+		`{"status":"RebootRequired"}`,
+	},
+	{"ClearCache", `{}`, `{"status":"Accepted"}`},
+	{
+		"ClearChargingProfile",
+		`{"id":1,"chargingProfilePurpose":"TxDefaultProfile"}`,
+		`{"status":"Unknown"}`,
+	},
+	{
+		"DataTransfer",
+		`{"vendorId":"com.example","messageId":"ping","data":"{}"}`,
+		`{"status":"Accepted","data":"pong"}`,
+	},
+	{
+		"DiagnosticsStatusNotification",
+		`{"status":"Uploaded"}`,
+		`{}`,
+	},
+	{
+		"FirmwareStatusNotification",
+		`{"status":"Installed"}`,
+		`{}`,
+	},
+	{
+		"GetCompositeSchedule",
+		`{"connectorId":1,"duration":3600,"chargingRateUnit":"W"}`,
+		`{"status":"Accepted","connectorId":1,"scheduleStart":` + testTime +
+			`,"chargingSchedule":` + testSchedule + `}`,
+	},
+	{
+		"GetConfiguration",
+		`{"key":["HeartbeatInterval"]}`,
+		`{"configurationKey":[{"key":"HeartbeatInterval","readonly":false,` +
+			`"value":"60"}],"unknownKey":["Foo"]}`,
+	},
+	{
+		"GetDiagnostics",
+		`{"location":"ftp://diagnostics.example.com/upload","retries":3}`,
+		`{"fileName":"diagnostics.zip"}`,
+	},
+	{"GetLocalListVersion", `{}`, `{"listVersion":4}`},
+	{"Heartbeat", `{}`, `{"currentTime":` + testTime + `}`},
+	{
+		"MeterValues",
+		`{"connectorId":1,"transactionId":42,"meterValue":[` +
+			testMeterValue + `]}`,
+		`{}`,
+	},
+	{
+		"RemoteStartTransaction",
+		`{"connectorId":1,"idTag":"RFID-ABC123"}`,
+		`{"status":"Accepted"}`,
+	},
+	{
+		"RemoteStopTransaction",
+		`{"transactionId":42}`,
+		`{"status":"Rejected"}`,
+	},
+	{
+		"ReserveNow",
+		`{"connectorId":1,"expiryDate":` + testTime +
+			`,"idTag":"RFID-ABC123","reservationId":7}`,
+		`{"status":"Occupied"}`,
+	},
+	{"Reset", `{"type":"Soft"}`, `{"status":"Accepted"}`},
+	{
+		"SendLocalList",
+		`{"listVersion":5,"updateType":"Differential",` +
+			`"localAuthorizationList":[{"idTag":"RFID-ABC123",` +
+			`"idTagInfo":{"status":"Blocked"}}]}`,
+		`{"status":"VersionMismatch"}`,
+	},
+	{
+		"SetChargingProfile",
+		`{"connectorId":0,"csChargingProfiles":` + testProfile + `}`,
+		`{"status":"NotSupported"}`,
+	},
+	{
+		"StartTransaction",
+		`{"connectorId":1,"idTag":"RFID-ABC123","meterStart":0,` +
+			`"timestamp":` + testTime + `}`,
+		`{"transactionId":42,"idTagInfo":` + testIdTagInfo + `}`,
+	},
+	{
+		"StatusNotification",
+		`{"connectorId":1,"errorCode":"NoError","status":"Charging",` +
+			`"timestamp":` + testTime + `}`,
+		`{}`,
+	},
+	{
+		"StopTransaction",
+		`{"transactionId":42,"meterStop":1234,"timestamp":` + testTime +
+			`,"reason":"Local","transactionData":[` + testMeterValue + `]}`,
+		`{"idTagInfo":` + testIdTagInfo + `}`,
+	},
+	{
+		"TriggerMessage",
+		`{"requestedMessage":"StatusNotification","connectorId":1}`,
+		`{"status":"NotImplemented"}`,
+	},
+	{"UnlockConnector", `{"connectorId":1}`, `{"status":"Unlocked"}`},
+	{
+		"UpdateFirmware",
+		`{"location":"https://firmware.example.com/fw.bin",` +
+			`"retrieveDate":` + testTime + `}`,
+		`{}`,
+	},
+}
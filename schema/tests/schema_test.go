@@ -0,0 +1,516 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/schema"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const schemaFiles = 56
+
+func TestSchema_EveryAction(t *testing.T) {
+	t.Parallel()
+
+	kinds := []schema.Kind{schema.Request, schema.Confirmation}
+
+	for _, action := range ocppj.Actions() {
+		for _, kind := range kinds {
+			data, err := schema.Schema(action, kind)
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+
+			var document struct {
+				Title string `json:"title"`
+			}
+
+			err = json.Unmarshal(data, &document)
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+
+			want := action + kind.String()
+			if document.Title != want {
+				t.Errorf(types.ErrorMismatchValue, want, document.Title)
+			}
+		}
+	}
+}
+
+func TestSchema_UnknownAction(t *testing.T) {
+	t.Parallel()
+
+	_, err := schema.Schema("Foo", schema.Request)
+	if !errors.Is(err, schema.ErrUnknownAction) {
+		t.Errorf(types.ErrorWrapping, err, schema.ErrUnknownAction)
+	}
+
+	err = schema.Validate("Foo", schema.Request, []byte(`{}`))
+	if !errors.Is(err, schema.ErrUnknownAction) {
+		t.Errorf(types.ErrorWrapping, err, schema.ErrUnknownAction)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	t.Parallel()
+
+	names, err := fs.Glob(schema.Files(), "*.json")
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if len(names) != schemaFiles {
+		t.Errorf(types.ErrorMismatchValue, schemaFiles, len(names))
+	}
+}
+
+func TestValidate_Conforming(t *testing.T) {
+	t.Parallel()
+
+	if len(conforming) != len(ocppj.Actions()) {
+		t.Fatalf(
+			types.ErrorMismatchValue,
+			len(ocppj.Actions()),
+			len(conforming),
+		)
+	}
+
+	for _, tc := range conforming {
+		err := schema.ValidateRequest(tc.action, []byte(tc.request))
+		if err != nil {
+			t.Errorf(types.ErrorUnexpectedError, err)
+		}
+
+		err = schema.ValidateConfirmation(tc.action, []byte(tc.confirmation))
+		if err != nil {
+			t.Errorf(types.ErrorUnexpectedError, err)
+		}
+	}
+}
+
+func TestCrossCheck_Conforming(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range conforming {
+		err := schema.CrossCheck(tc.action, schema.Request, []byte(tc.request))
+		if err != nil {
+			t.Errorf(types.ErrorUnexpectedError, err)
+		}
+
+		err = schema.CrossCheck(
+			tc.action,
+			schema.Confirmation,
+			[]byte(tc.confirmation),
+		)
+		if err != nil {
+			t.Errorf(types.ErrorUnexpectedError, err)
+		}
+	}
+}
+
+func TestValidate_Violations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		action  string
+		kind    schema.Kind
+		payload string
+		want    error
+		path    string
+	}{
+		{
+			"malformed",
+			"Authorize",
+			schema.Request,
+			`{"idTag":`,
+			schema.ErrSyntax,
+			"",
+		},
+		{
+			"trailing data",
+			"Authorize",
+			schema.Request,
+			`{"idTag":"A"} {}`,
+			schema.ErrSyntax,
+			"",
+		},
+		{
+			"not an object",
+			"Authorize",
+			schema.Request,
+			`["A"]`,
+			schema.ErrType,
+			"/",
+		},
+		{
+			"required",
+			"Authorize",
+			schema.Request,
+			`{}`,
+			schema.ErrRequired,
+			"/idTag",
+		},
+		{
+			"additional property",
+			"Heartbeat",
+			schema.Request,
+			`{"foo":1}`,
+			schema.ErrAdditionalProperty,
+			"/foo",
+		},
+		{
+			"maxLength",
+			"Authorize",
+			schema.Request,
+			`{"idTag":"123456789012345678901"}`,
+			schema.ErrMaxLength,
+			"/idTag",
+		},
+		{
+			"nested enum",
+			"Authorize",
+			schema.Confirmation,
+			`{"idTagInfo":{"status":"accepted"}}`,
+			schema.ErrEnum,
+			"/idTagInfo/status",
+		},
+		{
+			"string for integer",
+			"UnlockConnector",
+			schema.Request,
+			`{"connectorId":"1"}`,
+			schema.ErrType,
+			"/connectorId",
+		},
+		{
+			"fraction for integer",
+			"UnlockConnector",
+			schema.Request,
+			`{"connectorId":1.0}`,
+			schema.ErrType,
+			"/connectorId",
+		},
+		{
+			"date-time",
+			"Heartbeat",
+			schema.Confirmation,
+			`{"currentTime":"2025-01-02 15:00:00"}`,
+			schema.ErrFormat,
+			"/currentTime",
+		},
+		{
+			"uri",
+			"UpdateFirmware",
+			schema.Request,
+			`{"location":"firmware.bin","retrieveDate":` + testTime + `}`,
+			schema.ErrFormat,
+			"/location",
+		},
+		{
+			"array item",
+			"MeterValues",
+			schema.Request,
+			`{"connectorId":1,"meterValue":[{"timestamp":` + testTime +
+				`,"sampledValue":[{"value":"1","unit":"Joule"}]}]}`,
+			schema.ErrEnum,
+			"/meterValue/0/sampledValue/0/unit",
+		},
+		{
+			"multipleOf",
+			"SetChargingProfile",
+			schema.Request,
+			`{"connectorId":0,"csChargingProfiles":` + strings.Replace(
+				testProfile,
+				`"limit":16.0`,
+				`"limit":16.05`,
+				1,
+			) + `}`,
+			schema.ErrMultipleOf,
+			"/csChargingProfiles/chargingSchedule/" +
+				"chargingSchedulePeriod/0/limit",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := schema.Validate(tc.action, tc.kind, []byte(tc.payload))
+			if !errors.Is(err, schema.ErrInvalid) {
+				t.Fatalf(types.ErrorWrapping, err, schema.ErrInvalid)
+			}
+
+			if !errors.Is(err, tc.want) {
+				t.Errorf(types.ErrorWrapping, err, tc.want)
+			}
+
+			if !strings.Contains(err.Error(), tc.path+": ") {
+				t.Errorf(types.ErrorWantContains, err, tc.path)
+			}
+		})
+	}
+}
+
+func TestValidate_ReportsEveryViolation(t *testing.T) {
+	t.Parallel()
+
+	err := schema.ValidateRequest(
+		"StatusNotification",
+		[]byte(`{"connectorId":"1","status":"Broken","foo":true}`),
+	)
+
+	for _, want := range []error{
+		schema.ErrType,
+		schema.ErrEnum,
+		schema.ErrRequired,
+		schema.ErrAdditionalProperty,
+	} {
+		if !errors.Is(err, want) {
+			t.Errorf(types.ErrorWrapping, err, want)
+		}
+	}
+}
+
+func TestCrossCheck_BothReject(t *testing.T) {
+	t.Parallel()
+
+	for _, payload := range []string{
+		`{}`,
+		`{"idTag":1}`,
+		`{"idTag":"123456789012345678901"}`,
+		`not json`,
+	} {
+		err := schema.CrossCheck("Authorize", schema.Request, []byte(payload))
+		if err != nil {
+			t.Errorf(types.ErrorUnexpectedError, err)
+		}
+	}
+}
+
+func TestCrossCheck_Mismatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		action        string
+		payload       string
+		schemaAccepts bool
+	}{
+		{"empty idTag", "Authorize", `{"idTag":""}`, true},
+		{"non printable idTag", "Authorize", `{"idTag":"café"}`, true},
+		{
+			"relative location",
+			"UpdateFirmware",
+			`{"location":"firmware.bin","retrieveDate":` + testTime + `}`,
+			false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := schema.CrossCheck(
+				tc.action,
+				schema.Request,
+				[]byte(tc.payload),
+			)
+			if !errors.Is(err, schema.ErrMismatch) {
+				t.Fatalf(types.ErrorWrapping, err, schema.ErrMismatch)
+			}
+
+			var mismatch *schema.Mismatch
+			if !errors.As(err, &mismatch) {
+				t.Fatalf(types.ErrorWantNonNil, "*schema.Mismatch")
+			}
+
+			if (mismatch.SchemaErr == nil) != tc.schemaAccepts {
+				t.Errorf(
+					types.ErrorMismatchValue,
+					tc.schemaAccepts,
+					mismatch.SchemaErr == nil,
+				)
+			}
+
+			if (mismatch.LibraryErr == nil) == tc.schemaAccepts {
+				t.Errorf(
+					types.ErrorMismatchValue,
+					!tc.schemaAccepts,
+					mismatch.LibraryErr == nil,
+				)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	msg, err := schema.Decode(
+		"Authorize",
+		schema.Request,
+		[]byte(`{"idTag":"RFID-ABC123"}`),
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	req, ok := msg.(authorize.ReqMessage)
+	if !ok {
+		t.Fatalf(types.ErrorMismatchValue, "authorize.ReqMessage", msg)
+	}
+
+	if req.IdTag.String() != "RFID-ABC123" {
+		t.Errorf(types.ErrorMismatchValue, "RFID-ABC123", req.IdTag)
+	}
+
+	_, err = schema.Decode(
+		"Authorize",
+		schema.Request,
+		[]byte(`{"idTag":"RFID-ABC123","foo":1}`),
+	)
+	if !errors.Is(err, schema.ErrAdditionalProperty) {
+		t.Errorf(types.ErrorWrapping, err, schema.ErrAdditionalProperty)
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	t.Parallel()
+
+	kinds := []schema.Kind{schema.Request, schema.Confirmation}
+
+	for _, tc := range conforming {
+		for _, kind := range kinds {
+			payload := tc.request
+			if kind == schema.Confirmation {
+				payload = tc.confirmation
+			}
+
+			msg, err := schema.Decode(tc.action, kind, []byte(payload))
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+
+			action, gotKind, ok := schema.Identify(msg)
+			if !ok || action != tc.action || gotKind != kind {
+				t.Errorf("%s%s: got %s%s, %t", tc.action, kind, action,
+					gotKind, ok)
+			}
+		}
+	}
+
+	_, _, ok := schema.Identify(authorize.ReqInput{IdTag: "A"})
+	if ok {
+		t.Errorf(types.ErrorMismatchValue, false, ok)
+	}
+}
+
+// TestDecode_RequiredProperties removes each property of the conforming
+// payloads in turn: the message package must reject the payload as missing
+// a value, as the official schema does, when the property is required, and
+// only then.
+func TestDecode_RequiredProperties(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range conforming {
+		for kind, payload := range map[schema.Kind]string{
+			schema.Request:      tc.request,
+			schema.Confirmation: tc.confirmation,
+		} {
+			data, err := schema.Schema(tc.action, kind)
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+
+			var official schema.Definition
+
+			err = json.Unmarshal(data, &official)
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+
+			var document any
+
+			err = json.Unmarshal([]byte(payload), &document)
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+
+			name := tc.action + kind.String()
+			checkRemovals(t, name, kind, document, document, &official)
+		}
+	}
+}
+
+// checkRemovals decodes the payload without each property of node, an
+// object or list of the payload described by definition, then recurses.
+func checkRemovals(
+	t *testing.T,
+	name string,
+	kind schema.Kind,
+	root, node any,
+	definition *schema.Definition,
+) {
+	t.Helper()
+
+	switch typed := node.(type) {
+	case []any:
+		for _, item := range typed {
+			checkRemovals(t, name, kind, root, item, definition.Items)
+		}
+	case map[string]any:
+		for property, value := range typed {
+			delete(typed, property)
+
+			err := decodeDocument(name, kind, root)
+			required := slices.Contains(definition.Required, property)
+
+			switch {
+			case required && !errors.Is(err, types.ErrEmptyValue):
+				t.Errorf("%s without %s: error = %v, want wrapping %v",
+					name, property, err, types.ErrEmptyValue)
+			case required && ocppj.ErrorFor(err).Code !=
+				ocppj.OccurenceConstraintViolation:
+				t.Errorf(types.ErrorMismatchValue,
+					ocppj.OccurenceConstraintViolation, ocppj.ErrorFor(err))
+			case !required && errors.Is(err, ocppj.ErrMissingProperty):
+				t.Errorf("%s without optional %s: %v", name, property, err)
+			}
+
+			typed[property] = value
+
+			checkRemovals(
+				t, name, kind, root, value, definition.Properties[property],
+			)
+		}
+	}
+}
+
+// decodeDocument encodes a payload and decodes it with its message package.
+func decodeDocument(name string, kind schema.Kind, document any) error {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	action := strings.TrimSuffix(name, kind.String())
+
+	if kind == schema.Confirmation {
+		_, err = ocppj.DecodeConfirmation(action, data)
+
+		return err
+	}
+
+	_, err = ocppj.DecodeRequest(action, data)
+
+	return err
+}
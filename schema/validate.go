@@ -0,0 +1,376 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	// ErrInvalid is wrapped by every validation error.
+	ErrInvalid = errors.New("schema: invalid payload")
+	// ErrSyntax is returned for a payload that is not a single JSON value.
+	ErrSyntax = errors.New("malformed JSON")
+	// ErrType is returned for a value of the wrong JSON type.
+	ErrType = errors.New("wrong type")
+	// ErrRequired is returned for a missing required property.
+	ErrRequired = errors.New("required property missing")
+	// ErrAdditionalProperty is returned for a property the schema does not
+	// define.
+	ErrAdditionalProperty = errors.New("additional property not allowed")
+	// ErrMinLength is returned for a string shorter than its minLength.
+	ErrMinLength = errors.New("string below minLength")
+	// ErrMaxLength is returned for a string longer than its maxLength.
+	ErrMaxLength = errors.New("string exceeds maxLength")
+	// ErrPattern is returned for a string that does not match its pattern.
+	ErrPattern = errors.New("string does not match pattern")
+	// ErrEnum is returned for a value outside its enumeration.
+	ErrEnum = errors.New("value not in enum")
+	// ErrFormat is returned for a string that does not match its format.
+	ErrFormat = errors.New("string does not match format")
+	// ErrMultipleOf is returned for a number that is not a multiple of its
+	// multipleOf.
+	ErrMultipleOf = errors.New("number not a multiple of multipleOf")
+	// ErrRange is returned for a number outside its minimum and maximum.
+	ErrRange = errors.New("number out of range")
+	// ErrMinItems is returned for an array with fewer items than minItems.
+	ErrMinItems = errors.New("array below minItems")
+)
+
+// multipleOfTolerance absorbs the rounding of decimal fractions in float64.
+const multipleOfTolerance = 1e-9
+
+// patterns caches compiled pattern keywords.
+var patterns sync.Map
+
+// Validate checks a raw payload against the official schema of an action.
+// The error wraps ErrInvalid and, for each violation, one of ErrSyntax,
+// ErrType, ErrRequired, ErrAdditionalProperty, ErrMinLength, ErrMaxLength,
+// ErrPattern, ErrEnum, ErrFormat, ErrMultipleOf, ErrRange or ErrMinItems
+// with the JSON pointer of the offending value.
+func Validate(action string, kind Kind, payload []byte) error {
+	root, err := load(action, kind)
+	if err != nil {
+		return err
+	}
+
+	return root.Validate(payload)
+}
+
+// Validate checks a raw payload against the definition, see the package
+// level Validate.
+func (d *Definition) Validate(payload []byte) error {
+	value, err := parse(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalid, d.Title, err)
+	}
+
+	violations := d.validate(value, "")
+	if len(violations) > 0 {
+		return fmt.Errorf(
+			"%w: %s: %w",
+			ErrInvalid,
+			d.Title,
+			errors.Join(violations...),
+		)
+	}
+
+	return nil
+}
+
+// ValidateRequest checks the payload of a CALL, see Validate.
+func ValidateRequest(action string, payload []byte) error {
+	return Validate(action, Request, payload)
+}
+
+// ValidateConfirmation checks the payload of a CALLRESULT, see Validate.
+func ValidateConfirmation(action string, payload []byte) error {
+	return Validate(action, Confirmation, payload)
+}
+
+// parse decodes a single JSON value, keeping numbers in their literal form.
+func parse(payload []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var value any
+
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSyntax, err)
+	}
+
+	_, err = decoder.Token()
+	if !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: trailing data", ErrSyntax)
+	}
+
+	return value, nil
+}
+
+// validate returns the violations of value, located at path.
+func (d *Definition) validate(value any, path string) []error {
+	if !d.hasType(value) {
+		return []error{violation(path, ErrType, "want %s", d.Type)}
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		return d.validateObject(typed, path)
+	case []any:
+		return d.validateArray(typed, path)
+	case string:
+		return d.validateString(typed, path)
+	case json.Number:
+		return d.validateNumber(typed, path)
+	default:
+		return nil
+	}
+}
+
+// hasType reports whether value is of the schema type. A draft-04 integer
+// is a number literal without fraction or exponent.
+func (d *Definition) hasType(value any) bool {
+	switch d.Type {
+	case "object":
+		_, ok := value.(map[string]any)
+
+		return ok
+	case "array":
+		_, ok := value.([]any)
+
+		return ok
+	case "string":
+		_, ok := value.(string)
+
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+
+		return ok
+	case "number":
+		_, ok := value.(json.Number)
+
+		return ok
+	case "integer":
+		number, ok := value.(json.Number)
+
+		return ok && !strings.ContainsAny(number.String(), ".eE")
+	default:
+		return true
+	}
+}
+
+// validateObject checks required and additional properties, then every
+// property in name order.
+func (d *Definition) validateObject(
+	object map[string]any,
+	path string,
+) []error {
+	var violations []error
+
+	for _, name := range d.Required {
+		_, ok := object[name]
+		if !ok {
+			violations = append(
+				violations,
+				violation(path+"/"+name, ErrRequired, ""),
+			)
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		property, ok := d.Properties[name]
+		if !ok {
+			if d.AdditionalProperties != nil && !*d.AdditionalProperties {
+				violations = append(
+					violations,
+					violation(path+"/"+name, ErrAdditionalProperty, ""),
+				)
+			}
+
+			continue
+		}
+
+		violations = append(
+			violations,
+			property.validate(object[name], path+"/"+name)...,
+		)
+	}
+
+	return violations
+}
+
+// validateArray checks minItems and every item.
+func (d *Definition) validateArray(array []any, path string) []error {
+	var violations []error
+
+	if d.MinItems != nil && len(array) < *d.MinItems {
+		violations = append(violations, violation(
+			path,
+			ErrMinItems,
+			"%d items, minItems %d",
+			len(array),
+			*d.MinItems,
+		))
+	}
+
+	if d.Items == nil {
+		return violations
+	}
+
+	for i, item := range array {
+		violations = append(
+			violations,
+			d.Items.validate(item, fmt.Sprintf("%s/%d", path, i))...,
+		)
+	}
+
+	return violations
+}
+
+// validateString checks minLength, maxLength, pattern, enum and format.
+func (d *Definition) validateString(str, path string) []error {
+	var violations []error
+
+	length := utf8.RuneCountInString(str)
+	if d.MinLength != nil && length < *d.MinLength {
+		violations = append(violations, violation(
+			path,
+			ErrMinLength,
+			"length %d, minLength %d",
+			length,
+			*d.MinLength,
+		))
+	}
+
+	if d.MaxLength != nil && length > *d.MaxLength {
+		violations = append(violations, violation(
+			path,
+			ErrMaxLength,
+			"length %d, maxLength %d",
+			length,
+			*d.MaxLength,
+		))
+	}
+
+	if d.Pattern != "" && !matchesPattern(d.Pattern, str) {
+		violations = append(
+			violations,
+			violation(path, ErrPattern, "want %s", d.Pattern),
+		)
+	}
+
+	if d.Enum != nil && !slices.Contains(d.Enum, str) {
+		violations = append(violations, violation(path, ErrEnum, "%q", str))
+	}
+
+	if !matchesFormat(d.Format, str) {
+		violations = append(
+			violations,
+			violation(path, ErrFormat, "want %s", d.Format),
+		)
+	}
+
+	return violations
+}
+
+// validateNumber checks minimum, maximum and multipleOf.
+func (d *Definition) validateNumber(number json.Number, path string) []error {
+	if d.Minimum == nil && d.Maximum == nil && d.MultipleOf == nil {
+		return nil
+	}
+
+	value, err := number.Float64()
+	if err != nil {
+		return []error{violation(path, ErrType, "%s", number)}
+	}
+
+	var violations []error
+
+	if (d.Minimum != nil && value < *d.Minimum) ||
+		(d.Maximum != nil && value > *d.Maximum) {
+		violations = append(violations, violation(path, ErrRange, "%s", number))
+	}
+
+	if d.MultipleOf == nil {
+		return violations
+	}
+
+	quotient := value / *d.MultipleOf
+	if math.Abs(quotient-math.Round(quotient)) > multipleOfTolerance {
+		violations = append(violations, violation(
+			path,
+			ErrMultipleOf,
+			"%s, multipleOf %g",
+			number,
+			*d.MultipleOf,
+		))
+	}
+
+	return violations
+}
+
+// matchesFormat reports whether str matches a draft-04 format. Unknown
+// formats always match.
+func matchesFormat(format, str string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, str)
+
+		return err == nil
+	case "uri":
+		parsed, err := url.Parse(str)
+
+		return err == nil && parsed.Scheme != ""
+	default:
+		return true
+	}
+}
+
+// matchesPattern reports whether str matches an ECMA 262 pattern. The
+// patterns used by this package are also valid RE2 expressions.
+func matchesPattern(pattern, str string) bool {
+	compiled, ok := patterns.Load(pattern)
+	if !ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false
+		}
+
+		compiled, _ = patterns.LoadOrStore(pattern, re)
+	}
+
+	//nolint:forcetypeassert // Only *regexp.Regexp.
+	return compiled.(*regexp.Regexp).MatchString(str)
+}
+
+// violation builds the error of one violation at a JSON pointer.
+func violation(path string, kind error, format string, args ...any) error {
+	if path == "" {
+		path = "/"
+	}
+
+	if format == "" {
+		return fmt.Errorf("%s: %w", path, kind)
+	}
+
+	return fmt.Errorf("%s: %w: %s", path, kind, fmt.Sprintf(format, args...))
+}
@@ -84,6 +84,9 @@ const (
 	variantCustom             = "Custom"
 	variantPrimitiveDirect    = "PrimitiveDirect"
 	variantPrimitiveValidated = "PrimitiveValidated"
+
+	variantEncodingJSON = "EncodingJSON"
+	variantAppendJSON   = "AppendJSON"
 )
 
 const (
@@ -182,8 +185,6 @@ type scalingChartConfig struct {
 func main() {
 	opts := parseFlags()
 
-	flag.Parse()
-
 	err := run(opts)
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "error: %v\n", err)
@@ -210,6 +211,8 @@ func parseFlags() options {
 		"benchmark input file",
 	)
 
+	flag.Parse()
+
 	return options{
 		ImgDir:     *imgDir,
 		ReportPath: *reportPath,
@@ -395,7 +398,7 @@ func generateCharts(imgDir string, metrics map[string]metric) error {
 		return err
 	}
 
-	return nil
+	return writeEncodingCharts(imgDir, metrics)
 }
 
 func writeScalingCharts(imgDir string, metrics map[string]metric) error {
@@ -586,6 +589,80 @@ func writeRatioChart(imgDir string, metrics map[string]metric) error {
 	)
 }
 
+func writeEncodingCharts(imgDir string, metrics map[string]metric) error {
+	charts := []struct {
+		FileName string
+		Title    string
+		Field    string
+		YLabel   string
+	}{
+		{
+			FileName: "encoding_ns.svg",
+			Title:    "encoding/json vs AppendJSON (ns/op)",
+			Field:    metricNS,
+			YLabel:   yLabelNS,
+		},
+		{
+			FileName: "encoding_allocs.svg",
+			Title:    "encoding/json vs AppendJSON (allocs/op)",
+			Field:    metricAllocs,
+			YLabel:   yLabelAllocs,
+		},
+	}
+
+	for _, chart := range charts {
+		err := writeGroupedBarChart(
+			filepath.Join(imgDir, chart.FileName),
+			chart.Title,
+			encodingFamilies(),
+			encodingSeries(metrics, chart.Field),
+			chart.YLabel,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func encodingSeries(metrics map[string]metric, field string) []svgSeries {
+	variants := []string{variantEncodingJSON, variantAppendJSON}
+	colors := benchmarkColors()
+	series := make([]svgSeries, zeroInt, len(variants))
+
+	for index, variant := range variants {
+		values := make([]float64, zeroInt, len(encodingFamilies()))
+
+		for _, family := range encodingFamilies() {
+			values = append(values, metricFieldValue(
+				lookupMetric(metrics, encodingBenchmarkName(family, variant)),
+				field,
+			))
+		}
+
+		series = append(series, svgSeries{
+			Name:   variant,
+			Color:  colors[index],
+			Values: values,
+		})
+	}
+
+	return series
+}
+
+func encodingFamilies() []string {
+	return []string{
+		"HeartbeatConf",
+		"StatusNotificationReq",
+		"MeterValuesReq",
+	}
+}
+
+func encodingBenchmarkName(family, variant string) string {
+	return "Benchmark" + family + "_" + variant
+}
+
 func scalingValues(
 	metrics map[string]metric,
 	family string,
@@ -1195,6 +1272,7 @@ func reportLines(metrics map[string]metric) []string {
 	lines = append(lines, reportIntroSection()...)
 	lines = append(lines, reportChartSection()...)
 	lines = append(lines, reportKeyNumbersSection(metrics)...)
+	lines = append(lines, reportEncodingSection(metrics)...)
 	lines = append(lines, reportAnalysisSection()...)
 
 	return lines
@@ -1217,6 +1295,8 @@ func reportIntroSection() []string {
 		"- Core constructors: `DateTime`, `ParentIdTag`, `StartTransactionReq`",
 		"- Scaling path #1: `SendLocalListReq` (1 to 1000 entries)",
 		"- Scaling path #2: `GetConfigurationReq` (1 to 1000 keys)",
+		"- Encoding: `encoding/json` vs `AppendJSON` for `HeartbeatConf`, " +
+			"`StatusNotificationReq` and `MeterValuesReq`",
 		"- Metrics: `ns/op`, `B/op`, `allocs/op`",
 		reportBlankLine,
 	}
@@ -1251,6 +1331,14 @@ func reportChartSection() []string {
 		"![Custom vs PrimitiveValidated ratio]" +
 			"(img/custom_vs_validated_ratio.svg)",
 		reportBlankLine,
+		"### 7) encoding/json vs AppendJSON (ns/op)",
+		reportBlankLine,
+		"![encoding/json vs AppendJSON ns/op](img/encoding_ns.svg)",
+		reportBlankLine,
+		"### 8) encoding/json vs AppendJSON (allocs/op)",
+		reportBlankLine,
+		"![encoding/json vs AppendJSON allocs/op](img/encoding_allocs.svg)",
+		reportBlankLine,
 	}
 }
 
@@ -1291,6 +1379,36 @@ func reportKeyNumbersSection(metrics map[string]metric) []string {
 	}
 }
 
+func reportEncodingSection(metrics map[string]metric) []string {
+	lines := []string{
+		"## Encoding",
+		reportBlankLine,
+		"| Message | encoding/json ns/op | AppendJSON ns/op | Speedup |",
+		"| ------- | ------------------: | ---------------: | ------: |",
+	}
+
+	for _, family := range encodingFamilies() {
+		encodingNS := lookupMetric(
+			metrics,
+			encodingBenchmarkName(family, variantEncodingJSON),
+		).NsOp
+		appendNS := lookupMetric(
+			metrics,
+			encodingBenchmarkName(family, variantAppendJSON),
+		).NsOp
+
+		lines = append(lines, fmt.Sprintf(
+			"| %s | %.2f | %.2f | %.2fx |",
+			family,
+			encodingNS,
+			appendNS,
+			ratio(encodingNS, appendNS),
+		))
+	}
+
+	return append(lines, reportBlankLine)
+}
+
 func reportAnalysisSection() []string {
 	return []string{
 		"## Analysis",
@@ -19,4 +19,21 @@
 // If the response status is Failed or VersionMismatch and the updateType
 // was Differential, the Central System SHOULD retry by sending the full
 // list with updateType set to Full.
+//
+// # Streaming
+//
+// Local lists may hold tens of thousands of entries. ReqDecoder reads a
+// SendLocalList.req payload from an io.Reader and validates one
+// AuthorizationData at a time; Message returns the other fields once the
+// entries are read:
+//
+//	decoder := sendlocallist.NewReqDecoder(body)
+//	for {
+//		authData, err := decoder.Next()
+//		if errors.Is(err, io.EOF) {
+//			break
+//		}
+//		// err names the invalid entry, e.g. localAuthorizationList[3]
+//	}
+//	msg, err := decoder.Message()
 package sendlocallist
@@ -0,0 +1,66 @@
+package sendlocallist
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		ListVersion: int(m.ListVersion.Value()),
+		LocalAuthorizationList: wire.AuthorizationDataInputs(
+			m.LocalAuthorizationList,
+		),
+		UpdateType: m.UpdateType.String(),
+	}
+}
+
+// WithListVersion returns a copy of the message with ListVersion set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithListVersion(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ListVersion = value
+
+	return Req(input)
+}
+
+// WithLocalAuthorizationList returns a copy of the message with
+// LocalAuthorizationList set to value. The copy is validated by Req.
+func (m ReqMessage) WithLocalAuthorizationList(
+	value []types.AuthorizationDataInput,
+) (ReqMessage, error) {
+	input := m.ToInput()
+	input.LocalAuthorizationList = value
+
+	return Req(input)
+}
+
+// WithUpdateType returns a copy of the message with UpdateType set to value.
+// The copy is validated by Req.
+func (m ReqMessage) WithUpdateType(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.UpdateType = value
+
+	return Req(input)
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	return ConfInput{
+		Status: m.Status.String(),
+	}
+}
+
+// WithStatus returns a copy of the message with Status set to value. The
+// copy is validated by Conf.
+func (m ConfMessage) WithStatus(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.Status = value
+
+	return Conf(input)
+}
@@ -0,0 +1,59 @@
+package sendlocallist
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of SendLocalList.req.
+type reqWire struct {
+	ListVersion            uint16                   `json:"listVersion"`
+	LocalAuthorizationList []wire.AuthorizationData `json:"localAuthorizationList,omitempty"`
+	UpdateType             string                   `json:"updateType"`
+}
+
+// confWire is the OCPP-J payload of SendLocalList.conf.
+type confWire struct {
+	Status string `json:"status"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J SendLocalList.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		ListVersion:            m.ListVersion.Value(),
+		LocalAuthorizationList: wire.NewAuthorizationList(m.LocalAuthorizationList),
+		UpdateType:             m.UpdateType.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J SendLocalList.req payload and validates it
+// with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J SendLocalList.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		Status: m.Status.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J SendLocalList.conf payload and validates it
+// with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package sendlocallist
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// ReqDecoder reads an OCPP-J SendLocalList.req payload from an io.Reader and
+// validates its localAuthorizationList entries one at a time, so lists of
+// tens of thousands of entries are never held in memory as a whole.
+type ReqDecoder struct {
+	stream *wire.Stream
+}
+
+// NewReqDecoder returns a ReqDecoder reading the payload from r.
+func NewReqDecoder(r io.Reader) *ReqDecoder {
+	return &ReqDecoder{stream: wire.NewStream(r, "localAuthorizationList")}
+}
+
+// Next returns the next localAuthorizationList entry, validated as by Req.
+// It returns io.EOF after the last entry. An invalid entry yields an error
+// prefixed with its index, e.g. localAuthorizationList[3], and Next may be
+// called again; a malformed payload ends the stream.
+func (d *ReqDecoder) Next() (types.AuthorizationData, error) {
+	var input types.AuthorizationDataInput
+
+	index, err := d.stream.Next(&input)
+	if err != nil {
+		return types.AuthorizationData{}, err //nolint:wrapcheck // Wrapped.
+	}
+
+	authData, err := types.NewAuthorizationData(input)
+	if err != nil {
+		return types.AuthorizationData{}, fmt.Errorf(
+			"localAuthorizationList[%d]: %w",
+			index,
+			err,
+		)
+	}
+
+	return authData, nil
+}
+
+// Message reads the payload to its end, skipping the entries Next did not
+// return, and returns the message with ListVersion and UpdateType validated
+// as by Req. Its LocalAuthorizationList is nil: the entries are the ones
+// returned by Next. Returns an error if the payload is malformed or a field
+// is invalid.
+func (d *ReqDecoder) Message() (ReqMessage, error) {
+	var input ReqInput
+
+	_, err := d.stream.Rest(&input)
+	if err != nil {
+		return ReqMessage{}, err //nolint:wrapcheck // Already wrapped.
+	}
+
+	var (
+		errs      []error
+		validated reqValidation
+	)
+
+	validated.listVersion, errs = validateReqListVersion(
+		input.ListVersion,
+		errs,
+	)
+
+	validated.updateType, errs = validateReqUpdateType(
+		input.UpdateType,
+		errs,
+	)
+
+	if errs != nil {
+		return ReqMessage{}, errors.Join(errs...)
+	}
+
+	return ReqMessage{
+		ListVersion:            validated.listVersion,
+		LocalAuthorizationList: nil,
+		UpdateType:             validated.updateType,
+	}, nil
+}
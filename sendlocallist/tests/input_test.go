@@ -0,0 +1,61 @@
+package sendlocallist_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/sendlocallist"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+func TestReqMessage_ToInput(t *testing.T) {
+	t.Parallel()
+
+	expiryDate := "2025-12-31T23:59:59Z"
+	input := sendlocallist.ReqInput{
+		ListVersion: 7,
+		LocalAuthorizationList: []types.AuthorizationDataInput{
+			{IdTag: "TAG-ONE", IdTagInfo: nil},
+			{
+				IdTag: "TAG-TWO",
+				IdTagInfo: &types.IdTagInfoInput{
+					Status:      "Accepted",
+					ExpiryDate:  &expiryDate,
+					ParentIdTag: nil,
+				},
+			},
+		},
+		UpdateType: "Differential",
+	}
+
+	req, err := sendlocallist.Req(input)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	got := req.ToInput()
+	if !reflect.DeepEqual(got, input) {
+		t.Errorf(types.ErrorMismatch, input, got)
+	}
+
+	updated, err := req.WithListVersion(8)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if !reflect.DeepEqual(
+		updated.LocalAuthorizationList,
+		req.LocalAuthorizationList,
+	) {
+		t.Errorf(
+			types.ErrorMismatch,
+			req.LocalAuthorizationList,
+			updated.LocalAuthorizationList,
+		)
+	}
+
+	_, err = req.WithUpdateType("Partial")
+	if err == nil {
+		t.Errorf(types.ErrorWantNonNil, "invalid updateType")
+	}
+}
@@ -0,0 +1,139 @@
+package sendlocallist_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/sendlocallist"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// localListPayload returns a SendLocalList.req payload with entries id tags,
+// produced lazily by a reader.
+func localListPayload(entries int) io.Reader {
+	readers := []io.Reader{
+		strings.NewReader(`{"listVersion":3,"localAuthorizationList":[`),
+	}
+
+	for i := range entries {
+		separator := ","
+		if i == 0 {
+			separator = ""
+		}
+
+		readers = append(readers, strings.NewReader(fmt.Sprintf(
+			`%s{"idTag":"TAG-%d","idTagInfo":{"status":"Accepted"}}`,
+			separator,
+			i,
+		)))
+	}
+
+	return io.MultiReader(append(
+		readers,
+		strings.NewReader(`],"updateType":"Full"}`),
+	)...)
+}
+
+func TestReqDecoder_Stream(t *testing.T) {
+	t.Parallel()
+
+	const entries = 20000
+
+	decoder := sendlocallist.NewReqDecoder(localListPayload(entries))
+
+	for i := range entries {
+		authData, err := decoder.Next()
+		if err != nil {
+			t.Fatalf(types.ErrorUnexpectedError, err)
+		}
+
+		want := fmt.Sprintf("TAG-%d", i)
+		if authData.IdTag().String() != want {
+			t.Fatalf(types.ErrorMismatchValue, want, authData.IdTag())
+		}
+	}
+
+	_, err := decoder.Next()
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf(types.ErrorWrapping, err, io.EOF)
+	}
+
+	msg, err := decoder.Message()
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if msg.ListVersion.Value() != 3 || msg.UpdateType.String() != "Full" {
+		t.Errorf(types.ErrorMismatchValue, "3 Full", msg)
+	}
+}
+
+func TestReqDecoder_InvalidEntry(t *testing.T) {
+	t.Parallel()
+
+	decoder := sendlocallist.NewReqDecoder(strings.NewReader(
+		`{"updateType":"Differential","listVersion":4,` +
+			`"localAuthorizationList":[{"idTag":"TAG-0"},` +
+			`{"idTag":"TAG-1","expiry":1},{"idTag":""}]}`,
+	))
+
+	_, err := decoder.Next()
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	_, err = decoder.Next()
+	if !errors.Is(err, ocppj.ErrUnknownProperty) ||
+		!strings.Contains(err.Error(), "localAuthorizationList[1]") {
+		t.Errorf(types.ErrorWantContains, err, "localAuthorizationList[1]")
+	}
+
+	_, err = decoder.Next()
+	if !errors.Is(err, types.ErrEmptyValue) ||
+		!strings.Contains(err.Error(), "localAuthorizationList[2]") {
+		t.Errorf(types.ErrorWantContains, err, "localAuthorizationList[2]")
+	}
+
+	msg, err := decoder.Message()
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if msg.UpdateType.String() != "Differential" {
+		t.Errorf(types.ErrorMismatchValue, "Differential", msg.UpdateType)
+	}
+}
+
+func TestReqDecoder_FormationViolation(t *testing.T) {
+	t.Parallel()
+
+	payloads := []string{
+		`[]`,
+		`{"localAuthorizationList":{}}`,
+		`{"localAuthorizationList":[],"localAuthorizationList":[]}`,
+		`{"listVersion":1,"listVersion":2,"updateType":"Full"}`,
+		`{"listVersion":1,"updateType":"Full","foo":1}`,
+		`{"listVersion":1,"updateType":"Full"} {}`,
+	}
+
+	for _, payload := range payloads {
+		decoder := sendlocallist.NewReqDecoder(strings.NewReader(payload))
+
+		_, err := decoder.Message()
+		if err == nil {
+			t.Errorf("Message(%s): want error", payload)
+
+			continue
+		}
+
+		code := ocppj.ErrorFor(err).Code
+		if code != ocppj.FormationViolation &&
+			code != ocppj.TypeConstraintViolation {
+			t.Errorf("Message(%s): code = %s", payload, code)
+		}
+	}
+}
@@ -72,4 +72,14 @@
 //   - If recurrencyKind period is longer than schedule duration, Charge
 //     Point SHALL fall back to default behavior or lower stackLevel profile;
 //     if none available, normal charging is allowed.
+//
+// # Semantic Validation
+//
+// Req only checks that each field is well formed. Validate applies the
+// cross-field rules above (purpose versus connectorId, profile kind versus
+// startSchedule and recurrencyKind, startPeriod ordering) together with the
+// Charge Point limits given in ValidationConfig (ChargeProfileMaxStackLevel
+// and ChargingScheduleAllowedChargingRateUnit); a nil limit or an empty
+// unit list is unset and skips its check. A Central System SHOULD run it
+// before sending the request.
 package setchargingprofile
@@ -0,0 +1,60 @@
+package setchargingprofile_test
+
+import (
+	"fmt"
+
+	"github.com/aasanchez/ocpp16messages/setchargingprofile"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const exampleMaxStackLevel = 8
+
+// ExampleValidate demonstrates checking a well-formed request against the
+// semantic rules before sending it to a Charge Point connector that only
+// supports Ampere schedules.
+func ExampleValidate() {
+	req, err := setchargingprofile.Req(setchargingprofile.ReqInput{
+		ConnectorId: exampleConnectorId,
+		CsChargingProfiles: types.ChargingProfileInput{
+			ChargingProfileId:      exampleProfileId,
+			TransactionId:          nil,
+			StackLevel:             exampleStackLevel,
+			ChargingProfilePurpose: "ChargePointMaxProfile",
+			ChargingProfileKind:    "Absolute",
+			RecurrencyKind:         nil,
+			ValidFrom:              nil,
+			ValidTo:                nil,
+			ChargingSchedule: types.ChargingScheduleInput{
+				Duration:         nil,
+				ChargingRateUnit: "W",
+				ChargingSchedulePeriod: []types.ChargingSchedulePeriodInput{
+					{
+						StartPeriod:  exampleStartPeriod,
+						Limit:        exampleLimit,
+						NumberPhases: nil,
+					},
+				},
+				MinChargingRate: nil,
+				StartSchedule:   nil,
+			},
+		},
+	})
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	maxStackLevel := exampleMaxStackLevel
+
+	err = setchargingprofile.Validate(req, setchargingprofile.ValidationConfig{
+		ChargeProfileMaxStackLevel: &maxStackLevel,
+		ChargingScheduleAllowedChargingRateUnit: []types.ChargingRateUnit{
+			types.ChargingRateUnitAmperes,
+		},
+	})
+	fmt.Println(err)
+	// Output:
+	// connectorId: invalid value: ChargePointMaxProfile requires connectorId 0
+	// csChargingProfiles.chargingSchedule.chargingRateUnit: invalid value: W is not supported by the Charge Point
+}
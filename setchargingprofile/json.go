@@ -0,0 +1,58 @@
+package setchargingprofile
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of SetChargingProfile.req.
+type reqWire struct {
+	ConnectorId        uint16               `json:"connectorId"`
+	CsChargingProfiles wire.ChargingProfile `json:"csChargingProfiles"`
+}
+
+// confWire is the OCPP-J payload of SetChargingProfile.conf.
+type confWire struct {
+	Status string `json:"status"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J SetChargingProfile.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		ConnectorId:        m.ConnectorId.Value(),
+		CsChargingProfiles: wire.NewChargingProfile(m.CsChargingProfiles),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J SetChargingProfile.req payload and validates
+// it with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J SetChargingProfile.conf
+// payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		Status: m.Status.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J SetChargingProfile.conf payload and validates
+// it with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package setchargingprofile_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/setchargingprofile"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+func TestReqMessage_ToInput(t *testing.T) {
+	t.Parallel()
+
+	profile := validChargingProfileInput()
+	transactionId := valueTwo
+	validFrom := "2025-01-02T15:00:00Z"
+	phases := valueOne
+	profile.TransactionId = &transactionId
+	profile.ChargingProfilePurpose = "TxProfile"
+	profile.ValidFrom = &validFrom
+	profile.ChargingSchedule.ChargingSchedulePeriod[0].NumberPhases = &phases
+
+	input := setchargingprofile.ReqInput{
+		ConnectorId:        valueOne,
+		CsChargingProfiles: profile,
+	}
+
+	req := mustReq(t, input.ConnectorId, input.CsChargingProfiles)
+
+	got := req.ToInput()
+	if !reflect.DeepEqual(got, input) {
+		t.Errorf(types.ErrorMismatch, input, got)
+	}
+
+	rebuilt, err := setchargingprofile.Req(got)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if !reflect.DeepEqual(rebuilt, req) {
+		t.Errorf(types.ErrorMismatch, req, rebuilt)
+	}
+}
+
+func TestReqMessage_WithConnectorId(t *testing.T) {
+	t.Parallel()
+
+	req := mustReq(t, valueZero, validChargingProfileInput())
+
+	updated, err := req.WithConnectorId(valueTwo)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if updated.ConnectorId.Value() != valueTwo {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			valueTwo,
+			updated.ConnectorId.Value(),
+		)
+	}
+
+	if req.ConnectorId.Value() != valueZero {
+		t.Errorf(types.ErrorMismatchValue, valueZero, req.ConnectorId.Value())
+	}
+}
+
+func TestReqMessage_WithCsChargingProfiles_Revalidates(t *testing.T) {
+	t.Parallel()
+
+	req := mustReq(t, valueZero, validChargingProfileInput())
+
+	profile := req.ToInput().CsChargingProfiles
+	profile.StackLevel = valueNegative
+
+	_, err := req.WithCsChargingProfiles(profile)
+	if err == nil {
+		t.Errorf(types.ErrorWantNonNil, "negative stackLevel")
+	}
+}
@@ -0,0 +1,293 @@
+package setchargingprofile_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/setchargingprofile"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	valueThree       = 3
+	valueSixty       = 60
+	valueMaxStack    = 2
+	errStackLevel    = "csChargingProfiles.stackLevel"
+	errRateUnit      = "csChargingProfiles.chargingSchedule.chargingRateUnit"
+	errStartSchedule = "csChargingProfiles.chargingSchedule.startSchedule"
+	errRecurrency    = "csChargingProfiles.recurrencyKind"
+	errTransactionId = "csChargingProfiles.transactionId"
+	errPeriodOne     = "chargingSchedulePeriod[1].startPeriod"
+	errPeriodZero    = "chargingSchedulePeriod[0].startPeriod"
+	startSchedule    = "2025-01-15T10:00:00Z"
+	recurrencyDaily  = "Daily"
+)
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func validationConfig() setchargingprofile.ValidationConfig {
+	return setchargingprofile.ValidationConfig{
+		ChargeProfileMaxStackLevel: intPtr(valueMaxStack),
+		ChargingScheduleAllowedChargingRateUnit: []types.ChargingRateUnit{
+			types.ChargingRateUnitWatts,
+		},
+	}
+}
+
+func mustReq(
+	t *testing.T,
+	connectorId int,
+	profile types.ChargingProfileInput,
+) setchargingprofile.ReqMessage {
+	t.Helper()
+
+	req, err := setchargingprofile.Req(setchargingprofile.ReqInput{
+		ConnectorId:        connectorId,
+		CsChargingProfiles: profile,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return req
+}
+
+func assertInvalid(t *testing.T, err error, want string) {
+	t.Helper()
+
+	if err == nil {
+		t.Fatalf(types.ErrorWantNonNil, "error")
+	}
+
+	if !errors.Is(err, types.ErrInvalidValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrInvalidValue)
+	}
+
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf(types.ErrorWantContains, err, want)
+	}
+}
+
+func TestValidate_Valid(t *testing.T) {
+	t.Parallel()
+
+	req := mustReq(t, valueZero, validChargingProfileInput())
+
+	err := setchargingprofile.Validate(req, validationConfig())
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+}
+
+func TestValidate_Valid_EmptyAllowedUnits(t *testing.T) {
+	t.Parallel()
+
+	profile := validChargingProfileInput()
+	profile.ChargingSchedule.ChargingRateUnit = "A"
+
+	req := mustReq(t, valueZero, profile)
+
+	err := setchargingprofile.Validate(req, setchargingprofile.ValidationConfig{
+		ChargeProfileMaxStackLevel:              intPtr(valueMaxStack),
+		ChargingScheduleAllowedChargingRateUnit: nil,
+	})
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+}
+
+func TestValidate_Valid_UnsetMaxStackLevel(t *testing.T) {
+	t.Parallel()
+
+	profile := validChargingProfileInput()
+	profile.StackLevel = valueThree
+
+	req := mustReq(t, valueZero, profile)
+
+	err := setchargingprofile.Validate(req, setchargingprofile.ValidationConfig{
+		ChargeProfileMaxStackLevel:              nil,
+		ChargingScheduleAllowedChargingRateUnit: nil,
+	})
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+}
+
+func TestValidate_ZeroMaxStackLevel(t *testing.T) {
+	t.Parallel()
+
+	profile := validChargingProfileInput()
+	profile.StackLevel = valueThree
+
+	req := mustReq(t, valueZero, profile)
+
+	err := setchargingprofile.Validate(req, setchargingprofile.ValidationConfig{
+		ChargeProfileMaxStackLevel:              intPtr(valueZero),
+		ChargingScheduleAllowedChargingRateUnit: nil,
+	})
+	assertInvalid(t, err, errStackLevel)
+}
+
+func TestValidate_Valid_TxProfileWithTransaction(t *testing.T) {
+	t.Parallel()
+
+	transactionId := valueOne
+	profile := validChargingProfileInput()
+	profile.ChargingProfilePurpose = "TxProfile"
+	profile.TransactionId = &transactionId
+
+	req := mustReq(t, valueOne, profile)
+
+	err := setchargingprofile.Validate(req, validationConfig())
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+}
+
+func TestValidate_TxProfileOnConnectorZero(t *testing.T) {
+	t.Parallel()
+
+	profile := validChargingProfileInput()
+	profile.ChargingProfilePurpose = "TxProfile"
+
+	req := mustReq(t, valueZero, profile)
+
+	err := setchargingprofile.Validate(req, validationConfig())
+	assertInvalid(t, err, errConnectorId)
+}
+
+func TestValidate_ChargePointMaxProfileOnConnector(t *testing.T) {
+	t.Parallel()
+
+	profile := validChargingProfileInput()
+	profile.ChargingProfilePurpose = "ChargePointMaxProfile"
+
+	req := mustReq(t, valueOne, profile)
+
+	err := setchargingprofile.Validate(req, validationConfig())
+	assertInvalid(t, err, errConnectorId)
+}
+
+func TestValidate_TransactionIdWithoutTxProfile(t *testing.T) {
+	t.Parallel()
+
+	transactionId := valueOne
+	profile := validChargingProfileInput()
+	profile.TransactionId = &transactionId
+
+	req := mustReq(t, valueOne, profile)
+
+	err := setchargingprofile.Validate(req, validationConfig())
+	assertInvalid(t, err, errTransactionId)
+}
+
+func TestValidate_RelativeWithStartSchedule(t *testing.T) {
+	t.Parallel()
+
+	start := startSchedule
+	profile := validChargingProfileInput()
+	profile.ChargingProfileKind = "Relative"
+	profile.ChargingSchedule.StartSchedule = &start
+
+	req := mustReq(t, valueZero, profile)
+
+	err := setchargingprofile.Validate(req, validationConfig())
+	assertInvalid(t, err, errStartSchedule)
+}
+
+func TestValidate_RecurringWithoutRecurrencyKind(t *testing.T) {
+	t.Parallel()
+
+	profile := validChargingProfileInput()
+	profile.ChargingProfileKind = "Recurring"
+
+	req := mustReq(t, valueZero, profile)
+
+	err := setchargingprofile.Validate(req, validationConfig())
+	assertInvalid(t, err, errRecurrency)
+}
+
+func TestValidate_RecurrencyKindWithoutRecurring(t *testing.T) {
+	t.Parallel()
+
+	recurrency := recurrencyDaily
+	profile := validChargingProfileInput()
+	profile.RecurrencyKind = &recurrency
+
+	req := mustReq(t, valueZero, profile)
+
+	err := setchargingprofile.Validate(req, validationConfig())
+	assertInvalid(t, err, errRecurrency)
+}
+
+func TestValidate_FirstPeriodNotZero(t *testing.T) {
+	t.Parallel()
+
+	profile := validChargingProfileInput()
+	profile.ChargingSchedule.ChargingSchedulePeriod[0].StartPeriod = valueSixty
+
+	req := mustReq(t, valueZero, profile)
+
+	err := setchargingprofile.Validate(req, validationConfig())
+	assertInvalid(t, err, errPeriodZero)
+}
+
+func TestValidate_PeriodsNotIncreasing(t *testing.T) {
+	t.Parallel()
+
+	profile := validChargingProfileInput()
+	profile.ChargingSchedule.ChargingSchedulePeriod = append(
+		profile.ChargingSchedule.ChargingSchedulePeriod,
+		types.ChargingSchedulePeriodInput{
+			StartPeriod:  valueZero,
+			Limit:        valueLimitThirty,
+			NumberPhases: nil,
+		},
+	)
+
+	req := mustReq(t, valueZero, profile)
+
+	err := setchargingprofile.Validate(req, validationConfig())
+	assertInvalid(t, err, errPeriodOne)
+}
+
+func TestValidate_StackLevelExceedsMax(t *testing.T) {
+	t.Parallel()
+
+	profile := validChargingProfileInput()
+	profile.StackLevel = valueThree
+
+	req := mustReq(t, valueZero, profile)
+
+	err := setchargingprofile.Validate(req, validationConfig())
+	assertInvalid(t, err, errStackLevel)
+}
+
+func TestValidate_UnsupportedRateUnit(t *testing.T) {
+	t.Parallel()
+
+	profile := validChargingProfileInput()
+	profile.ChargingSchedule.ChargingRateUnit = "A"
+
+	req := mustReq(t, valueZero, profile)
+
+	err := setchargingprofile.Validate(req, validationConfig())
+	assertInvalid(t, err, errRateUnit)
+}
+
+func TestValidate_MultipleErrors(t *testing.T) {
+	t.Parallel()
+
+	profile := validChargingProfileInput()
+	profile.ChargingProfilePurpose = "TxProfile"
+	profile.StackLevel = valueThree
+
+	req := mustReq(t, valueZero, profile)
+
+	err := setchargingprofile.Validate(req, validationConfig())
+	assertInvalid(t, err, errConnectorId)
+	assertInvalid(t, err, errStackLevel)
+}
@@ -0,0 +1,235 @@
+package setchargingprofile
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const (
+	// chargePointConnectorId is the connectorId addressing the Charge Point
+	// as a whole.
+	chargePointConnectorId = 0
+	// firstStartPeriod is the required startPeriod of the first period.
+	firstStartPeriod = 0
+	// firstPeriodIndex is the index of the first schedule period.
+	firstPeriodIndex = 0
+	// unitsLenZero is the empty allowed units count.
+	unitsLenZero = 0
+)
+
+// ValidationConfig holds the Charge Point configuration the semantic rules
+// of Validate are checked against. Field names follow the OCPP 1.6 Smart
+// Charging configuration keys.
+type ValidationConfig struct {
+	// ChargeProfileMaxStackLevel is the maximum stackLevel the Charge Point
+	// accepts for a ChargingProfile. Nil disables the check; 0 allows only
+	// stackLevel 0.
+	ChargeProfileMaxStackLevel *int
+	// ChargingScheduleAllowedChargingRateUnit lists the charging rate units
+	// the Charge Point supports. An empty list disables the check.
+	ChargingScheduleAllowedChargingRateUnit []types.ChargingRateUnit
+}
+
+// Validate applies the cross-field rules of the OCPP 1.6 specification to an
+// already constructed SetChargingProfile.req. Req only validates field
+// formats; Validate is a separate layer a Central System runs before sending
+// the request to a specific Charge Point. All violations are accumulated and
+// returned together, each wrapping types.ErrInvalidValue. Returns an error if:
+//   - A TxProfile targets connectorId 0
+//   - A ChargePointMaxProfile targets a connectorId other than 0
+//   - A transactionId is set on a profile that is not a TxProfile
+//   - A Relative profile has a startSchedule
+//   - A Recurring profile has no recurrencyKind, or a non-Recurring profile
+//     has one
+//   - The first chargingSchedulePeriod does not start at 0, or startPeriod
+//     values are not strictly increasing
+//   - The stackLevel exceeds ChargeProfileMaxStackLevel, when it is set
+//   - The chargingRateUnit is not in ChargingScheduleAllowedChargingRateUnit
+func Validate(req ReqMessage, config ValidationConfig) error {
+	var errs []error
+
+	profile := req.CsChargingProfiles
+
+	errs = validatePurposeConnector(
+		profile.ChargingProfilePurpose(),
+		req.ConnectorId.Value(),
+		errs,
+	)
+	errs = validateTransactionScope(profile, errs)
+	errs = validateProfileKind(profile, errs)
+	errs = validatePeriods(profile.ChargingSchedule(), errs)
+	errs = validateStackLevel(profile.StackLevel(), config, errs)
+	errs = validateRateUnit(profile.ChargingSchedule(), config, errs)
+
+	return errors.Join(errs...)
+}
+
+// validatePurposeConnector checks which connectors a purpose may target.
+func validatePurposeConnector(
+	purpose types.ChargingProfilePurposeType,
+	connectorId uint16,
+	errs []error,
+) []error {
+	switch purpose {
+	case types.TxProfile:
+		if connectorId == chargePointConnectorId {
+			return append(errs, fmt.Errorf(
+				"connectorId: %w: TxProfile requires connectorId > 0",
+				types.ErrInvalidValue,
+			))
+		}
+	case types.ChargePointMaxProfile:
+		if connectorId != chargePointConnectorId {
+			return append(errs, fmt.Errorf(
+				"connectorId: %w: ChargePointMaxProfile requires connectorId 0",
+				types.ErrInvalidValue,
+			))
+		}
+	case types.TxDefaultProfile:
+	}
+
+	return errs
+}
+
+// validateTransactionScope checks that transactionId is only used by a
+// TxProfile.
+func validateTransactionScope(
+	profile types.ChargingProfile,
+	errs []error,
+) []error {
+	if profile.TransactionId() != nil &&
+		profile.ChargingProfilePurpose() != types.TxProfile {
+		return append(errs, fmt.Errorf(
+			"csChargingProfiles.transactionId: %w: only valid for TxProfile",
+			types.ErrInvalidValue,
+		))
+	}
+
+	return errs
+}
+
+// validateProfileKind checks the fields that depend on chargingProfileKind.
+func validateProfileKind(
+	profile types.ChargingProfile,
+	errs []error,
+) []error {
+	kind := profile.ChargingProfileKind()
+
+	if kind == types.ChargingProfileKindRelative &&
+		profile.ChargingSchedule().StartSchedule() != nil {
+		errs = append(errs, fmt.Errorf(
+			"csChargingProfiles.chargingSchedule.startSchedule: %w: "+
+				"not allowed for Relative profiles",
+			types.ErrInvalidValue,
+		))
+	}
+
+	hasRecurrency := profile.RecurrencyKind() != nil
+
+	if kind == types.ChargingProfileKindRecurring && !hasRecurrency {
+		errs = append(errs, fmt.Errorf(
+			"csChargingProfiles.recurrencyKind: %w: required for Recurring "+
+				"profiles",
+			types.ErrInvalidValue,
+		))
+	}
+
+	if kind != types.ChargingProfileKindRecurring && hasRecurrency {
+		errs = append(errs, fmt.Errorf(
+			"csChargingProfiles.recurrencyKind: %w: only valid for Recurring "+
+				"profiles",
+			types.ErrInvalidValue,
+		))
+	}
+
+	return errs
+}
+
+// validatePeriods checks that the first period starts at 0 and that
+// startPeriod values are strictly increasing.
+func validatePeriods(schedule types.ChargingSchedule, errs []error) []error {
+	periods := schedule.ChargingSchedulePeriod()
+
+	for i, period := range periods {
+		startPeriod := period.StartPeriod().Value()
+
+		if i == firstPeriodIndex {
+			if startPeriod != firstStartPeriod {
+				errs = append(errs, fmt.Errorf(
+					"csChargingProfiles.chargingSchedule."+
+						"chargingSchedulePeriod[%d].startPeriod: %w: "+
+						"first period must start at 0",
+					i,
+					types.ErrInvalidValue,
+				))
+			}
+
+			continue
+		}
+
+		previous := periods[i-1].StartPeriod().Value()
+		if startPeriod <= previous {
+			errs = append(errs, fmt.Errorf(
+				"csChargingProfiles.chargingSchedule."+
+					"chargingSchedulePeriod[%d].startPeriod: %w: "+
+					"%d is not greater than %d",
+				i,
+				types.ErrInvalidValue,
+				startPeriod,
+				previous,
+			))
+		}
+	}
+
+	return errs
+}
+
+// validateStackLevel checks the stack level against the configured maximum.
+func validateStackLevel(
+	stackLevel types.Integer,
+	config ValidationConfig,
+	errs []error,
+) []error {
+	if config.ChargeProfileMaxStackLevel == nil {
+		return errs
+	}
+
+	if int(stackLevel.Value()) > *config.ChargeProfileMaxStackLevel {
+		return append(errs, fmt.Errorf(
+			"csChargingProfiles.stackLevel: %w: %d exceeds "+
+				"ChargeProfileMaxStackLevel %d",
+			types.ErrInvalidValue,
+			stackLevel.Value(),
+			*config.ChargeProfileMaxStackLevel,
+		))
+	}
+
+	return errs
+}
+
+// validateRateUnit checks the schedule unit against the supported units.
+func validateRateUnit(
+	schedule types.ChargingSchedule,
+	config ValidationConfig,
+	errs []error,
+) []error {
+	allowed := config.ChargingScheduleAllowedChargingRateUnit
+	if len(allowed) == unitsLenZero {
+		return errs
+	}
+
+	unit := schedule.ChargingRateUnit()
+	if !slices.Contains(allowed, unit) {
+		return append(errs, fmt.Errorf(
+			"csChargingProfiles.chargingSchedule.chargingRateUnit: %w: "+
+				"%s is not supported by the Charge Point",
+			types.ErrInvalidValue,
+			unit.String(),
+		))
+	}
+
+	return errs
+}
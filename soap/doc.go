@@ -0,0 +1,36 @@
+// Package soap implements OCPP-S, the SOAP transport of OCPP 1.6, on top of
+// the message packages of this module, so a backend can serve Charge Points
+// speaking SOAP and OCPP-J with the same typed messages.
+//
+// # Envelopes
+//
+// Marshal encodes a ReqMessage or ConfMessage as a SOAP 1.2 envelope. The
+// body element is named after the OCPP 1.6 WSDL, the action with a lower
+// case initial and a Request or Response suffix, e.g. authorizeRequest, in
+// the namespace of the receiving service:
+//   - urn://Ocpp/Cs/2015/10/ for the actions sent by Charge Points
+//   - urn://Ocpp/Cp/2015/10/ for the actions sent by Central Systems
+//
+// Its children carry the property names of the OCPP-J payload in the order
+// of the WSDL, with lists as repeated elements. The mapping is derived from
+// the official JSON schemas of the schema package, so all 28 actions are
+// covered without per-message code.
+//
+// Header carries the chargeBoxIdentity and the WS-Addressing Action,
+// MessageID, RelatesTo, From and To headers. Unmarshal converts an envelope
+// back to the OCPP-J payload and decodes it with ocppj.DecodeRequest or
+// ocppj.DecodeConfirmation, which run the Req/Conf constructors. Faults
+// carry the OCPP-J error code as their subcode, e.g. cs:SecurityError, and
+// are returned as an *ocppj.Error.
+//
+// # HTTP
+//
+// Handler serves the Central System or Charge Point service over HTTP and
+// dispatches requests to an ocppj.Handler, the same function an ocppj.Conn
+// uses; RequestHeader gives it the chargeBoxIdentity and the From address
+// of the Charge Point service. Client posts requests to the peer's service:
+//
+//	client := soap.NewClient(centralSystemURL, ocppj.RoleChargePoint,
+//		soap.Header{ChargeBoxIdentity: "CP001", From: ownURL}, nil)
+//	err := client.Call(ctx, ocppj.ActionAuthorize, req, &conf)
+package soap
@@ -0,0 +1,527 @@
+package soap
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/schema"
+)
+
+// Namespaces of OCPP 1.6 SOAP messages.
+const (
+	// NamespaceEnvelope is the SOAP 1.2 envelope namespace.
+	NamespaceEnvelope = "http://www.w3.org/2003/05/soap-envelope"
+	// NamespaceAddressing is the WS-Addressing namespace.
+	NamespaceAddressing = "http://www.w3.org/2005/08/addressing"
+	// NamespaceCentralSystem is the namespace of the Central System
+	// service, which receives the actions sent by Charge Points.
+	NamespaceCentralSystem = "urn://Ocpp/Cs/2015/10/"
+	// NamespaceChargePoint is the namespace of the Charge Point service,
+	// which receives the actions sent by Central Systems.
+	NamespaceChargePoint = "urn://Ocpp/Cp/2015/10/"
+)
+
+// faultAction is the WS-Addressing action of SOAP faults.
+const faultAction = NamespaceAddressing + "/soap/fault"
+
+var (
+	// ErrMalformed is returned for data that is not an OCPP 1.6 SOAP 1.2
+	// envelope. Handler answers it with a FormationViolation fault.
+	ErrMalformed = errors.New("soap: malformed envelope")
+	// ErrInvalidPayload is returned by Marshal for a payload that does not
+	// encode to a JSON object.
+	ErrInvalidPayload = errors.New("soap: payload is not an object")
+)
+
+// Header holds the SOAP header of an OCPP message.
+type Header struct {
+	// ChargeBoxIdentity identifies the Charge Point in every message.
+	ChargeBoxIdentity string
+	// Action is the WS-Addressing action, e.g. /Authorize or
+	// /AuthorizeResponse. Marshal derives it from the envelope when empty.
+	Action string
+	// MessageID identifies the message; RelatesTo carries the MessageID of
+	// the request a response answers.
+	MessageID string
+	RelatesTo string
+	// From is the address of the sender's endpoint. Charge Points send it so
+	// the Central System can reach their service.
+	From string
+	// To is the address of the receiver's endpoint.
+	To string
+}
+
+// Envelope is a decoded OCPP 1.6 SOAP message.
+type Envelope struct {
+	Header Header
+	// Action is the OCPP action, e.g. Authorize.
+	Action string
+	// Kind tells a request from a response.
+	Kind schema.Kind
+	// Service is the role whose service receives the action: the Central
+	// System for the actions sent by Charge Points, the Charge Point for
+	// the others. The zero value selects the receiver from the action;
+	// DataTransfer then goes to the Central System.
+	Service ocppj.Role
+	// Payload is the ReqMessage or ConfMessage of the action's package,
+	// e.g. authorize.ReqMessage.
+	Payload any
+}
+
+// Marshal encodes an envelope as SOAP 1.2 XML. The body element and its
+// children carry the names of the OCPP 1.6 WSDL, e.g. authorizeRequest and
+// idTag, in the order of the WSDL; lists are written as repeated elements.
+func Marshal(envelope Envelope) ([]byte, error) {
+	def, err := load(envelope.Action, envelope.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(envelope.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("soap: %s: %w", envelope.Action, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value map[string]any
+
+	err = decoder.Decode(&value)
+	if err != nil || value == nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPayload, envelope.Action)
+	}
+
+	service := receiver(envelope.Service, envelope.Action)
+	prefix := prefixOf(service)
+
+	header := envelope.Header
+	if header.Action == "" {
+		header.Action = addressingAction(envelope.Action, envelope.Kind)
+	}
+
+	var buf bytes.Buffer
+
+	appendOpen(&buf, service)
+	appendHeader(&buf, prefix, header)
+	buf.WriteString("<s:Body>")
+	appendElement(
+		&buf,
+		prefix,
+		elementName(envelope.Action, envelope.Kind),
+		def,
+		value,
+	)
+	buf.WriteString("</s:Body></s:Envelope>")
+
+	return buf.Bytes(), nil
+}
+
+// MarshalFault encodes callErr as a SOAP 1.2 fault of the service. The
+// fault subcode is the OCPP error code, e.g. cs:FormationViolation, under
+// the Sender code, or the Receiver code for InternalError and GenericError.
+func MarshalFault(
+	header Header,
+	service ocppj.Role,
+	callErr *ocppj.Error,
+) []byte {
+	if header.Action == "" {
+		header.Action = faultAction
+	}
+
+	prefix := prefixOf(service)
+
+	var buf bytes.Buffer
+
+	appendOpen(&buf, service)
+	appendHeader(&buf, prefix, header)
+	buf.WriteString("<s:Body><s:Fault><s:Code><s:Value>")
+	buf.WriteString(faultCode(callErr.Code))
+	buf.WriteString("</s:Value><s:Subcode><s:Value>")
+	buf.WriteString(prefix + ":" + string(callErr.Code))
+	buf.WriteString("</s:Value></s:Subcode></s:Code>")
+	buf.WriteString(`<s:Reason><s:Text xml:lang="en">`)
+	_ = xml.EscapeText(&buf, []byte(callErr.Error()))
+	buf.WriteString("</s:Text></s:Reason></s:Fault></s:Body></s:Envelope>")
+
+	return buf.Bytes()
+}
+
+// Unmarshal decodes a SOAP 1.2 envelope. The payload is converted to its
+// OCPP-J form and decoded with ocppj.DecodeRequest or
+// ocppj.DecodeConfirmation, so only valid messages are returned and errors
+// can be classified with ocppj.ErrorFor. A fault is returned as an
+// *ocppj.Error, with the header of its envelope.
+func Unmarshal(data []byte) (Envelope, error) {
+	envelope, payload, err := parse(data)
+	if err != nil {
+		return envelope, err
+	}
+
+	if envelope.Kind == schema.Confirmation {
+		envelope.Payload, err = ocppj.DecodeConfirmation(
+			envelope.Action,
+			payload,
+		)
+	} else {
+		envelope.Payload, err = ocppj.DecodeRequest(envelope.Action, payload)
+	}
+
+	if err != nil {
+		return envelope, fmt.Errorf("%s: %w", envelope.Action, err)
+	}
+
+	return envelope, nil
+}
+
+// parse reads an envelope and returns it with the OCPP-J form of its
+// payload, leaving Payload nil.
+func parse(data []byte) (Envelope, json.RawMessage, error) {
+	envelope := Envelope{
+		Header:  Header{},
+		Action:  "",
+		Kind:    schema.Request,
+		Service: 0,
+		Payload: nil,
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	start, err := nextElement(decoder)
+	if err != nil {
+		return envelope, nil, err
+	}
+
+	if start.Name != (xml.Name{Space: NamespaceEnvelope, Local: "Envelope"}) {
+		return envelope, nil, fmt.Errorf(
+			"%w: root element %s is not a SOAP 1.2 Envelope",
+			ErrMalformed,
+			start.Name.Local,
+		)
+	}
+
+	for {
+		start, err = nextElement(decoder)
+		if err != nil {
+			return envelope, nil, err
+		}
+
+		switch {
+		case start.Name.Space != NamespaceEnvelope:
+			err = decoder.Skip()
+		case start.Name.Local == "Header":
+			err = readHeader(decoder, &envelope.Header)
+		case start.Name.Local == "Body":
+			payload, bodyErr := readBody(decoder, &envelope)
+
+			return envelope, payload, bodyErr
+		default:
+			err = decoder.Skip()
+		}
+
+		if err != nil {
+			return envelope, nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+		}
+	}
+}
+
+// readHeader reads the WS-Addressing and OCPP header elements.
+func readHeader(decoder *xml.Decoder, header *Header) error {
+	fields := map[string]*string{
+		"chargeBoxIdentity": &header.ChargeBoxIdentity,
+		"Action":            &header.Action,
+		"MessageID":         &header.MessageID,
+		"RelatesTo":         &header.RelatesTo,
+		"To":                &header.To,
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err //nolint:wrapcheck // Wrapped by parse.
+		}
+
+		switch typed := token.(type) {
+		case xml.StartElement:
+			err = readHeaderElement(decoder, typed, header, fields)
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// readHeaderElement reads one header element into its field, skipping the
+// elements of other extensions.
+func readHeaderElement(
+	decoder *xml.Decoder,
+	start xml.StartElement,
+	header *Header,
+	fields map[string]*string,
+) error {
+	field, known := fields[start.Name.Local]
+
+	switch {
+	case start.Name.Local == "From" &&
+		start.Name.Space == NamespaceAddressing:
+		var from struct {
+			Address string `xml:"Address"`
+		}
+
+		err := decoder.DecodeElement(&from, &start)
+		header.From = strings.TrimSpace(from.Address)
+
+		return err //nolint:wrapcheck // Wrapped by parse.
+	case known && (start.Name.Space == NamespaceAddressing ||
+		start.Name.Local == "chargeBoxIdentity"):
+		var text string
+
+		err := decoder.DecodeElement(&text, &start)
+		*field = strings.TrimSpace(text)
+
+		return err //nolint:wrapcheck // Wrapped by parse.
+	default:
+		return decoder.Skip() //nolint:wrapcheck // Wrapped by parse.
+	}
+}
+
+// readBody reads the body element or fault of an envelope.
+func readBody(
+	decoder *xml.Decoder,
+	envelope *Envelope,
+) (json.RawMessage, error) {
+	start, err := nextElement(decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	if start.Name == (xml.Name{Space: NamespaceEnvelope, Local: "Fault"}) {
+		return nil, readFault(decoder, start)
+	}
+
+	switch start.Name.Space {
+	case NamespaceCentralSystem:
+		envelope.Service = ocppj.RoleCentralSystem
+	case NamespaceChargePoint:
+		envelope.Service = ocppj.RoleChargePoint
+	default:
+		return nil, fmt.Errorf(
+			"%w: body element %s is not in an OCPP 1.6 namespace",
+			ErrMalformed,
+			start.Name.Local,
+		)
+	}
+
+	action, kind, ok := parseElementName(start.Name.Local)
+	if !ok {
+		return nil, ocppj.NewError(
+			ocppj.NotImplemented,
+			fmt.Sprintf("unknown body element %q", start.Name.Local),
+		)
+	}
+
+	envelope.Action = action
+	envelope.Kind = kind
+
+	def, err := load(action, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := readElement(decoder, start, def)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	return payload, nil
+}
+
+// readFault reads a SOAP fault into an *ocppj.Error.
+func readFault(decoder *xml.Decoder, start xml.StartElement) error {
+	var fault struct {
+		Subcode string `xml:"Code>Subcode>Value"`
+		Reason  string `xml:"Reason>Text"`
+	}
+
+	err := decoder.DecodeElement(&fault, &start)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	code := fault.Subcode
+	if index := strings.LastIndexByte(code, ':'); index >= 0 {
+		code = code[index+1:]
+	}
+
+	if code == "" {
+		code = string(ocppj.GenericError)
+	}
+
+	description := strings.TrimPrefix(fault.Reason, code+": ")
+	if description == code {
+		description = ""
+	}
+
+	return ocppj.NewError(ocppj.ErrorCode(code), description)
+}
+
+// nextElement returns the next start element.
+func nextElement(decoder *xml.Decoder) (xml.StartElement, error) {
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return xml.StartElement{}, fmt.Errorf(
+				"%w: unexpected end of data",
+				ErrMalformed,
+			)
+		}
+
+		if err != nil {
+			return xml.StartElement{}, fmt.Errorf("%w: %w", ErrMalformed, err)
+		}
+
+		switch typed := token.(type) {
+		case xml.StartElement:
+			return typed, nil
+		case xml.EndElement:
+			return xml.StartElement{}, fmt.Errorf(
+				"%w: %s has no expected child element",
+				ErrMalformed,
+				typed.Name.Local,
+			)
+		}
+	}
+}
+
+// appendOpen appends the XML declaration and the opening Envelope tag
+// declaring the namespace prefixes.
+func appendOpen(dst *bytes.Buffer, service ocppj.Role) {
+	dst.WriteString(xml.Header)
+	dst.WriteString(`<s:Envelope xmlns:s="` + NamespaceEnvelope + `"`)
+	dst.WriteString(` xmlns:a="` + NamespaceAddressing + `"`)
+	dst.WriteString(` xmlns:` + prefixOf(service) + `="`)
+	dst.WriteString(namespaceOf(service) + `">`)
+}
+
+// appendHeader appends the SOAP header. Empty fields are left out.
+func appendHeader(dst *bytes.Buffer, prefix string, header Header) {
+	dst.WriteString("<s:Header>")
+	appendText(dst, prefix+":chargeBoxIdentity", header.ChargeBoxIdentity)
+	appendText(dst, "a:Action", header.Action)
+	appendText(dst, "a:MessageID", header.MessageID)
+	appendText(dst, "a:RelatesTo", header.RelatesTo)
+
+	if header.From != "" {
+		dst.WriteString("<a:From>")
+		appendText(dst, "a:Address", header.From)
+		dst.WriteString("</a:From>")
+	}
+
+	appendText(dst, "a:To", header.To)
+	dst.WriteString("</s:Header>")
+}
+
+// appendText appends an element holding text, or nothing for empty text.
+func appendText(dst *bytes.Buffer, name, text string) {
+	if text == "" {
+		return
+	}
+
+	dst.WriteString("<" + name + ">")
+	_ = xml.EscapeText(dst, []byte(text))
+	dst.WriteString("</" + name + ">")
+}
+
+// receiver returns the role whose service receives the action.
+func receiver(service ocppj.Role, action string) ocppj.Role {
+	if service != 0 {
+		return service
+	}
+
+	if ocppj.Initiates(ocppj.RoleChargePoint, action) {
+		return ocppj.RoleCentralSystem
+	}
+
+	return ocppj.RoleChargePoint
+}
+
+// prefixOf returns the namespace prefix of a service.
+func prefixOf(service ocppj.Role) string {
+	if service == ocppj.RoleChargePoint {
+		return "cp"
+	}
+
+	return "cs"
+}
+
+// namespaceOf returns the namespace of a service.
+func namespaceOf(service ocppj.Role) string {
+	if service == ocppj.RoleChargePoint {
+		return NamespaceChargePoint
+	}
+
+	return NamespaceCentralSystem
+}
+
+// faultCode returns the SOAP fault code of an OCPP error code.
+func faultCode(code ocppj.ErrorCode) string {
+	if code == ocppj.InternalError || code == ocppj.GenericError {
+		return "s:Receiver"
+	}
+
+	return "s:Sender"
+}
+
+// addressingAction returns the WS-Addressing action of a message, e.g.
+// /Authorize or /AuthorizeResponse.
+func addressingAction(action string, kind schema.Kind) string {
+	if kind == schema.Confirmation {
+		return "/" + action + "Response"
+	}
+
+	return "/" + action
+}
+
+// elementName returns the WSDL name of the body element of a message, e.g.
+// authorizeRequest or authorizeResponse.
+func elementName(action string, kind schema.Kind) string {
+	first, size := utf8.DecodeRuneInString(action)
+
+	return string(unicode.ToLower(first)) + action[size:] + kind.String()
+}
+
+// parseElementName returns the action and kind of a body element name.
+func parseElementName(name string) (string, schema.Kind, bool) {
+	kind := schema.Request
+
+	stem, found := strings.CutSuffix(name, schema.Request.String())
+	if !found {
+		kind = schema.Confirmation
+		stem, found = strings.CutSuffix(name, schema.Confirmation.String())
+	}
+
+	first, size := utf8.DecodeRuneInString(stem)
+	action := string(unicode.ToUpper(first)) + stem[size:]
+
+	if !found || stem == "" || !slices.Contains(ocppj.Actions(), action) {
+		return "", kind, false
+	}
+
+	return action, kind, true
+}
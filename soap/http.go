@@ -0,0 +1,308 @@
+package soap
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/schema"
+)
+
+const (
+	// ContentType is the media type of SOAP 1.2 messages.
+	ContentType = "application/soap+xml; charset=utf-8"
+	// maxEnvelopeSize bounds the size of a received envelope.
+	maxEnvelopeSize = 1 << 20
+	// uuidLen is the number of bytes of a message id.
+	uuidLen = 16
+)
+
+// ErrUnrelated is returned by Client.Call for a response that does not
+// answer the request sent.
+var ErrUnrelated = errors.New("soap: response does not answer the request")
+
+// headerKey is the context key of the request Header.
+type headerKey struct{}
+
+// RequestHeader returns the SOAP header of the request a Handler serves,
+// holding the chargeBoxIdentity and, for Charge Points, the From address of
+// their service.
+func RequestHeader(ctx context.Context) (Header, bool) {
+	header, ok := ctx.Value(headerKey{}).(Header)
+
+	return header, ok
+}
+
+// Handler serves the OCPP 1.6 SOAP service of a role over HTTP: the Central
+// System service for RoleCentralSystem, the Charge Point service for
+// RoleChargePoint. Requests are decoded into the ReqMessage of their
+// action's package and passed to an ocppj.Handler, so one handler can serve
+// chargers over OCPP-J and SOAP alike; the SOAP header is available through
+// RequestHeader.
+type Handler struct {
+	role    ocppj.Role
+	handler ocppj.Handler
+}
+
+// NewHandler returns a Handler acting as role. handler may be nil, in which
+// case every request is answered with a NotSupported fault.
+func NewHandler(role ocppj.Role, handler ocppj.Handler) *Handler {
+	return &Handler{role: role, handler: handler}
+}
+
+// ServeHTTP answers a SOAP request with the response envelope, or with a
+// fault classified by ocppj.ErrorFor.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxEnvelopeSize+1))
+	if err == nil && len(data) > maxEnvelopeSize {
+		err = fmt.Errorf("%w: envelope exceeds %d bytes", ErrMalformed,
+			maxEnvelopeSize)
+	}
+
+	var request Envelope
+	if err == nil {
+		request, err = Unmarshal(data)
+	}
+
+	header := Header{
+		ChargeBoxIdentity: request.Header.ChargeBoxIdentity,
+		Action:            "",
+		MessageID:         newMessageID(),
+		RelatesTo:         request.Header.MessageID,
+		From:              "",
+		To:                request.Header.From,
+	}
+
+	confirmation, err := h.dispatch(r.Context(), request, err)
+	if err != nil {
+		h.writeFault(w, header, faultFor(err))
+
+		return
+	}
+
+	response, err := Marshal(Envelope{
+		Header:  header,
+		Action:  request.Action,
+		Kind:    schema.Confirmation,
+		Service: h.role,
+		Payload: confirmation,
+	})
+	if err != nil {
+		h.writeFault(w, header, ocppj.NewError(ocppj.InternalError,
+			err.Error()))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = w.Write(response)
+}
+
+// dispatch validates a decoded request and runs the handler.
+func (h *Handler) dispatch(
+	ctx context.Context,
+	request Envelope,
+	err error,
+) (any, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Kind != schema.Request || request.Service != h.role ||
+		!ocppj.Initiates(peerOf(h.role), request.Action) ||
+		h.handler == nil {
+		return nil, ocppj.NewError(
+			ocppj.NotSupported,
+			fmt.Sprintf("action %q is not supported", request.Action),
+		)
+	}
+
+	ctx = context.WithValue(ctx, headerKey{}, request.Header)
+
+	return h.handler(ctx, request.Action, request.Payload)
+}
+
+// writeFault answers with a fault, with status 400 for Sender faults and
+// 500 for Receiver faults as the SOAP 1.2 HTTP binding prescribes.
+func (h *Handler) writeFault(
+	w http.ResponseWriter,
+	header Header,
+	callErr *ocppj.Error,
+) {
+	status := http.StatusBadRequest
+	if faultCode(callErr.Code) == "s:Receiver" {
+		status = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	_, _ = w.Write(MarshalFault(header, h.role, callErr))
+}
+
+// faultFor classifies err like ocppj.ErrorFor, mapping malformed envelopes
+// to FormationViolation.
+func faultFor(err error) *ocppj.Error {
+	var callErr *ocppj.Error
+	if !errors.As(err, &callErr) && errors.Is(err, ErrMalformed) {
+		return ocppj.NewError(ocppj.FormationViolation, err.Error())
+	}
+
+	return ocppj.ErrorFor(err)
+}
+
+// Client sends the requests of a role to the SOAP service of its peer.
+type Client struct {
+	endpoint   string
+	role       ocppj.Role
+	header     Header
+	httpClient *http.Client
+}
+
+// NewClient returns a Client acting as role that posts to the service at
+// endpoint. header supplies the chargeBoxIdentity and the From and To
+// addresses of every request; its other fields are set per call.
+// httpClient may be nil to use http.DefaultClient.
+func NewClient(
+	endpoint string,
+	role ocppj.Role,
+	header Header,
+	httpClient *http.Client,
+) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if header.To == "" {
+		header.To = endpoint
+	}
+
+	return &Client{
+		endpoint:   endpoint,
+		role:       role,
+		header:     header,
+		httpClient: httpClient,
+	}
+}
+
+// Call sends a request and waits for the response, like ocppj.Conn.Call.
+// When confirmation is non-nil the response payload is decoded into it;
+// passing a pointer to the matching ConfMessage validates the answer. A
+// fault from the peer is returned as an *ocppj.Error.
+func (c *Client) Call(
+	ctx context.Context,
+	action string,
+	request any,
+	confirmation any,
+) error {
+	header := c.header
+	header.Action = ""
+	header.MessageID = newMessageID()
+	header.RelatesTo = ""
+
+	data, err := Marshal(Envelope{
+		Header:  header,
+		Action:  action,
+		Kind:    schema.Request,
+		Service: peerOf(c.role),
+		Payload: request,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+
+	response, err := c.post(ctx, action, data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+
+	envelope, payload, err := parse(response)
+	if err != nil {
+		return fmt.Errorf("%s.conf: %w", action, err)
+	}
+
+	if envelope.Action != action || envelope.Kind != schema.Confirmation ||
+		envelope.Header.RelatesTo != header.MessageID {
+		return fmt.Errorf("%s.conf: %w", action, ErrUnrelated)
+	}
+
+	if confirmation == nil {
+		return nil
+	}
+
+	err = json.Unmarshal(payload, confirmation)
+	if err != nil {
+		return fmt.Errorf("%s.conf: %w", action, err)
+	}
+
+	return nil
+}
+
+// post sends an envelope and returns the response envelope. Faults come
+// with an error status and are returned like responses.
+func (c *Client) post(
+	ctx context.Context,
+	action string,
+	data []byte,
+) ([]byte, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		c.endpoint,
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("soap: %w", err)
+	}
+
+	req.Header.Set(
+		"Content-Type",
+		ContentType+`; action="`+addressingAction(action, schema.Request)+`"`,
+	)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("soap: %w", err)
+	}
+	defer resp.Body.Close()
+
+	response, err := io.ReadAll(io.LimitReader(resp.Body, maxEnvelopeSize))
+	if err != nil {
+		return nil, fmt.Errorf("soap: %w", err)
+	}
+
+	return response, nil
+}
+
+// peerOf returns the role of the other side.
+func peerOf(role ocppj.Role) ocppj.Role {
+	if role == ocppj.RoleChargePoint {
+		return ocppj.RoleCentralSystem
+	}
+
+	return ocppj.RoleChargePoint
+}
+
+// newMessageID returns a random urn:uuid message id.
+func newMessageID() string {
+	var id [uuidLen]byte
+
+	_, _ = rand.Read(id[:])
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x",
+		id[0:4], id[4:6], id[6:8], id[8:10], id[10:])
+}
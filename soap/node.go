@@ -0,0 +1,142 @@
+package soap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/aasanchez/ocpp16messages/schema"
+)
+
+// node is the part of an official JSON schema that drives the XML mapping:
+// the type of each element and the order of the properties, which is the
+// order of the elements of the OCPP 1.6 WSDL.
+type node struct {
+	Type       string
+	Properties []property
+	Items      *node
+}
+
+// property is a named property of an object node.
+type property struct {
+	name string
+	node *node
+}
+
+// nodes caches the parsed schemas by action and kind.
+var nodes sync.Map
+
+// nodeKey identifies a cached schema.
+type nodeKey struct {
+	action string
+	kind   schema.Kind
+}
+
+// load returns the node of an action's payload.
+func load(action string, kind schema.Kind) (*node, error) {
+	key := nodeKey{action: action, kind: kind}
+
+	cached, ok := nodes.Load(key)
+	if ok {
+		//nolint:forcetypeassert // Only *node is stored.
+		return cached.(*node), nil
+	}
+
+	data, err := schema.Schema(action, kind)
+	if err != nil {
+		return nil, fmt.Errorf("soap: %w", err)
+	}
+
+	var root node
+
+	err = json.Unmarshal(data, &root)
+	if err != nil {
+		return nil, fmt.Errorf("soap: %s %s: %w", action, kind, err)
+	}
+
+	nodes.Store(key, &root)
+
+	return &root, nil
+}
+
+// UnmarshalJSON reads a schema keeping the order of its properties.
+func (n *node) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type       string          `json:"type"`
+		Properties json.RawMessage `json:"properties"`
+		Items      *node           `json:"items"`
+	}
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err //nolint:wrapcheck // Wrapped by load.
+	}
+
+	n.Type = raw.Type
+	n.Items = raw.Items
+	n.Properties = nil
+
+	if raw.Properties == nil {
+		return nil
+	}
+
+	var names map[string]*node
+
+	err = json.Unmarshal(raw.Properties, &names)
+	if err != nil {
+		return err //nolint:wrapcheck // Wrapped by load.
+	}
+
+	for _, name := range order(raw.Properties) {
+		n.Properties = append(n.Properties, property{name, names[name]})
+	}
+
+	return nil
+}
+
+// order returns the property names of a JSON object in document order.
+func order(object json.RawMessage) []string {
+	decoder := json.NewDecoder(bytes.NewReader(object))
+
+	_, err := decoder.Token()
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return names
+		}
+
+		name, _ := token.(string)
+		names = append(names, name)
+
+		var skipped json.RawMessage
+
+		err = decoder.Decode(&skipped)
+		if err != nil {
+			return names
+		}
+	}
+
+	return names
+}
+
+// property returns the node of the property named name, or nil.
+func (n *node) property(name string) *node {
+	if n == nil {
+		return nil
+	}
+
+	for _, prop := range n.Properties {
+		if prop.name == name {
+			return prop.node
+		}
+	}
+
+	return nil
+}
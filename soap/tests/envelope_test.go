@@ -0,0 +1,319 @@
+package soap_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/getconfiguration"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/schema"
+	"github.com/aasanchez/ocpp16messages/soap"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const authorizeEnvelope = `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+	`<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope"` +
+	` xmlns:a="http://www.w3.org/2005/08/addressing"` +
+	` xmlns:cs="urn://Ocpp/Cs/2015/10/">` +
+	`<s:Header><cs:chargeBoxIdentity>CP001</cs:chargeBoxIdentity>` +
+	`<a:Action>/Authorize</a:Action><a:MessageID>urn:uuid:1</a:MessageID>` +
+	`<a:From><a:Address>http://cp001.example.com/ocpp</a:Address></a:From>` +
+	`</s:Header><s:Body><cs:authorizeRequest>` +
+	`<cs:idTag>RFID-ABC123</cs:idTag></cs:authorizeRequest></s:Body>` +
+	`</s:Envelope>`
+
+// envelopeOf wraps a body element in an envelope of the Central System
+// service.
+func envelopeOf(body string) []byte {
+	return []byte(`<s:Envelope` +
+		` xmlns:s="http://www.w3.org/2003/05/soap-envelope"` +
+		` xmlns:cs="urn://Ocpp/Cs/2015/10/"` +
+		` xmlns:cp="urn://Ocpp/Cp/2015/10/"><s:Body>` + body +
+		`</s:Body></s:Envelope>`)
+}
+
+func TestMarshal_Authorize(t *testing.T) {
+	t.Parallel()
+
+	req, err := authorize.Req(authorize.ReqInput{IdTag: "RFID-ABC123"})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	data, err := soap.Marshal(soap.Envelope{
+		Header: soap.Header{
+			ChargeBoxIdentity: "CP001",
+			Action:            "",
+			MessageID:         "urn:uuid:1",
+			RelatesTo:         "",
+			From:              "http://cp001.example.com/ocpp",
+			To:                "",
+		},
+		Action:  ocppj.ActionAuthorize,
+		Kind:    schema.Request,
+		Service: 0,
+		Payload: req,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if string(data) != authorizeEnvelope {
+		t.Errorf(types.ErrorMismatch, authorizeEnvelope, string(data))
+	}
+}
+
+func TestUnmarshal_Authorize(t *testing.T) {
+	t.Parallel()
+
+	envelope, err := soap.Unmarshal([]byte(authorizeEnvelope))
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if envelope.Header.ChargeBoxIdentity != "CP001" ||
+		envelope.Header.From != "http://cp001.example.com/ocpp" ||
+		envelope.Header.MessageID != "urn:uuid:1" {
+		t.Errorf(types.ErrorMismatchValue, "CP001 header", envelope.Header)
+	}
+
+	if envelope.Service != ocppj.RoleCentralSystem ||
+		envelope.Kind != schema.Request {
+		t.Errorf(types.ErrorMismatchValue, "Central System request", envelope)
+	}
+
+	req, ok := envelope.Payload.(authorize.ReqMessage)
+	if !ok {
+		t.Fatalf(types.ErrorMismatchValue, "authorize.ReqMessage", envelope)
+	}
+
+	if req.IdTag.String() != "RFID-ABC123" {
+		t.Errorf(types.ErrorMismatchValue, "RFID-ABC123", req.IdTag)
+	}
+}
+
+func TestMarshal_RoundTripAllActions(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range conforming {
+		assertRoundTrip(t, tc.action, schema.Request, tc.request)
+		assertRoundTrip(t, tc.action, schema.Confirmation, tc.confirmation)
+	}
+}
+
+// assertRoundTrip checks that a payload survives the SOAP encoding.
+func assertRoundTrip(
+	t *testing.T,
+	action string,
+	kind schema.Kind,
+	payload string,
+) {
+	t.Helper()
+
+	var (
+		msg any
+		err error
+	)
+
+	if kind == schema.Confirmation {
+		msg, err = ocppj.DecodeConfirmation(action, []byte(payload))
+	} else {
+		msg, err = ocppj.DecodeRequest(action, []byte(payload))
+	}
+
+	if err != nil {
+		t.Fatalf("%s %s: %v", action, kind, err)
+	}
+
+	data, err := soap.Marshal(soap.Envelope{
+		Header:  soap.Header{},
+		Action:  action,
+		Kind:    kind,
+		Service: 0,
+		Payload: msg,
+	})
+	if err != nil {
+		t.Fatalf("%s %s: %v", action, kind, err)
+	}
+
+	envelope, err := soap.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n%s", action, kind, err, data)
+	}
+
+	want, _ := json.Marshal(msg)
+	got, _ := json.Marshal(envelope.Payload)
+
+	if string(got) != string(want) {
+		t.Errorf("%s %s: want %s, got %s", action, kind, want, got)
+	}
+}
+
+func TestUnmarshal_RepeatedElements(t *testing.T) {
+	t.Parallel()
+
+	envelope, err := soap.Unmarshal(envelopeOf(
+		`<cp:getConfigurationRequest>` +
+			`<cp:key>HeartbeatInterval</cp:key>` +
+			`<cp:key>MeterValueSampleInterval</cp:key>` +
+			`</cp:getConfigurationRequest>`,
+	))
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	req, ok := envelope.Payload.(getconfiguration.ReqMessage)
+	if !ok {
+		t.Fatalf(
+			types.ErrorMismatchValue,
+			"getconfiguration.ReqMessage",
+			envelope,
+		)
+	}
+
+	if envelope.Service != ocppj.RoleChargePoint {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			ocppj.RoleChargePoint,
+			envelope.Service,
+		)
+	}
+
+	if len(req.Key) != 2 {
+		t.Errorf(types.ErrorMismatchValue, 2, req.Key)
+	}
+}
+
+func TestUnmarshal_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data []byte
+		code ocppj.ErrorCode
+	}{
+		{
+			"not xml",
+			[]byte(`{"idTag":"RFID-ABC123"}`),
+			ocppj.FormationViolation,
+		},
+		{
+			"soap 1.1",
+			[]byte(`<s:Envelope xmlns:s=` +
+				`"http://schemas.xmlsoap.org/soap/envelope/"></s:Envelope>`),
+			ocppj.FormationViolation,
+		},
+		{
+			"empty body",
+			envelopeOf(``),
+			ocppj.FormationViolation,
+		},
+		{
+			"unknown namespace",
+			envelopeOf(`<authorizeRequest xmlns="urn:other">` +
+				`<idTag>A</idTag></authorizeRequest>`),
+			ocppj.FormationViolation,
+		},
+		{
+			"unknown action",
+			envelopeOf(`<cs:rebootRequest/>`),
+			ocppj.NotImplemented,
+		},
+		{
+			"unknown element",
+			envelopeOf(`<cs:authorizeRequest><cs:idTag>A</cs:idTag>` +
+				`<cs:extra>1</cs:extra></cs:authorizeRequest>`),
+			ocppj.FormationViolation,
+		},
+		{
+			"repeated element",
+			envelopeOf(`<cs:authorizeRequest><cs:idTag>A</cs:idTag>` +
+				`<cs:idTag>B</cs:idTag></cs:authorizeRequest>`),
+			ocppj.FormationViolation,
+		},
+		{
+			"missing element",
+			envelopeOf(`<cs:authorizeRequest/>`),
+			ocppj.OccurenceConstraintViolation,
+		},
+		{
+			"wrong type",
+			envelopeOf(`<cp:unlockConnectorRequest>` +
+				`<cp:connectorId>one</cp:connectorId>` +
+				`</cp:unlockConnectorRequest>`),
+			ocppj.TypeConstraintViolation,
+		},
+		{
+			"invalid value",
+			envelopeOf(`<cp:resetRequest><cp:type>Hard reboot</cp:type>` +
+				`</cp:resetRequest>`),
+			ocppj.PropertyConstraintViolation,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := soap.Unmarshal(tc.data)
+			if err == nil {
+				t.Fatalf(types.ErrorWantNonNil, "error")
+			}
+
+			var callErr *ocppj.Error
+			if errors.Is(err, soap.ErrMalformed) {
+				callErr = ocppj.NewError(ocppj.FormationViolation, "")
+			} else {
+				callErr = ocppj.ErrorFor(err)
+			}
+
+			if callErr.Code != tc.code {
+				t.Errorf(types.ErrorMismatchValue, tc.code, err)
+			}
+		})
+	}
+}
+
+func TestMarshalFault_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	data := soap.MarshalFault(
+		soap.Header{
+			ChargeBoxIdentity: "CP001",
+			Action:            "",
+			MessageID:         "urn:uuid:2",
+			RelatesTo:         "urn:uuid:1",
+			From:              "",
+			To:                "",
+		},
+		ocppj.RoleCentralSystem,
+		ocppj.NewError(ocppj.SecurityError, "unknown <charge box>"),
+	)
+
+	if !strings.Contains(string(data), "<s:Value>cs:SecurityError</s:Value>") {
+		t.Errorf(types.ErrorWantContains, string(data), "cs:SecurityError")
+	}
+
+	envelope, err := soap.Unmarshal(data)
+
+	var callErr *ocppj.Error
+	if !errors.As(err, &callErr) {
+		t.Fatalf(types.ErrorWrapping, err, "*ocppj.Error")
+	}
+
+	if callErr.Code != ocppj.SecurityError ||
+		callErr.Description != "unknown <charge box>" {
+		t.Errorf(types.ErrorMismatchValue, "SecurityError", callErr)
+	}
+
+	if envelope.Header.RelatesTo != "urn:uuid:1" {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			"urn:uuid:1",
+			envelope.Header.RelatesTo,
+		)
+	}
+}
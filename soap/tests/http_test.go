@@ -0,0 +1,146 @@
+package soap_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/reset"
+	"github.com/aasanchez/ocpp16messages/soap"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const testChargePoint = "CP001"
+
+// centralSystem answers Authorize with the identity of the charge box.
+func centralSystem(ctx context.Context, action string, _ any) (any, error) {
+	header, ok := soap.RequestHeader(ctx)
+	if !ok || action != ocppj.ActionAuthorize {
+		return nil, ocppj.NewError(ocppj.InternalError, "no header")
+	}
+
+	status := "Blocked"
+	if header.ChargeBoxIdentity == testChargePoint {
+		status = "Accepted"
+	}
+
+	return authorize.Conf(authorize.ConfInput{
+		Status:      status,
+		ExpiryDate:  nil,
+		ParentIdTag: nil,
+	})
+}
+
+// newCentralSystem starts a Central System service and returns a client of
+// testChargePoint for it.
+func newCentralSystem(t *testing.T) *soap.Client {
+	t.Helper()
+
+	server := httptest.NewServer(
+		soap.NewHandler(ocppj.RoleCentralSystem, centralSystem),
+	)
+	t.Cleanup(server.Close)
+
+	return soap.NewClient(
+		server.URL,
+		ocppj.RoleChargePoint,
+		soap.Header{
+			ChargeBoxIdentity: testChargePoint,
+			Action:            "",
+			MessageID:         "",
+			RelatesTo:         "",
+			From:              "http://cp001.example.com/ocpp",
+			To:                "",
+		},
+		server.Client(),
+	)
+}
+
+func TestClient_Call(t *testing.T) {
+	t.Parallel()
+
+	client := newCentralSystem(t)
+
+	req, err := authorize.Req(authorize.ReqInput{IdTag: "RFID-ABC123"})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	var conf authorize.ConfMessage
+
+	err = client.Call(context.Background(), ocppj.ActionAuthorize, req, &conf)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if conf.IdTagInfo.Status().String() != "Accepted" {
+		t.Errorf(types.ErrorMismatchValue, "Accepted", conf.IdTagInfo.Status())
+	}
+}
+
+func TestClient_CallNotSupported(t *testing.T) {
+	t.Parallel()
+
+	client := newCentralSystem(t)
+
+	req, err := reset.Req(reset.ReqInput{Type: "Soft"})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	// A Charge Point client never sends Reset; bypass the role by posting
+	// it to the Central System service as a Central System would.
+	err = client.Call(context.Background(), ocppj.ActionReset, req, nil)
+
+	var callErr *ocppj.Error
+	if !errors.As(err, &callErr) || callErr.Code != ocppj.NotSupported {
+		t.Errorf(types.ErrorWrapping, err, ocppj.NotSupported)
+	}
+}
+
+func TestHandler_Malformed(t *testing.T) {
+	t.Parallel()
+
+	handler := soap.NewHandler(ocppj.RoleCentralSystem, centralSystem)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(
+		http.MethodPost,
+		"/ocpp",
+		strings.NewReader(`<not-soap/>`),
+	))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf(types.ErrorMismatchValue, http.StatusBadRequest, recorder.Code)
+	}
+
+	_, err := soap.Unmarshal(recorder.Body.Bytes())
+	if !errors.Is(err, ocppj.NewError(ocppj.FormationViolation, "")) {
+		t.Errorf(types.ErrorWrapping, err, ocppj.FormationViolation)
+	}
+}
+
+func TestHandler_MethodNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	handler := soap.NewHandler(ocppj.RoleCentralSystem, centralSystem)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(
+		recorder,
+		httptest.NewRequest(http.MethodGet, "/ocpp", nil),
+	)
+
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			http.StatusMethodNotAllowed,
+			recorder.Code,
+		)
+	}
+}
@@ -0,0 +1,235 @@
+package soap
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// appendElements appends the XML elements of the JSON value of a property
+// named name. Lists become repeated elements, as in the WSDL.
+func appendElements(
+	dst *bytes.Buffer,
+	prefix, name string,
+	def *node,
+	value any,
+) {
+	list, ok := value.([]any)
+	if !ok {
+		appendElement(dst, prefix, name, def, value)
+
+		return
+	}
+
+	var items *node
+	if def != nil {
+		items = def.Items
+	}
+
+	for _, item := range list {
+		appendElement(dst, prefix, name, items, item)
+	}
+}
+
+// appendElement appends one XML element holding a JSON value.
+func appendElement(
+	dst *bytes.Buffer,
+	prefix, name string,
+	def *node,
+	value any,
+) {
+	dst.WriteString("<" + prefix + ":" + name + ">")
+
+	switch typed := value.(type) {
+	case map[string]any:
+		appendChildren(dst, prefix, def, typed)
+	case string:
+		_ = xml.EscapeText(dst, []byte(typed))
+	case json.Number:
+		dst.WriteString(typed.String())
+	case bool:
+		dst.WriteString(strconv.FormatBool(typed))
+	}
+
+	dst.WriteString("</" + prefix + ":" + name + ">")
+}
+
+// appendChildren appends the properties of a JSON object in the order of
+// the schema. Properties the schema does not define follow in name order.
+func appendChildren(
+	dst *bytes.Buffer,
+	prefix string,
+	def *node,
+	object map[string]any,
+) {
+	written := map[string]bool{}
+
+	if def != nil {
+		for _, prop := range def.Properties {
+			value, ok := object[prop.name]
+			if !ok || value == nil {
+				continue
+			}
+
+			appendElements(dst, prefix, prop.name, prop.node, value)
+			written[prop.name] = true
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		if !written[name] && object[name] != nil {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		appendElements(dst, prefix, name, nil, object[name])
+	}
+}
+
+// readElement reads the content of the element opened by start into a JSON
+// value shaped by def: an object for an object node, a number or boolean
+// for such a node when the text parses as one, and a string otherwise, so
+// that a wrong value reaches the message package as a JSON type error.
+// Elements without a node are read as objects when they have children.
+func readElement(
+	decoder *xml.Decoder,
+	start xml.StartElement,
+	def *node,
+) (any, error) {
+	var (
+		text     strings.Builder
+		children map[string]any
+	)
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: %s: %w",
+				ErrMalformed,
+				start.Name.Local,
+				err,
+			)
+		}
+
+		switch typed := token.(type) {
+		case xml.StartElement:
+			if children == nil {
+				children = map[string]any{}
+			}
+
+			err = readChild(decoder, typed, def, children)
+			if err != nil {
+				return nil, err
+			}
+		case xml.CharData:
+			text.Write(typed)
+		case xml.EndElement:
+			if children != nil || (def != nil && def.Type == "object") {
+				return objectOrEmpty(children, text.String(), start)
+			}
+
+			return scalar(def, text.String()), nil
+		}
+	}
+}
+
+// readChild reads a child element into the properties of its parent.
+// Repeated elements of a list property are collected into a JSON array.
+func readChild(
+	decoder *xml.Decoder,
+	start xml.StartElement,
+	parent *node,
+	children map[string]any,
+) error {
+	name := start.Name.Local
+	def := parent.property(name)
+
+	if def == nil || def.Type != "array" {
+		if _, seen := children[name]; seen {
+			return fmt.Errorf("%w: %s: repeated element", ErrMalformed, name)
+		}
+
+		value, err := readElement(decoder, start, def)
+		children[name] = value
+
+		return err
+	}
+
+	value, err := readElement(decoder, start, def.Items)
+	if err != nil {
+		return err
+	}
+
+	list, _ := children[name].([]any)
+	children[name] = append(list, value)
+
+	return nil
+}
+
+// objectOrEmpty returns the properties of an object element, rejecting text
+// mixed with child elements.
+func objectOrEmpty(
+	children map[string]any,
+	text string,
+	start xml.StartElement,
+) (any, error) {
+	if strings.TrimSpace(text) != "" {
+		return nil, fmt.Errorf(
+			"%w: %s: text in an object element",
+			ErrMalformed,
+			start.Name.Local,
+		)
+	}
+
+	if children == nil {
+		return map[string]any{}, nil
+	}
+
+	return children, nil
+}
+
+// scalar converts the text of a leaf element to the JSON value of def.
+func scalar(def *node, text string) any {
+	if def == nil {
+		return text
+	}
+
+	switch def.Type {
+	case "integer", "number":
+		literal := strings.TrimSpace(text)
+		if !isNumber(literal) {
+			return text
+		}
+
+		return json.Number(literal)
+	case "boolean":
+		switch strings.TrimSpace(text) {
+		case "true", "1":
+			return true
+		case "false", "0":
+			return false
+		default:
+			return text
+		}
+	default:
+		return text
+	}
+}
+
+// isNumber reports whether literal is a JSON number.
+func isNumber(literal string) bool {
+	if literal == "" || !json.Valid([]byte(literal)) {
+		return false
+	}
+
+	return literal[0] == '-' || (literal[0] >= '0' && literal[0] <= '9')
+}
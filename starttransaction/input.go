@@ -0,0 +1,109 @@
+package starttransaction
+
+import "github.com/aasanchez/ocpp16messages/internal/wire"
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		ConnectorId:   int(m.ConnectorId.Value()),
+		IdTag:         m.IdTag.String(),
+		MeterStart:    int(m.MeterStart.Value()),
+		Timestamp:     m.Timestamp.String(),
+		ReservationId: wire.OptionalInt(m.ReservationId),
+	}
+}
+
+// WithConnectorId returns a copy of the message with ConnectorId set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithConnectorId(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ConnectorId = value
+
+	return Req(input)
+}
+
+// WithIdTag returns a copy of the message with IdTag set to value. The copy
+// is validated by Req.
+func (m ReqMessage) WithIdTag(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.IdTag = value
+
+	return Req(input)
+}
+
+// WithMeterStart returns a copy of the message with MeterStart set to value.
+// The copy is validated by Req.
+func (m ReqMessage) WithMeterStart(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.MeterStart = value
+
+	return Req(input)
+}
+
+// WithTimestamp returns a copy of the message with Timestamp set to value.
+// The copy is validated by Req.
+func (m ReqMessage) WithTimestamp(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Timestamp = value
+
+	return Req(input)
+}
+
+// WithReservationId returns a copy of the message with ReservationId set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithReservationId(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ReservationId = &value
+
+	return Req(input)
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	return ConfInput{
+		TransactionId: int(m.TransactionId.Value()),
+		Status:        m.IdTagInfo.Status().String(),
+		ExpiryDate:    wire.OptionalString(m.IdTagInfo.ExpiryDate()),
+		ParentIdTag:   wire.OptionalString(m.IdTagInfo.ParentIdTag()),
+	}
+}
+
+// WithTransactionId returns a copy of the message with TransactionId set to
+// value. The copy is validated by Conf.
+func (m ConfMessage) WithTransactionId(value int) (ConfMessage, error) {
+	input := m.ToInput()
+	input.TransactionId = value
+
+	return Conf(input)
+}
+
+// WithStatus returns a copy of the message with Status set to value. The
+// copy is validated by Conf.
+func (m ConfMessage) WithStatus(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.Status = value
+
+	return Conf(input)
+}
+
+// WithExpiryDate returns a copy of the message with ExpiryDate set to value.
+// The copy is validated by Conf.
+func (m ConfMessage) WithExpiryDate(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.ExpiryDate = &value
+
+	return Conf(input)
+}
+
+// WithParentIdTag returns a copy of the message with ParentIdTag set to
+// value. The copy is validated by Conf.
+func (m ConfMessage) WithParentIdTag(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.ParentIdTag = &value
+
+	return Conf(input)
+}
@@ -0,0 +1,83 @@
+package starttransaction
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// reqWire is the OCPP-J payload of StartTransaction.req.
+type reqWire struct {
+	ConnectorId   uint16  `json:"connectorId"`
+	IdTag         string  `json:"idTag"`
+	MeterStart    uint16  `json:"meterStart"`
+	ReservationId *uint16 `json:"reservationId,omitempty"`
+	Timestamp     string  `json:"timestamp"`
+}
+
+// confWire is the OCPP-J payload of StartTransaction.conf.
+type confWire struct {
+	IdTagInfo     wire.IdTagInfo `json:"idTagInfo"`
+	TransactionId uint16         `json:"transactionId"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J StartTransaction.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		ConnectorId:   m.ConnectorId.Value(),
+		IdTag:         m.IdTag.String(),
+		MeterStart:    m.MeterStart.Value(),
+		ReservationId: wire.OptionalInteger(m.ReservationId),
+		Timestamp:     m.Timestamp.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J StartTransaction.req payload and validates it
+// with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J StartTransaction.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		IdTagInfo:     wire.NewIdTagInfo(m.IdTagInfo),
+		TransactionId: m.TransactionId.Value(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J StartTransaction.conf payload and validates
+// it with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, confFromWire)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// confPayload decodes the nested idTagInfo object of StartTransaction.conf,
+// which ConfInput flattens.
+type confPayload struct {
+	TransactionId int
+	IdTagInfo     types.IdTagInfoInput
+}
+
+// confFromWire flattens a decoded payload into ConfInput and validates it.
+func confFromWire(payload confPayload) (ConfMessage, error) {
+	return Conf(ConfInput{
+		TransactionId: payload.TransactionId,
+		Status:        payload.IdTagInfo.Status,
+		ExpiryDate:    payload.IdTagInfo.ExpiryDate,
+		ParentIdTag:   payload.IdTagInfo.ParentIdTag,
+	})
+}
@@ -0,0 +1,87 @@
+package statusnotification
+
+import "github.com/aasanchez/ocpp16messages/internal/wire"
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		ConnectorId:     int(m.ConnectorId.Value()),
+		ErrorCode:       m.ErrorCode.String(),
+		Status:          m.Status.String(),
+		Info:            wire.OptionalString(m.Info),
+		Timestamp:       wire.OptionalString(m.Timestamp),
+		VendorId:        wire.OptionalString(m.VendorId),
+		VendorErrorCode: wire.OptionalString(m.VendorErrorCode),
+	}
+}
+
+// WithConnectorId returns a copy of the message with ConnectorId set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithConnectorId(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ConnectorId = value
+
+	return Req(input)
+}
+
+// WithErrorCode returns a copy of the message with ErrorCode set to value.
+// The copy is validated by Req.
+func (m ReqMessage) WithErrorCode(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ErrorCode = value
+
+	return Req(input)
+}
+
+// WithStatus returns a copy of the message with Status set to value. The
+// copy is validated by Req.
+func (m ReqMessage) WithStatus(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Status = value
+
+	return Req(input)
+}
+
+// WithInfo returns a copy of the message with Info set to value. The copy is
+// validated by Req.
+func (m ReqMessage) WithInfo(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Info = &value
+
+	return Req(input)
+}
+
+// WithTimestamp returns a copy of the message with Timestamp set to value.
+// The copy is validated by Req.
+func (m ReqMessage) WithTimestamp(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Timestamp = &value
+
+	return Req(input)
+}
+
+// WithVendorId returns a copy of the message with VendorId set to value. The
+// copy is validated by Req.
+func (m ReqMessage) WithVendorId(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.VendorId = &value
+
+	return Req(input)
+}
+
+// WithVendorErrorCode returns a copy of the message with VendorErrorCode set
+// to value. The copy is validated by Req.
+func (m ReqMessage) WithVendorErrorCode(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.VendorErrorCode = &value
+
+	return Req(input)
+}
+
+// ToInput returns the empty ConfInput, as StatusNotification.conf has no
+// fields.
+func (ConfMessage) ToInput() ConfInput {
+	return ConfInput{}
+}
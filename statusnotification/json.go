@@ -0,0 +1,85 @@
+package statusnotification
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of StatusNotification.req.
+type reqWire struct {
+	ConnectorId     uint16  `json:"connectorId"`
+	ErrorCode       string  `json:"errorCode"`
+	Info            *string `json:"info,omitempty"`
+	Status          string  `json:"status"`
+	Timestamp       *string `json:"timestamp,omitempty"`
+	VendorId        *string `json:"vendorId,omitempty"`
+	VendorErrorCode *string `json:"vendorErrorCode,omitempty"`
+}
+
+// confWire is the OCPP-J payload of StatusNotification.conf.
+type confWire struct{}
+
+// MarshalJSON encodes the message as its OCPP-J StatusNotification.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		ConnectorId:     m.ConnectorId.Value(),
+		ErrorCode:       m.ErrorCode.String(),
+		Info:            wire.OptionalString(m.Info),
+		Status:          m.Status.String(),
+		Timestamp:       wire.OptionalString(m.Timestamp),
+		VendorId:        wire.OptionalString(m.VendorId),
+		VendorErrorCode: wire.OptionalString(m.VendorErrorCode),
+	})
+}
+
+// AppendJSON appends the OCPP-J StatusNotification.req payload to dst
+// without reflection. It writes the same bytes as MarshalJSON.
+func (m ReqMessage) AppendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	dst = wire.AppendIntegerField(dst, "connectorId", m.ConnectorId)
+	dst = wire.AppendStringField(dst, "errorCode", m.ErrorCode.String())
+	dst = wire.AppendOptionalField(dst, "info", m.Info)
+	dst = wire.AppendStringField(dst, "status", m.Status.String())
+	dst = wire.AppendOptionalField(dst, "timestamp", m.Timestamp)
+	dst = wire.AppendOptionalField(dst, "vendorId", m.VendorId)
+	dst = wire.AppendOptionalField(dst, "vendorErrorCode", m.VendorErrorCode)
+
+	return append(dst, '}')
+}
+
+// UnmarshalJSON decodes an OCPP-J StatusNotification.req payload and validates
+// it with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J StatusNotification.conf
+// payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{})
+}
+
+// AppendJSON appends the OCPP-J StatusNotification.conf payload to dst
+// without reflection. It writes the same bytes as MarshalJSON.
+func (m ConfMessage) AppendJSON(dst []byte) []byte {
+	return append(dst, '{', '}')
+}
+
+// UnmarshalJSON decodes an OCPP-J StatusNotification.conf payload and validates
+// it with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
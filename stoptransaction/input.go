@@ -0,0 +1,119 @@
+package stoptransaction
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		TransactionId:   int(m.TransactionId.Value()),
+		IdTag:           wire.OptionalString(m.IdTag),
+		MeterStop:       int(m.MeterStop.Value()),
+		Timestamp:       m.Timestamp.String(),
+		Reason:          wire.OptionalString(m.Reason),
+		TransactionData: wire.MeterValueInputs(m.TransactionData),
+	}
+}
+
+// WithTransactionId returns a copy of the message with TransactionId set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithTransactionId(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.TransactionId = value
+
+	return Req(input)
+}
+
+// WithIdTag returns a copy of the message with IdTag set to value. The copy
+// is validated by Req.
+func (m ReqMessage) WithIdTag(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.IdTag = &value
+
+	return Req(input)
+}
+
+// WithMeterStop returns a copy of the message with MeterStop set to value.
+// The copy is validated by Req.
+func (m ReqMessage) WithMeterStop(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.MeterStop = value
+
+	return Req(input)
+}
+
+// WithTimestamp returns a copy of the message with Timestamp set to value.
+// The copy is validated by Req.
+func (m ReqMessage) WithTimestamp(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Timestamp = value
+
+	return Req(input)
+}
+
+// WithReason returns a copy of the message with Reason set to value. The
+// copy is validated by Req.
+func (m ReqMessage) WithReason(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Reason = &value
+
+	return Req(input)
+}
+
+// WithTransactionData returns a copy of the message with TransactionData set
+// to value. The copy is validated by Req.
+func (m ReqMessage) WithTransactionData(
+	value []types.MeterValueInput,
+) (ReqMessage, error) {
+	input := m.ToInput()
+	input.TransactionData = value
+
+	return Req(input)
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	input := ConfInput{Status: nil, ExpiryDate: nil, ParentIdTag: nil}
+
+	if m.IdTagInfo != nil {
+		info := wire.IdTagInfoInput(*m.IdTagInfo)
+		input.Status = &info.Status
+		input.ExpiryDate = info.ExpiryDate
+		input.ParentIdTag = info.ParentIdTag
+	}
+
+	return input
+}
+
+// WithStatus returns a copy of the message with Status set to value. The
+// copy is validated by Conf.
+func (m ConfMessage) WithStatus(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.Status = &value
+
+	return Conf(input)
+}
+
+// WithExpiryDate returns a copy of the message with ExpiryDate set to value.
+// The copy is validated by Conf.
+func (m ConfMessage) WithExpiryDate(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.ExpiryDate = &value
+
+	return Conf(input)
+}
+
+// WithParentIdTag returns a copy of the message with ParentIdTag set to
+// value. The copy is validated by Conf.
+func (m ConfMessage) WithParentIdTag(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.ParentIdTag = &value
+
+	return Conf(input)
+}
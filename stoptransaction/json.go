@@ -0,0 +1,96 @@
+package stoptransaction
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// reqWire is the OCPP-J payload of StopTransaction.req.
+type reqWire struct {
+	IdTag           *string           `json:"idTag,omitempty"`
+	MeterStop       uint16            `json:"meterStop"`
+	Timestamp       string            `json:"timestamp"`
+	TransactionId   uint16            `json:"transactionId"`
+	Reason          *string           `json:"reason,omitempty"`
+	TransactionData []wire.MeterValue `json:"transactionData,omitempty"`
+}
+
+// confWire is the OCPP-J payload of StopTransaction.conf.
+type confWire struct {
+	IdTagInfo *wire.IdTagInfo `json:"idTagInfo,omitempty"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J StopTransaction.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		IdTag:           wire.OptionalString(m.IdTag),
+		MeterStop:       m.MeterStop.Value(),
+		Timestamp:       m.Timestamp.String(),
+		TransactionId:   m.TransactionId.Value(),
+		Reason:          wire.OptionalString(m.Reason),
+		TransactionData: wire.NewMeterValues(m.TransactionData),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J StopTransaction.req payload and validates it
+// with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J StopTransaction.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		IdTagInfo: idTagInfo(m.IdTagInfo),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J StopTransaction.conf payload and validates it
+// with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, confFromWire)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// confPayload decodes the nested idTagInfo object of StopTransaction.conf,
+// which ConfInput flattens.
+type confPayload struct {
+	IdTagInfo *types.IdTagInfoInput
+}
+
+// confFromWire flattens a decoded payload into ConfInput and validates it.
+func confFromWire(payload confPayload) (ConfMessage, error) {
+	if payload.IdTagInfo == nil {
+		return Conf(ConfInput{Status: nil, ExpiryDate: nil, ParentIdTag: nil})
+	}
+
+	return Conf(ConfInput{
+		Status:      &payload.IdTagInfo.Status,
+		ExpiryDate:  payload.IdTagInfo.ExpiryDate,
+		ParentIdTag: payload.IdTagInfo.ParentIdTag,
+	})
+}
+
+// idTagInfo converts the optional IdTagInfo to its wire form.
+func idTagInfo(info *types.IdTagInfo) *wire.IdTagInfo {
+	if info == nil {
+		return nil
+	}
+
+	converted := wire.NewIdTagInfo(*info)
+
+	return &converted
+}
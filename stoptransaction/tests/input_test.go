@@ -0,0 +1,118 @@
+package stoptransaction_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/stoptransaction"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+func TestReqMessage_ToInput(t *testing.T) {
+	t.Parallel()
+
+	idTag := "RFID-TAG-12345"
+	reason := "Local"
+	unit := "Wh"
+	input := stoptransaction.ReqInput{
+		TransactionId: 42,
+		IdTag:         &idTag,
+		MeterStop:     1500,
+		Timestamp:     "2025-01-02T15:00:00Z",
+		Reason:        &reason,
+		TransactionData: []types.MeterValueInput{
+			{
+				Timestamp: "2025-01-02T14:00:00Z",
+				SampledValue: []types.SampledValueInput{
+					{
+						Value:     "1500",
+						Context:   nil,
+						Format:    nil,
+						Measurand: nil,
+						Phase:     nil,
+						Location:  nil,
+						Unit:      &unit,
+					},
+				},
+			},
+		},
+	}
+
+	req, err := stoptransaction.Req(input)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	got := req.ToInput()
+	if !reflect.DeepEqual(got, input) {
+		t.Errorf(types.ErrorMismatch, input, got)
+	}
+
+	rebuilt, err := stoptransaction.Req(got)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if !reflect.DeepEqual(rebuilt, req) {
+		t.Errorf(types.ErrorMismatch, req, rebuilt)
+	}
+}
+
+func TestConfMessage_ToInput_WithoutIdTagInfo(t *testing.T) {
+	t.Parallel()
+
+	input := stoptransaction.ConfInput{
+		Status:      nil,
+		ExpiryDate:  nil,
+		ParentIdTag: nil,
+	}
+
+	conf, err := stoptransaction.Conf(input)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	got := conf.ToInput()
+	if !reflect.DeepEqual(got, input) {
+		t.Errorf(types.ErrorMismatch, input, got)
+	}
+}
+
+func TestConfMessage_WithStatus(t *testing.T) {
+	t.Parallel()
+
+	conf, err := stoptransaction.Conf(stoptransaction.ConfInput{
+		Status:      nil,
+		ExpiryDate:  nil,
+		ParentIdTag: nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	updated, err := conf.WithStatus("Blocked")
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if updated.IdTagInfo == nil {
+		t.Fatalf(types.ErrorWantNonNil, "IdTagInfo")
+	}
+
+	if updated.IdTagInfo.Status() != types.AuthorizationStatusBlocked {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			types.AuthorizationStatusBlocked,
+			updated.IdTagInfo.Status(),
+		)
+	}
+
+	if conf.IdTagInfo != nil {
+		t.Errorf(types.ErrorMismatchValue, nil, conf.IdTagInfo)
+	}
+
+	_, err = updated.WithExpiryDate("not-a-date")
+	if err == nil {
+		t.Errorf(types.ErrorWantNonNil, "invalid expiryDate")
+	}
+}
@@ -0,0 +1,207 @@
+package ocpp16messages_test
+
+import (
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages"
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/getcompositeschedule"
+	"github.com/aasanchez/ocpp16messages/getconfiguration"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// compositeSchedule builds a GetCompositeSchedule.conf whose schedule starts
+// at start and has one period per limit, a minute apart.
+func compositeSchedule(
+	t *testing.T,
+	start string,
+	limits ...float64,
+) getcompositeschedule.ConfMessage {
+	t.Helper()
+
+	connectorId := 1
+	periods := make([]types.ChargingSchedulePeriodInput, len(limits))
+
+	for i, limit := range limits {
+		periods[i] = types.ChargingSchedulePeriodInput{
+			StartPeriod:  i * 60,
+			Limit:        limit,
+			NumberPhases: nil,
+		}
+	}
+
+	conf, err := getcompositeschedule.Conf(getcompositeschedule.ConfInput{
+		Status:        "Accepted",
+		ConnectorId:   &connectorId,
+		ScheduleStart: &start,
+		ChargingSchedule: &types.ChargingScheduleInput{
+			Duration:               nil,
+			StartSchedule:          &start,
+			ChargingRateUnit:       "A",
+			ChargingSchedulePeriod: periods,
+			MinChargingRate:        nil,
+		},
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return conf
+}
+
+func TestDiff_DateTimeInstant(t *testing.T) {
+	t.Parallel()
+
+	want := compositeSchedule(t, "2025-01-15T10:00:00Z", 32, 16)
+	got := compositeSchedule(t, "2025-01-15T10:00:00.000Z", 32, 16)
+
+	if diffs := ocpp16messages.Diff(want, got); len(diffs) != 0 {
+		t.Errorf(types.ErrorMismatch, "no differences", diffs)
+	}
+
+	if !want.Equal(got) {
+		t.Errorf(types.ErrorMismatchValue, true, false)
+	}
+}
+
+func TestDiff_FieldPath(t *testing.T) {
+	t.Parallel()
+
+	want := compositeSchedule(t, "2025-01-15T10:00:00Z", 32, 16)
+	got := compositeSchedule(t, "2025-01-15T10:00:00Z", 32, 10)
+
+	diffs := ocpp16messages.Diff(want, got)
+	if len(diffs) != 1 {
+		t.Fatalf(types.ErrorMismatchValue, 1, len(diffs))
+	}
+
+	const path = "ChargingSchedule.ChargingSchedulePeriod[1].Limit"
+	if diffs[0].Path != path {
+		t.Errorf(types.ErrorMismatchValue, path, diffs[0].Path)
+	}
+
+	if diffs[0].A != 16.0 || diffs[0].B != 10.0 {
+		t.Errorf(types.ErrorMismatch, "16 != 10", diffs[0])
+	}
+
+	if want.Equal(got) {
+		t.Errorf(types.ErrorMismatchValue, false, true)
+	}
+}
+
+func TestDiff_MissingListItem(t *testing.T) {
+	t.Parallel()
+
+	want := compositeSchedule(t, "2025-01-15T10:00:00Z", 32, 16)
+	got := compositeSchedule(t, "2025-01-15T10:00:00Z", 32)
+
+	diffs := ocpp16messages.Diff(want, got)
+	if len(diffs) != 1 {
+		t.Fatalf(types.ErrorMismatchValue, 1, len(diffs))
+	}
+
+	const path = "ChargingSchedule.ChargingSchedulePeriod[1]"
+	if diffs[0].Path != path || diffs[0].B != nil {
+		t.Errorf(types.ErrorMismatch, path+": ... != <absent>", diffs[0])
+	}
+}
+
+func TestDiff_CiStringCaseInsensitive(t *testing.T) {
+	t.Parallel()
+
+	lower, err := bootnotification.Req(bootnotification.ReqInput{
+		ChargePointVendor:       "vendor",
+		ChargePointModel:        "Model",
+		ChargePointSerialNumber: nil,
+		ChargeBoxSerialNumber:   nil,
+		FirmwareVersion:         nil,
+		Iccid:                   nil,
+		Imsi:                    nil,
+		MeterType:               nil,
+		MeterSerialNumber:       nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	upper, err := lower.WithChargePointVendor("VENDOR")
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if !lower.Equal(upper) {
+		t.Errorf(
+			types.ErrorMismatch,
+			"equal",
+			ocpp16messages.Diff(lower, upper),
+		)
+	}
+
+	other, err := lower.WithChargePointModel("Other")
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	diffs := ocpp16messages.Diff(lower, other)
+	if len(diffs) != 1 {
+		t.Fatalf(types.ErrorMismatchValue, 1, len(diffs))
+	}
+
+	const want = `ChargePointModel: "Model" != "Other"`
+	if diffs[0].String() != want {
+		t.Errorf(types.ErrorMismatchValue, want, diffs[0].String())
+	}
+}
+
+func TestDiff_EnumerationCaseSensitive(t *testing.T) {
+	t.Parallel()
+
+	accepted := bootnotification.ConfMessage{
+		Status:      types.RegistrationStatus("Accepted"),
+		CurrentTime: types.DateTime{},
+		Interval:    types.Integer{},
+	}
+	other := accepted
+	other.Status = types.RegistrationStatus("accepted")
+
+	if accepted.Equal(other) {
+		t.Errorf(types.ErrorMismatchValue, false, true)
+	}
+}
+
+func TestDiff_NilAndEmptyLists(t *testing.T) {
+	t.Parallel()
+
+	empty := getconfiguration.ConfMessage{
+		ConfigurationKey: []types.KeyValue{},
+		UnknownKey:       nil,
+	}
+	absent := getconfiguration.ConfMessage{
+		ConfigurationKey: nil,
+		UnknownKey:       []types.CiString50Type{},
+	}
+
+	if !empty.Equal(absent) {
+		t.Errorf(
+			types.ErrorMismatch,
+			"equal",
+			ocpp16messages.Diff(empty, absent),
+		)
+	}
+}
+
+func TestDiff_DifferentTypes(t *testing.T) {
+	t.Parallel()
+
+	diffs := ocpp16messages.Diff(heartbeat.ReqMessage{}, heartbeat.ConfMessage{
+		CurrentTime: types.DateTime{},
+	})
+	if len(diffs) != 1 || diffs[0].Path != "" {
+		t.Errorf(types.ErrorMismatch, "one difference at the root", diffs)
+	}
+
+	if !ocpp16messages.Equal(heartbeat.ReqMessage{}, heartbeat.ReqMessage{}) {
+		t.Errorf(types.ErrorMismatchValue, true, false)
+	}
+}
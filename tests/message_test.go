@@ -0,0 +1,229 @@
+package ocpp16messages_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages"
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/bootnotification"
+	"github.com/aasanchez/ocpp16messages/cancelreservation"
+	"github.com/aasanchez/ocpp16messages/changeavailability"
+	"github.com/aasanchez/ocpp16messages/changeconfiguration"
+	"github.com/aasanchez/ocpp16messages/clearcache"
+	"github.com/aasanchez/ocpp16messages/clearchargingprofile"
+	"github.com/aasanchez/ocpp16messages/datatransfer"
+	"github.com/aasanchez/ocpp16messages/diagnosticsstatusnotification"
+	"github.com/aasanchez/ocpp16messages/firmwarestatusnotification"
+	"github.com/aasanchez/ocpp16messages/getcompositeschedule"
+	"github.com/aasanchez/ocpp16messages/getconfiguration"
+	"github.com/aasanchez/ocpp16messages/getdiagnostics"
+	"github.com/aasanchez/ocpp16messages/getlocallistversion"
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/metervalues"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/remotestoptransaction"
+	"github.com/aasanchez/ocpp16messages/reservenow"
+	"github.com/aasanchez/ocpp16messages/reset"
+	"github.com/aasanchez/ocpp16messages/sendlocallist"
+	"github.com/aasanchez/ocpp16messages/setchargingprofile"
+	"github.com/aasanchez/ocpp16messages/starttransaction"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	"github.com/aasanchez/ocpp16messages/stoptransaction"
+	"github.com/aasanchez/ocpp16messages/triggermessage"
+	"github.com/aasanchez/ocpp16messages/unlockconnector"
+	"github.com/aasanchez/ocpp16messages/updatefirmware"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// allMessages holds the zero value of every message type; the literal
+// fails to compile if one of them does not implement Message.
+var allMessages = []ocpp16messages.Message{
+	authorize.ReqMessage{},
+	authorize.ConfMessage{},
+	bootnotification.ReqMessage{},
+	bootnotification.ConfMessage{},
+	cancelreservation.ReqMessage{},
+	cancelreservation.ConfMessage{},
+	changeavailability.ReqMessage{},
+	changeavailability.ConfMessage{},
+	changeconfiguration.ReqMessage{},
+	changeconfiguration.ConfMessage{},
+	clearcache.ReqMessage{},
+	clearcache.ConfMessage{},
+	clearchargingprofile.ReqMessage{},
+	clearchargingprofile.ConfMessage{},
+	datatransfer.ReqMessage{},
+	datatransfer.ConfMessage{},
+	diagnosticsstatusnotification.ReqMessage{},
+	diagnosticsstatusnotification.ConfMessage{},
+	firmwarestatusnotification.ReqMessage{},
+	firmwarestatusnotification.ConfMessage{},
+	getcompositeschedule.ReqMessage{},
+	getcompositeschedule.ConfMessage{},
+	getconfiguration.ReqMessage{},
+	getconfiguration.ConfMessage{},
+	getdiagnostics.ReqMessage{},
+	getdiagnostics.ConfMessage{},
+	getlocallistversion.ReqMessage{},
+	getlocallistversion.ConfMessage{},
+	heartbeat.ReqMessage{},
+	heartbeat.ConfMessage{},
+	metervalues.ReqMessage{},
+	metervalues.ConfMessage{},
+	remotestarttransaction.ReqMessage{},
+	remotestarttransaction.ConfMessage{},
+	remotestoptransaction.ReqMessage{},
+	remotestoptransaction.ConfMessage{},
+	reservenow.ReqMessage{},
+	reservenow.ConfMessage{},
+	reset.ReqMessage{},
+	reset.ConfMessage{},
+	sendlocallist.ReqMessage{},
+	sendlocallist.ConfMessage{},
+	setchargingprofile.ReqMessage{},
+	setchargingprofile.ConfMessage{},
+	starttransaction.ReqMessage{},
+	starttransaction.ConfMessage{},
+	statusnotification.ReqMessage{},
+	statusnotification.ConfMessage{},
+	stoptransaction.ReqMessage{},
+	stoptransaction.ConfMessage{},
+	triggermessage.ReqMessage{},
+	triggermessage.ConfMessage{},
+	unlockconnector.ReqMessage{},
+	unlockconnector.ConfMessage{},
+	updatefirmware.ReqMessage{},
+	updatefirmware.ConfMessage{},
+}
+
+func TestMessage_EveryAction(t *testing.T) {
+	t.Parallel()
+
+	seen := map[string]int{}
+
+	for _, msg := range allMessages {
+		action := msg.Action()
+		seen[action]++
+
+		if !slices.Contains(ocppj.Actions(), action) {
+			t.Errorf("%T: unknown action %q", msg, action)
+		}
+
+		if msg.FeatureProfile() == "" {
+			t.Errorf("%T: empty feature profile", msg)
+		}
+
+		want := direction(action, msg.Kind())
+		if msg.Direction() != want {
+			t.Errorf("%T: want %s, got %s", msg, want, msg.Direction())
+		}
+	}
+
+	for _, action := range ocppj.Actions() {
+		if seen[action] != 2 {
+			t.Errorf("%s: %d message types, want 2", action, seen[action])
+		}
+	}
+}
+
+// direction derives the expected direction of a message from the
+// initiator of its action.
+func direction(
+	action string,
+	kind ocpp16messages.Kind,
+) ocpp16messages.Direction {
+	chargePoint := ocppj.Initiates(ocppj.RoleChargePoint, action)
+	centralSystem := ocppj.Initiates(ocppj.RoleCentralSystem, action)
+
+	switch {
+	case chargePoint && centralSystem:
+		return ocpp16messages.Bidirectional
+	case chargePoint == (kind == ocpp16messages.Request):
+		return ocpp16messages.ChargePointToCentralSystem
+	default:
+		return ocpp16messages.CentralSystemToChargePoint
+	}
+}
+
+func TestMessage_Kinds(t *testing.T) {
+	t.Parallel()
+
+	var req ocpp16messages.Message = reset.ReqMessage{}
+	if req.Kind() != ocpp16messages.Request || req.Kind().String() != "req" {
+		t.Errorf(types.ErrorMismatchValue, ocpp16messages.Request, req.Kind())
+	}
+
+	var conf ocpp16messages.Message = reset.ConfMessage{}
+	if conf.Kind() != ocpp16messages.Confirmation {
+		t.Errorf(
+			types.ErrorMismatchValue,
+			ocpp16messages.Confirmation,
+			conf.Kind(),
+		)
+	}
+
+	if conf.FeatureProfile() != ocpp16messages.Core ||
+		conf.Direction() != ocpp16messages.ChargePointToCentralSystem {
+		t.Errorf(types.ErrorMismatchValue, "Core CP->CS", conf.Direction())
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	req, err := ocpp16messages.Decode[authorize.ReqMessage](
+		[]byte(`{"idTag":"RFID-ABC123"}`),
+	)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if req.IdTag.String() != "RFID-ABC123" {
+		t.Errorf(types.ErrorMismatchValue, "RFID-ABC123", req.IdTag)
+	}
+
+	_, err = ocpp16messages.Decode[authorize.ReqMessage]([]byte(`{}`))
+	if !errors.Is(err, types.ErrEmptyValue) {
+		t.Errorf(types.ErrorWrapping, err, types.ErrEmptyValue)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	req, err := reset.Req(reset.ReqInput{Type: "Soft"})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	data, err := ocpp16messages.Encode(req)
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if string(data) != `{"type":"Soft"}` {
+		t.Errorf(types.ErrorMismatchValue, `{"type":"Soft"}`, string(data))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	req, err := heartbeat.Req(heartbeat.ReqInput{})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	err = ocpp16messages.Validate(req)
+	if err != nil {
+		t.Errorf(types.ErrorUnexpectedError, err)
+	}
+
+	err = ocpp16messages.Validate(authorize.ReqMessage{})
+	if err == nil {
+		t.Errorf(types.ErrorWantNonNil, "error")
+	}
+}
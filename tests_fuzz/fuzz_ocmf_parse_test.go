@@ -0,0 +1,52 @@
+//go:build fuzz
+
+package fuzz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/ocmf"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+func FuzzOCMFParse(f *testing.F) {
+	f.Add(`OCMF|{"PG":"T1","MS":"1","RD":[{"TM":"2018-07-24T13:22:04,000+0200 S",` +
+		`"RV":1,"RU":"kWh","ST":"G"}]}|{"SD":"3045"}`)
+	f.Add("OCMF|{}|{}")
+	f.Add("OCMF||")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, value string) {
+		if len(value) > maxFuzzLen {
+			t.Skip()
+		}
+
+		doc, err := ocmf.Parse(value)
+		if err != nil {
+			if !errors.Is(err, types.ErrEmptyValue) &&
+				!errors.Is(err, types.ErrInvalidValue) {
+				t.Fatalf(
+					"error = %v, want wrapping ErrEmptyValue or ErrInvalidValue",
+					err,
+				)
+			}
+
+			return
+		}
+
+		if doc.String() != value {
+			t.Fatalf("String() = %q, want %q", doc.String(), value)
+		}
+
+		if len(doc.Readings()) == 0 {
+			t.Fatal("Parse succeeded without readings")
+		}
+
+		for _, reading := range doc.Readings() {
+			if _, _, timeErr := reading.Time(); timeErr != nil {
+				t.Fatalf("Time() error = %v after successful Parse", timeErr)
+			}
+		}
+	})
+}
@@ -28,8 +28,9 @@ func FuzzRemoteStartTransactionReq(f *testing.F) {
 		}
 
 		req, err := rst.Req(rst.ReqInput{
-			IdTag:       idTag,
-			ConnectorId: connectorIdPtr,
+			IdTag:           idTag,
+			ConnectorId:     connectorIdPtr,
+			ChargingProfile: nil,
 		})
 		if err != nil {
 			if !errors.Is(err, types.ErrInvalidValue) && !errors.Is(err, types.ErrEmptyValue) {
@@ -0,0 +1,273 @@
+//go:build fuzz
+
+package fuzz
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/schema"
+)
+
+// schemaSeed is a conforming payload of one action.
+type schemaSeed struct {
+	action       string
+	request      string
+	confirmation string
+}
+
+const (
+	seedTime      = `"2025-01-02T15:00:00Z"`
+	seedIdTagInfo = `{"status":"Accepted","expiryDate":` + seedTime + `}`
+	seedSchedule  = `{"chargingRateUnit":"A","chargingSchedulePeriod":` +
+		`[{"startPeriod":0,"limit":16.0,"numberPhases":3}]}`
+	seedProfile = `{"chargingProfileId":1,"stackLevel":0,` +
+		`"chargingProfilePurpose":"TxDefaultProfile",` +
+		`"chargingProfileKind":"Relative","chargingSchedule":` +
+		seedSchedule + `}`
+	seedMeterValue = `{"timestamp":` + seedTime + `,"sampledValue":` +
+		`[{"value":"1234","measurand":"Energy.Active.Import.Register",` +
+		`"unit":"Wh"}]}`
+)
+
+// schemaSeeds lists a conforming request and confirmation of every action.
+var schemaSeeds = []schemaSeed{
+	{
+		"Authorize",
+		`{"idTag":"RFID-ABC123"}`,
+		`{"idTagInfo":` + seedIdTagInfo + `}`,
+	},
+	{
+		"BootNotification",
+		`{"chargePointVendor":"Vendor","chargePointModel":"Model",` +
+			`"firmwareVersion":"1.0.0"}`,
+		`{"status":"Accepted","currentTime":` + seedTime +
+			`,"interval":300}`,
+	},
+	{
+		"CancelReservation",
+		`{"reservationId":7}`,
+		`{"status":"Accepted"}`,
+	},
+	{
+		"ChangeAvailability",
+		`{"connectorId":1,"type":"Inoperative"}`,
+		`{"status":"Scheduled"}`,
+	},
+	{
+		"ChangeConfiguration",
+		`{"key":"HeartbeatInterval","value":"60"}`,
+		`{"status":"RebootRequired"}`,
+	},
+	{"ClearCache", `{}`, `{"status":"Accepted"}`},
+	{
+		"ClearChargingProfile",
+		`{"id":1,"chargingProfilePurpose":"TxDefaultProfile"}`,
+		`{"status":"Unknown"}`,
+	},
+	{
+		"DataTransfer",
+		`{"vendorId":"com.example","messageId":"ping","data":"{}"}`,
+		`{"status":"Accepted","data":"pong"}`,
+	},
+	{
+		"DiagnosticsStatusNotification",
+		`{"status":"Uploaded"}`,
+		`{}`,
+	},
+	{
+		"FirmwareStatusNotification",
+		`{"status":"Installed"}`,
+		`{}`,
+	},
+	{
+		"GetCompositeSchedule",
+		`{"connectorId":1,"duration":3600,"chargingRateUnit":"W"}`,
+		`{"status":"Accepted","connectorId":1,"scheduleStart":` + seedTime +
+			`,"chargingSchedule":` + seedSchedule + `}`,
+	},
+	{
+		"GetConfiguration",
+		`{"key":["HeartbeatInterval"]}`,
+		`{"configurationKey":[{"key":"HeartbeatInterval","readonly":false,` +
+			`"value":"60"}],"unknownKey":["Foo"]}`,
+	},
+	{
+		"GetDiagnostics",
+		`{"location":"ftp://diagnostics.example.com/upload","retries":3}`,
+		`{"fileName":"diagnostics.zip"}`,
+	},
+	{"GetLocalListVersion", `{}`, `{"listVersion":4}`},
+	{"Heartbeat", `{}`, `{"currentTime":` + seedTime + `}`},
+	{
+		"MeterValues",
+		`{"connectorId":1,"transactionId":42,"meterValue":[` +
+			seedMeterValue + `]}`,
+		`{}`,
+	},
+	{
+		"RemoteStartTransaction",
+		`{"connectorId":1,"idTag":"RFID-ABC123"}`,
+		`{"status":"Accepted"}`,
+	},
+	{
+		"RemoteStopTransaction",
+		`{"transactionId":42}`,
+		`{"status":"Rejected"}`,
+	},
+	{
+		"ReserveNow",
+		`{"connectorId":1,"expiryDate":` + seedTime +
+			`,"idTag":"RFID-ABC123","reservationId":7}`,
+		`{"status":"Occupied"}`,
+	},
+	{"Reset", `{"type":"Soft"}`, `{"status":"Accepted"}`},
+	{
+		"SendLocalList",
+		`{"listVersion":5,"updateType":"Differential",` +
+			`"localAuthorizationList":[{"idTag":"RFID-ABC123",` +
+			`"idTagInfo":{"status":"Blocked"}}]}`,
+		`{"status":"VersionMismatch"}`,
+	},
+	{
+		"SetChargingProfile",
+		`{"connectorId":0,"csChargingProfiles":` + seedProfile + `}`,
+		`{"status":"NotSupported"}`,
+	},
+	{
+		"StartTransaction",
+		`{"connectorId":1,"idTag":"RFID-ABC123","meterStart":0,` +
+			`"timestamp":` + seedTime + `}`,
+		`{"transactionId":42,"idTagInfo":` + seedIdTagInfo + `}`,
+	},
+	{
+		"StatusNotification",
+		`{"connectorId":1,"errorCode":"NoError","status":"Charging",` +
+			`"timestamp":` + seedTime + `}`,
+		`{}`,
+	},
+	{
+		"StopTransaction",
+		`{"transactionId":42,"meterStop":1234,"timestamp":` + seedTime +
+			`,"reason":"Local","transactionData":[` + seedMeterValue + `]}`,
+		`{"idTagInfo":` + seedIdTagInfo + `}`,
+	},
+	{
+		"TriggerMessage",
+		`{"requestedMessage":"StatusNotification","connectorId":1}`,
+		`{"status":"NotImplemented"}`,
+	},
+	{"UnlockConnector", `{"connectorId":1}`, `{"status":"Unlocked"}`},
+	{
+		"UpdateFirmware",
+		`{"location":"https://firmware.example.com/fw.bin",` +
+			`"retrieveDate":` + seedTime + `}`,
+		`{}`,
+	},
+}
+
+// schemaMutations turn a conforming payload into near misses.
+var schemaMutations = []func(string) string{
+	func(p string) string { return p },
+	func(p string) string { return `{"unknown":1,` + p[1:] },
+	func(p string) string { return p[:len(p)-1] },
+	func(string) string { return `{}` },
+	func(string) string { return `null` },
+}
+
+func FuzzSchemaRequest(f *testing.F) {
+	for _, seed := range schemaSeeds {
+		for _, mutate := range schemaMutations {
+			f.Add(seed.action, mutate(seed.request))
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, action, payload string) {
+		fuzzSchema(t, action, schema.Request, payload)
+	})
+}
+
+func FuzzSchemaConfirmation(f *testing.F) {
+	for _, seed := range schemaSeeds {
+		for _, mutate := range schemaMutations {
+			f.Add(seed.action, mutate(seed.confirmation))
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, action, payload string) {
+		fuzzSchema(t, action, schema.Confirmation, payload)
+	})
+}
+
+// fuzzSchema runs a payload through the schema validator and the message
+// package, checks that CrossCheck reports their disagreement and that
+// every message the library accepts encodes to a conforming payload.
+func fuzzSchema(t *testing.T, action string, kind schema.Kind, payload string) {
+	t.Helper()
+
+	if len(payload) > maxFuzzLen {
+		t.Skip()
+	}
+
+	schemaErr := schema.Validate(action, kind, []byte(payload))
+	if errors.Is(schemaErr, schema.ErrUnknownAction) {
+		t.Skip()
+	}
+
+	if schemaErr != nil && !errors.Is(schemaErr, schema.ErrInvalid) {
+		t.Fatalf("Validate error = %v, want wrapping ErrInvalid", schemaErr)
+	}
+
+	decode := ocppj.DecodeRequest
+	if kind == schema.Confirmation {
+		decode = ocppj.DecodeConfirmation
+	}
+
+	msg, libraryErr := decode(action, []byte(payload))
+
+	crossErr := schema.CrossCheck(action, kind, []byte(payload))
+	if (schemaErr == nil) == (libraryErr == nil) {
+		if crossErr != nil {
+			t.Fatalf("CrossCheck error = %v, want nil", crossErr)
+		}
+	} else {
+		var mismatch *schema.Mismatch
+		if !errors.As(crossErr, &mismatch) {
+			t.Fatalf("CrossCheck error = %v, want *Mismatch", crossErr)
+		}
+
+		if (mismatch.SchemaErr == nil) != (schemaErr == nil) {
+			t.Fatalf(
+				"Mismatch.SchemaErr = %v, want %v",
+				mismatch.SchemaErr,
+				schemaErr,
+			)
+		}
+	}
+
+	if libraryErr != nil {
+		return
+	}
+
+	encoded, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("json.Marshal error = %v after successful decode", err)
+	}
+
+	err = schema.Validate(action, kind, encoded)
+	if err != nil && !isRelativeLocation(err) {
+		t.Fatalf("encoded %s does not conform: %v", encoded, err)
+	}
+}
+
+// isRelativeLocation reports whether a Validate error is only the location
+// without a scheme the constructors accept, a documented difference.
+func isRelativeLocation(err error) bool {
+	violations := strings.Split(err.Error(), "\n")
+
+	return errors.Is(err, schema.ErrFormat) && len(violations) == 1 &&
+		strings.Contains(violations[0], "/location")
+}
@@ -0,0 +1,201 @@
+package testsjson_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/heartbeat"
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+	"github.com/aasanchez/ocpp16messages/metervalues"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// appender is a message with a reflection-free encoder.
+type appender interface {
+	AppendJSON(dst []byte) []byte
+}
+
+func assertAppendJSON(t *testing.T, msg appender) {
+	t.Helper()
+
+	want, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	prefix := []byte(`[2,"id","Action",`)
+
+	got := msg.AppendJSON(bytes.Clone(prefix))
+	if !bytes.Equal(got, append(prefix, want...)) {
+		t.Errorf("AppendJSON = %s, want %s%s", got, prefix, want)
+	}
+}
+
+func TestAppendJSON_Heartbeat(t *testing.T) {
+	t.Parallel()
+
+	req, err := heartbeat.Req(heartbeat.ReqInput{})
+	if err != nil {
+		t.Fatalf("heartbeat.Req: %v", err)
+	}
+
+	conf, err := heartbeat.Conf(heartbeat.ConfInput{
+		CurrentTime: "2025-01-02T15:00:00.123Z",
+	})
+	if err != nil {
+		t.Fatalf("heartbeat.Conf: %v", err)
+	}
+
+	assertAppendJSON(t, req)
+	assertAppendJSON(t, conf)
+}
+
+func TestAppendJSON_StatusNotification(t *testing.T) {
+	t.Parallel()
+
+	timestamp := "2025-01-02T15:00:00Z"
+	info := `Door "A" <open> & \locked\`
+	vendor := "Vendor"
+
+	inputs := []statusnotification.ReqInput{
+		{
+			ConnectorId:     1,
+			ErrorCode:       "NoError",
+			Status:          "Available",
+			Info:            nil,
+			Timestamp:       nil,
+			VendorId:        nil,
+			VendorErrorCode: nil,
+		},
+		{
+			ConnectorId:     2,
+			ErrorCode:       "OtherError",
+			Status:          "Faulted",
+			Info:            &info,
+			Timestamp:       &timestamp,
+			VendorId:        &vendor,
+			VendorErrorCode: &vendor,
+		},
+	}
+
+	for _, input := range inputs {
+		req, err := statusnotification.Req(input)
+		if err != nil {
+			t.Fatalf("statusnotification.Req: %v", err)
+		}
+
+		assertAppendJSON(t, req)
+	}
+
+	assertAppendJSON(t, statusnotification.ConfMessage{})
+}
+
+func TestAppendJSON_MeterValues(t *testing.T) {
+	t.Parallel()
+
+	transactionId := 7
+	unit := "Wh"
+	measurand := "Energy.Active.Import.Register"
+
+	req, err := metervalues.Req(metervalues.ReqInput{
+		ConnectorId:   1,
+		TransactionId: &transactionId,
+		MeterValue: []types.MeterValueInput{
+			{
+				Timestamp: "2025-01-02T15:00:00Z",
+				SampledValue: []types.SampledValueInput{
+					{
+						Value:     "100",
+						Context:   nil,
+						Format:    nil,
+						Measurand: &measurand,
+						Phase:     nil,
+						Location:  nil,
+						Unit:      &unit,
+					},
+					{
+						Value:     "7",
+						Context:   nil,
+						Format:    nil,
+						Measurand: nil,
+						Phase:     nil,
+						Location:  nil,
+						Unit:      nil,
+					},
+				},
+			},
+			{
+				Timestamp: "2025-01-02T15:01:00Z",
+				SampledValue: []types.SampledValueInput{
+					{
+						Value:     "101",
+						Context:   nil,
+						Format:    nil,
+						Measurand: nil,
+						Phase:     nil,
+						Location:  nil,
+						Unit:      nil,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("metervalues.Req: %v", err)
+	}
+
+	assertAppendJSON(t, req)
+	assertAppendJSON(t, metervalues.ConfMessage{})
+}
+
+func TestAppendString_MatchesEncodingJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, str := range []string{
+		"",
+		"plain",
+		"quote \" backslash \\ slash /",
+		"html <b>&amp;</b>",
+		"control \n\r\t\b\f\x00\x1f\x7f",
+		"unicode \u00e9 \u2713 \u2028 \u2029",
+		"invalid \xff\xfe utf-8",
+		"truncated \xc3",
+		"surrogate \xed\xa0\x80",
+	} {
+		want, err := json.Marshal(str)
+		if err != nil {
+			t.Fatalf("json.Marshal: %v", err)
+		}
+
+		got := wire.AppendString(nil, str)
+		if !bytes.Equal(got, want) {
+			t.Errorf("AppendString(%q) = %s, want %s", str, got, want)
+		}
+	}
+}
+
+func TestAppendString_ControlCharacters(t *testing.T) {
+	t.Parallel()
+
+	// The C0 controls and DEL, each between two letters.
+	controls := []byte{0x7f}
+	for char := range byte(' ') {
+		controls = append(controls, char)
+	}
+
+	for _, char := range controls {
+		str := "a" + string(rune(char)) + "b"
+
+		want, err := json.Marshal(str)
+		if err != nil {
+			t.Fatalf("json.Marshal: %v", err)
+		}
+
+		got := wire.AppendString(nil, str)
+		if !bytes.Equal(got, want) {
+			t.Errorf("AppendString(%q) = %s, want %s", str, got, want)
+		}
+	}
+}
@@ -10,8 +10,9 @@ func TestRemoteStartTransactionReq_JSONRoundTrip(t *testing.T) {
 	t.Parallel()
 
 	req, err := rst.Req(rst.ReqInput{
-		IdTag:       "RFID-TAG-12345",
-		ConnectorId: nil,
+		IdTag:           "RFID-TAG-12345",
+		ConnectorId:     nil,
+		ChargingProfile: nil,
 	})
 	if err != nil {
 		t.Fatalf("remotestarttransaction.Req: %v", err)
@@ -0,0 +1,225 @@
+package testsjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aasanchez/ocpp16messages/authorize"
+	"github.com/aasanchez/ocpp16messages/ocppj"
+	"github.com/aasanchez/ocpp16messages/remotestarttransaction"
+	"github.com/aasanchez/ocpp16messages/starttransaction"
+	"github.com/aasanchez/ocpp16messages/statusnotification"
+	"github.com/aasanchez/ocpp16messages/stoptransaction"
+	"github.com/aasanchez/ocpp16messages/unlockconnector"
+)
+
+func TestStatusNotificationReq_WireFormat(t *testing.T) {
+	t.Parallel()
+
+	timestamp := "2025-01-02T15:00:00Z"
+
+	req, err := statusnotification.Req(statusnotification.ReqInput{
+		ConnectorId:     1,
+		ErrorCode:       "NoError",
+		Status:          "Charging",
+		Info:            nil,
+		Timestamp:       &timestamp,
+		VendorId:        nil,
+		VendorErrorCode: nil,
+	})
+	if err != nil {
+		t.Fatalf("statusnotification.Req: %v", err)
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	want := `{"connectorId":1,"errorCode":"NoError","status":"Charging",` +
+		`"timestamp":"2025-01-02T15:00:00Z"}`
+	if string(data) != want {
+		t.Errorf("json = %s, want %s", data, want)
+	}
+}
+
+func TestAuthorizeConf_WireFormat(t *testing.T) {
+	t.Parallel()
+
+	payload := `{"idTagInfo":{"status":"Blocked",` +
+		`"expiryDate":"2025-06-30T00:00:00Z"}}`
+
+	var conf authorize.ConfMessage
+
+	err := json.Unmarshal([]byte(payload), &conf)
+	if err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+
+	if conf.IdTagInfo.Status().String() != "Blocked" {
+		t.Errorf("status = %s, want Blocked", conf.IdTagInfo.Status())
+	}
+
+	data, err := json.Marshal(conf)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	assertJSONSemanticallyEqual(t, []byte(payload), data)
+}
+
+func TestStartTransactionConf_WireFormatInvalid(t *testing.T) {
+	t.Parallel()
+
+	payloads := []string{
+		`{"transactionId":1}`,
+		`{"transactionId":"1","idTagInfo":{"status":"Accepted"}}`,
+		`{"transactionId":1,"idTagInfo":{"status":"Maybe"}}`,
+	}
+
+	for _, payload := range payloads {
+		var conf starttransaction.ConfMessage
+
+		err := json.Unmarshal([]byte(payload), &conf)
+		if err == nil {
+			t.Errorf("json.Unmarshal(%s): want error", payload)
+		}
+	}
+}
+
+func TestStopTransactionReq_WireFormatStrict(t *testing.T) {
+	t.Parallel()
+
+	const fields = `"meterStop":100,"timestamp":"2025-01-02T15:00:00Z"`
+
+	tests := []struct {
+		name    string
+		payload string
+		code    ocppj.ErrorCode
+		want    error
+	}{
+		{
+			"unknown property",
+			`{"transactionId":1,` + fields + `,"odometer":5}`,
+			ocppj.FormationViolation,
+			ocppj.ErrUnknownProperty,
+		},
+		{
+			"nested unknown property",
+			`{"transactionId":1,` + fields + `,"transactionData":[` +
+				`{"timestamp":"2025-01-02T15:00:00Z","sampledValue":` +
+				`[{"value":"100","scale":2}]}]}`,
+			ocppj.FormationViolation,
+			ocppj.ErrUnknownProperty,
+		},
+		{
+			"property case",
+			`{"TransactionId":1,` + fields + `}`,
+			ocppj.FormationViolation,
+			ocppj.ErrUnknownProperty,
+		},
+		{
+			"duplicate key",
+			`{"transactionId":1,"transactionId":2,` + fields + `}`,
+			ocppj.FormationViolation,
+			ocppj.ErrDuplicateKey,
+		},
+		{
+			"missing transactionId",
+			`{` + fields + `}`,
+			ocppj.OccurenceConstraintViolation,
+			ocppj.ErrMissingProperty,
+		},
+		{
+			"missing meterStop",
+			`{"transactionId":1,"timestamp":"2025-01-02T15:00:00Z"}`,
+			ocppj.OccurenceConstraintViolation,
+			ocppj.ErrMissingProperty,
+		},
+		{
+			"fraction for integer",
+			`{"transactionId":1.0,` + fields + `}`,
+			ocppj.TypeConstraintViolation,
+			nil,
+		},
+		{
+			"exponent for integer",
+			`{"transactionId":1,"meterStop":1e2,` +
+				`"timestamp":"2025-01-02T15:00:00Z"}`,
+			ocppj.TypeConstraintViolation,
+			nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var req stoptransaction.ReqMessage
+
+			err := json.Unmarshal([]byte(tc.payload), &req)
+			if err == nil {
+				t.Fatalf("json.Unmarshal(%s): want error", tc.payload)
+			}
+
+			if tc.want != nil && !errors.Is(err, tc.want) {
+				t.Errorf("error = %v, want wrapping %v", err, tc.want)
+			}
+
+			code := ocppj.ErrorFor(err).Code
+			if code != tc.code {
+				t.Errorf("code = %s, want %s", code, tc.code)
+			}
+		})
+	}
+}
+
+func TestUnlockConnectorReq_WireFormatEmpty(t *testing.T) {
+	t.Parallel()
+
+	var req unlockconnector.ReqMessage
+
+	err := json.Unmarshal([]byte(`{}`), &req)
+	if !errors.Is(err, ocppj.ErrMissingProperty) {
+		t.Errorf("error = %v, want wrapping %v", err, ocppj.ErrMissingProperty)
+	}
+
+	code := ocppj.ErrorFor(err).Code
+	if code != ocppj.OccurenceConstraintViolation {
+		t.Errorf("code = %s, want %s", code, ocppj.OccurenceConstraintViolation)
+	}
+}
+
+func TestRemoteStartTransactionReq_WireFormatChargingProfile(t *testing.T) {
+	t.Parallel()
+
+	payload := `{"idTag":"RFID-ABC123","chargingProfile":` +
+		`{"chargingProfileId":1,"stackLevel":0,` +
+		`"chargingProfilePurpose":"TxProfile",` +
+		`"chargingProfileKind":"Relative","chargingSchedule":` +
+		`{"chargingRateUnit":"A","chargingSchedulePeriod":` +
+		`[{"startPeriod":0,"limit":16}]}}}`
+
+	var req remotestarttransaction.ReqMessage
+
+	err := json.Unmarshal([]byte(payload), &req)
+	if err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+
+	if req.IdTag.String() != "RFID-ABC123" {
+		t.Errorf("idTag = %s, want RFID-ABC123", req.IdTag)
+	}
+
+	if req.ChargingProfile == nil {
+		t.Fatal("chargingProfile dropped")
+	}
+
+	encoded, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	assertJSONSemanticallyEqual(t, []byte(payload), encoded)
+}
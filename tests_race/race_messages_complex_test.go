@@ -240,8 +240,9 @@ func TestRace_RemoteStartTransactionReq(t *testing.T) {
 
 	connectorId := 1
 	input := rst.ReqInput{
-		IdTag:       "TAG-1",
-		ConnectorId: &connectorId,
+		IdTag:           "TAG-1",
+		ConnectorId:     &connectorId,
+		ChargingProfile: nil,
 	}
 
 	runConcurrent(t, raceWorkers, raceIterations, func(_, _ int) error {
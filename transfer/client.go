@@ -0,0 +1,153 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+)
+
+// UploadField is the multipart form field an HTTP upload carries the file
+// in.
+const UploadField = "file"
+
+// ErrHTTPStatus is returned when an HTTP server answers a transfer with a
+// status other than 2xx.
+var ErrHTTPStatus = errors.New("transfer: unexpected HTTP status")
+
+// Client downloads firmware and uploads diagnostics over HTTP, HTTPS and
+// FTP. The zero value uses http.DefaultClient.
+type Client struct {
+	// HTTP is the client of http and https locations. Nil selects
+	// http.DefaultClient.
+	HTTP *http.Client
+}
+
+// Download fetches the file at location, with a GET or an FTP RETR.
+func (c Client) Download(
+	ctx context.Context,
+	location *url.URL,
+) ([]byte, error) {
+	if location.Scheme == SchemeFTP {
+		session, err := dialFTP(ctx, location)
+		if err != nil {
+			return nil, err
+		}
+
+		defer session.close()
+
+		return session.retrieve(ctx, location.Path)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		location.String(),
+		http.NoBody,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("transfer: %w", err)
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("transfer: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	err = checkStatus(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("transfer: %w", err)
+	}
+
+	return data, nil
+}
+
+// Upload stores a file named fileName under the directory location: over
+// FTP with a STOR of the joined path, over HTTP with a multipart/form-data
+// POST to location carrying the file in UploadField.
+func (c Client) Upload(
+	ctx context.Context,
+	location *url.URL,
+	fileName string,
+	data []byte,
+) error {
+	if location.Scheme == SchemeFTP {
+		session, err := dialFTP(ctx, location)
+		if err != nil {
+			return err
+		}
+
+		defer session.close()
+
+		return session.store(ctx, path.Join(location.Path, fileName), data)
+	}
+
+	var body bytes.Buffer
+
+	form := multipart.NewWriter(&body)
+
+	part, err := form.CreateFormFile(UploadField, fileName)
+	if err == nil {
+		_, err = part.Write(data)
+	}
+
+	if err == nil {
+		err = form.Close()
+	}
+
+	if err != nil {
+		return fmt.Errorf("transfer: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		location.String(),
+		&body,
+	)
+	if err != nil {
+		return fmt.Errorf("transfer: %w", err)
+	}
+
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("transfer: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	return checkStatus(resp)
+}
+
+// httpClient returns the HTTP client to use.
+func (c Client) httpClient() *http.Client {
+	if c.HTTP == nil {
+		return http.DefaultClient
+	}
+
+	return c.HTTP
+}
+
+// checkStatus fails a response whose status is not 2xx.
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode < http.StatusOK ||
+		resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s", ErrHTTPStatus, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+// Package transfer carries out firmware updates and diagnostics uploads on
+// the Charge Point side.
+//
+// ParseLocation validates the location of an UpdateFirmware.req or a
+// GetDiagnostics.req: an absolute http, https or ftp URL whose decoded path,
+// user and password hold no control characters, so that none reaches the
+// FTP control connection. Client downloads and uploads files at such
+// locations; an FTP URL may carry its user and password, and logs in
+// anonymously otherwise.
+//
+// An Executor runs a transfer once its confirmation is sent, attempting it
+// again on failure as the retries and retryInterval of the request ask, and
+// reports each step with FirmwareStatusNotification.req or
+// DiagnosticsStatusNotification.req:
+//
+//	executor := transfer.NewExecutor(conn, transfer.Client{HTTP: nil}, nil)
+//
+//	// Once the UpdateFirmware.conf is sent:
+//	err := executor.UpdateFirmware(ctx, req, flash)
+//
+//	// Once the GetDiagnostics.conf naming "diagnostics.log" is sent:
+//	err := executor.UploadDiagnostics(ctx, req, "diagnostics.log", logs)
+//
+// ocpp16test.NewHTTPFileServer and ocpp16test.NewFTPFileServer provide local
+// servers to test against.
+package transfer
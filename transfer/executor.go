@@ -0,0 +1,252 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/diagnosticsstatusnotification"
+	"github.com/aasanchez/ocpp16messages/firmwarestatusnotification"
+	"github.com/aasanchez/ocpp16messages/getdiagnostics"
+	"github.com/aasanchez/ocpp16messages/registration"
+	"github.com/aasanchez/ocpp16messages/updatefirmware"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// DefaultRetryInterval is the wait between attempts when a request gives
+// retries but no retryInterval.
+const DefaultRetryInterval = 30 * time.Second
+
+// Caller sends a CALL and decodes its answer. *ocppj.Conn implements it.
+type Caller interface {
+	Call(ctx context.Context, action string, request, confirmation any) error
+}
+
+// Executor carries out UpdateFirmware.req and GetDiagnostics.req on the
+// Charge Point side, reporting progress with FirmwareStatusNotification.req
+// and DiagnosticsStatusNotification.req. A failed transfer is attempted
+// again retries times, retryInterval seconds apart. Errors sending a status
+// do not stop a transfer; they are returned with its result.
+type Executor struct {
+	caller Caller
+	client Client
+	clock  registration.Clock
+}
+
+// NewExecutor returns an Executor transferring with client and reporting
+// through caller. A nil clock selects registration.SystemClock.
+func NewExecutor(
+	caller Caller,
+	client Client,
+	clock registration.Clock,
+) *Executor {
+	if clock == nil {
+		clock = registration.SystemClock{}
+	}
+
+	return &Executor{caller: caller, client: client, clock: clock}
+}
+
+// UpdateFirmware waits for the retrieveDate of req, downloads the firmware
+// and passes it to install, reporting Downloading, then Downloaded or
+// DownloadFailed, then Installing and Installed or InstallationFailed. Run
+// it after the UpdateFirmware.conf is sent. Returns an error if:
+//   - The location is invalid or unsupported, after reporting
+//     DownloadFailed
+//   - Every download attempt fails
+//   - install fails
+//   - ctx ends
+//   - A status cannot be sent
+func (e *Executor) UpdateFirmware(
+	ctx context.Context,
+	req updatefirmware.ReqMessage,
+	install func(ctx context.Context, firmware []byte) error,
+) error {
+	var errs []error
+
+	report := func(status types.FirmwareStatus) {
+		errs = append(errs, e.firmwareStatus(ctx, status))
+	}
+
+	location, err := ParseLocation(req.Location.String())
+	if err != nil {
+		report(types.FirmwareStatusDownloadFailed)
+
+		return errors.Join(append(errs, err)...)
+	}
+
+	err = e.waitUntil(ctx, req.RetrieveDate.Value())
+	if err != nil {
+		return err
+	}
+
+	var firmware []byte
+
+	report(types.FirmwareStatusDownloading)
+
+	err = e.retry(ctx, req.Retries, req.RetryInterval, func() error {
+		var downloadErr error
+
+		firmware, downloadErr = e.client.Download(ctx, location)
+
+		return downloadErr
+	})
+	if err != nil {
+		report(types.FirmwareStatusDownloadFailed)
+
+		return errors.Join(append(errs, err)...)
+	}
+
+	report(types.FirmwareStatusDownloaded)
+	report(types.FirmwareStatusInstalling)
+
+	err = install(ctx, firmware)
+	if err != nil {
+		report(types.FirmwareStatusInstallationFailed)
+
+		return errors.Join(append(errs, fmt.Errorf("transfer: %w", err))...)
+	}
+
+	report(types.FirmwareStatusInstalled)
+
+	return errors.Join(errs...)
+}
+
+// UploadDiagnostics uploads the diagnostics file, named as in the
+// GetDiagnostics.conf, to the location of req, reporting Uploading, then
+// Uploaded or UploadFailed. Run it after the GetDiagnostics.conf is sent.
+// Returns an error if:
+//   - The location is invalid or unsupported, after reporting UploadFailed
+//   - Every upload attempt fails
+//   - ctx ends
+//   - A status cannot be sent
+func (e *Executor) UploadDiagnostics(
+	ctx context.Context,
+	req getdiagnostics.ReqMessage,
+	fileName string,
+	data []byte,
+) error {
+	var errs []error
+
+	report := func(status types.DiagnosticsStatus) {
+		errs = append(errs, e.diagnosticsStatus(ctx, status))
+	}
+
+	location, err := ParseLocation(req.Location.String())
+	if err != nil {
+		report(types.DiagnosticsStatusUploadFailed)
+
+		return errors.Join(append(errs, err)...)
+	}
+
+	report(types.DiagnosticsStatusUploading)
+
+	err = e.retry(ctx, req.Retries, req.RetryInterval, func() error {
+		return e.client.Upload(ctx, location, fileName, data)
+	})
+	if err != nil {
+		report(types.DiagnosticsStatusUploadFailed)
+
+		return errors.Join(append(errs, err)...)
+	}
+
+	report(types.DiagnosticsStatusUploaded)
+
+	return errors.Join(errs...)
+}
+
+// retry runs attempt once, then up to retries more times while it fails.
+func (e *Executor) retry(
+	ctx context.Context,
+	retries *types.Integer,
+	retryInterval *types.Integer,
+	attempt func() error,
+) error {
+	remaining := 0
+	if retries != nil {
+		remaining = int(retries.Value())
+	}
+
+	wait := DefaultRetryInterval
+	if retryInterval != nil {
+		wait = time.Duration(retryInterval.Value()) * time.Second
+	}
+
+	for {
+		err := attempt()
+		if err == nil || remaining == 0 || ctx.Err() != nil {
+			return err
+		}
+
+		remaining--
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("transfer: %w", ctx.Err())
+		case <-e.clock.After(wait):
+		}
+	}
+}
+
+// waitUntil waits for a time of the clock.
+func (e *Executor) waitUntil(ctx context.Context, until time.Time) error {
+	wait := until.Sub(e.clock.Now())
+	if wait <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("transfer: %w", ctx.Err())
+	case <-e.clock.After(wait):
+		return nil
+	}
+}
+
+// firmwareStatus sends a FirmwareStatusNotification.req.
+func (e *Executor) firmwareStatus(
+	ctx context.Context,
+	status types.FirmwareStatus,
+) error {
+	req, err := firmwarestatusnotification.Req(
+		firmwarestatusnotification.ReqInput{Status: status.String()},
+	)
+	if err == nil {
+		var conf firmwarestatusnotification.ConfMessage
+
+		err = e.caller.Call(ctx, firmwarestatusnotification.Action, req, &conf)
+	}
+
+	if err != nil {
+		return fmt.Errorf("transfer: %s: %w", status, err)
+	}
+
+	return nil
+}
+
+// diagnosticsStatus sends a DiagnosticsStatusNotification.req.
+func (e *Executor) diagnosticsStatus(
+	ctx context.Context,
+	status types.DiagnosticsStatus,
+) error {
+	req, err := diagnosticsstatusnotification.Req(
+		diagnosticsstatusnotification.ReqInput{Status: status.String()},
+	)
+	if err == nil {
+		var conf diagnosticsstatusnotification.ConfMessage
+
+		err = e.caller.Call(
+			ctx,
+			diagnosticsstatusnotification.Action,
+			req,
+			&conf,
+		)
+	}
+
+	if err != nil {
+		return fmt.Errorf("transfer: %s: %w", status, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,281 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FTP reply codes the client relies on.
+const (
+	ftpDefaultPort     = "21"
+	ftpReady           = 220
+	ftpLoggedIn        = 230
+	ftpNeedPassword    = 331
+	ftpExtendedPassive = 229
+	ftpPassive         = 227
+	ftpPassiveFields   = 6
+	ftpPortShift       = 8
+	ftpPreliminary     = 1
+	ftpCompletion      = 2
+)
+
+// ftpSession is a logged-in FTP control connection.
+type ftpSession struct {
+	conn net.Conn
+	text *textproto.Conn
+	stop func() bool
+}
+
+// dialFTP connects and logs in with the user and password of location, or
+// anonymously. The connection is aborted when ctx ends.
+func dialFTP(ctx context.Context, location *url.URL) (*ftpSession, error) {
+	address := location.Host
+	if location.Port() == "" {
+		address = net.JoinHostPort(location.Hostname(), ftpDefaultPort)
+	}
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("ftp: %w", err)
+	}
+
+	session := &ftpSession{
+		conn: conn,
+		text: textproto.NewConn(conn),
+		stop: context.AfterFunc(ctx, func() {
+			_ = conn.SetDeadline(time.Unix(1, 0))
+		}),
+	}
+
+	err = session.login(location.User)
+	if err != nil {
+		session.close()
+
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// login reads the greeting and authenticates.
+func (s *ftpSession) login(user *url.Userinfo) error {
+	_, _, err := s.text.ReadResponse(ftpReady)
+	if err != nil {
+		return fmt.Errorf("ftp: %w", err)
+	}
+
+	name, password := "anonymous", "anonymous@"
+	if user != nil {
+		name = user.Username()
+		password, _ = user.Password()
+	}
+
+	code, _, err := s.cmd(0, "USER %s", name)
+	if err != nil {
+		return err
+	}
+
+	if code == ftpNeedPassword {
+		code, _, err = s.cmd(0, "PASS %s", password)
+		if err != nil {
+			return err
+		}
+	}
+
+	if code != ftpLoggedIn {
+		return fmt.Errorf("ftp: login refused with code %d", code)
+	}
+
+	_, _, err = s.cmd(ftpCompletion, "TYPE I")
+
+	return err
+}
+
+// cmd sends a command and reads its reply, checking its code against
+// expect as textproto.Conn.ReadResponse does.
+func (s *ftpSession) cmd(
+	expect int,
+	format string,
+	args ...any,
+) (int, string, error) {
+	_, err := s.text.Cmd(format, args...)
+	if err != nil {
+		return 0, "", fmt.Errorf("ftp: %w", err)
+	}
+
+	code, message, err := s.text.ReadResponse(expect)
+	if err != nil {
+		return code, message, fmt.Errorf("ftp: %w", err)
+	}
+
+	return code, message, nil
+}
+
+// passive opens a data connection, with EPSV or else PASV. The host the
+// server announces is ignored in favour of the one of the control
+// connection.
+func (s *ftpSession) passive(ctx context.Context) (net.Conn, error) {
+	port, err := s.extendedPassive()
+	if err != nil {
+		port, err = s.legacyPassive()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	host, _, err := net.SplitHostPort(s.conn.RemoteAddr().String())
+	if err != nil {
+		return nil, fmt.Errorf("ftp: %w", err)
+	}
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(
+		ctx,
+		"tcp",
+		net.JoinHostPort(host, strconv.Itoa(port)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ftp: %w", err)
+	}
+
+	context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Unix(1, 0)) })
+
+	return conn, nil
+}
+
+// extendedPassive parses "229 Entering Extended Passive Mode (|||port|)".
+func (s *ftpSession) extendedPassive() (int, error) {
+	_, message, err := s.cmd(ftpExtendedPassive, "EPSV")
+	if err != nil {
+		return 0, err
+	}
+
+	start := strings.Index(message, "(|||")
+	end := strings.LastIndex(message, "|)")
+
+	if start < 0 || end < start {
+		return 0, fmt.Errorf("ftp: malformed EPSV reply %q", message)
+	}
+
+	port, err := strconv.Atoi(message[start+len("(|||") : end])
+	if err != nil {
+		return 0, fmt.Errorf("ftp: malformed EPSV reply %q", message)
+	}
+
+	return port, nil
+}
+
+// legacyPassive parses "227 Entering Passive Mode (h1,h2,h3,h4,p1,p2)".
+func (s *ftpSession) legacyPassive() (int, error) {
+	_, message, err := s.cmd(ftpPassive, "PASV")
+	if err != nil {
+		return 0, err
+	}
+
+	start := strings.Index(message, "(")
+	end := strings.LastIndex(message, ")")
+
+	if start < 0 || end < start {
+		return 0, fmt.Errorf("ftp: malformed PASV reply %q", message)
+	}
+
+	fields := strings.Split(message[start+1:end], ",")
+	if len(fields) != ftpPassiveFields {
+		return 0, fmt.Errorf("ftp: malformed PASV reply %q", message)
+	}
+
+	high, errHigh := strconv.Atoi(strings.TrimSpace(fields[4]))
+	low, errLow := strconv.Atoi(strings.TrimSpace(fields[5]))
+
+	if errHigh != nil || errLow != nil {
+		return 0, fmt.Errorf("ftp: malformed PASV reply %q", message)
+	}
+
+	return high<<ftpPortShift | low, nil
+}
+
+// retrieve downloads a file.
+func (s *ftpSession) retrieve(
+	ctx context.Context,
+	path string,
+) ([]byte, error) {
+	data, err := s.passive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer data.Close()
+
+	_, _, err = s.cmd(ftpPreliminary, "RETR %s", path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := io.ReadAll(data)
+	if err != nil {
+		return nil, fmt.Errorf("ftp: %w", err)
+	}
+
+	_ = data.Close()
+
+	_, _, err = s.text.ReadResponse(ftpCompletion)
+	if err != nil {
+		return nil, fmt.Errorf("ftp: %w", err)
+	}
+
+	return content, nil
+}
+
+// store uploads a file.
+func (s *ftpSession) store(
+	ctx context.Context,
+	path string,
+	content []byte,
+) error {
+	data, err := s.passive(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer data.Close()
+
+	_, _, err = s.cmd(ftpPreliminary, "STOR %s", path)
+	if err != nil {
+		return err
+	}
+
+	_, err = data.Write(content)
+	if err != nil {
+		return fmt.Errorf("ftp: %w", err)
+	}
+
+	err = data.Close()
+	if err != nil {
+		return fmt.Errorf("ftp: %w", err)
+	}
+
+	_, _, err = s.text.ReadResponse(ftpCompletion)
+	if err != nil {
+		return fmt.Errorf("ftp: %w", err)
+	}
+
+	return nil
+}
+
+// close ends the session.
+func (s *ftpSession) close() {
+	_, _ = s.text.Cmd("QUIT")
+	_ = s.text.Close()
+	s.stop()
+}
@@ -0,0 +1,70 @@
+package transfer
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+
+	types "github.com/aasanchez/ocpp16types"
+)
+
+// URL schemes a Location may use.
+const (
+	SchemeHTTP  = "http"
+	SchemeHTTPS = "https"
+	SchemeFTP   = "ftp"
+)
+
+// ErrUnsupportedScheme is returned for a Location whose scheme is not http,
+// https or ftp.
+var ErrUnsupportedScheme = errors.New("transfer: unsupported location scheme")
+
+// ParseLocation parses the Location of an UpdateFirmware.req or a
+// GetDiagnostics.req. The scheme is lowercased. Returns an error if:
+//   - The location is not an absolute URL with a host, wrapping
+//     types.ErrInvalidValue
+//   - The decoded path, user or password holds a control character, such
+//     as a percent-encoded CR LF that would inject FTP commands, wrapping
+//     types.ErrInvalidValue
+//   - The scheme is not http, https or ftp, wrapping ErrUnsupportedScheme
+func ParseLocation(location string) (*url.URL, error) {
+	parsed, err := url.Parse(location)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf(
+			"location: %w: %q is not an absolute URL",
+			types.ErrInvalidValue,
+			location,
+		)
+	}
+
+	password, _ := parsed.User.Password()
+
+	for _, part := range []struct{ name, value string }{
+		{"path", parsed.Path},
+		{"user", parsed.User.Username()},
+		{"password", password},
+	} {
+		if strings.ContainsFunc(part.value, unicode.IsControl) {
+			return nil, fmt.Errorf(
+				"location: %w: control character in the %s",
+				types.ErrInvalidValue,
+				part.name,
+			)
+		}
+	}
+
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+
+	switch parsed.Scheme {
+	case SchemeHTTP, SchemeHTTPS, SchemeFTP:
+		return parsed, nil
+	default:
+		return nil, fmt.Errorf(
+			"location: %w: %s",
+			ErrUnsupportedScheme,
+			parsed.Scheme,
+		)
+	}
+}
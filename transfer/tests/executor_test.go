@@ -0,0 +1,262 @@
+package transfer_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aasanchez/ocpp16messages/diagnosticsstatusnotification"
+	"github.com/aasanchez/ocpp16messages/firmwarestatusnotification"
+	"github.com/aasanchez/ocpp16messages/getdiagnostics"
+	"github.com/aasanchez/ocpp16messages/ocpp16test"
+	"github.com/aasanchez/ocpp16messages/transfer"
+	"github.com/aasanchez/ocpp16messages/updatefirmware"
+	types "github.com/aasanchez/ocpp16types"
+)
+
+const retrieveDate = "2025-01-15T10:00:00Z"
+
+var (
+	firmware      = []byte("firmware image")
+	diagnostics   = []byte("diagnostics log")
+	errInstalling = errors.New("flash failed")
+)
+
+// instantClock is past every retrieveDate and fires every timer at once.
+type instantClock struct{}
+
+func (instantClock) Now() time.Time {
+	return time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (instantClock) After(time.Duration) <-chan time.Time {
+	fired := make(chan time.Time, 1)
+	fired <- time.Time{}
+
+	return fired
+}
+
+// statusCaller records the statuses the Executor reports.
+type statusCaller struct {
+	statuses []string
+}
+
+func (s *statusCaller) Call(
+	_ context.Context,
+	_ string,
+	request any,
+	_ any,
+) error {
+	switch req := request.(type) {
+	case firmwarestatusnotification.ReqMessage:
+		s.statuses = append(s.statuses, req.Status.String())
+	case diagnosticsstatusnotification.ReqMessage:
+		s.statuses = append(s.statuses, req.Status.String())
+	}
+
+	return nil
+}
+
+func updateFirmware(
+	t *testing.T,
+	location string,
+	retries int,
+) updatefirmware.ReqMessage {
+	t.Helper()
+
+	retryInterval := 1
+
+	req, err := updatefirmware.Req(updatefirmware.ReqInput{
+		Location:      location,
+		RetrieveDate:  retrieveDate,
+		Retries:       &retries,
+		RetryInterval: &retryInterval,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return req
+}
+
+func getDiagnostics(
+	t *testing.T,
+	location string,
+	retries int,
+) getdiagnostics.ReqMessage {
+	t.Helper()
+
+	req, err := getdiagnostics.Req(getdiagnostics.ReqInput{
+		Location:      location,
+		Retries:       &retries,
+		RetryInterval: nil,
+		StartTime:     nil,
+		StopTime:      nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	return req
+}
+
+func newExecutor() (*transfer.Executor, *statusCaller) {
+	caller := new(statusCaller)
+
+	return transfer.NewExecutor(
+		caller,
+		transfer.Client{HTTP: nil},
+		instantClock{},
+	), caller
+}
+
+func TestExecutor_UpdateFirmware(t *testing.T) {
+	t.Parallel()
+
+	servers := map[string]func(testing.TB) *ocpp16test.FileServer{
+		"http": ocpp16test.NewHTTPFileServer,
+		"ftp":  ocpp16test.NewFTPFileServer,
+	}
+
+	for name, newServer := range servers {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newServer(t)
+			server.Put("firmware.bin", firmware)
+			server.FailNext(1)
+
+			executor, caller := newExecutor()
+
+			var installed []byte
+
+			err := executor.UpdateFirmware(
+				context.Background(),
+				updateFirmware(t, server.URL()+"firmware.bin", 1),
+				func(_ context.Context, image []byte) error {
+					installed = image
+
+					return nil
+				},
+			)
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+
+			if !bytes.Equal(installed, firmware) {
+				t.Errorf(types.ErrorMismatch, firmware, installed)
+			}
+
+			want := "Downloading Downloaded Installing Installed"
+			if got := strings.Join(caller.statuses, " "); got != want {
+				t.Errorf(types.ErrorMismatch, want, got)
+			}
+		})
+	}
+}
+
+func TestExecutor_UpdateFirmware_failures(t *testing.T) {
+	t.Parallel()
+
+	server := ocpp16test.NewHTTPFileServer(t)
+	server.Put("firmware.bin", firmware)
+
+	tests := []struct {
+		name     string
+		location string
+		failures int
+		install  error
+		want     error
+		statuses string
+	}{
+		{
+			name:     "retries exhausted",
+			location: server.URL() + "firmware.bin",
+			failures: 2,
+			install:  nil,
+			want:     transfer.ErrHTTPStatus,
+			statuses: "Downloading DownloadFailed",
+		},
+		{
+			name:     "unsupported scheme",
+			location: "sftp://example.com/firmware.bin",
+			failures: 0,
+			install:  nil,
+			want:     transfer.ErrUnsupportedScheme,
+			statuses: "DownloadFailed",
+		},
+		{
+			name:     "installation",
+			location: server.URL() + "firmware.bin",
+			failures: 0,
+			install:  errInstalling,
+			want:     errInstalling,
+			statuses: "Downloading Downloaded Installing InstallationFailed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.FailNext(tt.failures)
+
+			executor, caller := newExecutor()
+
+			err := executor.UpdateFirmware(
+				context.Background(),
+				updateFirmware(t, tt.location, 1),
+				func(context.Context, []byte) error { return tt.install },
+			)
+			if !errors.Is(err, tt.want) {
+				t.Errorf(types.ErrorWrapping, err, tt.want)
+			}
+
+			got := strings.Join(caller.statuses, " ")
+			if got != tt.statuses {
+				t.Errorf(types.ErrorMismatch, tt.statuses, got)
+			}
+		})
+	}
+}
+
+func TestExecutor_UploadDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	servers := map[string]func(testing.TB) *ocpp16test.FileServer{
+		"http": ocpp16test.NewHTTPFileServer,
+		"ftp":  ocpp16test.NewFTPFileServer,
+	}
+
+	for name, newServer := range servers {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newServer(t)
+			server.FailNext(2)
+
+			executor, caller := newExecutor()
+
+			err := executor.UploadDiagnostics(
+				context.Background(),
+				getDiagnostics(t, server.URL(), 2),
+				"diagnostics.log",
+				diagnostics,
+			)
+			if err != nil {
+				t.Fatalf(types.ErrorUnexpectedError, err)
+			}
+
+			uploaded, ok := server.Upload("diagnostics.log")
+			if !ok || !bytes.Equal(uploaded, diagnostics) {
+				t.Errorf(types.ErrorMismatch, diagnostics, uploaded)
+			}
+
+			want := "Uploading Uploaded"
+			if got := strings.Join(caller.statuses, " "); got != want {
+				t.Errorf(types.ErrorMismatch, want, got)
+			}
+		})
+	}
+}
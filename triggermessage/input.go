@@ -0,0 +1,49 @@
+package triggermessage
+
+import "github.com/aasanchez/ocpp16messages/internal/wire"
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		RequestedMessage: m.RequestedMessage.String(),
+		ConnectorId:      wire.OptionalInt(m.ConnectorId),
+	}
+}
+
+// WithRequestedMessage returns a copy of the message with RequestedMessage
+// set to value. The copy is validated by Req.
+func (m ReqMessage) WithRequestedMessage(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.RequestedMessage = value
+
+	return Req(input)
+}
+
+// WithConnectorId returns a copy of the message with ConnectorId set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithConnectorId(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.ConnectorId = &value
+
+	return Req(input)
+}
+
+// ToInput returns the ConfInput the message is built from, so that
+// Conf(m.ToInput()) returns an equal message. The Input shares no memory
+// with the message.
+func (m ConfMessage) ToInput() ConfInput {
+	return ConfInput{
+		Status: m.Status.String(),
+	}
+}
+
+// WithStatus returns a copy of the message with Status set to value. The
+// copy is validated by Conf.
+func (m ConfMessage) WithStatus(value string) (ConfMessage, error) {
+	input := m.ToInput()
+	input.Status = value
+
+	return Conf(input)
+}
@@ -0,0 +1,57 @@
+package triggermessage
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of TriggerMessage.req.
+type reqWire struct {
+	RequestedMessage string  `json:"requestedMessage"`
+	ConnectorId      *uint16 `json:"connectorId,omitempty"`
+}
+
+// confWire is the OCPP-J payload of TriggerMessage.conf.
+type confWire struct {
+	Status string `json:"status"`
+}
+
+// MarshalJSON encodes the message as its OCPP-J TriggerMessage.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		RequestedMessage: m.RequestedMessage.String(),
+		ConnectorId:      wire.OptionalInteger(m.ConnectorId),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J TriggerMessage.req payload and validates it
+// with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J TriggerMessage.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{
+		Status: m.Status.String(),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J TriggerMessage.conf payload and validates it
+// with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package updatefirmware
+
+import "github.com/aasanchez/ocpp16messages/internal/wire"
+
+// ToInput returns the ReqInput the message is built from, so that
+// Req(m.ToInput()) returns an equal message. The Input shares no memory with
+// the message.
+func (m ReqMessage) ToInput() ReqInput {
+	return ReqInput{
+		Location:      m.Location.String(),
+		RetrieveDate:  m.RetrieveDate.String(),
+		Retries:       wire.OptionalInt(m.Retries),
+		RetryInterval: wire.OptionalInt(m.RetryInterval),
+	}
+}
+
+// WithLocation returns a copy of the message with Location set to value. The
+// copy is validated by Req.
+func (m ReqMessage) WithLocation(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Location = value
+
+	return Req(input)
+}
+
+// WithRetrieveDate returns a copy of the message with RetrieveDate set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithRetrieveDate(value string) (ReqMessage, error) {
+	input := m.ToInput()
+	input.RetrieveDate = value
+
+	return Req(input)
+}
+
+// WithRetries returns a copy of the message with Retries set to value. The
+// copy is validated by Req.
+func (m ReqMessage) WithRetries(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.Retries = &value
+
+	return Req(input)
+}
+
+// WithRetryInterval returns a copy of the message with RetryInterval set to
+// value. The copy is validated by Req.
+func (m ReqMessage) WithRetryInterval(value int) (ReqMessage, error) {
+	input := m.ToInput()
+	input.RetryInterval = &value
+
+	return Req(input)
+}
+
+// ToInput returns the empty ConfInput, as UpdateFirmware.conf has no fields.
+func (ConfMessage) ToInput() ConfInput {
+	return ConfInput{}
+}
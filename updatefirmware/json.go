@@ -0,0 +1,57 @@
+package updatefirmware
+
+import (
+	"github.com/aasanchez/ocpp16messages/internal/wire"
+)
+
+// reqWire is the OCPP-J payload of UpdateFirmware.req.
+type reqWire struct {
+	Location      string  `json:"location"`
+	Retries       *uint16 `json:"retries,omitempty"`
+	RetrieveDate  string  `json:"retrieveDate"`
+	RetryInterval *uint16 `json:"retryInterval,omitempty"`
+}
+
+// confWire is the OCPP-J payload of UpdateFirmware.conf.
+type confWire struct{}
+
+// MarshalJSON encodes the message as its OCPP-J UpdateFirmware.req payload.
+func (m ReqMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(reqWire{
+		Location:      m.Location.String(),
+		Retries:       wire.OptionalInteger(m.Retries),
+		RetrieveDate:  m.RetrieveDate.String(),
+		RetryInterval: wire.OptionalInteger(m.RetryInterval),
+	})
+}
+
+// UnmarshalJSON decodes an OCPP-J UpdateFirmware.req payload and validates it
+// with Req, so only valid messages are produced.
+func (m *ReqMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Req)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
+
+// MarshalJSON encodes the message as its OCPP-J UpdateFirmware.conf payload.
+func (m ConfMessage) MarshalJSON() ([]byte, error) {
+	return wire.Marshal(confWire{})
+}
+
+// UnmarshalJSON decodes an OCPP-J UpdateFirmware.conf payload and validates it
+// with Conf, so only valid messages are produced.
+func (m *ConfMessage) UnmarshalJSON(data []byte) error {
+	msg, err := wire.Decode(data, Conf)
+	if err != nil {
+		return err
+	}
+
+	*m = msg
+
+	return nil
+}
@@ -170,6 +170,26 @@ func TestReq_Invalid_EmptyLocation(t *testing.T) {
 	}
 }
 
+func TestReq_Valid_RelativeLocation(t *testing.T) {
+	t.Parallel()
+
+	// The schema requires a URI; Req leaves that to schema.CrossCheck and
+	// transfer.ParseLocation.
+	req, err := uf.Req(uf.ReqInput{
+		Location:      "files/upload",
+		RetrieveDate:  validRetrieveDateValue,
+		Retries:       nil,
+		RetryInterval: nil,
+	})
+	if err != nil {
+		t.Fatalf(types.ErrorUnexpectedError, err)
+	}
+
+	if req.Location.String() != "files/upload" {
+		t.Errorf(types.ErrorMismatch, "files/upload", req.Location.String())
+	}
+}
+
 func TestReq_Invalid_EmptyRetrieveDate(t *testing.T) {
 	t.Parallel()
 